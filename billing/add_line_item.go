@@ -2,6 +2,7 @@ package billing
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"encore.dev/beta/errs"
@@ -14,33 +15,50 @@ import (
 type CreateLineItemRequest struct {
 	IdempotencyKey string `header:"X-Idempotency-Key" json:"-"`
 
+	// Kind defaults to charge. Charges and credits take a positive amount; credits reduce the
+	// bill total. Adjustments take a signed amount.
+	Kind        string `json:"kind" validate:"omitempty,oneof=charge credit adjustment"`
 	Currency    string `json:"currency" validate:"required,len=3,alpha"`
-	AmountCents int64  `json:"amount_cents" validate:"required,min=1"`
-	Description string `json:"description" validate:"required,max=255"`
-	ReferenceID string `json:"reference_id" validate:"required,max=100"`
+	AmountCents int64  `json:"amount_cents" validate:"required_without=UnitAmountCents,excluded_with=UnitAmountCents"`
+	// UnitAmountCents and Quantity replace AmountCents for usage fees; Quantity is a decimal
+	// string defaulting to 1 and the extended amount is computed by the service
+	UnitAmountCents int64  `json:"unit_amount_cents" validate:"required_with=Quantity"`
+	Quantity        string `json:"quantity" validate:"omitempty,numeric"`
+	Description     string `json:"description" validate:"required,max=255"`
+	ReferenceID     string `json:"reference_id" validate:"required,max=100"`
+	// IncurredAt is when the fee happened, now by default; it must fall inside the billing period
+	// and is required while the bill is in its grace period
+	IncurredAt time.Time `json:"incurred_at"`
 }
 
 type LineItemResponse struct {
 	LineItem model.LineItem `json:"line_item"`
 }
 
-// encore:api public path=/v1/bills/:id/line_items method=POST tag:idempotency
+// encore:api auth path=/v1/bills/:id/line_items method=POST tag:idempotency
 func (s *Service) AddLineItem(ctx context.Context, id int32, req *CreateLineItemRequest) (*LineItemResponse, error) {
 	if id <= 0 {
 		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
 	}
 
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
 	lineItem := &model.LineItem{
-		BillID:         id,
-		Currency:       req.Currency,
-		AmountCents:    req.AmountCents,
-		Description:    req.Description,
-		ReferenceID:    req.ReferenceID,
-		IncurredAt:     time.Now(),
-		IdempotencyKey: req.IdempotencyKey,
+		BillID:          id,
+		Kind:            model.LineItemKind(req.Kind),
+		Currency:        req.Currency,
+		AmountCents:     req.AmountCents,
+		UnitAmountCents: req.UnitAmountCents,
+		Quantity:        req.Quantity,
+		Description:     req.Description,
+		ReferenceID:     req.ReferenceID,
+		IncurredAt:      req.IncurredAt,
+		IdempotencyKey:  req.IdempotencyKey,
 	}
 
-	result, err := s.business.AddLineItemToBill(ctx, id, lineItem)
+	result, err := s.business.AddLineItemToBill(ctx, id, lineItem, callerActor())
 	if err != nil {
 		rlog.Error("failed to create line item", "error", err, "bill_id", id)
 		return nil, err
@@ -51,6 +69,8 @@ func (s *Service) AddLineItem(ctx context.Context, id int32, req *CreateLineItem
 		return s.signalAddLineItem(ctx, result.BillWorkflowID, result.ID)
 	})
 
+	s.newAmountFormatter(ctx).lineItem(result)
+
 	return &LineItemResponse{
 		LineItem: *result,
 	}, nil
@@ -62,6 +82,20 @@ func (r *CreateLineItemRequest) Validate() error {
 		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
 	}
 
+	if err := validateLineItemSign(r.Kind, r.AmountCents, r.UnitAmountCents); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
+
+// validateLineItemSign rejects negative amounts on charges and credits; credits are
+// submitted as positive amounts and only adjustments may be negative
+func validateLineItemSign(kind string, amountCents, unitAmountCents int64) error {
+	if kind != string(model.LineItemKindAdjustment) && (amountCents < 0 || unitAmountCents < 0) {
+		return errors.New("amount_cents must be positive for charge and credit line items")
+	}
+
 	return nil
 }
 
@@ -18,6 +18,8 @@ import (
 )
 
 func TestAddLineItem(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -25,8 +27,9 @@ func TestAddLineItem(t *testing.T) {
 	mockTemporal := mocks.NewClient(t)
 
 	service := &Service{
-		business: mockBusiness,
-		temporal: mockTemporal,
+		business:   mockBusiness,
+		temporal:   mockTemporal,
+		currencies: stubMinorUnits(ctrl),
 	}
 
 	now := time.Now()
@@ -213,7 +216,7 @@ func TestAddLineItem(t *testing.T) {
 			// Set up business mock expectations for AddLineItemToBill
 			if tc.expectAddLineItemCall {
 				mockBusiness.EXPECT().
-					AddLineItemToBill(gomock.Any(), tc.billID, gomock.Any()).
+					AddLineItemToBill(gomock.Any(), tc.billID, gomock.Any(), "admin").
 					DoAndReturn(func(ctx context.Context, billID int32, lineItem *model.LineItem) (*model.LineItem, error) {
 						// Verify the line item fields are set correctly
 						assert.Equal(t, tc.billID, lineItem.BillID)
@@ -222,7 +225,7 @@ func TestAddLineItem(t *testing.T) {
 						assert.Equal(t, tc.request.Description, lineItem.Description)
 						assert.Equal(t, tc.request.ReferenceID, lineItem.ReferenceID)
 						assert.Equal(t, tc.request.IdempotencyKey, lineItem.IdempotencyKey)
-						assert.False(t, lineItem.IncurredAt.IsZero())
+						assert.Equal(t, tc.request.IncurredAt, lineItem.IncurredAt)
 
 						return tc.mockAddLineItemReturn, tc.mockAddLineItemError
 					}).
@@ -345,7 +348,92 @@ func TestCreateLineItemRequest_Validation(t *testing.T) {
 				Description: "Negative amount",
 				ReferenceID: "ref-006",
 			},
-			expectedError: "min",
+			expectedError: "must be positive",
+		},
+		{
+			name: "negative_credit_amount",
+			request: &CreateLineItemRequest{
+				Kind:        "credit",
+				Currency:    "USD",
+				AmountCents: -100,
+				Description: "Credits are submitted as positive amounts",
+				ReferenceID: "ref-credit",
+			},
+			expectedError: "must be positive",
+		},
+		{
+			name: "valid_credit",
+			request: &CreateLineItemRequest{
+				Kind:        "credit",
+				Currency:    "USD",
+				AmountCents: 100,
+				Description: "Goodwill credit",
+				ReferenceID: "ref-credit",
+			},
+		},
+		{
+			name: "valid_negative_adjustment",
+			request: &CreateLineItemRequest{
+				Kind:        "adjustment",
+				Currency:    "USD",
+				AmountCents: -100,
+				Description: "Billing correction",
+				ReferenceID: "ref-adjustment",
+			},
+		},
+		{
+			name: "valid_unit_price_with_quantity",
+			request: &CreateLineItemRequest{
+				Currency:        "USD",
+				UnitAmountCents: 250,
+				Quantity:        "37",
+				Description:     "Wire transfers",
+				ReferenceID:     "ref-usage",
+			},
+		},
+		{
+			name: "amount_with_unit_price",
+			request: &CreateLineItemRequest{
+				Currency:        "USD",
+				AmountCents:     9250,
+				UnitAmountCents: 250,
+				Description:     "Ambiguous amount",
+				ReferenceID:     "ref-usage",
+			},
+			expectedError: "excluded_with",
+		},
+		{
+			name: "quantity_without_unit_price",
+			request: &CreateLineItemRequest{
+				Currency:    "USD",
+				AmountCents: 250,
+				Quantity:    "37",
+				Description: "Missing unit price",
+				ReferenceID: "ref-usage",
+			},
+			expectedError: "required_with",
+		},
+		{
+			name: "invalid_quantity",
+			request: &CreateLineItemRequest{
+				Currency:        "USD",
+				UnitAmountCents: 250,
+				Quantity:        "a few",
+				Description:     "Invalid quantity",
+				ReferenceID:     "ref-usage",
+			},
+			expectedError: "numeric",
+		},
+		{
+			name: "invalid_kind",
+			request: &CreateLineItemRequest{
+				Kind:        "refund",
+				Currency:    "USD",
+				AmountCents: 100,
+				Description: "Unknown kind",
+				ReferenceID: "ref-kind",
+			},
+			expectedError: "oneof",
 		},
 		{
 			name: "missing_description",
@@ -0,0 +1,159 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/business/bill"
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+type AddLineItemsBatchRequest struct {
+	// Mode is atomic (default), adding all items or none, or partial, adding the valid ones
+	Mode  string          `json:"mode" validate:"omitempty,oneof=atomic partial"`
+	Items []BatchLineItem `json:"items"`
+}
+
+// BatchLineItem is a line item of a batch; it carries its own idempotency key
+type BatchLineItem struct {
+	IdempotencyKey  string `json:"idempotency_key" validate:"required,max=255"`
+	Kind            string `json:"kind" validate:"omitempty,oneof=charge credit adjustment"`
+	Currency        string `json:"currency" validate:"required,len=3,alpha"`
+	AmountCents     int64  `json:"amount_cents" validate:"required_without=UnitAmountCents,excluded_with=UnitAmountCents"`
+	UnitAmountCents int64  `json:"unit_amount_cents" validate:"required_with=Quantity"`
+	Quantity        string `json:"quantity" validate:"omitempty,numeric"`
+	Description     string `json:"description" validate:"required,max=255"`
+	ReferenceID     string `json:"reference_id" validate:"required,max=100"`
+	// IncurredAt is when the fee happened, now by default; it must fall inside the billing period
+	// and is required while the bill is in its grace period
+	IncurredAt time.Time `json:"incurred_at"`
+}
+
+type AddLineItemsBatchResponse struct {
+	Results    []model.LineItemBatchResult `json:"results"`
+	Created    int                         `json:"created"`
+	Duplicates int                         `json:"duplicates"`
+	Failed     int                         `json:"failed"`
+}
+
+//encore:api auth path=/v1/bills/:id/line_items:batch method=POST
+func (s *Service) AddLineItemsBatch(ctx context.Context, id int32, req *AddLineItemsBatchRequest) (*AddLineItemsBatchResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	mode := model.LineItemBatchMode(req.Mode)
+	if mode == "" {
+		mode = model.LineItemBatchModeAtomic
+	}
+
+	// Items are validated before the bill is locked; in partial mode invalid items are
+	// reported as failed and left out of the batch
+	results := make([]model.LineItemBatchResult, len(req.Items))
+	lineItems := make([]*model.LineItem, 0, len(req.Items))
+	positions := make([]int, 0, len(req.Items))
+	for i, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			if mode == model.LineItemBatchModeAtomic {
+				return nil, &errs.Error{Code: errs.InvalidArgument, Message: fmt.Sprintf("item %d: %s", i, err.Error())}
+			}
+			results[i] = model.LineItemBatchResult{Index: i, Status: model.LineItemBatchStatusFailed, Error: err.Error()}
+			continue
+		}
+
+		lineItems = append(lineItems, &model.LineItem{
+			BillID:          id,
+			Kind:            model.LineItemKind(item.Kind),
+			Currency:        item.Currency,
+			AmountCents:     item.AmountCents,
+			UnitAmountCents: item.UnitAmountCents,
+			Quantity:        item.Quantity,
+			Description:     item.Description,
+			ReferenceID:     item.ReferenceID,
+			IncurredAt:      item.IncurredAt,
+			IdempotencyKey:  item.IdempotencyKey,
+		})
+		positions = append(positions, i)
+	}
+
+	if len(lineItems) > 0 {
+		added, err := s.business.AddLineItemsToBill(ctx, id, lineItems, mode, callerActor())
+		if err != nil {
+			rlog.Error("failed to add line items", "error", err, "bill_id", id, "items", len(lineItems))
+			return nil, err
+		}
+		for j, result := range added {
+			result.Index = positions[j]
+			results[positions[j]] = result
+		}
+	}
+
+	response := &AddLineItemsBatchResponse{Results: results}
+	var createdIDs []int32
+	var workflowID string
+	for _, result := range results {
+		switch result.Status {
+		case model.LineItemBatchStatusCreated:
+			response.Created++
+			createdIDs = append(createdIDs, result.LineItem.ID)
+			workflowID = result.LineItem.BillWorkflowID
+		case model.LineItemBatchStatusDuplicate:
+			response.Duplicates++
+		case model.LineItemBatchStatusFailed:
+			response.Failed++
+		}
+	}
+
+	amounts := s.newAmountFormatter(ctx)
+	for _, result := range response.Results {
+		if result.LineItem != nil {
+			amounts.lineItem(result.LineItem)
+		}
+	}
+
+	// One signal covers the whole batch
+	if len(createdIDs) > 0 {
+		runAsync("signal_add_line_items", func(ctx context.Context) error {
+			return s.temporal.SignalWorkflow(ctx, workflowID, "", workflow.AddLineItemSignalName, workflow.AddLineItemSignal{
+				LineItemIDs: createdIDs,
+			})
+		})
+	}
+
+	return response, nil
+}
+
+// Validate implements validation for AddLineItemsBatchRequest. Items are validated by the
+// handler so that partial mode can report them individually.
+func (r *AddLineItemsBatchRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	if len(r.Items) == 0 {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "items must not be empty"}
+	}
+	if len(r.Items) > bill.MaxLineItemBatchSize {
+		return &errs.Error{Code: errs.InvalidArgument, Message: fmt.Sprintf("a batch holds at most %d items", bill.MaxLineItemBatchSize)}
+	}
+
+	return nil
+}
+
+// Validate checks a single batch item
+func (i *BatchLineItem) Validate() error {
+	if err := validate.Struct(i); err != nil {
+		return err
+	}
+
+	return validateLineItemSign(i.Kind, i.AmountCents, i.UnitAmountCents)
+}
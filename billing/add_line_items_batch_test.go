@@ -0,0 +1,151 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/business/bill"
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+func TestAddLineItemsBatch(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	originalRunAsync := runAsync
+	runAsync = func(op string, fn func(ctx context.Context) error) { _ = fn(context.Background()) }
+	defer func() { runAsync = originalRunAsync }()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	mockTemporal := mocks.NewClient(t)
+	service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
+
+	validItem := BatchLineItem{IdempotencyKey: "k-1", Currency: "USD", AmountCents: 100, Description: "fee", ReferenceID: "ref-1"}
+	duplicateItem := BatchLineItem{IdempotencyKey: "k-2", Currency: "USD", AmountCents: 200, Description: "fee", ReferenceID: "ref-2"}
+	invalidItem := BatchLineItem{IdempotencyKey: "k-3", Currency: "US", AmountCents: 300, Description: "fee", ReferenceID: "ref-3"}
+
+	t.Run("partial_mode_reports_invalid_items", func(t *testing.T) {
+		mockBusiness.EXPECT().
+			AddLineItemsToBill(gomock.Any(), int32(1), gomock.Len(2), model.LineItemBatchModePartial, "admin").
+			Return([]model.LineItemBatchResult{
+				{Index: 0, Status: model.LineItemBatchStatusCreated, LineItem: &model.LineItem{ID: 10, BillWorkflowID: "workflow-123"}},
+				{Index: 1, Status: model.LineItemBatchStatusDuplicate, LineItem: &model.LineItem{ID: 9}},
+			}, nil)
+		mockTemporal.On("SignalWorkflow", mock.Anything, "workflow-123", "", workflow.AddLineItemSignalName,
+			workflow.AddLineItemSignal{LineItemIDs: []int32{10}}).Return(nil).Once()
+
+		response, err := service.AddLineItemsBatch(context.Background(), 1, &AddLineItemsBatchRequest{
+			Mode:  "partial",
+			Items: []BatchLineItem{validItem, invalidItem, duplicateItem},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Created)
+		assert.Equal(t, 1, response.Duplicates)
+		assert.Equal(t, 1, response.Failed)
+		assert.Equal(t, model.LineItemBatchStatusCreated, response.Results[0].Status)
+		assert.Equal(t, model.LineItemBatchStatusFailed, response.Results[1].Status)
+		assert.Equal(t, 1, response.Results[1].Index)
+		assert.Contains(t, response.Results[1].Error, "len")
+		assert.Equal(t, model.LineItemBatchStatusDuplicate, response.Results[2].Status)
+		assert.Equal(t, 2, response.Results[2].Index)
+	})
+
+	t.Run("max_batch_size_sends_one_signal", func(t *testing.T) {
+		items := make([]BatchLineItem, bill.MaxLineItemBatchSize)
+		added := make([]model.LineItemBatchResult, bill.MaxLineItemBatchSize)
+		createdIDs := make([]int32, bill.MaxLineItemBatchSize)
+		for i := range items {
+			items[i] = BatchLineItem{IdempotencyKey: fmt.Sprintf("k-%d", i), Currency: "USD", AmountCents: 100, Description: "fee", ReferenceID: "ref"}
+			createdIDs[i] = int32(i + 1)
+			added[i] = model.LineItemBatchResult{Index: i, Status: model.LineItemBatchStatusCreated, LineItem: &model.LineItem{ID: createdIDs[i], BillWorkflowID: "workflow-123"}}
+		}
+
+		mockBusiness.EXPECT().
+			AddLineItemsToBill(gomock.Any(), int32(1), gomock.Len(bill.MaxLineItemBatchSize), model.LineItemBatchModeAtomic, "admin").
+			Return(added, nil)
+		// The workflow recalculates the total once for the whole batch
+		mockTemporal.On("SignalWorkflow", mock.Anything, "workflow-123", "", workflow.AddLineItemSignalName,
+			workflow.AddLineItemSignal{LineItemIDs: createdIDs}).Return(nil).Once()
+
+		request := &AddLineItemsBatchRequest{Items: items}
+		assert.NoError(t, request.Validate())
+		response, err := service.AddLineItemsBatch(context.Background(), 1, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, bill.MaxLineItemBatchSize, response.Created)
+	})
+
+	t.Run("atomic_mode_rejects_invalid_item", func(t *testing.T) {
+		response, err := service.AddLineItemsBatch(context.Background(), 1, &AddLineItemsBatchRequest{
+			Items: []BatchLineItem{validItem, invalidItem},
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "item 1:")
+		assert.Nil(t, response)
+	})
+
+	t.Run("invalid_bill_id", func(t *testing.T) {
+		response, err := service.AddLineItemsBatch(context.Background(), 0, &AddLineItemsBatchRequest{
+			Items: []BatchLineItem{validItem},
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid bill ID")
+		assert.Nil(t, response)
+	})
+}
+
+func TestAddLineItemsBatchRequest_Validation(t *testing.T) {
+	item := BatchLineItem{IdempotencyKey: "k-1", Currency: "USD", AmountCents: 100, Description: "fee", ReferenceID: "ref-1"}
+
+	testCases := []struct {
+		name          string
+		request       *AddLineItemsBatchRequest
+		expectedError string
+	}{
+		{
+			name:    "valid_request",
+			request: &AddLineItemsBatchRequest{Mode: "atomic", Items: []BatchLineItem{item}},
+		},
+		{
+			name:          "empty_items",
+			request:       &AddLineItemsBatchRequest{},
+			expectedError: "items must not be empty",
+		},
+		{
+			name:          "too_many_items",
+			request:       &AddLineItemsBatchRequest{Items: make([]BatchLineItem, bill.MaxLineItemBatchSize+1)},
+			expectedError: "a batch holds at most",
+		},
+		{
+			name:          "invalid_mode",
+			request:       &AddLineItemsBatchRequest{Mode: "best_effort", Items: []BatchLineItem{item}},
+			expectedError: "oneof",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
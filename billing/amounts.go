@@ -0,0 +1,54 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/rlog"
+
+	"encore.app/billing/business/currency"
+	"encore.app/billing/model"
+)
+
+// amountFormatter fills in the decimal-string amounts of API responses, looking up the minor
+// units of each currency once per response
+type amountFormatter struct {
+	ctx        context.Context
+	currencies currency.Business
+	minorUnits map[string]int32
+}
+
+func (s *Service) newAmountFormatter(ctx context.Context) *amountFormatter {
+	return &amountFormatter{
+		ctx:        ctx,
+		currencies: s.currencies,
+		minorUnits: make(map[string]int32),
+	}
+}
+
+// units returns the minor units of a currency. A failed lookup falls back to the default rather
+// than failing a response whose integer amounts are correct either way.
+func (f *amountFormatter) units(code string) int32 {
+	if units, ok := f.minorUnits[code]; ok {
+		return units
+	}
+
+	units, err := f.currencies.GetMinorUnits(f.ctx, code)
+	if err != nil {
+		rlog.Warn("failed to get currency minor units", "error", err, "currency", code)
+		units = model.DefaultMinorUnits
+	}
+	f.minorUnits[code] = units
+	return units
+}
+
+func (f *amountFormatter) bill(bill *model.Bill) {
+	bill.SetDecimalAmounts(f.units(bill.Currency))
+}
+
+func (f *amountFormatter) lineItem(lineItem *model.LineItem) {
+	lineItem.SetDecimalAmounts(f.units(lineItem.Currency))
+}
+
+func (f *amountFormatter) payment(payment *model.Payment) {
+	payment.SetDecimalAmounts(f.units(payment.Currency))
+}
@@ -0,0 +1,78 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/model"
+)
+
+// stubMinorUnits returns a currency business that reports two minor units for every currency
+func stubMinorUnits(ctrl *gomock.Controller) *currency_business.MockBusiness {
+	mockCurrencies := currency_business.NewMockBusiness(ctrl)
+	mockCurrencies.EXPECT().GetMinorUnits(gomock.Any(), gomock.Any()).Return(model.DefaultMinorUnits, nil).AnyTimes()
+	return mockCurrencies
+}
+
+func TestFormatAmount(t *testing.T) {
+	testCases := []struct {
+		amount     int64
+		minorUnits int32
+		expected   string
+	}{
+		{amount: 1250, minorUnits: 2, expected: "12.50"},
+		{amount: 5, minorUnits: 2, expected: "0.05"},
+		{amount: -1250, minorUnits: 2, expected: "-12.50"},
+		{amount: 1250, minorUnits: 0, expected: "1250"},
+		{amount: 1250, minorUnits: 3, expected: "1.250"},
+		{amount: 0, minorUnits: 3, expected: "0.000"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, model.FormatAmount(tc.amount, tc.minorUnits))
+	}
+}
+
+func TestAmountFormatter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCurrencies := currency_business.NewMockBusiness(ctrl)
+	service := &Service{currencies: mockCurrencies}
+
+	mockCurrencies.EXPECT().GetMinorUnits(gomock.Any(), "JPY").Return(int32(0), nil).Times(1)
+	mockCurrencies.EXPECT().GetMinorUnits(gomock.Any(), "KWD").Return(int32(0), &errs.Error{Code: errs.NotFound, Message: "currency not found"}).Times(1)
+
+	amounts := service.newAmountFormatter(context.Background())
+
+	bill := &model.Bill{
+		Currency:         "JPY",
+		TotalAmountCents: 1500,
+		AmountPaidCents:  500,
+		BalanceCents:     1000,
+		LineItems:        []model.LineItem{{Currency: "JPY", UnitAmountCents: 750, AmountCents: 1500}},
+	}
+	amounts.bill(bill)
+	assert.Equal(t, "1500", bill.TotalAmount)
+	assert.Equal(t, "500", bill.AmountPaid)
+	assert.Equal(t, "1000", bill.Balance)
+	assert.Equal(t, "750", bill.LineItems[0].UnitAmount)
+	assert.Equal(t, "1500", bill.LineItems[0].Amount)
+
+	// The minor units of a currency are looked up once per response
+	payment := &model.Payment{Currency: "JPY", AmountCents: 500}
+	amounts.payment(payment)
+	assert.Equal(t, "500", payment.Amount)
+
+	// An unknown currency falls back to two minor units
+	lineItem := &model.LineItem{Currency: "KWD", UnitAmountCents: 1250, AmountCents: 1250}
+	amounts.lineItem(lineItem)
+	amounts.lineItem(lineItem)
+	assert.Equal(t, "12.50", lineItem.Amount)
+}
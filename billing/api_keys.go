@@ -0,0 +1,73 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type IssueAPIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type IssueAPIKeyResponse struct {
+	APIKey model.IssuedAPIKey `json:"api_key"`
+}
+
+type APIKeyResponse struct {
+	APIKey model.APIKey `json:"api_key"`
+}
+
+// IssueAPIKey creates a new API key for an account. The plaintext key is only returned here.
+//
+//encore:api auth path=/v1/accounts/:id/api_keys method=POST
+func (s *Service) IssueAPIKey(ctx context.Context, id int32, req *IssueAPIKeyRequest) (*IssueAPIKeyResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid account ID"}
+	}
+
+	result, err := s.apiKeys.IssueAPIKey(ctx, id, req.Name)
+	if err != nil {
+		rlog.Error("failed to issue api key", "error", err, "account_id", id)
+		return nil, err
+	}
+
+	return &IssueAPIKeyResponse{
+		APIKey: *result,
+	}, nil
+}
+
+//encore:api auth path=/v1/api_keys/:id method=DELETE
+func (s *Service) RevokeAPIKey(ctx context.Context, id int32) (*APIKeyResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid api key ID"}
+	}
+
+	result, err := s.apiKeys.RevokeAPIKey(ctx, id)
+	if err != nil {
+		rlog.Error("failed to revoke api key", "error", err, "id", id)
+		return nil, err
+	}
+
+	return &APIKeyResponse{
+		APIKey: *result,
+	}, nil
+}
+
+// Validate implements validation for IssueAPIKeyRequest
+func (r *IssueAPIKeyRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/apikey_business"
+	"encore.app/billing/model"
+)
+
+func TestIssueAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeys := apikey_business.NewMockBusiness(ctrl)
+	service := &Service{apiKeys: mockAPIKeys}
+
+	testCases := []struct {
+		name            string
+		caller          *AuthData
+		accountID       int32
+		mockReturn      *model.IssuedAPIKey
+		mockError       error
+		expectIssueCall bool
+		expectedError   string
+	}{
+		{
+			name:      "admin_issues_key",
+			caller:    &AuthData{Admin: true},
+			accountID: 1,
+			mockReturn: &model.IssuedAPIKey{
+				APIKey: model.APIKey{ID: 3, AccountID: 1, Name: "ci", KeyPrefix: "pb_01234567"},
+				Key:    "pb_0123456789",
+			},
+			expectIssueCall: true,
+		},
+		{
+			name:          "account_caller_denied",
+			caller:        &AuthData{AccountID: 1},
+			accountID:     1,
+			expectedError: "admin access required",
+		},
+		{
+			name:          "invalid_account_id",
+			caller:        &AuthData{Admin: true},
+			accountID:     0,
+			expectedError: "invalid account ID",
+		},
+		{
+			name:            "account_not_found",
+			caller:          &AuthData{Admin: true},
+			accountID:       404,
+			mockError:       &errs.Error{Code: errs.NotFound, Message: "account not found"},
+			expectIssueCall: true,
+			expectedError:   "account not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withCaller(t, tc.caller)
+
+			if tc.expectIssueCall {
+				mockAPIKeys.EXPECT().
+					IssueAPIKey(gomock.Any(), tc.accountID, "ci").
+					Return(tc.mockReturn, tc.mockError).
+					Times(1)
+			}
+
+			response, err := service.IssueAPIKey(context.Background(), tc.accountID, &IssueAPIKeyRequest{Name: "ci"})
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockReturn.Key, response.APIKey.Key)
+				assert.Equal(t, tc.mockReturn.ID, response.APIKey.ID)
+			}
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeys := apikey_business.NewMockBusiness(ctrl)
+	service := &Service{apiKeys: mockAPIKeys}
+
+	testCases := []struct {
+		name             string
+		caller           *AuthData
+		keyID            int32
+		mockError        error
+		expectRevokeCall bool
+		expectedError    string
+	}{
+		{
+			name:             "admin_revokes_key",
+			caller:           &AuthData{Admin: true},
+			keyID:            3,
+			expectRevokeCall: true,
+		},
+		{
+			name:          "account_caller_denied",
+			caller:        &AuthData{AccountID: 1},
+			keyID:         3,
+			expectedError: "admin access required",
+		},
+		{
+			name:             "key_not_found",
+			caller:           &AuthData{Admin: true},
+			keyID:            404,
+			mockError:        &errs.Error{Code: errs.NotFound, Message: "api key not found"},
+			expectRevokeCall: true,
+			expectedError:    "api key not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withCaller(t, tc.caller)
+
+			if tc.expectRevokeCall {
+				var result *model.APIKey
+				if tc.mockError == nil {
+					result = &model.APIKey{ID: tc.keyID, AccountID: 1}
+				}
+				mockAPIKeys.EXPECT().
+					RevokeAPIKey(gomock.Any(), tc.keyID).
+					Return(result, tc.mockError).
+					Times(1)
+			}
+
+			response, err := service.RevokeAPIKey(context.Background(), tc.keyID)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.keyID, response.APIKey.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type AuditLogResponse struct {
+	Entries []model.AuditEntry `json:"entries"`
+}
+
+type AuditVerificationResponse struct {
+	Verification model.AuditVerification `json:"verification"`
+}
+
+type AuditReportResponse struct {
+	Report model.AuditReport `json:"report"`
+}
+
+// GetAuditLog returns the hash-chained audit log of a bill.
+//
+//encore:api auth path=/v1/bills/:id/audit method=GET
+func (s *Service) GetAuditLog(ctx context.Context, id int32) (*AuditLogResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.business.GetAuditLog(ctx, id)
+	if err != nil {
+		rlog.Error("failed to get audit log", "error", err, "id", id)
+		return nil, err
+	}
+
+	return &AuditLogResponse{
+		Entries: entries,
+	}, nil
+}
+
+// VerifyAuditLog rehashes the audit log of a bill and reports where the chain breaks, if it does.
+//
+//encore:api auth path=/v1/bills/:id/audit/verify method=GET
+func (s *Service) VerifyAuditLog(ctx context.Context, id int32) (*AuditVerificationResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := s.business.VerifyAuditLog(ctx, id)
+	if err != nil {
+		rlog.Error("failed to verify audit log", "error", err, "id", id)
+		return nil, err
+	}
+
+	if !result.Valid {
+		rlog.Warn("audit log verification failed", "bill_id", id, "seq", result.BrokenAtSeq, "problem", result.Problem)
+	}
+
+	return &AuditVerificationResponse{
+		Verification: *result,
+	}, nil
+}
+
+// VerifyAuditLogs verifies the audit log of every bill. Admin only.
+//
+//encore:api auth path=/v1/audit/verify method=GET
+func (s *Service) VerifyAuditLogs(ctx context.Context) (*AuditReportResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	report, err := s.business.VerifyAuditLogs(ctx)
+	if err != nil {
+		rlog.Error("failed to verify audit logs", "error", err)
+		return nil, err
+	}
+
+	for _, failure := range report.Failures {
+		rlog.Warn("audit log verification failed", "bill_id", failure.BillID, "seq", failure.BrokenAtSeq, "problem", failure.Problem)
+	}
+
+	return &AuditReportResponse{
+		Report: *report,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+func TestVerifyAuditLog(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness}
+
+	verification := &model.AuditVerification{BillID: 1, Entries: 3, BrokenAtSeq: 2, Problem: "entry has been modified"}
+	mockBusiness.EXPECT().VerifyAuditLog(gomock.Any(), int32(1)).Return(verification, nil)
+
+	response, err := service.VerifyAuditLog(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, *verification, response.Verification)
+}
+
+func TestVerifyAuditLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness}
+
+	t.Run("admin", func(t *testing.T) {
+		withCaller(t, &AuthData{Admin: true})
+		mockBusiness.EXPECT().VerifyAuditLogs(gomock.Any()).Return(&model.AuditReport{BillsChecked: 4}, nil)
+
+		response, err := service.VerifyAuditLogs(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, response.Report.BillsChecked)
+	})
+
+	t.Run("account_caller_is_rejected", func(t *testing.T) {
+		withCaller(t, &AuthData{AccountID: 7})
+
+		response, err := service.VerifyAuditLogs(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+	})
+}
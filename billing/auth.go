@@ -0,0 +1,134 @@
+package billing
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+var secrets struct {
+	// AdminAPIKey authenticates operators for administrative endpoints
+	AdminAPIKey string
+}
+
+const adminUID auth.UID = "admin"
+
+// AuthData describes the authenticated caller of an endpoint
+type AuthData struct {
+	// AccountID is the account the API key was issued to; zero for admins
+	AccountID int32
+	APIKeyID  int32
+	Admin     bool
+}
+
+// currentCaller is an indirection over auth.Data so tests can supply
+// the authenticated caller without running the Encore runtime.
+var currentCaller = func() *AuthData {
+	data, _ := auth.Data().(*AuthData)
+	return data
+}
+
+// AuthHandler authenticates bearer API keys. The configured admin key grants
+// admin access; any other key must match an active key of an enabled account.
+//
+//encore:authhandler
+func (s *Service) AuthHandler(ctx context.Context, token string) (auth.UID, *AuthData, error) {
+	if secrets.AdminAPIKey != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secrets.AdminAPIKey)) == 1 {
+		return adminUID, &AuthData{Admin: true}, nil
+	}
+
+	key, err := s.apiKeys.Authenticate(ctx, token)
+	if err != nil {
+		rlog.Warn("api key authentication failed", "error", err)
+		return "", nil, err
+	}
+
+	return auth.UID(fmt.Sprintf("account:%d", key.AccountID)), &AuthData{
+		AccountID: key.AccountID,
+		APIKeyID:  key.ID,
+	}, nil
+}
+
+// callerActor names the authenticated caller in the bill history
+func callerActor() string {
+	caller := currentCaller()
+	switch {
+	case caller == nil:
+		return model.ActorSystem
+	case caller.Admin:
+		return string(adminUID)
+	default:
+		return fmt.Sprintf("account:%d", caller.AccountID)
+	}
+}
+
+// requireAdmin rejects callers that did not authenticate with the admin key
+func requireAdmin() error {
+	if caller := currentCaller(); caller == nil || !caller.Admin {
+		return &errs.Error{Code: errs.PermissionDenied, Message: "admin access required"}
+	}
+
+	return nil
+}
+
+// authorizeBill ensures the caller may act on the bill. Admins may act on any
+// bill; other callers only on bills owned by their account.
+func (s *Service) authorizeBill(ctx context.Context, billID int32) error {
+	caller := currentCaller()
+	if caller == nil {
+		return &errs.Error{Code: errs.Unauthenticated, Message: "authentication required"}
+	}
+	if caller.Admin {
+		return nil
+	}
+
+	return s.business.CheckBillOwnership(ctx, billID, caller.AccountID)
+}
+
+// authorizeSubscription fetches a subscription the caller may act on, hiding
+// subscriptions owned by other accounts behind NotFound
+func (s *Service) authorizeSubscription(ctx context.Context, id int32) (*model.Subscription, error) {
+	caller := currentCaller()
+	if caller == nil {
+		return nil, &errs.Error{Code: errs.Unauthenticated, Message: "authentication required"}
+	}
+
+	subscription, err := s.subscriptions.GetSubscription(ctx, id)
+	if err != nil {
+		rlog.Error("failed to get subscription", "error", err, "id", id)
+		return nil, err
+	}
+	if !caller.Admin && subscription.AccountID != caller.AccountID {
+		return nil, &errs.Error{Code: errs.NotFound, Message: "subscription not found"}
+	}
+
+	return subscription, nil
+}
+
+// resolveAccountID returns the account a request acts on. Account callers always
+// act on their own account; admins must name the account explicitly.
+func resolveAccountID(requested int32) (int32, error) {
+	caller := currentCaller()
+	if caller == nil {
+		return 0, &errs.Error{Code: errs.Unauthenticated, Message: "authentication required"}
+	}
+
+	if caller.Admin {
+		if requested <= 0 {
+			return 0, &errs.Error{Code: errs.InvalidArgument, Message: "account_id is required"}
+		}
+		return requested, nil
+	}
+
+	if requested != 0 && requested != caller.AccountID {
+		return 0, &errs.Error{Code: errs.NotFound, Message: "account not found"}
+	}
+
+	return caller.AccountID, nil
+}
@@ -0,0 +1,177 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/apikey_business"
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+// withCaller makes caller the authenticated caller for the duration of the test
+func withCaller(t *testing.T, caller *AuthData) {
+	t.Helper()
+
+	original := currentCaller
+	currentCaller = func() *AuthData { return caller }
+	t.Cleanup(func() { currentCaller = original })
+}
+
+func TestAuthHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeys := apikey_business.NewMockBusiness(ctrl)
+	service := &Service{apiKeys: mockAPIKeys}
+
+	originalAdminKey := secrets.AdminAPIKey
+	secrets.AdminAPIKey = "admin-secret"
+	defer func() { secrets.AdminAPIKey = originalAdminKey }()
+
+	testCases := []struct {
+		name             string
+		token            string
+		mockKey          *model.APIKey
+		mockError        error
+		expectLookupCall bool
+		expectedUID      auth.UID
+		expectedData     *AuthData
+		expectedError    string
+	}{
+		{
+			name:         "admin_key",
+			token:        "admin-secret",
+			expectedUID:  adminUID,
+			expectedData: &AuthData{Admin: true},
+		},
+		{
+			name:             "account_key",
+			token:            "pb_account",
+			mockKey:          &model.APIKey{ID: 3, AccountID: 7},
+			expectLookupCall: true,
+			expectedUID:      "account:7",
+			expectedData:     &AuthData{AccountID: 7, APIKeyID: 3},
+		},
+		{
+			name:             "invalid_key",
+			token:            "pb_unknown",
+			mockError:        &errs.Error{Code: errs.Unauthenticated, Message: "invalid api key"},
+			expectLookupCall: true,
+			expectedError:    "invalid api key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectLookupCall {
+				mockAPIKeys.EXPECT().
+					Authenticate(gomock.Any(), tc.token).
+					Return(tc.mockKey, tc.mockError).
+					Times(1)
+			}
+
+			uid, data, err := service.AuthHandler(context.Background(), tc.token)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, data)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedUID, uid)
+				assert.Equal(t, tc.expectedData, data)
+			}
+		})
+	}
+}
+
+func TestAuthorizeBill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness}
+
+	t.Run("admin_skips_ownership_check", func(t *testing.T) {
+		withCaller(t, &AuthData{Admin: true})
+
+		assert.NoError(t, service.authorizeBill(context.Background(), 1))
+	})
+
+	t.Run("account_owns_bill", func(t *testing.T) {
+		withCaller(t, &AuthData{AccountID: 7})
+		mockBusiness.EXPECT().CheckBillOwnership(gomock.Any(), int32(1), int32(7)).Return(nil)
+
+		assert.NoError(t, service.authorizeBill(context.Background(), 1))
+	})
+
+	t.Run("account_does_not_own_bill", func(t *testing.T) {
+		withCaller(t, &AuthData{AccountID: 7})
+		notFound := &errs.Error{Code: errs.NotFound, Message: "bill not found"}
+		mockBusiness.EXPECT().CheckBillOwnership(gomock.Any(), int32(2), int32(7)).Return(notFound)
+
+		assert.Equal(t, notFound, service.authorizeBill(context.Background(), 2))
+	})
+}
+
+func TestResolveAccountID(t *testing.T) {
+	testCases := []struct {
+		name          string
+		caller        *AuthData
+		requested     int32
+		expected      int32
+		expectedError string
+	}{
+		{
+			name:     "account_defaults_to_own",
+			caller:   &AuthData{AccountID: 7},
+			expected: 7,
+		},
+		{
+			name:      "account_requests_own",
+			caller:    &AuthData{AccountID: 7},
+			requested: 7,
+			expected:  7,
+		},
+		{
+			name:          "account_requests_other",
+			caller:        &AuthData{AccountID: 7},
+			requested:     8,
+			expectedError: "account not found",
+		},
+		{
+			name:      "admin_requests_any",
+			caller:    &AuthData{Admin: true},
+			requested: 8,
+			expected:  8,
+		},
+		{
+			name:          "admin_must_name_account",
+			caller:        &AuthData{Admin: true},
+			expectedError: "account_id is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withCaller(t, tc.caller)
+
+			accountID, err := resolveAccountID(tc.requested)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, accountID)
+			}
+		})
+	}
+}
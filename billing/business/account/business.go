@@ -0,0 +1,25 @@
+package account
+
+import (
+	"context"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/accounts"
+)
+
+type Business interface {
+	CreateAccount(ctx context.Context, account *model.Account) (*model.Account, error)
+	GetAccount(ctx context.Context, id int32) (*model.Account, error)
+	ListAccounts(ctx context.Context, limit, offset int32) ([]*model.Account, int64, error)
+	UpdateAccount(ctx context.Context, id int32, update *model.AccountUpdate) (*model.Account, error)
+}
+
+type business struct {
+	accountRepo accounts.Querier
+}
+
+func NewAccountBusiness(accountRepo accounts.Querier) Business {
+	return &business{
+		accountRepo: accountRepo,
+	}
+}
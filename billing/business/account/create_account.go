@@ -0,0 +1,51 @@
+package account
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/accounts"
+)
+
+// CreateAccount registers a new customer account that can own bills
+func (b *business) CreateAccount(ctx context.Context, account *model.Account) (*model.Account, error) {
+	dbAccount, err := b.accountRepo.CreateAccount(ctx, accounts.CreateAccountParams{
+		Name:  account.Name,
+		Email: optionalText(account.Email),
+	})
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to create account"}
+	}
+
+	return convertDBAccountToModel(dbAccount), nil
+}
+
+// convertDBAccountToModel converts a database Account to a domain model Account
+func convertDBAccountToModel(dbAccount accounts.Account) *model.Account {
+	account := &model.Account{
+		ID:               dbAccount.ID,
+		Name:             dbAccount.Name,
+		Enabled:          dbAccount.Enabled,
+		PaymentTermsDays: dbAccount.PaymentTermsDays,
+		CreatedAt:        dbAccount.CreatedAt.Time,
+		UpdatedAt:        dbAccount.UpdatedAt.Time,
+	}
+
+	if dbAccount.Email.Valid {
+		account.Email = &dbAccount.Email.String
+	}
+
+	return account
+}
+
+// optionalText maps an optional string onto a nullable text column
+func optionalText(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
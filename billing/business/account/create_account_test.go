@@ -0,0 +1,96 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/account_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/accounts"
+)
+
+func TestCreateAccount(t *testing.T) {
+	email := "billing@acme.test"
+
+	testCases := []struct {
+		name           string
+		input          *model.Account
+		expectedParams accounts.CreateAccountParams
+		mockReturn     accounts.Account
+		mockError      error
+		expectedError  string
+		expectSuccess  bool
+	}{
+		{
+			name:  "happy_case_with_email",
+			input: &model.Account{Name: "Acme Corp", Email: &email},
+			expectedParams: accounts.CreateAccountParams{
+				Name:  "Acme Corp",
+				Email: pgtype.Text{String: email, Valid: true},
+			},
+			mockReturn: accounts.Account{
+				ID:      1,
+				Name:    "Acme Corp",
+				Email:   pgtype.Text{String: email, Valid: true},
+				Enabled: true,
+			},
+			expectSuccess: true,
+		},
+		{
+			name:  "happy_case_without_email",
+			input: &model.Account{Name: "Solo Trader"},
+			expectedParams: accounts.CreateAccountParams{
+				Name:  "Solo Trader",
+				Email: pgtype.Text{Valid: false},
+			},
+			mockReturn: accounts.Account{
+				ID:      2,
+				Name:    "Solo Trader",
+				Enabled: true,
+			},
+			expectSuccess: true,
+		},
+		{
+			name:  "database_error",
+			input: &model.Account{Name: "Broken"},
+			expectedParams: accounts.CreateAccountParams{
+				Name:  "Broken",
+				Email: pgtype.Text{Valid: false},
+			},
+			mockError:     errors.New("insert failed"),
+			expectedError: "failed to create account",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAccountRepo := account_repo.NewMockQuerier(ctrl)
+			business := &business{accountRepo: mockAccountRepo}
+
+			mockAccountRepo.EXPECT().
+				CreateAccount(gomock.Any(), tc.expectedParams).
+				Return(tc.mockReturn, tc.mockError)
+
+			result, err := business.CreateAccount(context.Background(), tc.input)
+
+			if tc.expectSuccess {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockReturn.ID, result.ID)
+				assert.Equal(t, tc.mockReturn.Name, result.Name)
+				assert.True(t, result.Enabled)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
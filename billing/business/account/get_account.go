@@ -0,0 +1,25 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+)
+
+// GetAccount retrieves an account by ID
+func (b *business) GetAccount(ctx context.Context, id int32) (*model.Account, error) {
+	dbAccount, err := b.accountRepo.GetAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.NotFound, Message: "account not found"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get account"}
+	}
+
+	return convertDBAccountToModel(dbAccount), nil
+}
@@ -0,0 +1,92 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/account_repo"
+	"encore.app/billing/repository/accounts"
+)
+
+func TestGetAccount(t *testing.T) {
+	testCases := []struct {
+		name          string
+		accountID     int32
+		mockReturn    accounts.Account
+		mockError     error
+		expectedError string
+		expectSuccess bool
+	}{
+		{
+			name:      "happy_case",
+			accountID: 1,
+			mockReturn: accounts.Account{
+				ID:      1,
+				Name:    "Acme Corp",
+				Email:   pgtype.Text{String: "billing@acme.test", Valid: true},
+				Enabled: true,
+			},
+			expectSuccess: true,
+		},
+		{
+			name:      "account_without_email",
+			accountID: 2,
+			mockReturn: accounts.Account{
+				ID:      2,
+				Name:    "No Email Ltd",
+				Enabled: false,
+			},
+			expectSuccess: true,
+		},
+		{
+			name:          "account_not_found",
+			accountID:     404,
+			mockError:     pgx.ErrNoRows,
+			expectedError: "account not found",
+		},
+		{
+			name:          "database_error",
+			accountID:     3,
+			mockError:     errors.New("connection refused"),
+			expectedError: "failed to get account",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAccountRepo := account_repo.NewMockQuerier(ctrl)
+			business := &business{accountRepo: mockAccountRepo}
+
+			mockAccountRepo.EXPECT().
+				GetAccount(gomock.Any(), tc.accountID).
+				Return(tc.mockReturn, tc.mockError)
+
+			result, err := business.GetAccount(context.Background(), tc.accountID)
+
+			if tc.expectSuccess {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockReturn.ID, result.ID)
+				assert.Equal(t, tc.mockReturn.Name, result.Name)
+				assert.Equal(t, tc.mockReturn.Enabled, result.Enabled)
+				if tc.mockReturn.Email.Valid {
+					assert.Equal(t, tc.mockReturn.Email.String, *result.Email)
+				} else {
+					assert.Nil(t, result.Email)
+				}
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package account
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/accounts"
+)
+
+// ListAccounts retrieves accounts with pagination
+func (b *business) ListAccounts(ctx context.Context, limit, offset int32) ([]*model.Account, int64, error) {
+	dbAccounts, err := b.accountRepo.ListAccounts(ctx, accounts.ListAccountsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, &errs.Error{Code: errs.Internal, Message: "failed to list accounts"}
+	}
+
+	totalCount, err := b.accountRepo.CountAccounts(ctx)
+	if err != nil {
+		return nil, 0, &errs.Error{Code: errs.Internal, Message: "failed to count accounts"}
+	}
+
+	accountList := make([]*model.Account, len(dbAccounts))
+	for i, dbAccount := range dbAccounts {
+		accountList[i] = convertDBAccountToModel(dbAccount)
+	}
+
+	return accountList, totalCount, nil
+}
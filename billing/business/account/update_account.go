@@ -0,0 +1,39 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/accounts"
+)
+
+// UpdateAccount changes the name, email, enabled flag or payment terms of an account
+func (b *business) UpdateAccount(ctx context.Context, id int32, update *model.AccountUpdate) (*model.Account, error) {
+	params := accounts.UpdateAccountParams{
+		ID:    id,
+		Name:  optionalText(update.Name),
+		Email: optionalText(update.Email),
+	}
+	if update.Enabled != nil {
+		params.Enabled = pgtype.Bool{Bool: *update.Enabled, Valid: true}
+	}
+	if update.PaymentTermsDays != nil {
+		params.PaymentTermsDays = pgtype.Int4{Int32: *update.PaymentTermsDays, Valid: true}
+	}
+
+	dbAccount, err := b.accountRepo.UpdateAccount(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.NotFound, Message: "account not found"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to update account"}
+	}
+
+	return convertDBAccountToModel(dbAccount), nil
+}
@@ -0,0 +1,91 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/account_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/accounts"
+)
+
+func TestUpdateAccount(t *testing.T) {
+	disabled := false
+	newName := "Acme Holdings"
+
+	testCases := []struct {
+		name           string
+		accountID      int32
+		update         *model.AccountUpdate
+		expectedParams accounts.UpdateAccountParams
+		mockReturn     accounts.Account
+		mockError      error
+		expectedError  string
+		expectSuccess  bool
+	}{
+		{
+			name:      "disable_account",
+			accountID: 1,
+			update:    &model.AccountUpdate{Enabled: &disabled},
+			expectedParams: accounts.UpdateAccountParams{
+				ID:      1,
+				Enabled: pgtype.Bool{Bool: false, Valid: true},
+			},
+			mockReturn:    accounts.Account{ID: 1, Name: "Acme Corp", Enabled: false},
+			expectSuccess: true,
+		},
+		{
+			name:      "rename_account_keeps_enabled_flag",
+			accountID: 2,
+			update:    &model.AccountUpdate{Name: &newName},
+			expectedParams: accounts.UpdateAccountParams{
+				ID:   2,
+				Name: pgtype.Text{String: newName, Valid: true},
+			},
+			mockReturn:    accounts.Account{ID: 2, Name: newName, Enabled: true},
+			expectSuccess: true,
+		},
+		{
+			name:      "account_not_found",
+			accountID: 404,
+			update:    &model.AccountUpdate{Enabled: &disabled},
+			expectedParams: accounts.UpdateAccountParams{
+				ID:      404,
+				Enabled: pgtype.Bool{Bool: false, Valid: true},
+			},
+			mockError:     pgx.ErrNoRows,
+			expectedError: "account not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAccountRepo := account_repo.NewMockQuerier(ctrl)
+			business := &business{accountRepo: mockAccountRepo}
+
+			mockAccountRepo.EXPECT().
+				UpdateAccount(gomock.Any(), tc.expectedParams).
+				Return(tc.mockReturn, tc.mockError)
+
+			result, err := business.UpdateAccount(context.Background(), tc.accountID, tc.update)
+
+			if tc.expectSuccess {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockReturn.Name, result.Name)
+				assert.Equal(t, tc.mockReturn.Enabled, result.Enabled)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
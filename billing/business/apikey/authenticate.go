@@ -0,0 +1,33 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+)
+
+// Authenticate resolves a presented key to its active API key record, rejecting revoked keys and disabled accounts
+func (b *business) Authenticate(ctx context.Context, key string) (*model.APIKey, error) {
+	dbKey, err := b.apiKeyRepo.GetActiveAPIKeyByHash(ctx, hashKey(key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.Unauthenticated, Message: "invalid api key"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to authenticate api key"}
+	}
+
+	account, err := b.accountService.GetAccount(ctx, dbKey.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.Enabled {
+		return nil, &errs.Error{Code: errs.Unauthenticated, Message: "account is disabled"}
+	}
+
+	return convertDBAPIKeyToModel(dbKey), nil
+}
@@ -0,0 +1,85 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/account_business"
+	"encore.app/billing/mocks/repository/apikey_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/apikeys"
+)
+
+func TestAuthenticate(t *testing.T) {
+	const key = "pb_0123456789abcdef"
+
+	testCases := []struct {
+		name              string
+		lookupError       error
+		account           *model.Account
+		expectAccountCall bool
+		expectedError     string
+		expectSuccess     bool
+	}{
+		{
+			name:              "happy_case",
+			account:           &model.Account{ID: 7, Enabled: true},
+			expectAccountCall: true,
+			expectSuccess:     true,
+		},
+		{
+			name:          "unknown_or_revoked_key",
+			lookupError:   pgx.ErrNoRows,
+			expectedError: "invalid api key",
+		},
+		{
+			name:          "database_error",
+			lookupError:   errors.New("connection refused"),
+			expectedError: "failed to authenticate api key",
+		},
+		{
+			name:              "account_disabled",
+			account:           &model.Account{ID: 7, Enabled: false},
+			expectAccountCall: true,
+			expectedError:     "account is disabled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAPIKeyRepo := apikey_repo.NewMockQuerier(ctrl)
+			mockAccountService := account_business.NewMockBusiness(ctrl)
+			business := &business{apiKeyRepo: mockAPIKeyRepo, accountService: mockAccountService}
+
+			mockAPIKeyRepo.EXPECT().
+				GetActiveAPIKeyByHash(gomock.Any(), hashKey(key)).
+				Return(apikeys.ApiKey{ID: 3, AccountID: 7, KeyPrefix: key[:displayPrefixLen]}, tc.lookupError)
+
+			if tc.expectAccountCall {
+				mockAccountService.EXPECT().
+					GetAccount(gomock.Any(), int32(7)).
+					Return(tc.account, nil)
+			}
+
+			result, err := business.Authenticate(context.Background(), key)
+
+			if tc.expectSuccess {
+				assert.NoError(t, err)
+				assert.Equal(t, int32(3), result.ID)
+				assert.Equal(t, int32(7), result.AccountID)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
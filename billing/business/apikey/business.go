@@ -0,0 +1,27 @@
+package apikey
+
+import (
+	"context"
+
+	"encore.app/billing/business/account"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/apikeys"
+)
+
+type Business interface {
+	IssueAPIKey(ctx context.Context, accountID int32, name string) (*model.IssuedAPIKey, error)
+	RevokeAPIKey(ctx context.Context, id int32) (*model.APIKey, error)
+	Authenticate(ctx context.Context, key string) (*model.APIKey, error)
+}
+
+type business struct {
+	apiKeyRepo     apikeys.Querier
+	accountService account.Business
+}
+
+func NewAPIKeyBusiness(apiKeyRepo apikeys.Querier, accountService account.Business) Business {
+	return &business{
+		apiKeyRepo:     apiKeyRepo,
+		accountService: accountService,
+	}
+}
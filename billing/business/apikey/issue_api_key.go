@@ -0,0 +1,84 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/apikeys"
+)
+
+const (
+	keyPrefix      = "pb_"
+	keyRandomBytes = 32
+	// displayPrefixLen is how much of the key is kept in clear text so operators can tell keys apart
+	displayPrefixLen = len(keyPrefix) + 8
+)
+
+// IssueAPIKey generates a new key for an enabled account and stores only its hash
+func (b *business) IssueAPIKey(ctx context.Context, accountID int32, name string) (*model.IssuedAPIKey, error) {
+	account, err := b.accountService.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.Enabled {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "account is disabled"}
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to generate api key"}
+	}
+
+	dbKey, err := b.apiKeyRepo.CreateAPIKey(ctx, apikeys.CreateAPIKeyParams{
+		AccountID: accountID,
+		Name:      name,
+		KeyPrefix: key[:displayPrefixLen],
+		KeyHash:   hashKey(key),
+	})
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to create api key"}
+	}
+
+	return &model.IssuedAPIKey{
+		APIKey: *convertDBAPIKeyToModel(dbKey),
+		Key:    key,
+	}, nil
+}
+
+// generateKey returns a random, prefixed API key
+func generateKey() (string, error) {
+	buf := make([]byte, keyRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashKey derives the lookup hash stored for a key. Keys carry 256 bits of
+// entropy, so an unsalted SHA-256 is sufficient and keeps lookups indexable.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// convertDBAPIKeyToModel converts a database ApiKey to a domain model APIKey
+func convertDBAPIKeyToModel(dbKey apikeys.ApiKey) *model.APIKey {
+	key := &model.APIKey{
+		ID:        dbKey.ID,
+		AccountID: dbKey.AccountID,
+		Name:      dbKey.Name,
+		KeyPrefix: dbKey.KeyPrefix,
+		CreatedAt: dbKey.CreatedAt.Time,
+	}
+
+	if dbKey.RevokedAt.Valid {
+		key.RevokedAt = &dbKey.RevokedAt.Time
+	}
+
+	return key
+}
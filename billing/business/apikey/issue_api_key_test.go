@@ -0,0 +1,112 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/account_business"
+	"encore.app/billing/mocks/repository/apikey_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/apikeys"
+)
+
+func TestIssueAPIKey(t *testing.T) {
+	testCases := []struct {
+		name             string
+		account          *model.Account
+		accountError     error
+		createError      error
+		expectCreateCall bool
+		expectedError    string
+		expectSuccess    bool
+	}{
+		{
+			name:             "happy_case",
+			account:          &model.Account{ID: 1, Name: "Acme Corp", Enabled: true},
+			expectCreateCall: true,
+			expectSuccess:    true,
+		},
+		{
+			name:          "account_not_found",
+			accountError:  &errs.Error{Code: errs.NotFound, Message: "account not found"},
+			expectedError: "account not found",
+		},
+		{
+			name:          "account_disabled",
+			account:       &model.Account{ID: 1, Name: "Acme Corp", Enabled: false},
+			expectedError: "account is disabled",
+		},
+		{
+			name:             "database_error",
+			account:          &model.Account{ID: 1, Name: "Acme Corp", Enabled: true},
+			createError:      errors.New("connection refused"),
+			expectCreateCall: true,
+			expectedError:    "failed to create api key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAPIKeyRepo := apikey_repo.NewMockQuerier(ctrl)
+			mockAccountService := account_business.NewMockBusiness(ctrl)
+			business := &business{apiKeyRepo: mockAPIKeyRepo, accountService: mockAccountService}
+
+			mockAccountService.EXPECT().
+				GetAccount(gomock.Any(), int32(1)).
+				Return(tc.account, tc.accountError)
+
+			var stored apikeys.CreateAPIKeyParams
+			if tc.expectCreateCall {
+				mockAPIKeyRepo.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, arg apikeys.CreateAPIKeyParams) (apikeys.ApiKey, error) {
+						stored = arg
+						return apikeys.ApiKey{
+							ID:        10,
+							AccountID: arg.AccountID,
+							Name:      arg.Name,
+							KeyPrefix: arg.KeyPrefix,
+							KeyHash:   arg.KeyHash,
+						}, tc.createError
+					})
+			}
+
+			result, err := business.IssueAPIKey(context.Background(), 1, "ci")
+
+			if tc.expectSuccess {
+				assert.NoError(t, err)
+				assert.Equal(t, int32(10), result.ID)
+				assert.Equal(t, int32(1), result.AccountID)
+				assert.True(t, strings.HasPrefix(result.Key, keyPrefix))
+				assert.Equal(t, result.Key[:displayPrefixLen], result.KeyPrefix)
+				// Only the hash of the key is persisted
+				assert.Equal(t, hashKey(result.Key), stored.KeyHash)
+				assert.NotContains(t, stored.KeyHash, result.Key)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestGenerateKey_Unique(t *testing.T) {
+	first, err := generateKey()
+	assert.NoError(t, err)
+	second, err := generateKey()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.Len(t, first, len(keyPrefix)+2*keyRandomBytes)
+}
@@ -0,0 +1,25 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+)
+
+// RevokeAPIKey revokes a key so it can no longer authenticate; revoking twice keeps the original timestamp
+func (b *business) RevokeAPIKey(ctx context.Context, id int32) (*model.APIKey, error) {
+	dbKey, err := b.apiKeyRepo.RevokeAPIKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.NotFound, Message: "api key not found"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to revoke api key"}
+	}
+
+	return convertDBAPIKeyToModel(dbKey), nil
+}
@@ -2,9 +2,11 @@ package bill
 
 import (
 	"context"
+
+	"encore.app/billing/model"
 )
 
-// ActivateBill transitions a bill from pending to active status
+// ActivateBill transitions a bill from pending to active status when its billing period begins
 func (b *business) ActivateBill(ctx context.Context, billID int32) error {
-	return b.stateMachine.TransitionToActive(ctx, billID)
+	return b.stateMachine.TransitionToActive(ctx, billID, model.ActorSystem)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"encore.dev/beta/errs"
@@ -11,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	domain "encore.app/billing/domain/bill_state_machine"
 	"encore.app/billing/model"
 	"encore.app/billing/repository/bills"
 	"encore.app/billing/repository/lineitems"
@@ -18,41 +20,20 @@ import (
 
 // AddLineItemToBill adds a line item to a bill with proper row locking to prevent race conditions
 // This method coordinates with CloseBill to ensure atomicity and consistency
-func (b *business) AddLineItemToBill(ctx context.Context, billID int32, lineItem *model.LineItem) (*model.LineItem, error) {
+func (b *business) AddLineItemToBill(ctx context.Context, billID int32, lineItem *model.LineItem, actor string) (*model.LineItem, error) {
 	var result *model.LineItem
 
-	err := b.stateMachine.GetBillWithLock(ctx, billID, func(currentBill bills.Bill) error {
-		if currentBill.Status != string(model.BillStatusActive) {
+	err := b.stateMachine.GetBillWithLock(ctx, billID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if !acceptsLineItems(currentBill.Status) {
 			return &errs.Error{Code: errs.InvalidArgument, Message: "bill is not in active state for adding line items"}
 		}
 
-		conversion, err := b.currencyService.ConvertAmount(ctx, lineItem.Currency, currentBill.Currency, lineItem.AmountCents)
+		params, err := b.buildLineItemParams(ctx, currentBill, lineItem)
 		if err != nil {
 			return err
 		}
 
-		var metadataJSON []byte
-		if conversion.Metadata != nil {
-			metadataJSON, err = json.Marshal(conversion.Metadata)
-			if err != nil {
-				return &errs.Error{Code: errs.Internal, Message: "failed to marshal metadata"}
-			}
-		}
-
-		if lineItem.IncurredAt.IsZero() {
-			lineItem.IncurredAt = time.Now()
-		}
-
-		dbLineItem, err := b.stateMachine.GetTxLineItemRepo().CreateLineItem(ctx, lineitems.CreateLineItemParams{
-			BillID:         pgtype.Int4{Int32: billID, Valid: true},
-			AmountCents:    conversion.ConvertedAmount,
-			Currency:       currentBill.Currency,
-			Description:    pgtype.Text{String: lineItem.Description, Valid: true},
-			IncurredAt:     pgtype.Timestamptz{Time: lineItem.IncurredAt, Valid: true},
-			ReferenceID:    pgtype.Text{String: lineItem.ReferenceID, Valid: true},
-			Metadata:       metadataJSON,
-			IdempotencyKey: lineItem.IdempotencyKey,
-		})
+		dbLineItem, err := tx.LineItems.CreateLineItem(ctx, params)
 		if err != nil {
 			var e *pgconn.PgError
 			if errors.As(err, &e) && e.Code == pgerrcode.UniqueViolation {
@@ -61,9 +42,13 @@ func (b *business) AddLineItemToBill(ctx context.Context, billID int32, lineItem
 			return &errs.Error{Code: errs.Internal, Message: "failed to create line item"}
 		}
 
+		if err := checkBillTotal(ctx, tx, currentBill); err != nil {
+			return err
+		}
+
 		result = convertDBLineItemToModel(dbLineItem)
 		result.SetBillWorkflowID(currentBill.WorkflowID.String)
-		return nil
+		return b.recordLineItemCreated(ctx, tx, result, actor)
 	})
 	if err != nil {
 		return nil, err
@@ -71,3 +56,85 @@ func (b *business) AddLineItemToBill(ctx context.Context, billID int32, lineItem
 
 	return result, nil
 }
+
+// buildLineItemParams validates a line item and converts it into the bill currency
+func (b *business) buildLineItemParams(ctx context.Context, currentBill bills.Bill, lineItem *model.LineItem) (lineitems.CreateLineItemParams, error) {
+	kind := lineItem.Kind
+	if kind == "" {
+		kind = model.LineItemKindCharge
+	}
+
+	// A flat amount is a single unit of that amount
+	unitAmountCents := lineItem.UnitAmountCents
+	if unitAmountCents == 0 {
+		if lineItem.Quantity != "" {
+			return lineitems.CreateLineItemParams{}, &errs.Error{Code: errs.InvalidArgument, Message: "unit_amount_cents is required with quantity"}
+		}
+		unitAmountCents = lineItem.AmountCents
+	}
+	if err := validateLineItemAmount(kind, unitAmountCents); err != nil {
+		return lineitems.CreateLineItemParams{}, err
+	}
+	quantity, err := parseQuantity(lineItem.Quantity)
+	if err != nil {
+		return lineitems.CreateLineItemParams{}, err
+	}
+
+	// A bill in its grace period is past its end, so only items that say when they were incurred
+	// can still belong to the period; stamping them now would let the skew below admit them
+	if lineItem.IncurredAt.IsZero() {
+		if currentBill.Status == string(model.BillStatusClosingGrace) {
+			return lineitems.CreateLineItemParams{}, &errs.Error{Code: errs.InvalidArgument, Message: "incurred_at is required while the bill is in its grace period"}
+		}
+		lineItem.IncurredAt = time.Now()
+	} else if err := checkIncurredAt(currentBill, lineItem.IncurredAt); err != nil {
+		return lineitems.CreateLineItemParams{}, err
+	}
+
+	// The unit price is converted at the rates of when it was incurred, so the stored breakdown
+	// multiplies out in the bill currency
+	conversion, err := b.currencyService.ConvertAmount(ctx, lineItem.Currency, currentBill.Currency, kind.SignedAmount(unitAmountCents), lineItem.IncurredAt)
+	if err != nil {
+		return lineitems.CreateLineItemParams{}, err
+	}
+	amountCents, err := extendedAmount(conversion.ConvertedAmount, quantity)
+	if err != nil {
+		return lineitems.CreateLineItemParams{}, err
+	}
+
+	var metadataJSON []byte
+	if conversion.Metadata != nil {
+		metadataJSON, err = json.Marshal(conversion.Metadata)
+		if err != nil {
+			return lineitems.CreateLineItemParams{}, &errs.Error{Code: errs.Internal, Message: "failed to marshal metadata"}
+		}
+	}
+
+	return lineitems.CreateLineItemParams{
+		BillID:          pgtype.Int4{Int32: currentBill.ID, Valid: true},
+		AmountCents:     amountCents,
+		Currency:        currentBill.Currency,
+		Description:     pgtype.Text{String: lineItem.Description, Valid: true},
+		IncurredAt:      pgtype.Timestamptz{Time: lineItem.IncurredAt, Valid: true},
+		ReferenceID:     pgtype.Text{String: lineItem.ReferenceID, Valid: true},
+		Metadata:        metadataJSON,
+		IdempotencyKey:  lineItem.IdempotencyKey,
+		Kind:            string(kind),
+		Quantity:        quantityToNumeric(quantity),
+		UnitAmountCents: conversion.ConvertedAmount,
+	}, nil
+}
+
+// incurredAtSkew widens the billing period on both ends when checking incurred_at, allowing for
+// clock differences between the service and the systems reporting fees
+const incurredAtSkew = 5 * time.Minute
+
+// checkIncurredAt rejects a line item that was not incurred within the billing period
+func checkIncurredAt(currentBill bills.Bill, incurredAt time.Time) error {
+	start, end := currentBill.StartTime.Time, currentBill.EndTime.Time
+	if incurredAt.Before(start.Add(-incurredAtSkew)) || !incurredAt.Before(end.Add(incurredAtSkew)) {
+		return &errs.Error{Code: errs.InvalidArgument, Message: fmt.Sprintf("incurred_at must fall inside the billing period (%s to %s)",
+			start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))}
+	}
+	return nil
+}
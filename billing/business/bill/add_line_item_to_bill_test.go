@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	domain "encore.app/billing/domain/bill_state_machine"
 	"encore.app/billing/mocks/business/currency_business"
 	"encore.app/billing/mocks/domain/state_machine"
 	"encore.app/billing/mocks/repository/lineitem_repo"
@@ -35,6 +37,8 @@ func TestAddLineItemToBill(t *testing.T) {
 		mockConversionErr error
 		mockCreateReturn  lineitems.LineItem
 		mockCreateError   error
+		mockTotal         int64
+		allowNegative     bool
 		expectedError     string
 		expectSuccess     bool
 	}{
@@ -110,6 +114,93 @@ func TestAddLineItemToBill(t *testing.T) {
 			expectedError:     "line item already exists",
 			expectSuccess:     false,
 		},
+		{
+			name:   "quantity_extends_converted_unit_price",
+			billID: 1,
+			lineItem: &model.LineItem{
+				UnitAmountCents: 250,
+				Quantity:        "37",
+				Currency:        "GEL",
+				Description:     "Wire transfers",
+				IdempotencyKey:  "quantity-key",
+			},
+			mockBillStatus: string(model.BillStatusActive),
+			mockConversion: &model.ConversionResult{
+				ConvertedAmount: 94, // the unit price in USD cents
+			},
+			mockCreateReturn: lineitems.LineItem{
+				ID:              5,
+				BillID:          pgtype.Int4{Int32: 1, Valid: true},
+				AmountCents:     3478,
+				UnitAmountCents: 94,
+				Currency:        "USD",
+				IdempotencyKey:  "quantity-key",
+			},
+			mockTotal:     3478,
+			expectSuccess: true,
+		},
+		{
+			name:   "credit_is_converted_as_negative_amount",
+			billID: 1,
+			lineItem: &model.LineItem{
+				Kind:           model.LineItemKindCredit,
+				AmountCents:    1000,
+				Currency:       "GEL",
+				Description:    "Goodwill credit",
+				IdempotencyKey: "credit-key",
+			},
+			mockBillStatus: string(model.BillStatusActive),
+			mockConversion: &model.ConversionResult{
+				ConvertedAmount: -377,
+			},
+			mockCreateReturn: lineitems.LineItem{
+				ID:             2,
+				BillID:         pgtype.Int4{Int32: 1, Valid: true},
+				Kind:           string(model.LineItemKindCredit),
+				AmountCents:    -377,
+				Currency:       "USD",
+				IdempotencyKey: "credit-key",
+			},
+			mockTotal:     623,
+			expectSuccess: true,
+		},
+		{
+			name:   "credit_cannot_take_total_negative",
+			billID: 1,
+			lineItem: &model.LineItem{
+				Kind:           model.LineItemKindCredit,
+				AmountCents:    1000,
+				Currency:       "USD",
+				Description:    "Refund larger than the bill",
+				IdempotencyKey: "credit-key-negative",
+			},
+			mockBillStatus: string(model.BillStatusActive),
+			mockConversion: &model.ConversionResult{
+				ConvertedAmount: -1000,
+			},
+			mockCreateReturn: lineitems.LineItem{ID: 3, AmountCents: -1000, Currency: "USD"},
+			mockTotal:        -500,
+			expectedError:    "bill total cannot go negative",
+			expectSuccess:    false,
+		},
+		{
+			name:   "negative_adjustment_on_bill_allowing_negative_total",
+			billID: 1,
+			lineItem: &model.LineItem{
+				Kind:           model.LineItemKindAdjustment,
+				AmountCents:    -1000,
+				Currency:       "USD",
+				Description:    "Correction",
+				IdempotencyKey: "adjustment-key",
+			},
+			mockBillStatus: string(model.BillStatusActive),
+			mockConversion: &model.ConversionResult{
+				ConvertedAmount: -1000,
+			},
+			mockCreateReturn: lineitems.LineItem{ID: 4, Kind: string(model.LineItemKindAdjustment), AmountCents: -1000, Currency: "USD"},
+			allowNegative:    true,
+			expectSuccess:    true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -121,34 +212,51 @@ func TestAddLineItemToBill(t *testing.T) {
 
 			mockStateMachine.EXPECT().
 				GetBillWithLock(gomock.Any(), tc.billID, gomock.Any()).
-				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(bills.Bill) error) error {
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
 					mockBill := bills.Bill{
 						ID:         tc.billID,
 						Status:     tc.mockBillStatus,
 						Currency:   "USD",
 						WorkflowID: pgtype.Text{String: "workflow-123", Valid: true},
+
+						AllowNegativeTotal: tc.allowNegative,
 					}
 
-					return businessLogic(mockBill)
+					return businessLogic(&domain.TxScope{LineItems: mockLineItemRepo}, mockBill)
 				})
 
 			if tc.expectSuccess || tc.mockBillStatus == string(model.BillStatusActive) {
 				mockCurrencyService.EXPECT().
-					ConvertAmount(gomock.Any(), tc.lineItem.Currency, "USD", tc.lineItem.AmountCents).
+					ConvertAmount(gomock.Any(), tc.lineItem.Currency, "USD", tc.lineItem.Kind.SignedAmount(tc.lineItem.AmountCents+tc.lineItem.UnitAmountCents), gomock.Any()).
 					Return(tc.mockConversion, tc.mockConversionErr)
 
 				if tc.mockConversionErr == nil {
-					mockStateMachine.EXPECT().
-						GetTxLineItemRepo().
-						Return(mockLineItemRepo)
-
 					mockLineItemRepo.EXPECT().
 						CreateLineItem(gomock.Any(), gomock.Any()).
-						Return(tc.mockCreateReturn, tc.mockCreateError)
+						DoAndReturn(func(_ context.Context, params lineitems.CreateLineItemParams) (lineitems.LineItem, error) {
+							// The stored extended amount is the converted unit price times the quantity
+							assert.Equal(t, tc.mockConversion.ConvertedAmount, params.UnitAmountCents)
+							if tc.mockCreateError == nil {
+								assert.Equal(t, tc.mockCreateReturn.AmountCents, params.AmountCents)
+							}
+							return tc.mockCreateReturn, tc.mockCreateError
+						})
+
+					if tc.mockCreateError == nil && !tc.allowNegative {
+						mockLineItemRepo.EXPECT().
+							GetTotalAmountByBill(gomock.Any(), pgtype.Int4{Int32: tc.billID, Valid: true}).
+							Return(tc.mockTotal, nil)
+					}
 				}
 			}
 
-			result, err := business.AddLineItemToBill(context.Background(), tc.billID, tc.lineItem)
+			if tc.expectSuccess {
+				mockStateMachine.EXPECT().
+					RecordAuditTx(gomock.Any(), gomock.Any(), gomock.Any(), model.AuditActionLineItemCreated, "admin", gomock.Any()).
+					Return(nil)
+			}
+
+			result, err := business.AddLineItemToBill(context.Background(), tc.billID, tc.lineItem, "admin")
 
 			if tc.expectSuccess {
 				assert.NoError(t, err)
@@ -168,3 +276,52 @@ func TestAddLineItemToBill(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckIncurredAt(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	bill := bills.Bill{
+		StartTime: pgtype.Timestamptz{Time: start, Valid: true},
+		EndTime:   pgtype.Timestamptz{Time: end, Valid: true},
+	}
+
+	assert.NoError(t, checkIncurredAt(bill, start))
+	assert.NoError(t, checkIncurredAt(bill, end.Add(-time.Second)))
+	// Clock skew is tolerated on both ends of the period
+	assert.NoError(t, checkIncurredAt(bill, start.Add(-time.Minute)))
+	assert.NoError(t, checkIncurredAt(bill, end.Add(time.Minute)))
+
+	err := checkIncurredAt(bill, end.Add(24*time.Hour))
+	assert.Equal(t, "incurred_at must fall inside the billing period (2025-03-01T00:00:00Z to 2025-04-01T00:00:00Z)", errorMessage(err))
+	assert.Error(t, checkIncurredAt(bill, start.Add(-time.Hour)))
+}
+
+func TestBuildLineItemParams_GraceRequiresIncurredAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+	business := &business{currencyService: mockCurrencyService}
+
+	// The period ended a minute ago, well within the skew allowed on incurred_at
+	end := time.Now().Add(-time.Minute)
+	bill := bills.Bill{
+		ID:        1,
+		Status:    string(model.BillStatusClosingGrace),
+		Currency:  "USD",
+		StartTime: pgtype.Timestamptz{Time: end.AddDate(0, -1, 0), Valid: true},
+		EndTime:   pgtype.Timestamptz{Time: end, Valid: true},
+	}
+
+	_, err := business.buildLineItemParams(context.Background(), bill, &model.LineItem{Currency: "USD", AmountCents: 100})
+	assert.Equal(t, "incurred_at is required while the bill is in its grace period", errorMessage(err))
+
+	incurredAt := end.Add(-time.Hour)
+	mockCurrencyService.EXPECT().
+		ConvertAmount(gomock.Any(), "USD", "USD", int64(100), incurredAt).
+		Return(&model.ConversionResult{ConvertedAmount: 100}, nil)
+
+	params, err := business.buildLineItemParams(context.Background(), bill, &model.LineItem{Currency: "USD", AmountCents: 100, IncurredAt: incurredAt})
+	assert.NoError(t, err)
+	assert.Equal(t, incurredAt, params.IncurredAt.Time)
+}
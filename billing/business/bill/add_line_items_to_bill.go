@@ -0,0 +1,180 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+// MaxLineItemBatchSize bounds the number of items, and so the conversions done under a single
+// bill row lock, of one batch
+const MaxLineItemBatchSize = 5000
+
+// AddLineItemsToBill adds a batch of line items to a bill under a single row lock and transaction.
+// Items whose idempotency key already exists on the bill are reported as duplicates rather than
+// added again. In atomic mode any invalid item fails the whole batch; in partial mode invalid
+// items are reported as failed and the rest are added. A batch that would take the bill total
+// below zero is rejected as a whole in either mode.
+func (b *business) AddLineItemsToBill(ctx context.Context, billID int32, items []*model.LineItem, mode model.LineItemBatchMode, actor string) ([]model.LineItemBatchResult, error) {
+	results := make([]model.LineItemBatchResult, len(items))
+
+	err := b.stateMachine.GetBillWithLock(ctx, billID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if !acceptsLineItems(currentBill.Status) {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "bill is not in active state for adding line items"}
+		}
+
+		keys := make([]string, len(items))
+		for i, item := range items {
+			keys[i] = item.IdempotencyKey
+		}
+		existing, err := tx.LineItems.GetLineItemsByIdempotencyKeys(ctx, keys)
+		if err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to look up line items"}
+		}
+		existingByKey := make(map[string]lineitems.LineItem, len(existing))
+		for _, dbLineItem := range existing {
+			existingByKey[dbLineItem.IdempotencyKey] = dbLineItem
+		}
+
+		// Validate and convert every item before inserting anything
+		params := make([]*lineitems.CreateLineItemParams, len(items))
+		seen := make(map[string]bool, len(items))
+		for i, item := range items {
+			results[i].Index = i
+
+			if seen[item.IdempotencyKey] {
+				err = &errs.Error{Code: errs.InvalidArgument, Message: "idempotency key is repeated in the batch"}
+			} else if dbLineItem, ok := existingByKey[item.IdempotencyKey]; ok {
+				if dbLineItem.BillID.Int32 != billID {
+					err = &errs.Error{Code: errs.AlreadyExists, Message: "idempotency key is already used by another bill"}
+				} else {
+					results[i].Status = model.LineItemBatchStatusDuplicate
+					results[i].LineItem = convertDBLineItemToModel(dbLineItem)
+					continue
+				}
+			} else {
+				seen[item.IdempotencyKey] = true
+				var itemParams lineitems.CreateLineItemParams
+				if itemParams, err = b.buildLineItemParams(ctx, currentBill, item); err == nil {
+					params[i] = &itemParams
+					continue
+				}
+			}
+
+			if mode == model.LineItemBatchModeAtomic {
+				return batchItemError(i, err)
+			}
+			results[i].Status = model.LineItemBatchStatusFailed
+			results[i].Error = errorMessage(err)
+		}
+
+		if err := b.insertBatch(ctx, tx, currentBill, params, results, actor); err != nil {
+			return err
+		}
+
+		return checkBillTotal(ctx, tx, currentBill)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// insertBatch adds the line items whose params are set and fills in their results. The items are
+// copied in at once, read back by idempotency key and audited in a single write, so the time the
+// bill stays locked barely grows with the size of the batch.
+func (b *business) insertBatch(ctx context.Context, tx *domain.TxScope, currentBill bills.Bill, params []*lineitems.CreateLineItemParams, results []model.LineItemBatchResult, actor string) error {
+	rows := make([]lineitems.CreateLineItemsParams, 0, len(params))
+	createdKeys := make([]string, 0, len(params))
+	for _, itemParams := range params {
+		if itemParams == nil {
+			continue
+		}
+		rows = append(rows, copyLineItemParams(itemParams))
+		createdKeys = append(createdKeys, itemParams.IdempotencyKey)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if _, err := tx.LineItems.CreateLineItems(ctx, rows); err != nil {
+		var e *pgconn.PgError
+		if errors.As(err, &e) && e.Code == pgerrcode.UniqueViolation {
+			return &errs.Error{Code: errs.AlreadyExists, Message: "line item already exists"}
+		}
+		return &errs.Error{Code: errs.Internal, Message: "failed to create line items"}
+	}
+
+	created, err := tx.LineItems.GetLineItemsByIdempotencyKeys(ctx, createdKeys)
+	if err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to look up line items"}
+	}
+	createdByKey := make(map[string]lineitems.LineItem, len(created))
+	for _, dbLineItem := range created {
+		createdByKey[dbLineItem.IdempotencyKey] = dbLineItem
+	}
+
+	records := make([]domain.AuditRecord, 0, 2*len(rows))
+	for i, itemParams := range params {
+		if itemParams == nil {
+			continue
+		}
+		dbLineItem, ok := createdByKey[itemParams.IdempotencyKey]
+		if !ok {
+			return &errs.Error{Code: errs.Internal, Message: "failed to look up line items"}
+		}
+
+		results[i].Status = model.LineItemBatchStatusCreated
+		results[i].LineItem = convertDBLineItemToModel(dbLineItem)
+		results[i].LineItem.SetBillWorkflowID(currentBill.WorkflowID.String)
+		records = append(records, lineItemCreatedAudit(results[i].LineItem)...)
+	}
+
+	return b.recordAuditBatch(ctx, tx, currentBill.ID, actor, records)
+}
+
+// copyLineItemParams turns the parameters of a single line item into a row of a bulk insert
+func copyLineItemParams(params *lineitems.CreateLineItemParams) lineitems.CreateLineItemsParams {
+	return lineitems.CreateLineItemsParams{
+		BillID:          params.BillID,
+		AmountCents:     params.AmountCents,
+		Currency:        params.Currency,
+		Description:     params.Description,
+		IncurredAt:      params.IncurredAt,
+		ReferenceID:     params.ReferenceID,
+		Metadata:        params.Metadata,
+		IdempotencyKey:  params.IdempotencyKey,
+		Kind:            params.Kind,
+		Quantity:        params.Quantity,
+		UnitAmountCents: params.UnitAmountCents,
+	}
+}
+
+// batchItemError prefixes an item error with its position in the batch
+func batchItemError(index int, err error) error {
+	code := errs.Internal
+	var e *errs.Error
+	if errors.As(err, &e) {
+		code = e.Code
+	}
+	return &errs.Error{Code: code, Message: fmt.Sprintf("item %d: %s", index, errorMessage(err))}
+}
+
+// errorMessage returns the client-facing message of an error
+func errorMessage(err error) string {
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return e.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,163 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/mocks/repository/lineitem_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+func TestAddLineItemsToBill(t *testing.T) {
+	conversionErr := &errs.Error{Code: errs.InvalidArgument, Message: "currency not supported"}
+
+	batch := func() []*model.LineItem {
+		return []*model.LineItem{
+			{Currency: "USD", AmountCents: 100, Description: "fee", IdempotencyKey: "k-new"},
+			{Currency: "XXX", AmountCents: 200, Description: "fee", IdempotencyKey: "k-bad"},
+			{Currency: "USD", AmountCents: 300, Description: "fee", IdempotencyKey: "k-dup"},
+		}
+	}
+	existing := []lineitems.LineItem{
+		{ID: 9, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 300, UnitAmountCents: 300, Currency: "USD", IdempotencyKey: "k-dup"},
+	}
+
+	setup := func(t *testing.T) (*business, *state_machine.MockStateMachine, *currency_business.MockBusiness, *lineitem_repo.MockQuerier) {
+		ctrl := gomock.NewController(t)
+		mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+		mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+		mockLineItemRepo := lineitem_repo.NewMockQuerier(ctrl)
+
+		mockStateMachine.EXPECT().
+			GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+				return businessLogic(&domain.TxScope{LineItems: mockLineItemRepo}, bills.Bill{
+					ID:         1,
+					Status:     string(model.BillStatusActive),
+					Currency:   "USD",
+					WorkflowID: pgtype.Text{String: "workflow-123", Valid: true},
+				})
+			})
+		mockLineItemRepo.EXPECT().
+			GetLineItemsByIdempotencyKeys(gomock.Any(), []string{"k-new", "k-bad", "k-dup"}).
+			Return(existing, nil)
+		mockCurrencyService.EXPECT().
+			ConvertAmount(gomock.Any(), "USD", "USD", int64(100), gomock.Any()).
+			Return(&model.ConversionResult{ConvertedAmount: 100}, nil)
+		mockCurrencyService.EXPECT().
+			ConvertAmount(gomock.Any(), "XXX", "USD", int64(200), gomock.Any()).
+			Return(nil, conversionErr)
+
+		return &business{stateMachine: mockStateMachine, currencyService: mockCurrencyService}, mockStateMachine, mockCurrencyService, mockLineItemRepo
+	}
+
+	t.Run("partial_mode_reports_each_item", func(t *testing.T) {
+		business, mockStateMachine, _, mockLineItemRepo := setup(t)
+
+		mockLineItemRepo.EXPECT().
+			CreateLineItems(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, rows []lineitems.CreateLineItemsParams) (int64, error) {
+				require.Len(t, rows, 1)
+				assert.Equal(t, "k-new", rows[0].IdempotencyKey)
+				return 1, nil
+			})
+		mockLineItemRepo.EXPECT().
+			GetLineItemsByIdempotencyKeys(gomock.Any(), []string{"k-new"}).
+			Return([]lineitems.LineItem{{ID: 10, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 100, IdempotencyKey: "k-new"}}, nil)
+		mockLineItemRepo.EXPECT().GetTotalAmountByBill(gomock.Any(), gomock.Any()).Return(int64(400), nil)
+		// Only the created item is audited
+		mockStateMachine.EXPECT().
+			RecordAuditBatchTx(gomock.Any(), gomock.Any(), int32(1), "admin", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *domain.TxScope, _ int32, _ string, records []domain.AuditRecord) error {
+				require.Len(t, records, 1)
+				assert.Equal(t, model.AuditActionLineItemCreated, records[0].Action)
+				assert.Equal(t, "k-new", records[0].Payload.(*model.LineItem).IdempotencyKey)
+				return nil
+			})
+
+		results, err := business.AddLineItemsToBill(context.Background(), 1, batch(), model.LineItemBatchModePartial, "admin")
+
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, model.LineItemBatchStatusCreated, results[0].Status)
+		assert.Equal(t, int32(10), results[0].LineItem.ID)
+		assert.Equal(t, "workflow-123", results[0].LineItem.BillWorkflowID)
+		assert.Equal(t, model.LineItemBatchStatusFailed, results[1].Status)
+		assert.Equal(t, "currency not supported", results[1].Error)
+		assert.Equal(t, model.LineItemBatchStatusDuplicate, results[2].Status)
+		assert.Equal(t, int32(9), results[2].LineItem.ID)
+		for i, result := range results {
+			assert.Equal(t, i, result.Index)
+		}
+	})
+
+	t.Run("atomic_mode_fails_whole_batch", func(t *testing.T) {
+		business, _, _, _ := setup(t)
+
+		results, err := business.AddLineItemsToBill(context.Background(), 1, batch(), model.LineItemBatchModeAtomic, "admin")
+
+		assert.Nil(t, results)
+		var e *errs.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, errs.InvalidArgument, e.Code)
+		assert.Equal(t, "item 1: currency not supported", e.Message)
+	})
+
+	t.Run("max_batch_size_is_written_at_once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+		mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+		mockLineItemRepo := lineitem_repo.NewMockQuerier(ctrl)
+		business := &business{stateMachine: mockStateMachine, currencyService: mockCurrencyService}
+
+		items := make([]*model.LineItem, MaxLineItemBatchSize)
+		created := make([]lineitems.LineItem, MaxLineItemBatchSize)
+		for i := range items {
+			key := fmt.Sprintf("k-%d", i)
+			items[i] = &model.LineItem{Currency: "EUR", AmountCents: 100, Description: "fee", IdempotencyKey: key}
+			created[i] = lineitems.LineItem{ID: int32(i + 1), BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 110, IdempotencyKey: key, Metadata: []byte(`{"original_currency":"EUR"}`)}
+		}
+
+		mockStateMachine.EXPECT().
+			GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+				return businessLogic(&domain.TxScope{LineItems: mockLineItemRepo}, bills.Bill{ID: 1, Status: string(model.BillStatusActive), Currency: "USD"})
+			})
+		mockLineItemRepo.EXPECT().GetLineItemsByIdempotencyKeys(gomock.Any(), gomock.Len(MaxLineItemBatchSize)).Return(nil, nil)
+		mockCurrencyService.EXPECT().
+			ConvertAmount(gomock.Any(), "EUR", "USD", int64(100), gomock.Any()).
+			Return(&model.ConversionResult{ConvertedAmount: 110, Metadata: &model.CurrencyMetadata{OriginalCurrency: "EUR"}}, nil).
+			Times(MaxLineItemBatchSize)
+		// One bulk insert, one read back and one audit write, whatever the size of the batch
+		mockLineItemRepo.EXPECT().
+			CreateLineItems(gomock.Any(), gomock.Len(MaxLineItemBatchSize)).
+			Return(int64(MaxLineItemBatchSize), nil)
+		mockLineItemRepo.EXPECT().GetLineItemsByIdempotencyKeys(gomock.Any(), gomock.Len(MaxLineItemBatchSize)).Return(created, nil)
+		mockStateMachine.EXPECT().
+			RecordAuditBatchTx(gomock.Any(), gomock.Any(), int32(1), "admin", gomock.Len(2*MaxLineItemBatchSize)).
+			Return(nil)
+		mockLineItemRepo.EXPECT().GetTotalAmountByBill(gomock.Any(), gomock.Any()).Return(int64(110*MaxLineItemBatchSize), nil)
+
+		results, err := business.AddLineItemsToBill(context.Background(), 1, items, model.LineItemBatchModeAtomic, "admin")
+
+		require.NoError(t, err)
+		require.Len(t, results, MaxLineItemBatchSize)
+		for i, result := range results {
+			assert.Equal(t, model.LineItemBatchStatusCreated, result.Status)
+			assert.Equal(t, int32(i+1), result.LineItem.ID)
+		}
+	})
+}
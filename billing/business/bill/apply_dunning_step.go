@@ -0,0 +1,118 @@
+package bill
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+
+	"encore.dev/beta/errs"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+// dunningStepAudit is the audit payload of a dunning step
+type dunningStepAudit struct {
+	Step         int                 `json:"step"`
+	Action       model.DunningAction `json:"action"`
+	LateFeeCents int64               `json:"late_fee_cents,omitempty"`
+	BalanceCents int64               `json:"balance_cents"`
+}
+
+// ApplyDunningStep applies one step of a dunning schedule to a closed bill with a balance left.
+// A late fee is added as a charge line item and to the bill total. The returned notice is nil
+// when the bill no longer needs dunning, e.g. because it was paid in the meantime.
+func (b *business) ApplyDunningStep(ctx context.Context, billID int32, stepIndex int, step model.DunningStep) (*model.DunningNotice, error) {
+	var notice *model.DunningNotice
+
+	err := b.stateMachine.GetBillWithLock(ctx, billID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		balance := currentBill.TotalAmountCents.Int64 - currentBill.AmountPaidCents
+		if currentBill.Status != string(model.BillStatusClosed) || balance <= 0 {
+			return nil
+		}
+
+		var lateFee int64
+		if step.Action == model.DunningActionLateFee {
+			lateFee = step.LateFee(balance)
+			if lateFee <= 0 {
+				return &errs.Error{Code: errs.InvalidArgument, Message: "late fee must be positive"}
+			}
+
+			added, err := b.addLateFee(ctx, tx, currentBill, stepIndex, lateFee)
+			if err != nil {
+				return err
+			}
+			if added {
+				balance += lateFee
+			}
+		}
+
+		if err := b.recordAudit(ctx, tx, currentBill.ID, model.AuditActionDunningStep, model.ActorSystem, dunningStepAudit{
+			Step:         stepIndex,
+			Action:       step.Action,
+			LateFeeCents: lateFee,
+			BalanceCents: balance,
+		}); err != nil {
+			return err
+		}
+
+		notice = &model.DunningNotice{
+			BillID:       currentBill.ID,
+			AccountID:    currentBill.AccountID.Int32,
+			Currency:     currentBill.Currency,
+			Step:         stepIndex,
+			Action:       step.Action,
+			LateFeeCents: lateFee,
+			BalanceCents: balance,
+			DueAt:        currentBill.DueAt.Time,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notice, nil
+}
+
+// addLateFee adds the late fee of a dunning step as a line item and to the bill total. A step
+// retried after its fee was committed finds the line item by its idempotency key and adds nothing.
+func (b *business) addLateFee(ctx context.Context, tx *domain.TxScope, currentBill bills.Bill, stepIndex int, feeCents int64) (bool, error) {
+	idempotencyKey := fmt.Sprintf("late-fee-%d-%d", currentBill.ID, stepIndex)
+	existing, err := tx.LineItems.GetLineItemsByIdempotencyKeys(ctx, []string{idempotencyKey})
+	if err != nil {
+		return false, &errs.Error{Code: errs.Internal, Message: "failed to check existing late fee"}
+	}
+	if len(existing) > 0 {
+		return false, nil
+	}
+
+	dbLineItem, err := tx.LineItems.CreateLineItem(ctx, lineitems.CreateLineItemParams{
+		BillID:          pgtype.Int4{Int32: currentBill.ID, Valid: true},
+		AmountCents:     feeCents,
+		Currency:        currentBill.Currency,
+		Description:     pgtype.Text{String: "Late fee", Valid: true},
+		IncurredAt:      pgtype.Timestamptz{Time: currentBill.DueAt.Time, Valid: true},
+		ReferenceID:     pgtype.Text{String: fmt.Sprintf("dunning-%d", stepIndex), Valid: true},
+		IdempotencyKey:  idempotencyKey,
+		Kind:            string(model.LineItemKindCharge),
+		Quantity:        quantityToNumeric(decimal.NewFromInt(1)),
+		UnitAmountCents: feeCents,
+	})
+	if err != nil {
+		return false, &errs.Error{Code: errs.Internal, Message: "failed to create late fee"}
+	}
+
+	if err := b.recordLineItemCreated(ctx, tx, convertDBLineItemToModel(dbLineItem), model.ActorSystem); err != nil {
+		return false, err
+	}
+	if err := b.stateMachine.AddToBillTotalTx(ctx, tx, currentBill.ID, feeCents, model.ActorSystem); err != nil {
+		return false, &errs.Error{Code: errs.Internal, Message: "failed to add late fee to bill total"}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,140 @@
+package bill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/mocks/repository/lineitem_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+func TestApplyDunningStep(t *testing.T) {
+	dueAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	reminder := model.DunningStep{After: 24 * time.Hour, Action: model.DunningActionReminder}
+	lateFee := model.DunningStep{After: 14 * 24 * time.Hour, Action: model.DunningActionLateFee, LateFeeCents: 100, LateFeeBasisPoints: 200}
+
+	unpaidBill := func(status model.BillStatus, paid int64) bills.Bill {
+		return bills.Bill{
+			ID:               1,
+			AccountID:        pgtype.Int4{Int32: 7, Valid: true},
+			Currency:         "USD",
+			Status:           string(status),
+			TotalAmountCents: pgtype.Int8{Int64: 5000, Valid: true},
+			AmountPaidCents:  paid,
+			DueAt:            pgtype.Timestamptz{Time: dueAt, Valid: true},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		bill           bills.Bill
+		step           model.DunningStep
+		existingFee    bool
+		expectFee      int64
+		expectedNotice *model.DunningNotice
+		expectedError  string
+	}{
+		{
+			name: "reminder",
+			bill: unpaidBill(model.BillStatusClosed, 1000),
+			step: reminder,
+			expectedNotice: &model.DunningNotice{
+				BillID: 1, AccountID: 7, Currency: "USD", Step: 2, Action: model.DunningActionReminder, BalanceCents: 4000, DueAt: dueAt,
+			},
+		},
+		{
+			name:      "late_fee_is_added_to_the_bill",
+			bill:      unpaidBill(model.BillStatusClosed, 1000),
+			step:      lateFee,
+			expectFee: 180,
+			expectedNotice: &model.DunningNotice{
+				BillID: 1, AccountID: 7, Currency: "USD", Step: 2, Action: model.DunningActionLateFee, LateFeeCents: 180, BalanceCents: 4180, DueAt: dueAt,
+			},
+		},
+		{
+			name:        "retried_late_fee_is_not_charged_twice",
+			bill:        unpaidBill(model.BillStatusClosed, 1000),
+			step:        lateFee,
+			existingFee: true,
+			expectedNotice: &model.DunningNotice{
+				BillID: 1, AccountID: 7, Currency: "USD", Step: 2, Action: model.DunningActionLateFee, LateFeeCents: 180, BalanceCents: 4000, DueAt: dueAt,
+			},
+		},
+		{
+			name:          "late_fee_must_be_positive",
+			bill:          unpaidBill(model.BillStatusClosed, 1000),
+			step:          model.DunningStep{Action: model.DunningActionLateFee},
+			expectedError: "late fee must be positive",
+		},
+		{
+			name: "paid_bill_needs_no_dunning",
+			bill: unpaidBill(model.BillStatusClosed, 5000),
+			step: reminder,
+		},
+		{
+			name: "cancelled_bill_needs_no_dunning",
+			bill: unpaidBill(model.BillStatusCancelled, 0),
+			step: lateFee,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			mockLineItemRepo := lineitem_repo.NewMockQuerier(ctrl)
+			business := &business{stateMachine: mockStateMachine}
+			txScope := &domain.TxScope{LineItems: mockLineItemRepo}
+
+			mockStateMachine.EXPECT().
+				GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+					return businessLogic(txScope, tc.bill)
+				})
+
+			if tc.step.Action == model.DunningActionLateFee && tc.expectedNotice != nil {
+				var existing []lineitems.LineItem
+				if tc.existingFee {
+					existing = []lineitems.LineItem{{ID: 40, IdempotencyKey: "late-fee-1-2"}}
+				}
+				mockLineItemRepo.EXPECT().GetLineItemsByIdempotencyKeys(gomock.Any(), []string{"late-fee-1-2"}).Return(existing, nil)
+			}
+			if tc.expectFee > 0 {
+				mockLineItemRepo.EXPECT().CreateLineItem(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params lineitems.CreateLineItemParams) (lineitems.LineItem, error) {
+						assert.Equal(t, tc.expectFee, params.AmountCents)
+						assert.Equal(t, string(model.LineItemKindCharge), params.Kind)
+						assert.Equal(t, dueAt, params.IncurredAt.Time)
+						return lineitems.LineItem{ID: 41, BillID: params.BillID, AmountCents: params.AmountCents, Currency: params.Currency, Kind: params.Kind}, nil
+					})
+				mockStateMachine.EXPECT().RecordAuditTx(gomock.Any(), txScope, int32(1), model.AuditActionLineItemCreated, model.ActorSystem, gomock.Any()).Return(nil)
+				mockStateMachine.EXPECT().AddToBillTotalTx(gomock.Any(), txScope, int32(1), tc.expectFee, model.ActorSystem).Return(nil)
+			}
+			if tc.expectedNotice != nil {
+				mockStateMachine.EXPECT().RecordAuditTx(gomock.Any(), txScope, int32(1), model.AuditActionDunningStep, model.ActorSystem, gomock.Any()).Return(nil)
+			}
+
+			notice, err := business.ApplyDunningStep(context.Background(), 1, 2, tc.step)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedError, errorMessage(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedNotice, notice)
+		})
+	}
+}
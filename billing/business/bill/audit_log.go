@@ -0,0 +1,198 @@
+package bill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/auditlog"
+	"encore.app/billing/repository/bills"
+)
+
+// auditVerifyBatchSize is how many bills VerifyAuditLogs loads per query
+const auditVerifyBatchSize = 500
+
+// conversionAudit is the audit payload of a line item converted into the bill currency
+type conversionAudit struct {
+	LineItemID          int32   `json:"line_item_id"`
+	OriginalAmountCents int64   `json:"original_amount_cents"`
+	OriginalCurrency    string  `json:"original_currency"`
+	ExchangeRate        float64 `json:"exchange_rate"`
+	FromRateID          int32   `json:"from_rate_id,omitempty"`
+	ToRateID            int32   `json:"to_rate_id,omitempty"`
+	UnitAmountCents     int64   `json:"unit_amount_cents"`
+	Currency            string  `json:"currency"`
+}
+
+// lineItemChange is the audit payload of an edited line item
+type lineItemChange struct {
+	Before *model.LineItem `json:"before"`
+	After  *model.LineItem `json:"after"`
+}
+
+// recordAudit appends an entry to the audit chain of a bill within the transaction
+func (b *business) recordAudit(ctx context.Context, tx *domain.TxScope, billID int32, action model.AuditAction, actor string, payload any) error {
+	if err := b.stateMachine.RecordAuditTx(ctx, tx, billID, action, actor, payload); err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to record audit entry"}
+	}
+	return nil
+}
+
+// recordLineItemCreated audits a new line item and the conversion of its amount
+func (b *business) recordLineItemCreated(ctx context.Context, tx *domain.TxScope, lineItem *model.LineItem, actor string) error {
+	if err := b.recordAudit(ctx, tx, lineItem.BillID, model.AuditActionLineItemCreated, actor, lineItem); err != nil {
+		return err
+	}
+	return b.recordConversion(ctx, tx, lineItem, actor)
+}
+
+// recordConversion audits the exchange rate applied to a line item, if it was converted
+func (b *business) recordConversion(ctx context.Context, tx *domain.TxScope, lineItem *model.LineItem, actor string) error {
+	if lineItem.Metadata == nil {
+		return nil
+	}
+
+	return b.recordAudit(ctx, tx, lineItem.BillID, model.AuditActionCurrencyConverted, actor, newConversionAudit(lineItem))
+}
+
+// recordAuditBatch appends several entries to the audit chain of a bill within the transaction
+func (b *business) recordAuditBatch(ctx context.Context, tx *domain.TxScope, billID int32, actor string, records []domain.AuditRecord) error {
+	if err := b.stateMachine.RecordAuditBatchTx(ctx, tx, billID, actor, records); err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to record audit entries"}
+	}
+	return nil
+}
+
+// lineItemCreatedAudit returns the audit entries of a new line item and the conversion of its amount
+func lineItemCreatedAudit(lineItem *model.LineItem) []domain.AuditRecord {
+	records := []domain.AuditRecord{{Action: model.AuditActionLineItemCreated, Payload: lineItem}}
+	if lineItem.Metadata != nil {
+		records = append(records, domain.AuditRecord{Action: model.AuditActionCurrencyConverted, Payload: newConversionAudit(lineItem)})
+	}
+	return records
+}
+
+// newConversionAudit builds the audit payload of a converted line item
+func newConversionAudit(lineItem *model.LineItem) conversionAudit {
+	return conversionAudit{
+		LineItemID:          lineItem.ID,
+		OriginalAmountCents: lineItem.Metadata.OriginalAmountCents,
+		OriginalCurrency:    lineItem.Metadata.OriginalCurrency,
+		ExchangeRate:        lineItem.Metadata.ExchangeRate,
+		FromRateID:          lineItem.Metadata.FromRateID,
+		ToRateID:            lineItem.Metadata.ToRateID,
+		UnitAmountCents:     lineItem.UnitAmountCents,
+		Currency:            lineItem.Currency,
+	}
+}
+
+// GetAuditLog returns the audit chain of a bill, oldest entry first
+func (b *business) GetAuditLog(ctx context.Context, billID int32) ([]model.AuditEntry, error) {
+	if _, err := b.getBillForAudit(ctx, billID); err != nil {
+		return nil, err
+	}
+
+	dbEntries, err := b.auditRepo.ListAuditEntries(ctx, billID)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get audit log"}
+	}
+
+	entries := make([]model.AuditEntry, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		entries[i] = convertDBAuditEntryToModel(dbEntry)
+	}
+
+	return entries, nil
+}
+
+// VerifyAuditLog rehashes the audit chain of a bill and reports the first broken entry
+func (b *business) VerifyAuditLog(ctx context.Context, billID int32) (*model.AuditVerification, error) {
+	dbBill, err := b.getBillForAudit(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.verifyAuditChain(ctx, dbBill)
+}
+
+// VerifyAuditLogs verifies the audit chain of every bill and returns the ones that fail
+func (b *business) VerifyAuditLogs(ctx context.Context) (*model.AuditReport, error) {
+	report := &model.AuditReport{Failures: []model.AuditVerification{}}
+
+	afterID := int32(0)
+	for {
+		ids, err := b.billRepo.ListBillIDsAfter(ctx, bills.ListBillIDsAfterParams{ID: afterID, Limit: auditVerifyBatchSize})
+		if err != nil {
+			return nil, &errs.Error{Code: errs.Internal, Message: "failed to list bills"}
+		}
+
+		for _, id := range ids {
+			dbBill, err := b.getBillForAudit(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			result, err := b.verifyAuditChain(ctx, dbBill)
+			if err != nil {
+				return nil, err
+			}
+
+			report.BillsChecked++
+			if !result.Valid {
+				report.Failures = append(report.Failures, *result)
+			}
+		}
+
+		if len(ids) < auditVerifyBatchSize {
+			return report, nil
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
+
+func (b *business) getBillForAudit(ctx context.Context, billID int32) (bills.Bill, error) {
+	dbBill, err := b.billRepo.GetBill(ctx, billID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return bills.Bill{}, &errs.Error{Code: errs.NotFound, Message: "bill not found"}
+		}
+		return bills.Bill{}, &errs.Error{Code: errs.Internal, Message: "failed to get bill"}
+	}
+	return dbBill, nil
+}
+
+func (b *business) verifyAuditChain(ctx context.Context, dbBill bills.Bill) (*model.AuditVerification, error) {
+	dbEntries, err := b.auditRepo.ListAuditEntries(ctx, dbBill.ID)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get audit log"}
+	}
+
+	result := domain.VerifyAuditChain(dbBill.ID, dbEntries, dbBill.AuditHeadHash.String)
+	return &result, nil
+}
+
+// convertDBAuditEntryToModel converts database AuditEntry to domain model AuditEntry
+func convertDBAuditEntryToModel(dbEntry auditlog.AuditEntry) model.AuditEntry {
+	payload := json.RawMessage(dbEntry.Payload)
+	if !json.Valid(payload) {
+		// A tampered payload is still shown, as a string, so it can be inspected
+		payload, _ = json.Marshal(dbEntry.Payload)
+	}
+
+	return model.AuditEntry{
+		ID:        dbEntry.ID,
+		BillID:    dbEntry.BillID,
+		Seq:       dbEntry.Seq,
+		Action:    model.AuditAction(dbEntry.Action),
+		Actor:     dbEntry.Actor,
+		Payload:   payload,
+		PrevHash:  dbEntry.PrevHash,
+		Hash:      dbEntry.Hash,
+		CreatedAt: dbEntry.CreatedAt.Time,
+	}
+}
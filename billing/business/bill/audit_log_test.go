@@ -0,0 +1,123 @@
+package bill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/auditlog_repo"
+	"encore.app/billing/mocks/repository/bill_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/auditlog"
+	"encore.app/billing/repository/bills"
+)
+
+func TestGetAuditLog(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("returns_entries_in_order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+		mockAuditRepo := auditlog_repo.NewMockQuerier(ctrl)
+		business := &business{billRepo: mockBillRepo, auditRepo: mockAuditRepo}
+
+		mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(1)).Return(bills.Bill{ID: 1}, nil)
+		mockAuditRepo.EXPECT().ListAuditEntries(gomock.Any(), int32(1)).Return([]auditlog.AuditEntry{
+			{
+				ID:        1,
+				BillID:    1,
+				Seq:       1,
+				Action:    string(model.AuditActionBillCreated),
+				Actor:     "admin",
+				Payload:   `{"id":1}`,
+				Hash:      "aa",
+				CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+			},
+			{
+				ID:        2,
+				BillID:    1,
+				Seq:       2,
+				Action:    string(model.AuditActionLineItemCreated),
+				Actor:     "account:7",
+				Payload:   `not json`,
+				PrevHash:  "aa",
+				Hash:      "bb",
+				CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+			},
+		}, nil)
+
+		entries, err := business.GetAuditLog(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, model.AuditActionBillCreated, entries[0].Action)
+		assert.Equal(t, json.RawMessage(`{"id":1}`), entries[0].Payload)
+		assert.Equal(t, "aa", entries[1].PrevHash)
+		// A payload that is not JSON is shown as a string
+		assert.Equal(t, json.RawMessage(`"not json"`), entries[1].Payload)
+	})
+
+	t.Run("bill_not_found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+		business := &business{billRepo: mockBillRepo, auditRepo: auditlog_repo.NewMockQuerier(ctrl)}
+
+		mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(404)).Return(bills.Bill{}, pgx.ErrNoRows)
+
+		entries, err := business.GetAuditLog(context.Background(), 404)
+
+		assert.Nil(t, entries)
+		var e *errs.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, errs.NotFound, e.Code)
+	})
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+	mockAuditRepo := auditlog_repo.NewMockQuerier(ctrl)
+	business := &business{billRepo: mockBillRepo, auditRepo: mockAuditRepo}
+
+	// The bill points at a head entry that is no longer in the log
+	mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(1)).
+		Return(bills.Bill{ID: 1, AuditHeadHash: pgtype.Text{String: "aa", Valid: true}}, nil)
+	mockAuditRepo.EXPECT().ListAuditEntries(gomock.Any(), int32(1)).Return(nil, nil)
+
+	result, err := business.VerifyAuditLog(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int32(1), result.BrokenAtSeq)
+}
+
+func TestVerifyAuditLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+	mockAuditRepo := auditlog_repo.NewMockQuerier(ctrl)
+	business := &business{billRepo: mockBillRepo, auditRepo: mockAuditRepo}
+
+	mockBillRepo.EXPECT().
+		ListBillIDsAfter(gomock.Any(), bills.ListBillIDsAfterParams{ID: 0, Limit: auditVerifyBatchSize}).
+		Return([]int32{1, 2}, nil)
+	mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(1)).Return(bills.Bill{ID: 1}, nil)
+	mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(2)).
+		Return(bills.Bill{ID: 2, AuditHeadHash: pgtype.Text{String: "aa", Valid: true}}, nil)
+	mockAuditRepo.EXPECT().ListAuditEntries(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	report, err := business.VerifyAuditLogs(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.BillsChecked)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, int32(2), report.Failures[0].BillID)
+}
@@ -2,45 +2,82 @@ package bill
 
 import (
 	"context"
+	"time"
 
+	"encore.app/billing/business/account"
 	"encore.app/billing/business/currency"
 	domain "encore.app/billing/domain/bill_state_machine"
 	"encore.app/billing/model"
+	"encore.app/billing/repository/auditlog"
+	"encore.app/billing/repository/billevents"
 	"encore.app/billing/repository/bills"
 	"encore.app/billing/repository/lineitems"
+	"encore.app/billing/repository/payments"
 )
 
 type Business interface {
-	CreateBill(ctx context.Context, bill *model.Bill) (*model.Bill, error)
-	GetBill(ctx context.Context, id int32) (*model.Bill, error)
-	ListBills(ctx context.Context, limit, offset int32) ([]*model.Bill, int64, error)
+	CreateBill(ctx context.Context, bill *model.Bill, actor string) (*model.Bill, error)
+	GetBill(ctx context.Context, id int32, opts model.GetBillOptions) (*model.Bill, error)
+	GetBillByIdempotencyKey(ctx context.Context, idempotencyKey string) (*model.Bill, error)
+	CheckBillOwnership(ctx context.Context, billID, accountID int32) error
+	ListBills(ctx context.Context, accountID int32, filter model.BillFilter) (*model.BillPage, error)
 	ActivateBill(ctx context.Context, billID int32) error
-	CloseBill(ctx context.Context, id int32, reason string) error
-	UpdateBillTotal(ctx context.Context, billID int32) error
+	StartClosingGrace(ctx context.Context, id int32, actor string) error
+	CloseBill(ctx context.Context, id int32, reason, actor string) error
+	CancelBill(ctx context.Context, id int32, reason, actor string) error
+	UpdateBillPeriod(ctx context.Context, id int32, endTime time.Time, reason, actor string) error
+	RecoverBill(ctx context.Context, id int32, recovery model.BillRecovery, actor string) error
+	GetBillHistory(ctx context.Context, billID int32) ([]model.BillEvent, error)
+	UpdateBillTotal(ctx context.Context, billID int32, actor string) error
+	MarkOverdueBills(ctx context.Context, now time.Time, notify func(context.Context, model.Bill) error) (int, error)
+	ApplyDunningStep(ctx context.Context, billID int32, stepIndex int, step model.DunningStep) (*model.DunningNotice, error)
 
-	AddLineItemToBill(ctx context.Context, billID int32, lineItem *model.LineItem) (*model.LineItem, error)
+	GetAuditLog(ctx context.Context, billID int32) ([]model.AuditEntry, error)
+	VerifyAuditLog(ctx context.Context, billID int32) (*model.AuditVerification, error)
+	VerifyAuditLogs(ctx context.Context) (*model.AuditReport, error)
+
+	AddLineItemToBill(ctx context.Context, billID int32, lineItem *model.LineItem, actor string) (*model.LineItem, error)
+	AddLineItemsToBill(ctx context.Context, billID int32, items []*model.LineItem, mode model.LineItemBatchMode, actor string) ([]model.LineItemBatchResult, error)
 	GetLineItemsByBill(ctx context.Context, billID int32) ([]model.LineItem, error)
+	ListLineItems(ctx context.Context, billID int32, filter model.LineItemFilter) (*model.LineItemPage, error)
+	UpdateLineItem(ctx context.Context, billID, lineItemID int32, update *model.LineItemUpdate, actor string) (*model.LineItem, error)
+	VoidLineItem(ctx context.Context, billID, lineItemID int32, reason, actor string) (*model.LineItem, error)
+
+	RecordPayment(ctx context.Context, billID int32, payment *model.Payment, actor string) (*model.Payment, error)
+	ListPayments(ctx context.Context, billID int32) ([]model.Payment, error)
 }
 
 // BillBusiness handles business logic for bills and line items
 type business struct {
 	billRepo        bills.Querier
 	lineItemRepo    lineitems.Querier
+	eventRepo       billevents.Querier
+	auditRepo       auditlog.Querier
+	paymentRepo     payments.Querier
 	stateMachine    domain.StateMachine
 	currencyService currency.Business
+	accountService  account.Business
 }
 
 // NewBillBusiness creates a new unified bill business layer
 func NewBillBusiness(
 	billRepo bills.Querier,
 	lineItemRepo lineitems.Querier,
+	eventRepo billevents.Querier,
+	auditRepo auditlog.Querier,
+	paymentRepo payments.Querier,
 	stateMachine domain.StateMachine,
 	currencyService currency.Business,
+	accountService account.Business,
 ) Business {
 	return &business{
 		billRepo:        billRepo,
 		lineItemRepo:    lineItemRepo,
+		eventRepo:       eventRepo,
+		auditRepo:       auditRepo,
+		paymentRepo:     paymentRepo,
 		currencyService: currencyService,
 		stateMachine:    stateMachine,
+		accountService:  accountService,
 	}
 }
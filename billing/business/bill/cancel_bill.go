@@ -0,0 +1,29 @@
+package bill
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+// CancelBill cancels a pending or active bill. A cancelled bill accepts no line item changes
+// and owes nothing; its line items and total are kept as they were.
+func (b *business) CancelBill(ctx context.Context, id int32, reason, actor string) error {
+	return b.stateMachine.GetBillWithLock(ctx, id, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		switch currentBill.Status {
+		case string(model.BillStatusCancelled):
+			// Bill is already cancelled - idempotent operation
+			return nil
+
+		case string(model.BillStatusPending), string(model.BillStatusActive):
+			return b.stateMachine.TransitionToCancelledTx(ctx, tx, id, reason, actor)
+
+		default:
+			return &errs.Error{Code: errs.FailedPrecondition, Message: "only pending or active bills can be cancelled"}
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package bill
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+func TestCancelBill(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        model.BillStatus
+		expectCancel  bool
+		expectedError string
+	}{
+		{name: "pending_bill", status: model.BillStatusPending, expectCancel: true},
+		{name: "active_bill", status: model.BillStatusActive, expectCancel: true},
+		{name: "already_cancelled_bill_idempotent", status: model.BillStatusCancelled},
+		{name: "closed_bill", status: model.BillStatusClosed, expectedError: "only pending or active bills can be cancelled"},
+		{name: "attention_required_bill", status: model.BillStatusAttentionRequired, expectedError: "only pending or active bills can be cancelled"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			business := &business{stateMachine: mockStateMachine}
+			txScope := &domain.TxScope{}
+
+			mockStateMachine.EXPECT().
+				GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+					return businessLogic(txScope, bills.Bill{ID: billID, Status: string(tc.status)})
+				})
+			if tc.expectCancel {
+				mockStateMachine.EXPECT().
+					TransitionToCancelledTx(gomock.Any(), txScope, int32(1), "created by mistake", "admin").
+					Return(nil)
+			}
+
+			err := business.CancelBill(context.Background(), 1, "created by mistake", "admin")
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tc.expectedError, errorMessage(err))
+			}
+		})
+	}
+}
+
+func TestConvertDBBillToModel_CancelledBillKeepsTotal(t *testing.T) {
+	bill := convertDBBillToModel(bills.Bill{
+		Status:           string(model.BillStatusCancelled),
+		TotalAmountCents: pgtype.Int8{Int64: 1000, Valid: true},
+	})
+
+	assert.Equal(t, int64(1000), bill.TotalAmountCents)
+	assert.Equal(t, int64(0), bill.BalanceCents)
+}
@@ -0,0 +1,28 @@
+package bill
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+)
+
+// CheckBillOwnership verifies that a bill belongs to the given account. Bills owned
+// by other accounts are reported as not found so their existence is not disclosed.
+func (b *business) CheckBillOwnership(ctx context.Context, billID, accountID int32) error {
+	dbBill, err := b.billRepo.GetBill(ctx, billID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &errs.Error{Code: errs.NotFound, Message: "bill not found"}
+		}
+		return &errs.Error{Code: errs.Internal, Message: "failed to get bill"}
+	}
+
+	if !dbBill.AccountID.Valid || dbBill.AccountID.Int32 != accountID {
+		return &errs.Error{Code: errs.NotFound, Message: "bill not found"}
+	}
+
+	return nil
+}
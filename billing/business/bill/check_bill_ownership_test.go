@@ -0,0 +1,78 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/bill_repo"
+	"encore.app/billing/repository/bills"
+)
+
+func TestCheckBillOwnership(t *testing.T) {
+	testCases := []struct {
+		name          string
+		accountID     int32
+		mockBill      bills.Bill
+		mockError     error
+		expectedError string
+	}{
+		{
+			name:      "owned_by_caller",
+			accountID: 7,
+			mockBill:  bills.Bill{ID: 1, AccountID: pgtype.Int4{Int32: 7, Valid: true}},
+		},
+		{
+			name:          "owned_by_other_account",
+			accountID:     7,
+			mockBill:      bills.Bill{ID: 1, AccountID: pgtype.Int4{Int32: 8, Valid: true}},
+			expectedError: "bill not found",
+		},
+		{
+			name:          "bill_without_owner",
+			accountID:     7,
+			mockBill:      bills.Bill{ID: 1},
+			expectedError: "bill not found",
+		},
+		{
+			name:          "bill_not_found",
+			accountID:     7,
+			mockError:     pgx.ErrNoRows,
+			expectedError: "bill not found",
+		},
+		{
+			name:          "database_error",
+			accountID:     7,
+			mockError:     errors.New("connection refused"),
+			expectedError: "failed to get bill",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+			business := &business{billRepo: mockBillRepo}
+
+			mockBillRepo.EXPECT().
+				GetBill(gomock.Any(), int32(1)).
+				Return(tc.mockBill, tc.mockError)
+
+			err := business.CheckBillOwnership(context.Background(), 1, tc.accountID)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
@@ -5,47 +5,67 @@ import (
 
 	"encore.dev/beta/errs"
 
+	domain "encore.app/billing/domain/bill_state_machine"
 	"encore.app/billing/model"
 	"encore.app/billing/repository/bills"
 )
 
-// Close handles closing a bill with proper locking, state transitions, and error handling
-func (b *business) CloseBill(ctx context.Context, id int32, reason string) error {
-	return b.stateMachine.GetBillWithLock(ctx, id, func(currentBill bills.Bill) error {
+// Close handles closing a bill with proper locking, state transitions, and error handling.
+// The actor is recorded in the bill history for every transition made.
+func (b *business) CloseBill(ctx context.Context, id int32, reason, actor string) error {
+	var finalizeErr error
+	err := b.stateMachine.GetBillWithLock(ctx, id, func(tx *domain.TxScope, currentBill bills.Bill) error {
 		switch currentBill.Status {
 		case string(model.BillStatusClosed):
 			// Bill is already closed - idempotent operation
 			return nil
 
 		case string(model.BillStatusPending):
-			return b.stateMachine.TransitionToClosedTx(ctx, id, reason)
+			return b.stateMachine.TransitionToClosedTx(ctx, tx, id, reason, actor)
 
-		case string(model.BillStatusActive):
-			// Full closing process for active bills
+		case string(model.BillStatusActive), string(model.BillStatusClosingGrace):
+			// Full closing process for active bills and bills whose grace window has ended
 			// Step 1: Set to closing state
-			err := b.stateMachine.TransitionToClosingTx(ctx, id, reason)
+			err := b.stateMachine.TransitionToClosingTx(ctx, tx, id, reason, actor)
 			if err != nil {
 				return err
 			}
 
 			// Step 2: Recalculate final bill total within the same transaction
 			// In reality, this would also involve finalizing many other aspects
-			err = b.stateMachine.UpdateBillTotalTx(ctx, id)
+			err = b.stateMachine.UpdateBillTotalTx(ctx, tx, id, actor)
 			if err != nil {
-				// Set error status
-				errorMsg := "failed to calculate final bill total: " + err.Error()
-				failureErr := b.stateMachine.TransitionToFailureStateTx(ctx, id, errorMsg)
-				if failureErr != nil {
-					return failureErr // Return failure transition error if it fails
-				}
-				return &errs.Error{Code: errs.Internal, Message: errorMsg} // Return original error
+				// Roll back the partial close; the failure is recorded below
+				finalizeErr = err
+				return err
 			}
 
 			// Step 3: Set final status to closed
-			return b.stateMachine.TransitionToClosedTx(ctx, id, reason)
+			return b.stateMachine.TransitionToClosedTx(ctx, tx, id, reason, actor)
 
 		default:
 			return &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill status for closure"}
 		}
 	})
+	if finalizeErr != nil {
+		return b.markAttentionRequired(ctx, id, "failed to calculate final bill total: "+errorMessage(finalizeErr), actor)
+	}
+	return err
+}
+
+// markAttentionRequired moves a bill whose finalization failed to attention_required in its own
+// transaction, since the transaction of the failed close has been rolled back. It returns the
+// finalization error so the caller still sees the close fail.
+func (b *business) markAttentionRequired(ctx context.Context, id int32, errorMsg, actor string) error {
+	err := b.stateMachine.GetBillWithLock(ctx, id, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if currentBill.Status != string(model.BillStatusActive) && currentBill.Status != string(model.BillStatusClosingGrace) {
+			// Another request moved the bill on in the meantime
+			return nil
+		}
+		return b.stateMachine.TransitionToFailureStateTx(ctx, tx, id, errorMsg, actor)
+	})
+	if err != nil {
+		return err
+	}
+	return &errs.Error{Code: errs.Internal, Message: errorMsg}
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	domain "encore.app/billing/domain/bill_state_machine"
 	"encore.app/billing/mocks/domain/state_machine"
 	"encore.app/billing/model"
 	"encore.app/billing/repository/bills"
@@ -52,6 +53,16 @@ func TestCloseBill(t *testing.T) {
 			expectUpdateBillTotal:        true,
 			expectTransitionToClosed:     true,
 		},
+		{
+			name:                      "closing_grace_bill_full_process_success",
+			billID:                    11,
+			reason:                    "auto_close",
+			mockBillStatus:            string(model.BillStatusClosingGrace),
+			expectSuccess:             true,
+			expectTransitionToClosing: true,
+			expectUpdateBillTotal:     true,
+			expectTransitionToClosed:  true,
+		},
 		{
 			name:                         "active_bill_transition_to_closing_fails",
 			billID:                       3,
@@ -147,47 +158,54 @@ func TestCloseBill(t *testing.T) {
 
 			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
 			business := &business{stateMachine: mockStateMachine}
+			txScope := &domain.TxScope{}
 
-			// Mock GetBillWithLock to simulate bill status and execute business logic
+			// Mock GetBillWithLock to simulate bill status and execute business logic.
+			// A failed finalization is rolled back and recorded under a second lock.
+			lockCalls := 1
+			if tc.expectFailureTransition {
+				lockCalls = 2
+			}
 			mockStateMachine.EXPECT().
 				GetBillWithLock(gomock.Any(), tc.billID, gomock.Any()).
-				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(bills.Bill) error) error {
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
 					// Simulate the bill with test case status
 					mockBill := bills.Bill{
 						ID:     tc.billID,
 						Status: tc.mockBillStatus,
 					}
-					return businessLogic(mockBill)
-				})
+					return businessLogic(txScope, mockBill)
+				}).
+				Times(lockCalls)
 
 			// Setup expectations based on test case flow
 			if tc.expectTransitionToClosing {
 				mockStateMachine.EXPECT().
-					TransitionToClosingTx(gomock.Any(), tc.billID, tc.reason).
+					TransitionToClosingTx(gomock.Any(), txScope, tc.billID, tc.reason, "admin").
 					Return(tc.mockTransitionToClosingError)
 			}
 
 			if tc.expectUpdateBillTotal {
 				mockStateMachine.EXPECT().
-					UpdateBillTotalTx(gomock.Any(), tc.billID).
+					UpdateBillTotalTx(gomock.Any(), txScope, tc.billID, "admin").
 					Return(tc.mockUpdateBillTotalError)
 			}
 
 			if tc.expectFailureTransition {
 				expectedErrorMsg := "failed to calculate final bill total: " + tc.mockUpdateBillTotalError.Error()
 				mockStateMachine.EXPECT().
-					TransitionToFailureStateTx(gomock.Any(), tc.billID, expectedErrorMsg).
+					TransitionToFailureStateTx(gomock.Any(), txScope, tc.billID, expectedErrorMsg, "admin").
 					Return(tc.mockFailureTransitionError)
 			}
 
 			if tc.expectTransitionToClosed {
 				mockStateMachine.EXPECT().
-					TransitionToClosedTx(gomock.Any(), tc.billID, tc.reason).
+					TransitionToClosedTx(gomock.Any(), txScope, tc.billID, tc.reason, "admin").
 					Return(tc.mockTransitionToClosedError)
 			}
 
 			// Execute the test
-			err := business.CloseBill(context.Background(), tc.billID, tc.reason)
+			err := business.CloseBill(context.Background(), tc.billID, tc.reason, "admin")
 
 			// Assertions
 			if tc.expectSuccess {
@@ -195,9 +213,9 @@ func TestCloseBill(t *testing.T) {
 			} else {
 				assert.Error(t, err)
 				if tc.expectedError != "" {
-					assert.Contains(t, err.Error(), tc.expectedError)
+					assert.Contains(t, errorMessage(err), tc.expectedError)
 				}
 			}
 		})
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,36 @@
+package bill
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+// reasonGracePeriod is the close reason recorded when a bill enters its grace window
+const reasonGracePeriod = "grace_period"
+
+// StartClosingGrace moves an active bill whose period has ended into closing_grace, where it
+// still accepts line items incurred during the period until the workflow closes it
+func (b *business) StartClosingGrace(ctx context.Context, id int32, actor string) error {
+	return b.stateMachine.GetBillWithLock(ctx, id, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		switch currentBill.Status {
+		case string(model.BillStatusClosingGrace):
+			return nil
+
+		case string(model.BillStatusActive):
+			return b.stateMachine.TransitionToClosingGraceTx(ctx, tx, id, reasonGracePeriod, actor)
+
+		default:
+			return &errs.Error{Code: errs.FailedPrecondition, Message: "only active bills can enter the grace period"}
+		}
+	})
+}
+
+// acceptsLineItems reports whether line items may be added to a bill in the given status
+func acceptsLineItems(status string) bool {
+	return status == string(model.BillStatusActive) || status == string(model.BillStatusClosingGrace)
+}
@@ -0,0 +1,57 @@
+package bill
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+func TestStartClosingGrace(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        model.BillStatus
+		expectGrace   bool
+		expectedError string
+	}{
+		{name: "active_bill", status: model.BillStatusActive, expectGrace: true},
+		{name: "already_in_grace_idempotent", status: model.BillStatusClosingGrace},
+		{name: "closed_bill", status: model.BillStatusClosed, expectedError: "only active bills can enter the grace period"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			business := &business{stateMachine: mockStateMachine}
+			txScope := &domain.TxScope{}
+
+			mockStateMachine.EXPECT().
+				GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+					return businessLogic(txScope, bills.Bill{ID: billID, Status: string(tc.status)})
+				})
+			if tc.expectGrace {
+				mockStateMachine.EXPECT().
+					TransitionToClosingGraceTx(gomock.Any(), txScope, int32(1), "grace_period", model.ActorSystem).
+					Return(nil)
+			}
+
+			err := business.StartClosingGrace(context.Background(), 1, model.ActorSystem)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tc.expectedError, errorMessage(err))
+			}
+		})
+	}
+}
@@ -4,19 +4,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"encore.dev/beta/errs"
 
+	domain "encore.app/billing/domain/bill_state_machine"
 	"encore.app/billing/model"
 	"encore.app/billing/repository/bills"
 )
 
-// CreateBill handles the business logic for creating a new bill with explicit idempotency
-func (b *business) CreateBill(ctx context.Context, bill *model.Bill) (*model.Bill, error) {
+// CreateBill handles the business logic for creating a new bill with explicit idempotency.
+// The bill and the first entry of its audit chain are written in one transaction.
+func (b *business) CreateBill(ctx context.Context, bill *model.Bill, actor string) (*model.Bill, error) {
+	account, err := b.accountService.GetAccount(ctx, bill.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.Enabled {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "account is disabled"}
+	}
+
 	currency, err := b.currencyService.GetCurrency(ctx, bill.Currency)
 	if err != nil {
 		return nil, err
@@ -25,40 +37,109 @@ func (b *business) CreateBill(ctx context.Context, bill *model.Bill) (*model.Bil
 		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "currency is not enabled"}
 	}
 
+	timezone := bill.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid timezone"}
+	}
+
+	startTime, endTime := bill.StartTime, bill.EndTime
+	period := pgtype.Text{Valid: false}
+	if bill.Period != nil {
+		// The start time picks which period is billed; the period itself defines both boundaries
+		startTime, endTime, err = resolvePeriod(*bill.Period, bill.StartTime, loc)
+		if err != nil {
+			return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid period"}
+		}
+		if !endTime.After(time.Now()) {
+			return nil, &errs.Error{Code: errs.InvalidArgument, Message: "period has already ended"}
+		}
+		period = pgtype.Text{String: string(*bill.Period), Valid: true}
+	}
+
+	// Bills without their own payment terms take the account's
+	paymentTermsDays := account.PaymentTermsDays
+	if bill.PaymentTermsDays != nil {
+		paymentTermsDays = *bill.PaymentTermsDays
+	}
+
 	workflowID := fmt.Sprintf("bill-%s", bill.IdempotencyKey)
 
-	dbBill, err := b.billRepo.CreateBill(ctx, bills.CreateBillParams{
-		Status:         string(model.BillStatusPending),
-		Currency:       bill.Currency,
-		StartTime:      pgtype.Timestamptz{Time: bill.StartTime, Valid: true},
-		EndTime:        pgtype.Timestamptz{Time: bill.EndTime, Valid: true},
-		IdempotencyKey: bill.IdempotencyKey,
-		WorkflowID:     pgtype.Text{String: workflowID, Valid: true},
-	})
-	if err != nil {
-		var e *pgconn.PgError
-		if errors.As(err, &e) && e.Code == pgerrcode.UniqueViolation {
-			return nil, &errs.Error{Code: errs.AlreadyExists, Message: "bill is duplicated"}
+	var result *model.Bill
+	err = b.stateMachine.WithTx(ctx, func(tx *domain.TxScope) error {
+		// The currency read above is checked again under a share lock, so it cannot be disabled
+		// or have its minor units changed before the bill is committed
+		lockedCurrency, err := tx.Currencies.GetCurrencyByCodeForShare(ctx, pgtype.Text{String: bill.Currency, Valid: true})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &errs.Error{Code: errs.NotFound, Message: "currency not supported"}
+			}
+			return &errs.Error{Code: errs.Internal, Message: "failed to lock currency"}
 		}
+		if !lockedCurrency.Enabled {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "currency is not enabled"}
+		}
+
+		dbBill, err := tx.Bills.CreateBill(ctx, bills.CreateBillParams{
+			Status:             string(model.BillStatusPending),
+			Currency:           bill.Currency,
+			StartTime:          pgtype.Timestamptz{Time: startTime, Valid: true},
+			EndTime:            pgtype.Timestamptz{Time: endTime, Valid: true},
+			IdempotencyKey:     bill.IdempotencyKey,
+			WorkflowID:         pgtype.Text{String: workflowID, Valid: true},
+			AccountID:          pgtype.Int4{Int32: bill.AccountID, Valid: true},
+			Timezone:           timezone,
+			Period:             period,
+			AllowNegativeTotal: bill.AllowNegativeTotal,
+			GracePeriodSeconds: bill.GracePeriodSeconds,
+			PaymentTermsDays:   paymentTermsDays,
+		})
+		if err != nil {
+			var e *pgconn.PgError
+			if errors.As(err, &e) && e.Code == pgerrcode.UniqueViolation {
+				return &errs.Error{Code: errs.AlreadyExists, Message: "bill is duplicated"}
+			}
 
-		return nil, &errs.Error{Code: errs.Internal, Message: "failed to create bill"}
+			return &errs.Error{Code: errs.Internal, Message: "failed to create bill"}
+		}
+
+		result = convertDBBillToModel(dbBill)
+		return b.recordAudit(ctx, tx, dbBill.ID, model.AuditActionBillCreated, actor, result)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return convertDBBillToModel(dbBill), nil
+	return result, nil
 }
 
 // convertDBBillToModel converts a database Bill to a domain model Bill
 func convertDBBillToModel(dbBill bills.Bill) *model.Bill {
 	bill := &model.Bill{
-		ID:               dbBill.ID,
-		Currency:         dbBill.Currency,
-		Status:           model.BillStatus(dbBill.Status),
-		TotalAmountCents: dbBill.TotalAmountCents.Int64,
-		StartTime:        dbBill.StartTime.Time,
-		EndTime:          dbBill.EndTime.Time,
-		IdempotencyKey:   dbBill.IdempotencyKey,
-		CreatedAt:        dbBill.CreatedAt.Time,
-		UpdatedAt:        dbBill.UpdatedAt.Time,
+		ID:                 dbBill.ID,
+		AccountID:          dbBill.AccountID.Int32,
+		Currency:           dbBill.Currency,
+		Status:             model.BillStatus(dbBill.Status),
+		TotalAmountCents:   dbBill.TotalAmountCents.Int64,
+		AmountPaidCents:    dbBill.AmountPaidCents,
+		BalanceCents:       dbBill.TotalAmountCents.Int64 - dbBill.AmountPaidCents,
+		StartTime:          dbBill.StartTime.Time,
+		EndTime:            dbBill.EndTime.Time,
+		Timezone:           dbBill.Timezone,
+		AllowNegativeTotal: dbBill.AllowNegativeTotal,
+		GracePeriodSeconds: dbBill.GracePeriodSeconds,
+		PaymentTermsDays:   &dbBill.PaymentTermsDays,
+		IdempotencyKey:     dbBill.IdempotencyKey,
+		CreatedAt:          dbBill.CreatedAt.Time,
+		UpdatedAt:          dbBill.UpdatedAt.Time,
+	}
+
+	// A cancelled bill keeps its total but owes nothing
+	if bill.Status == model.BillStatusCancelled {
+		bill.BalanceCents = 0
 	}
 
 	if dbBill.CloseReason.Valid {
@@ -73,9 +154,26 @@ func convertDBBillToModel(dbBill bills.Bill) *model.Bill {
 		bill.BilledAt = &dbBill.BilledAt.Time
 	}
 
+	if dbBill.DueAt.Valid {
+		bill.DueAt = &dbBill.DueAt.Time
+	}
+
+	if dbBill.OverdueAt.Valid {
+		bill.OverdueAt = &dbBill.OverdueAt.Time
+	}
+
 	if dbBill.WorkflowID.Valid {
 		bill.WorkflowID = &dbBill.WorkflowID.String
 	}
 
+	if dbBill.Period.Valid {
+		period := model.BillPeriod(dbBill.Period.String)
+		bill.Period = &period
+	}
+
+	if bill.Status == model.BillStatusClosed {
+		bill.PaymentStatus = model.SettlementStatus(bill.TotalAmountCents, bill.AmountPaidCents)
+	}
+
 	return bill
 }
@@ -2,20 +2,27 @@ package bill
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"encore.dev/beta/errs"
 
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/business/account_business"
 	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/mocks/domain/state_machine"
 	"encore.app/billing/mocks/repository/bill_repo"
+	"encore.app/billing/mocks/repository/currency_repo"
 	"encore.app/billing/model"
 	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/currencies"
 )
 
 func TestCreateBill(t *testing.T) {
@@ -24,14 +31,23 @@ func TestCreateBill(t *testing.T) {
 
 	mockRepo := bill_repo.NewMockQuerier(ctrl)
 	mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+	mockAccountService := account_business.NewMockBusiness(ctrl)
+	mockStateMachine := state_machine.NewMockStateMachine(ctrl)
 	business := &business{
 		billRepo:        mockRepo,
+		stateMachine:    mockStateMachine,
 		currencyService: mockCurrencyService,
+		accountService:  mockAccountService,
+	}
+	withTx := func(ctx context.Context, fn func(*domain.TxScope) error) error {
+		return fn(&domain.TxScope{Bills: mockRepo, Currencies: enabledCurrencyRepo(ctrl)})
 	}
 
 	testCases := []struct {
 		name                     string
 		input                    *model.Bill
+		mockAccountReturn        *model.Account
+		mockAccountError         error
 		mockCurrencyReturn       *model.CurrencyInfo
 		mockCurrencyError        error
 		mockBillReturn           bills.Bill
@@ -44,6 +60,7 @@ func TestCreateBill(t *testing.T) {
 		{
 			name: "happy_case",
 			input: &model.Bill{
+				AccountID:      7,
 				Currency:       "USD",
 				StartTime:      time.Now(),
 				EndTime:        time.Now().Add(time.Hour),
@@ -65,9 +82,40 @@ func TestCreateBill(t *testing.T) {
 			expectCurrencyCall: true,
 			expectBillRepoCall: true,
 		},
+		{
+			name: "account_not_found",
+			input: &model.Bill{
+				AccountID:      404,
+				Currency:       "USD",
+				StartTime:      time.Now(),
+				EndTime:        time.Now().Add(time.Hour),
+				IdempotencyKey: "test-key-no-account",
+			},
+			mockAccountError:   &errs.Error{Code: errs.NotFound, Message: "account not found"},
+			expectedError:      "account not found",
+			expectSuccess:      false,
+			expectCurrencyCall: false,
+			expectBillRepoCall: false,
+		},
+		{
+			name: "account_disabled",
+			input: &model.Bill{
+				AccountID:      8,
+				Currency:       "USD",
+				StartTime:      time.Now(),
+				EndTime:        time.Now().Add(time.Hour),
+				IdempotencyKey: "test-key-disabled-account",
+			},
+			mockAccountReturn:  &model.Account{ID: 8, Enabled: false},
+			expectedError:      "account is disabled",
+			expectSuccess:      false,
+			expectCurrencyCall: false,
+			expectBillRepoCall: false,
+		},
 		{
 			name: "currency_not_found",
 			input: &model.Bill{
+				AccountID:      7,
 				Currency:       "INVALID",
 				StartTime:      time.Now(),
 				EndTime:        time.Now().Add(time.Hour),
@@ -83,6 +131,7 @@ func TestCreateBill(t *testing.T) {
 		{
 			name: "currency_disabled",
 			input: &model.Bill{
+				AccountID:      7,
 				Currency:       "EUR",
 				StartTime:      time.Now(),
 				EndTime:        time.Now().Add(time.Hour),
@@ -98,9 +147,29 @@ func TestCreateBill(t *testing.T) {
 			expectCurrencyCall: true,
 			expectBillRepoCall: false,
 		},
+		{
+			name: "invalid_timezone",
+			input: &model.Bill{
+				AccountID:      7,
+				Currency:       "USD",
+				StartTime:      time.Now(),
+				EndTime:        time.Now().Add(time.Hour),
+				Timezone:       "Mars/Olympus_Mons",
+				IdempotencyKey: "test-key-timezone",
+			},
+			mockCurrencyReturn: &model.CurrencyInfo{
+				Code:    "USD",
+				Enabled: true,
+			},
+			expectedError:      "invalid timezone",
+			expectSuccess:      false,
+			expectCurrencyCall: true,
+			expectBillRepoCall: false,
+		},
 		{
 			name: "duplicate_error",
 			input: &model.Bill{
+				AccountID:      7,
 				Currency:       "USD",
 				StartTime:      time.Now(),
 				EndTime:        time.Now().Add(time.Hour),
@@ -121,6 +190,7 @@ func TestCreateBill(t *testing.T) {
 		{
 			name: "general_error",
 			input: &model.Bill{
+				AccountID:      7,
 				Currency:       "USD",
 				StartTime:      time.Now(),
 				EndTime:        time.Now().Add(time.Hour),
@@ -142,6 +212,15 @@ func TestCreateBill(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			// Setup account service mock expectations; accounts are enabled unless stated otherwise
+			accountReturn := tc.mockAccountReturn
+			if accountReturn == nil && tc.mockAccountError == nil {
+				accountReturn = &model.Account{ID: tc.input.AccountID, Enabled: true}
+			}
+			mockAccountService.EXPECT().
+				GetAccount(gomock.Any(), tc.input.AccountID).
+				Return(accountReturn, tc.mockAccountError)
+
 			// Setup currency service mock expectations
 			if tc.expectCurrencyCall {
 				mockCurrencyService.EXPECT().
@@ -149,14 +228,21 @@ func TestCreateBill(t *testing.T) {
 					Return(tc.mockCurrencyReturn, tc.mockCurrencyError)
 			}
 
-			// Setup bill repository mock expectations
+			// Setup bill repository mock expectations; the bill is created in a transaction
 			if tc.expectBillRepoCall {
+				mockStateMachine.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(withTx)
 				mockRepo.EXPECT().
 					CreateBill(gomock.Any(), gomock.Any()).
 					Return(tc.mockBillReturn, tc.mockBillError)
 			}
 
-			result, err := business.CreateBill(context.Background(), tc.input)
+			if tc.expectSuccess {
+				mockStateMachine.EXPECT().
+					RecordAuditTx(gomock.Any(), gomock.Any(), tc.mockBillReturn.ID, model.AuditActionBillCreated, "admin", gomock.Any()).
+					Return(nil)
+			}
+
+			result, err := business.CreateBill(context.Background(), tc.input, "admin")
 
 			if tc.expectSuccess {
 				assert.NoError(t, err)
@@ -171,3 +257,174 @@ func TestCreateBill(t *testing.T) {
 		})
 	}
 }
+
+// enabledCurrencyRepo is a transaction-bound currency repository whose currencies are all enabled
+func enabledCurrencyRepo(ctrl *gomock.Controller) *currency_repo.MockQuerier {
+	repo := currency_repo.NewMockQuerier(ctrl)
+	repo.EXPECT().GetCurrencyByCodeForShare(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, code pgtype.Text) (currencies.Currency, error) {
+			return currencies.Currency{Code: code, Enabled: true, MinorUnits: 2}, nil
+		}).AnyTimes()
+	return repo
+}
+
+func TestCreateBill_CurrencyDisabledBeforeLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := bill_repo.NewMockQuerier(ctrl)
+	mockCurrencyRepo := currency_repo.NewMockQuerier(ctrl)
+	mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+	mockAccountService := account_business.NewMockBusiness(ctrl)
+	mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+	business := &business{
+		billRepo:        mockRepo,
+		stateMachine:    mockStateMachine,
+		currencyService: mockCurrencyService,
+		accountService:  mockAccountService,
+	}
+
+	mockAccountService.EXPECT().GetAccount(gomock.Any(), int32(7)).Return(&model.Account{ID: 7, Enabled: true}, nil)
+	mockCurrencyService.EXPECT().GetCurrency(gomock.Any(), "GEL").Return(&model.CurrencyInfo{Code: "GEL", Enabled: true}, nil)
+	mockStateMachine.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(*domain.TxScope) error) error {
+		return fn(&domain.TxScope{Bills: mockRepo, Currencies: mockCurrencyRepo})
+	})
+	// The currency was disabled after it was first read; no bill may be created in it
+	mockCurrencyRepo.EXPECT().GetCurrencyByCodeForShare(gomock.Any(), pgtype.Text{String: "GEL", Valid: true}).
+		Return(currencies.Currency{Code: pgtype.Text{String: "GEL", Valid: true}, Enabled: false}, nil)
+
+	result, err := business.CreateBill(context.Background(), &model.Bill{
+		AccountID:      7,
+		Currency:       "GEL",
+		StartTime:      time.Now(),
+		EndTime:        time.Now().Add(time.Hour),
+		IdempotencyKey: "test-key",
+	}, "admin")
+
+	assert.Nil(t, result)
+	var e *errs.Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, errs.InvalidArgument, e.Code)
+	assert.Equal(t, "currency is not enabled", e.Message)
+}
+
+func TestCreateBill_ResolvesPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := bill_repo.NewMockQuerier(ctrl)
+	mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+	mockAccountService := account_business.NewMockBusiness(ctrl)
+	mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+	business := &business{
+		billRepo:        mockRepo,
+		stateMachine:    mockStateMachine,
+		currencyService: mockCurrencyService,
+		accountService:  mockAccountService,
+	}
+	withTx := func(ctx context.Context, fn func(*domain.TxScope) error) error {
+		return fn(&domain.TxScope{Bills: mockRepo, Currencies: enabledCurrencyRepo(ctrl)})
+	}
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().In(loc)
+	year, month, day := now.Date()
+	expectedStart := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	expectedEnd := time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+
+	mockAccountService.EXPECT().GetAccount(gomock.Any(), int32(7)).Return(&model.Account{ID: 7, Enabled: true}, nil)
+	mockCurrencyService.EXPECT().GetCurrency(gomock.Any(), "USD").Return(&model.CurrencyInfo{Code: "USD", Enabled: true}, nil)
+	mockStateMachine.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(withTx)
+	mockStateMachine.EXPECT().RecordAuditTx(gomock.Any(), gomock.Any(), int32(1), model.AuditActionBillCreated, "admin", gomock.Any()).Return(nil)
+	mockRepo.EXPECT().
+		CreateBill(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params bills.CreateBillParams) (bills.Bill, error) {
+			return bills.Bill{
+				ID:        1,
+				Currency:  params.Currency,
+				StartTime: params.StartTime,
+				EndTime:   params.EndTime,
+				Timezone:  params.Timezone,
+				Period:    params.Period,
+			}, nil
+		})
+
+	period := model.BillPeriodDay
+	result, err := business.CreateBill(context.Background(), &model.Bill{
+		AccountID:      7,
+		Currency:       "USD",
+		StartTime:      now,
+		Timezone:       "Europe/Berlin",
+		Period:         &period,
+		IdempotencyKey: "test-key-period",
+	}, "admin")
+
+	assert.NoError(t, err)
+	assert.True(t, result.StartTime.Equal(expectedStart))
+	assert.True(t, result.EndTime.Equal(expectedEnd))
+	assert.Equal(t, "Europe/Berlin", result.Timezone)
+	if assert.NotNil(t, result.Period) {
+		assert.Equal(t, model.BillPeriodDay, *result.Period)
+	}
+}
+
+func TestCreateBill_PaymentTerms(t *testing.T) {
+	dueOnReceipt := int32(0)
+
+	testCases := []struct {
+		name         string
+		billTerms    *int32
+		expectedDays int32
+	}{
+		{name: "defaults_to_account_terms", expectedDays: 15},
+		{name: "bill_terms_override_account", billTerms: &dueOnReceipt, expectedDays: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := bill_repo.NewMockQuerier(ctrl)
+			mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+			mockAccountService := account_business.NewMockBusiness(ctrl)
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			business := &business{
+				billRepo:        mockRepo,
+				stateMachine:    mockStateMachine,
+				currencyService: mockCurrencyService,
+				accountService:  mockAccountService,
+			}
+
+			mockAccountService.EXPECT().GetAccount(gomock.Any(), int32(7)).Return(&model.Account{ID: 7, Enabled: true, PaymentTermsDays: 15}, nil)
+			mockCurrencyService.EXPECT().GetCurrency(gomock.Any(), "USD").Return(&model.CurrencyInfo{Code: "USD", Enabled: true}, nil)
+			mockStateMachine.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(*domain.TxScope) error) error {
+				return fn(&domain.TxScope{Bills: mockRepo, Currencies: enabledCurrencyRepo(ctrl)})
+			})
+			mockStateMachine.EXPECT().RecordAuditTx(gomock.Any(), gomock.Any(), int32(1), model.AuditActionBillCreated, "admin", gomock.Any()).Return(nil)
+			mockRepo.EXPECT().
+				CreateBill(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, params bills.CreateBillParams) (bills.Bill, error) {
+					assert.Equal(t, tc.expectedDays, params.PaymentTermsDays)
+					return bills.Bill{ID: 1, Currency: params.Currency, PaymentTermsDays: params.PaymentTermsDays}, nil
+				})
+
+			result, err := business.CreateBill(context.Background(), &model.Bill{
+				AccountID:        7,
+				Currency:         "USD",
+				StartTime:        time.Now(),
+				EndTime:          time.Now().Add(24 * time.Hour),
+				PaymentTermsDays: tc.billTerms,
+				IdempotencyKey:   "test-key-terms",
+			}, "admin")
+
+			assert.NoError(t, err)
+			if assert.NotNil(t, result.PaymentTermsDays) {
+				assert.Equal(t, tc.expectedDays, *result.PaymentTermsDays)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package bill
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"encore.dev/beta/errs"
+)
+
+// encodeCursor returns an opaque cursor pointing after the row with the given sort key value
+// and ID. Time keys are passed as Unix microseconds. The cursor records what it was sorted
+// by so that it is not reused under a different ordering.
+func encodeCursor(sort string, value int64, id int32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%d", sort, value, id)))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor for the given ordering
+func decodeCursor(cursor, sort string) (int64, int32, error) {
+	invalid := &errs.Error{Code: errs.InvalidArgument, Message: "invalid cursor"}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, invalid
+	}
+
+	rest, ok := strings.CutPrefix(string(raw), sort+":")
+	if !ok {
+		return 0, 0, invalid
+	}
+
+	var value int64
+	var id int32
+	if n, err := fmt.Sscanf(rest, "%d:%d", &value, &id); err != nil || n != 2 {
+		return 0, 0, invalid
+	}
+
+	return value, id, nil
+}
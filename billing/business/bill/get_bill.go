@@ -11,8 +11,9 @@ import (
 	"encore.app/billing/model"
 )
 
-// GetBill handles the business logic for retrieving a bill by ID with line items
-func (b *business) GetBill(ctx context.Context, id int32) (*model.Bill, error) {
+// GetBill handles the business logic for retrieving a bill by ID with line items. The options
+// may leave the line items out or embed only the newest ones, with a cursor to the rest.
+func (b *business) GetBill(ctx context.Context, id int32, opts model.GetBillOptions) (*model.Bill, error) {
 	dbBill, err := b.billRepo.GetBill(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -23,11 +24,22 @@ func (b *business) GetBill(ctx context.Context, id int32) (*model.Bill, error) {
 
 	bill := convertDBBillToModel(dbBill)
 
-	lineItems, err := b.GetLineItemsByBill(ctx, id)
-	if err != nil {
-		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get line items"}
+	switch {
+	case opts.OmitLineItems:
+	case opts.LineItemLimit > 0:
+		page, err := b.listLineItems(ctx, id, model.LineItemFilter{Limit: opts.LineItemLimit})
+		if err != nil {
+			return nil, err
+		}
+		bill.LineItems = page.LineItems
+		bill.LineItemsNextCursor = page.NextCursor
+	default:
+		lineItems, err := b.GetLineItemsByBill(ctx, id)
+		if err != nil {
+			return nil, &errs.Error{Code: errs.Internal, Message: "failed to get line items"}
+		}
+		bill.LineItems = lineItems
 	}
-	bill.LineItems = lineItems
 
 	return bill, nil
 }
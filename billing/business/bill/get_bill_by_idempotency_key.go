@@ -0,0 +1,25 @@
+package bill
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+)
+
+// GetBillByIdempotencyKey retrieves a bill, without line items, by the idempotency key it was created with
+func (b *business) GetBillByIdempotencyKey(ctx context.Context, idempotencyKey string) (*model.Bill, error) {
+	dbBill, err := b.billRepo.GetBillByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.NotFound, Message: "bill not found"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get bill"}
+	}
+
+	return convertDBBillToModel(dbBill), nil
+}
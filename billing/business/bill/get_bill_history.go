@@ -0,0 +1,48 @@
+package bill
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/billevents"
+)
+
+// GetBillHistory returns the status transitions of a bill, oldest first
+func (b *business) GetBillHistory(ctx context.Context, billID int32) ([]model.BillEvent, error) {
+	if _, err := b.billRepo.GetBill(ctx, billID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.NotFound, Message: "bill not found"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get bill"}
+	}
+
+	dbEvents, err := b.eventRepo.ListBillEvents(ctx, billID)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get bill history"}
+	}
+
+	events := make([]model.BillEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = convertDBBillEventToModel(dbEvent)
+	}
+
+	return events, nil
+}
+
+// convertDBBillEventToModel converts database BillEvent to domain model BillEvent
+func convertDBBillEventToModel(dbEvent billevents.BillEvent) model.BillEvent {
+	return model.BillEvent{
+		ID:         dbEvent.ID,
+		BillID:     dbEvent.BillID,
+		FromStatus: model.BillStatus(dbEvent.FromStatus.String),
+		ToStatus:   model.BillStatus(dbEvent.ToStatus),
+		Reason:     dbEvent.Reason.String,
+		Actor:      dbEvent.Actor,
+		CreatedAt:  dbEvent.CreatedAt.Time,
+	}
+}
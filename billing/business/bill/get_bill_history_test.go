@@ -0,0 +1,77 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/bill_repo"
+	"encore.app/billing/mocks/repository/billevent_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/billevents"
+	"encore.app/billing/repository/bills"
+)
+
+func TestGetBillHistory(t *testing.T) {
+	activatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	closedAt := activatedAt.Add(24 * time.Hour)
+
+	t.Run("returns_transitions_in_order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+		mockEventRepo := billevent_repo.NewMockQuerier(ctrl)
+		business := &business{billRepo: mockBillRepo, eventRepo: mockEventRepo}
+
+		mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(1)).Return(bills.Bill{ID: 1}, nil)
+		mockEventRepo.EXPECT().ListBillEvents(gomock.Any(), int32(1)).Return([]billevents.BillEvent{
+			{
+				ID:         1,
+				BillID:     1,
+				FromStatus: pgtype.Text{String: "pending", Valid: true},
+				ToStatus:   "active",
+				Actor:      model.ActorSystem,
+				CreatedAt:  pgtype.Timestamptz{Time: activatedAt, Valid: true},
+			},
+			{
+				ID:         2,
+				BillID:     1,
+				FromStatus: pgtype.Text{String: "active", Valid: true},
+				ToStatus:   "closing",
+				Reason:     pgtype.Text{String: "customer request", Valid: true},
+				Actor:      "account:7",
+				CreatedAt:  pgtype.Timestamptz{Time: closedAt, Valid: true},
+			},
+		}, nil)
+
+		events, err := business.GetBillHistory(context.Background(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, []model.BillEvent{
+			{ID: 1, BillID: 1, FromStatus: model.BillStatusPending, ToStatus: model.BillStatusActive, Actor: model.ActorSystem, CreatedAt: activatedAt},
+			{ID: 2, BillID: 1, FromStatus: model.BillStatusActive, ToStatus: model.BillStatusClosing, Reason: "customer request", Actor: "account:7", CreatedAt: closedAt},
+		}, events)
+	})
+
+	t.Run("bill_not_found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+		business := &business{billRepo: mockBillRepo, eventRepo: billevent_repo.NewMockQuerier(ctrl)}
+
+		mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(404)).Return(bills.Bill{}, pgx.ErrNoRows)
+
+		events, err := business.GetBillHistory(context.Background(), 404)
+
+		assert.Nil(t, events)
+		var e *errs.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, errs.NotFound, e.Code)
+	})
+}
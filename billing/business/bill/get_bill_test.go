@@ -12,6 +12,7 @@ import (
 
 	"encore.app/billing/mocks/repository/bill_repo"
 	"encore.app/billing/mocks/repository/lineitem_repo"
+	"encore.app/billing/model"
 	"encore.app/billing/repository/bills"
 	"encore.app/billing/repository/lineitems"
 )
@@ -129,7 +130,7 @@ func TestGetBill(t *testing.T) {
 					Return(tc.mockLineItemsReturn, tc.mockLineItemsError)
 			}
 
-			result, err := business.GetBill(context.Background(), tc.billID)
+			result, err := business.GetBill(context.Background(), tc.billID, model.GetBillOptions{})
 
 			if tc.expectSuccess {
 				assert.NoError(t, err)
@@ -35,14 +35,17 @@ func (b *business) GetLineItemsByBill(ctx context.Context, billID int32) ([]mode
 // convertDBLineItemToModel converts database LineItem to domain model LineItem
 func convertDBLineItemToModel(dbLineItem lineitems.LineItem) *model.LineItem {
 	lineItem := &model.LineItem{
-		ID:             dbLineItem.ID,
-		BillID:         dbLineItem.BillID.Int32,
-		AmountCents:    dbLineItem.AmountCents,
-		Currency:       dbLineItem.Currency,
-		IncurredAt:     dbLineItem.IncurredAt.Time,
-		IdempotencyKey: dbLineItem.IdempotencyKey,
-		CreatedAt:      dbLineItem.CreatedAt.Time,
-		UpdatedAt:      dbLineItem.UpdatedAt.Time,
+		ID:              dbLineItem.ID,
+		BillID:          dbLineItem.BillID.Int32,
+		Kind:            model.LineItemKind(dbLineItem.Kind),
+		Quantity:        numericToQuantity(dbLineItem.Quantity).String(),
+		UnitAmountCents: dbLineItem.UnitAmountCents,
+		AmountCents:     dbLineItem.AmountCents,
+		Currency:        dbLineItem.Currency,
+		IncurredAt:      dbLineItem.IncurredAt.Time,
+		IdempotencyKey:  dbLineItem.IdempotencyKey,
+		CreatedAt:       dbLineItem.CreatedAt.Time,
+		UpdatedAt:       dbLineItem.UpdatedAt.Time,
 	}
 
 	if dbLineItem.Description.Valid {
@@ -53,6 +56,15 @@ func convertDBLineItemToModel(dbLineItem lineitems.LineItem) *model.LineItem {
 		lineItem.ReferenceID = dbLineItem.ReferenceID.String
 	}
 
+	if dbLineItem.VoidedAt.Valid {
+		lineItem.VoidedAt = &dbLineItem.VoidedAt.Time
+		lineItem.VoidReason = dbLineItem.VoidReason.String
+	}
+
+	if dbLineItem.ReplacesLineItemID.Valid {
+		lineItem.ReplacesLineItemID = &dbLineItem.ReplacesLineItemID.Int32
+	}
+
 	if len(dbLineItem.Metadata) > 0 {
 		var metadata model.CurrencyMetadata
 		if err := json.Unmarshal(dbLineItem.Metadata, &metadata); err == nil {
@@ -0,0 +1,48 @@
+package bill
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+// validateLineItemAmount checks a submitted amount against the line item kind. Charges and
+// credits are submitted as positive amounts; adjustments may be negative but not zero.
+func validateLineItemAmount(kind model.LineItemKind, amountCents int64) error {
+	switch kind {
+	case model.LineItemKindCharge, model.LineItemKindCredit:
+		if amountCents <= 0 {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "amount_cents must be positive for charge and credit line items"}
+		}
+	case model.LineItemKindAdjustment:
+		if amountCents == 0 {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "amount_cents must not be zero"}
+		}
+	default:
+		return &errs.Error{Code: errs.InvalidArgument, Message: "invalid line item kind"}
+	}
+	return nil
+}
+
+// checkBillTotal rejects a line item change that takes the bill total below zero,
+// unless the bill allows a negative total. It runs after the change inside the same
+// transaction, so returning an error rolls the change back.
+func checkBillTotal(ctx context.Context, tx *domain.TxScope, bill bills.Bill) error {
+	if bill.AllowNegativeTotal {
+		return nil
+	}
+
+	total, err := tx.LineItems.GetTotalAmountByBill(ctx, pgtype.Int4{Int32: bill.ID, Valid: true})
+	if err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to calculate bill total"}
+	}
+	if total < 0 {
+		return &errs.Error{Code: errs.FailedPrecondition, Message: "bill total cannot go negative"}
+	}
+	return nil
+}
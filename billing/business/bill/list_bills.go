@@ -3,8 +3,10 @@ package bill
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"encore.dev/beta/errs"
 
@@ -12,28 +14,154 @@ import (
 	"encore.app/billing/repository/bills"
 )
 
-// ListBills handles the business logic for retrieving bills with pagination
-func (b *business) ListBills(ctx context.Context, limit, offset int32) ([]*model.Bill, int64, error) {
-	dbBills, err := b.billRepo.ListBills(ctx, bills.ListBillsParams{
-		Limit:  limit,
-		Offset: offset,
-	})
+// ListBills handles the business logic for retrieving an account's bills. Pages are read with
+// keyset pagination on the sort key and bill ID, so a cursor stays cheap however deep it is.
+func (b *business) ListBills(ctx context.Context, accountID int32, filter model.BillFilter) (*model.BillPage, error) {
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = model.BillSortCreatedAt
+	}
+	cursorSort := string(sortBy) + ":desc"
+	if filter.Ascending {
+		cursorSort = string(sortBy) + ":asc"
+	}
+
+	statuses := make([]string, len(filter.Statuses))
+	for i, status := range filter.Statuses {
+		statuses[i] = string(status)
+	}
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+
+	params := bills.ListBillsByCreatedAtDescParams{
+		AccountID:     pgtype.Int4{Int32: accountID, Valid: true},
+		Statuses:      statuses,
+		Currency:      pgtype.Text{String: filter.Currency, Valid: filter.Currency != ""},
+		StartFrom:     optionalTimestamptz(filter.StartFrom),
+		StartTo:       optionalTimestamptz(filter.StartTo),
+		EndFrom:       optionalTimestamptz(filter.EndFrom),
+		EndTo:         optionalTimestamptz(filter.EndTo),
+		MinTotalCents: optionalInt8(filter.MinTotalCents),
+		MaxTotalCents: optionalInt8(filter.MaxTotalCents),
+		// One extra row tells whether another page follows
+		Limit:  filter.Limit + 1,
+		Offset: filter.Offset,
+	}
+
+	var cursorValue int64
+	if filter.Cursor != "" {
+		value, id, err := decodeCursor(filter.Cursor, cursorSort)
+		if err != nil {
+			return nil, err
+		}
+		cursorValue = value
+		params.CursorTime = pgtype.Timestamptz{Time: time.UnixMicro(value).UTC(), Valid: true}
+		params.CursorID = pgtype.Int4{Int32: id, Valid: true}
+	}
+
+	var dbBills []bills.Bill
+	var err error
+	switch sortBy {
+	case model.BillSortCreatedAt:
+		if filter.Ascending {
+			dbBills, err = b.billRepo.ListBillsByCreatedAtAsc(ctx, bills.ListBillsByCreatedAtAscParams(params))
+		} else {
+			dbBills, err = b.billRepo.ListBillsByCreatedAtDesc(ctx, params)
+		}
+	case model.BillSortStartTime:
+		if filter.Ascending {
+			dbBills, err = b.billRepo.ListBillsByStartTimeAsc(ctx, bills.ListBillsByStartTimeAscParams(params))
+		} else {
+			dbBills, err = b.billRepo.ListBillsByStartTimeDesc(ctx, bills.ListBillsByStartTimeDescParams(params))
+		}
+	case model.BillSortTotal:
+		totalParams := bills.ListBillsByTotalDescParams{
+			AccountID:     params.AccountID,
+			Statuses:      params.Statuses,
+			Currency:      params.Currency,
+			StartFrom:     params.StartFrom,
+			StartTo:       params.StartTo,
+			EndFrom:       params.EndFrom,
+			EndTo:         params.EndTo,
+			MinTotalCents: params.MinTotalCents,
+			MaxTotalCents: params.MaxTotalCents,
+			CursorTotal:   pgtype.Int8{Int64: cursorValue, Valid: params.CursorID.Valid},
+			CursorID:      params.CursorID,
+			Limit:         params.Limit,
+			Offset:        params.Offset,
+		}
+		if filter.Ascending {
+			dbBills, err = b.billRepo.ListBillsByTotalAsc(ctx, bills.ListBillsByTotalAscParams(totalParams))
+		} else {
+			dbBills, err = b.billRepo.ListBillsByTotalDesc(ctx, totalParams)
+		}
+	default:
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid sort key"}
+	}
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, 0, &errs.Error{Code: errs.NotFound, Message: "bills not found"}
+			return nil, &errs.Error{Code: errs.NotFound, Message: "bills not found"}
 		}
-		return nil, 0, &errs.Error{Code: errs.Internal, Message: "failed to list bills"}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to list bills"}
 	}
 
-	totalCount, err := b.billRepo.CountBills(ctx)
-	if err != nil {
-		return nil, 0, &errs.Error{Code: errs.Internal, Message: "failed to count bills"}
+	page := &model.BillPage{}
+	if int32(len(dbBills)) > filter.Limit {
+		dbBills = dbBills[:filter.Limit]
+		page.NextCursor = encodeCursor(cursorSort, billSortValue(dbBills[len(dbBills)-1], sortBy), dbBills[len(dbBills)-1].ID)
 	}
 
-	billList := make([]*model.Bill, len(dbBills))
+	page.Bills = make([]*model.Bill, len(dbBills))
 	for i, dbBill := range dbBills {
-		billList[i] = convertDBBillToModel(dbBill)
+		page.Bills[i] = convertDBBillToModel(dbBill)
+	}
+
+	if filter.IncludeTotalCount {
+		totalCount, err := b.billRepo.CountFilteredBills(ctx, bills.CountFilteredBillsParams{
+			AccountID:     params.AccountID,
+			Statuses:      params.Statuses,
+			Currency:      params.Currency,
+			StartFrom:     params.StartFrom,
+			StartTo:       params.StartTo,
+			EndFrom:       params.EndFrom,
+			EndTo:         params.EndTo,
+			MinTotalCents: params.MinTotalCents,
+			MaxTotalCents: params.MaxTotalCents,
+			// Listings ordered by total leave cancelled bills out, so their count does too
+			ExcludeCancelled: sortBy == model.BillSortTotal,
+		})
+		if err != nil {
+			return nil, &errs.Error{Code: errs.Internal, Message: "failed to count bills"}
+		}
+		page.TotalCount = &totalCount
+	}
+
+	return page, nil
+}
+
+// billSortValue returns the value of the sort key a cursor records for a bill
+func billSortValue(bill bills.Bill, sortBy model.BillSortKey) int64 {
+	switch sortBy {
+	case model.BillSortStartTime:
+		return bill.StartTime.Time.UnixMicro()
+	case model.BillSortTotal:
+		return bill.TotalAmountCents.Int64
+	default:
+		return bill.CreatedAt.Time.UnixMicro()
 	}
+}
 
-	return billList, totalCount, nil
+func optionalTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func optionalInt8(v *int64) pgtype.Int8 {
+	if v == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: *v, Valid: true}
 }
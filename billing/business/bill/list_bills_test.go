@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"encore.dev/beta/errs"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
@@ -182,31 +183,40 @@ func TestListBills(t *testing.T) {
 
 			mockBillRepo := bill_repo.NewMockQuerier(ctrl)
 			business := &business{billRepo: mockBillRepo}
+			accountID := int32(42)
 
 			// Mock ListBills call
 			mockBillRepo.EXPECT().
-				ListBills(gomock.Any(), bills.ListBillsParams{
-					Limit:  tc.limit,
-					Offset: tc.offset,
+				ListBillsByCreatedAtDesc(gomock.Any(), bills.ListBillsByCreatedAtDescParams{
+					AccountID: pgtype.Int4{Int32: accountID, Valid: true},
+					Limit:     tc.limit + 1,
+					Offset:    tc.offset,
 				}).
 				Return(tc.mockListBillsReturn, tc.mockListBillsError)
 
 			// Mock CountBills call only if ListBills succeeds
 			if tc.mockListBillsError == nil {
 				mockBillRepo.EXPECT().
-					CountBills(gomock.Any()).
+					CountFilteredBills(gomock.Any(), bills.CountFilteredBillsParams{
+						AccountID: pgtype.Int4{Int32: accountID, Valid: true},
+					}).
 					Return(tc.mockCountReturn, tc.mockCountError)
 			}
 
 			// Execute the test
-			result, totalCount, err := business.ListBills(context.Background(), tc.limit, tc.offset)
+			page, err := business.ListBills(context.Background(), accountID, model.BillFilter{
+				Limit:             tc.limit,
+				Offset:            tc.offset,
+				IncludeTotalCount: true,
+			})
 
 			// Assertions
 			if tc.expectSuccess {
 				assert.NoError(t, err)
-				assert.NotNil(t, result)
+				assert.NotNil(t, page)
+				result := page.Bills
 				assert.Equal(t, tc.expectedBillsCount, len(result))
-				assert.Equal(t, tc.expectedTotalCount, totalCount)
+				assert.Equal(t, &tc.expectedTotalCount, page.TotalCount)
 
 				// Verify each bill's conversion from database model to domain model
 				for i, bill := range result {
@@ -230,12 +240,103 @@ func TestListBills(t *testing.T) {
 				}
 			} else {
 				assert.Error(t, err)
-				assert.Nil(t, result)
-				assert.Equal(t, int64(0), totalCount)
+				assert.Nil(t, page)
 				if tc.expectedError != "" {
 					assert.Contains(t, err.Error(), tc.expectedError)
 				}
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestListBills_SortAndCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+	business := &business{billRepo: mockBillRepo}
+
+	rows := []bills.Bill{
+		{ID: 4, Currency: "USD", TotalAmountCents: pgtype.Int8{Int64: 100, Valid: true}},
+		{ID: 2, Currency: "USD", TotalAmountCents: pgtype.Int8{Int64: 250, Valid: true}},
+		{ID: 9, Currency: "USD", TotalAmountCents: pgtype.Int8{Int64: 900, Valid: true}},
+	}
+
+	mockBillRepo.EXPECT().
+		ListBillsByTotalAsc(gomock.Any(), bills.ListBillsByTotalAscParams{
+			AccountID: pgtype.Int4{Int32: 42, Valid: true},
+			Statuses:  []string{"closed"},
+			Limit:     3,
+		}).
+		Return(rows, nil)
+
+	filter := model.BillFilter{
+		Statuses:  []model.BillStatus{model.BillStatusClosed},
+		SortBy:    model.BillSortTotal,
+		Ascending: true,
+		Limit:     2,
+	}
+	page, err := business.ListBills(context.Background(), 42, filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Bills, 2)
+	assert.Nil(t, page.TotalCount)
+	assert.NotEmpty(t, page.NextCursor)
+
+	// The next page continues after the last bill of the previous one
+	mockBillRepo.EXPECT().
+		ListBillsByTotalAsc(gomock.Any(), bills.ListBillsByTotalAscParams{
+			AccountID:   pgtype.Int4{Int32: 42, Valid: true},
+			Statuses:    []string{"closed"},
+			CursorTotal: pgtype.Int8{Int64: 250, Valid: true},
+			CursorID:    pgtype.Int4{Int32: 2, Valid: true},
+			Limit:       3,
+		}).
+		Return(rows[2:], nil)
+
+	filter.Cursor = page.NextCursor
+	page, err = business.ListBills(context.Background(), 42, filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Bills, 1)
+	assert.Empty(t, page.NextCursor)
+
+	// A cursor is only valid for the ordering it was issued for
+	filter.SortBy = model.BillSortStartTime
+	page, err = business.ListBills(context.Background(), 42, filter)
+
+	assert.Nil(t, page)
+	var e *errs.Error
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, errs.InvalidArgument, e.Code)
+	}
+}
+
+func TestListBills_TotalSortCountLeavesOutCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+	business := &business{billRepo: mockBillRepo}
+
+	mockBillRepo.EXPECT().
+		ListBillsByTotalDesc(gomock.Any(), bills.ListBillsByTotalDescParams{
+			AccountID: pgtype.Int4{Int32: 42, Valid: true},
+			Limit:     11,
+		}).
+		Return([]bills.Bill{{ID: 1, Currency: "USD", Status: string(model.BillStatusClosed)}}, nil)
+	mockBillRepo.EXPECT().
+		CountFilteredBills(gomock.Any(), bills.CountFilteredBillsParams{
+			AccountID:        pgtype.Int4{Int32: 42, Valid: true},
+			ExcludeCancelled: true,
+		}).
+		Return(int64(1), nil)
+
+	page, err := business.ListBills(context.Background(), 42, model.BillFilter{
+		SortBy:            model.BillSortTotal,
+		Limit:             10,
+		IncludeTotalCount: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), *page.TotalCount)
+}
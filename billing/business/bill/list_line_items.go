@@ -0,0 +1,71 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/lineitems"
+)
+
+// lineItemCursorSort tags line item cursors, which always follow incurred_at descending
+const lineItemCursorSort = "incurred_at"
+
+// ListLineItems returns one page of a bill's line items, newest first, matching the filter
+func (b *business) ListLineItems(ctx context.Context, billID int32, filter model.LineItemFilter) (*model.LineItemPage, error) {
+	if _, err := b.billRepo.GetBill(ctx, billID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.NotFound, Message: "bill not found"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get bill"}
+	}
+
+	return b.listLineItems(ctx, billID, filter)
+}
+
+// listLineItems pages through line items with keyset pagination on (incurred_at, id)
+func (b *business) listLineItems(ctx context.Context, billID int32, filter model.LineItemFilter) (*model.LineItemPage, error) {
+	params := lineitems.ListLineItemsParams{
+		BillID:       pgtype.Int4{Int32: billID, Valid: true},
+		Currency:     pgtype.Text{String: filter.Currency, Valid: filter.Currency != ""},
+		ReferenceID:  pgtype.Text{String: filter.ReferenceID, Valid: filter.ReferenceID != ""},
+		Description:  pgtype.Text{String: filter.Description, Valid: filter.Description != ""},
+		IncurredFrom: optionalTimestamptz(filter.IncurredFrom),
+		IncurredTo:   optionalTimestamptz(filter.IncurredTo),
+		// One extra row tells whether another page follows
+		Limit: filter.Limit + 1,
+	}
+	if filter.Cursor != "" {
+		incurredAt, id, err := decodeCursor(filter.Cursor, lineItemCursorSort)
+		if err != nil {
+			return nil, err
+		}
+		params.CursorIncurredAt = pgtype.Timestamptz{Time: time.UnixMicro(incurredAt).UTC(), Valid: true}
+		params.CursorID = pgtype.Int4{Int32: id, Valid: true}
+	}
+
+	dbLineItems, err := b.lineItemRepo.ListLineItems(ctx, params)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to list line items"}
+	}
+
+	page := &model.LineItemPage{}
+	if int32(len(dbLineItems)) > filter.Limit {
+		dbLineItems = dbLineItems[:filter.Limit]
+		last := dbLineItems[len(dbLineItems)-1]
+		page.NextCursor = encodeCursor(lineItemCursorSort, last.IncurredAt.Time.UnixMicro(), last.ID)
+	}
+
+	page.LineItems = make([]model.LineItem, len(dbLineItems))
+	for i, dbLineItem := range dbLineItems {
+		page.LineItems[i] = *convertDBLineItemToModel(dbLineItem)
+	}
+
+	return page, nil
+}
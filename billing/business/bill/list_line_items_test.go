@@ -0,0 +1,134 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/bill_repo"
+	"encore.app/billing/mocks/repository/lineitem_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+func TestListLineItems(t *testing.T) {
+	incurredAt := func(day int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: time.Date(2025, 1, day, 12, 0, 0, 0, time.UTC), Valid: true}
+	}
+	rows := []lineitems.LineItem{
+		{ID: 3, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 300, Currency: "USD", IncurredAt: incurredAt(3)},
+		{ID: 2, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 200, Currency: "USD", IncurredAt: incurredAt(2)},
+		{ID: 1, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 100, Currency: "USD", IncurredAt: incurredAt(1)},
+	}
+
+	t.Run("pages_through_line_items", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+		mockLineItemRepo := lineitem_repo.NewMockQuerier(ctrl)
+		business := &business{billRepo: mockBillRepo, lineItemRepo: mockLineItemRepo}
+
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(1)).Return(bills.Bill{ID: 1}, nil).Times(2)
+		mockLineItemRepo.EXPECT().
+			ListLineItems(gomock.Any(), lineitems.ListLineItemsParams{
+				BillID:       pgtype.Int4{Int32: 1, Valid: true},
+				IncurredFrom: pgtype.Timestamptz{Time: from, Valid: true},
+				Currency:     pgtype.Text{String: "GEL", Valid: true},
+				Description:  pgtype.Text{String: "fee", Valid: true},
+				Limit:        3,
+			}).
+			Return(rows, nil)
+
+		filter := model.LineItemFilter{IncurredFrom: &from, Currency: "GEL", Description: "fee", Limit: 2}
+		page, err := business.ListLineItems(context.Background(), 1, filter)
+
+		require.NoError(t, err)
+		require.Len(t, page.LineItems, 2)
+		assert.Equal(t, int32(3), page.LineItems[0].ID)
+		assert.Equal(t, int32(2), page.LineItems[1].ID)
+		require.NotEmpty(t, page.NextCursor)
+
+		// The next page continues after the last line item returned
+		mockLineItemRepo.EXPECT().
+			ListLineItems(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params lineitems.ListLineItemsParams) ([]lineitems.LineItem, error) {
+				assert.True(t, params.CursorIncurredAt.Time.Equal(incurredAt(2).Time))
+				assert.Equal(t, pgtype.Int4{Int32: 2, Valid: true}, params.CursorID)
+				return rows[2:], nil
+			})
+
+		filter.Cursor = page.NextCursor
+		page, err = business.ListLineItems(context.Background(), 1, filter)
+
+		require.NoError(t, err)
+		require.Len(t, page.LineItems, 1)
+		assert.Equal(t, int32(1), page.LineItems[0].ID)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("invalid_cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+		business := &business{billRepo: mockBillRepo, lineItemRepo: lineitem_repo.NewMockQuerier(ctrl)}
+
+		mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(1)).Return(bills.Bill{ID: 1}, nil)
+
+		page, err := business.ListLineItems(context.Background(), 1, model.LineItemFilter{Cursor: "not a cursor", Limit: 10})
+
+		assert.Nil(t, page)
+		var e *errs.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, errs.InvalidArgument, e.Code)
+	})
+
+	t.Run("bill_not_found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+		business := &business{billRepo: mockBillRepo, lineItemRepo: lineitem_repo.NewMockQuerier(ctrl)}
+
+		mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(404)).Return(bills.Bill{}, pgx.ErrNoRows)
+
+		page, err := business.ListLineItems(context.Background(), 404, model.LineItemFilter{Limit: 10})
+
+		assert.Nil(t, page)
+		var e *errs.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, errs.NotFound, e.Code)
+	})
+}
+
+func TestGetBill_LineItemOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+	mockLineItemRepo := lineitem_repo.NewMockQuerier(ctrl)
+	business := &business{billRepo: mockBillRepo, lineItemRepo: mockLineItemRepo}
+
+	mockBillRepo.EXPECT().GetBill(gomock.Any(), int32(1)).Return(bills.Bill{ID: 1, Currency: "USD"}, nil).Times(2)
+
+	// Omitted line items are not loaded at all
+	result, err := business.GetBill(context.Background(), 1, model.GetBillOptions{OmitLineItems: true})
+	require.NoError(t, err)
+	assert.Empty(t, result.LineItems)
+
+	mockLineItemRepo.EXPECT().
+		ListLineItems(gomock.Any(), lineitems.ListLineItemsParams{BillID: pgtype.Int4{Int32: 1, Valid: true}, Limit: 2}).
+		Return([]lineitems.LineItem{
+			{ID: 2, BillID: pgtype.Int4{Int32: 1, Valid: true}, IncurredAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+			{ID: 1, BillID: pgtype.Int4{Int32: 1, Valid: true}, IncurredAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+		}, nil)
+
+	result, err = business.GetBill(context.Background(), 1, model.GetBillOptions{LineItemLimit: 1})
+	require.NoError(t, err)
+	require.Len(t, result.LineItems, 1)
+	assert.Equal(t, int32(2), result.LineItems[0].ID)
+	assert.NotEmpty(t, result.LineItemsNextCursor)
+}
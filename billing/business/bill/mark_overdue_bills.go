@@ -0,0 +1,75 @@
+package bill
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+// overdueBatchSize is the number of overdue bills flagged per query
+const overdueBatchSize = 100
+
+// MarkOverdueBills flags every closed bill whose due date is before now and which still has a
+// balance to pay, and returns the number of bills flagged. notify is called for each bill before
+// its flag is committed, so a bill is only left flagged once notify succeeded for it.
+func (b *business) MarkOverdueBills(ctx context.Context, now time.Time, notify func(context.Context, model.Bill) error) (int, error) {
+	marked := 0
+
+	for {
+		candidates, err := b.billRepo.ListOverdueBills(ctx, bills.ListOverdueBillsParams{
+			DueAt: pgtype.Timestamptz{Time: now, Valid: true},
+			Limit: overdueBatchSize,
+		})
+		if err != nil {
+			return marked, &errs.Error{Code: errs.Internal, Message: "failed to list overdue bills"}
+		}
+
+		for _, candidate := range candidates {
+			flagged := false
+			err := b.stateMachine.GetBillWithLock(ctx, candidate.ID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+				// A payment may have settled the bill since it was listed
+				if !isOverdue(currentBill, now) {
+					return nil
+				}
+				if err := b.stateMachine.MarkOverdueTx(ctx, tx, currentBill.ID, now, model.ActorSystem); err != nil {
+					return &errs.Error{Code: errs.Internal, Message: "failed to mark bill overdue"}
+				}
+
+				bill := convertDBBillToModel(currentBill)
+				bill.OverdueAt = &now
+				if err := notify(ctx, *bill); err != nil {
+					return err
+				}
+				flagged = true
+				return nil
+			})
+			if err != nil {
+				return marked, err
+			}
+			if flagged {
+				marked++
+			}
+		}
+
+		// Flagged and settled bills drop out of the listing, so the next query starts afresh
+		if len(candidates) < overdueBatchSize {
+			return marked, nil
+		}
+	}
+}
+
+// isOverdue reports whether a bill is closed, past its due date and not fully paid, and has
+// not been flagged yet
+func isOverdue(dbBill bills.Bill, now time.Time) bool {
+	return dbBill.Status == string(model.BillStatusClosed) &&
+		!dbBill.OverdueAt.Valid &&
+		dbBill.DueAt.Valid && dbBill.DueAt.Time.Before(now) &&
+		dbBill.AmountPaidCents < dbBill.TotalAmountCents.Int64
+}
@@ -0,0 +1,118 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/mocks/repository/bill_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+func TestMarkOverdueBills(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	dueAt := pgtype.Timestamptz{Time: now.Add(-24 * time.Hour), Valid: true}
+
+	overdueBill := func(id int32, paid int64) bills.Bill {
+		return bills.Bill{
+			ID:               id,
+			AccountID:        pgtype.Int4{Int32: 7, Valid: true},
+			Currency:         "USD",
+			Status:           string(model.BillStatusClosed),
+			TotalAmountCents: pgtype.Int8{Int64: 1000, Valid: true},
+			AmountPaidCents:  paid,
+			DueAt:            dueAt,
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		candidates     []bills.Bill
+		locked         map[int32]bills.Bill
+		expectMarked   []int32
+		notifyErr      error
+		expectedCount  int
+		expectedError  string
+		expectedEvents []int32
+	}{
+		{
+			name:           "flags_unpaid_bills",
+			candidates:     []bills.Bill{overdueBill(1, 0), overdueBill(2, 400)},
+			locked:         map[int32]bills.Bill{1: overdueBill(1, 0), 2: overdueBill(2, 400)},
+			expectMarked:   []int32{1, 2},
+			expectedCount:  2,
+			expectedEvents: []int32{1, 2},
+		},
+		{
+			name:          "skips_bill_paid_since_listing",
+			candidates:    []bills.Bill{overdueBill(3, 0)},
+			locked:        map[int32]bills.Bill{3: overdueBill(3, 1000)},
+			expectedCount: 0,
+		},
+		{
+			name:          "notify_failure_stops_the_run",
+			candidates:    []bills.Bill{overdueBill(4, 0), overdueBill(5, 0)},
+			locked:        map[int32]bills.Bill{4: overdueBill(4, 0)},
+			expectMarked:  []int32{4},
+			notifyErr:     errors.New("topic unavailable"),
+			expectedCount: 0,
+			expectedError: "topic unavailable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+			business := &business{billRepo: mockBillRepo, stateMachine: mockStateMachine}
+			txScope := &domain.TxScope{}
+
+			mockBillRepo.EXPECT().
+				ListOverdueBills(gomock.Any(), bills.ListOverdueBillsParams{
+					DueAt: pgtype.Timestamptz{Time: now, Valid: true},
+					Limit: overdueBatchSize,
+				}).
+				Return(tc.candidates, nil)
+			mockStateMachine.EXPECT().
+				GetBillWithLock(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+					return businessLogic(txScope, tc.locked[billID])
+				}).
+				Times(len(tc.locked))
+			for _, id := range tc.expectMarked {
+				mockStateMachine.EXPECT().MarkOverdueTx(gomock.Any(), txScope, id, now, model.ActorSystem).Return(nil)
+			}
+
+			var events []int32
+			count, err := business.MarkOverdueBills(context.Background(), now, func(ctx context.Context, bill model.Bill) error {
+				assert.Equal(t, int32(7), bill.AccountID)
+				assert.Equal(t, int64(1000)-bill.AmountPaidCents, bill.BalanceCents)
+				require.NotNil(t, bill.OverdueAt)
+				assert.Equal(t, now, *bill.OverdueAt)
+				events = append(events, bill.ID)
+				return tc.notifyErr
+			})
+
+			assert.Equal(t, tc.expectedCount, count)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedError, errorMessage(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEvents, events)
+		})
+	}
+}
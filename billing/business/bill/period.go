@@ -0,0 +1,29 @@
+package bill
+
+import (
+	"fmt"
+	"time"
+
+	"encore.app/billing/model"
+)
+
+// resolvePeriod returns the [start, end) boundaries of the calendar period containing t.
+// Boundaries are local midnight in loc, so a day spanning a DST change lasts 23 or 25 hours
+// and months and weeks keep their wall-clock alignment across transitions.
+func resolvePeriod(period model.BillPeriod, t time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	year, month, day := t.In(loc).Date()
+
+	switch period {
+	case model.BillPeriodCalendarMonth:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		return start, time.Date(year, month+1, 1, 0, 0, 0, 0, loc), nil
+	case model.BillPeriodISOWeek:
+		// time.Weekday counts from Sunday; ISO weeks start on Monday
+		offset := (int(time.Date(year, month, day, 0, 0, 0, 0, loc).Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, loc), time.Date(year, month, day-offset+7, 0, 0, 0, 0, loc), nil
+	case model.BillPeriodDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, loc), time.Date(year, month, day+1, 0, 0, 0, 0, loc), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown period %q", period)
+	}
+}
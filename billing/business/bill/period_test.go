@@ -0,0 +1,119 @@
+package bill
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"encore.app/billing/model"
+)
+
+func TestResolvePeriod(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		period        model.BillPeriod
+		at            time.Time
+		loc           *time.Location
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedHours float64
+	}{
+		{
+			name:          "calendar_month",
+			period:        model.BillPeriodCalendarMonth,
+			at:            time.Date(2025, 2, 14, 12, 0, 0, 0, time.UTC),
+			loc:           time.UTC,
+			expectedStart: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			expectedHours: 28 * 24,
+		},
+		{
+			name:   "calendar_month_uses_local_date",
+			period: model.BillPeriodCalendarMonth,
+			// Already March in Tokyo
+			at:            time.Date(2025, 2, 28, 20, 0, 0, 0, time.UTC),
+			loc:           tokyo,
+			expectedStart: time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo),
+			expectedEnd:   time.Date(2025, 4, 1, 0, 0, 0, 0, tokyo),
+			expectedHours: 31 * 24,
+		},
+		{
+			name:          "calendar_month_across_dst",
+			period:        model.BillPeriodCalendarMonth,
+			at:            time.Date(2025, 3, 20, 12, 0, 0, 0, newYork),
+			loc:           newYork,
+			expectedStart: time.Date(2025, 3, 1, 0, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 4, 1, 0, 0, 0, 0, newYork),
+			expectedHours: 31*24 - 1,
+		},
+		{
+			name:   "iso_week_from_sunday",
+			period: model.BillPeriodISOWeek,
+			// Sunday belongs to the week starting the previous Monday
+			at:            time.Date(2025, 1, 5, 23, 0, 0, 0, time.UTC),
+			loc:           time.UTC,
+			expectedStart: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			expectedHours: 7 * 24,
+		},
+		{
+			name:          "iso_week_from_monday",
+			period:        model.BillPeriodISOWeek,
+			at:            time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			loc:           time.UTC,
+			expectedStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+			expectedHours: 7 * 24,
+		},
+		{
+			name:          "day",
+			period:        model.BillPeriodDay,
+			at:            time.Date(2025, 6, 1, 3, 30, 0, 0, time.UTC),
+			loc:           newYork,
+			expectedStart: time.Date(2025, 5, 31, 0, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 6, 1, 0, 0, 0, 0, newYork),
+			expectedHours: 24,
+		},
+		{
+			name:          "day_spring_forward",
+			period:        model.BillPeriodDay,
+			at:            time.Date(2025, 3, 9, 12, 0, 0, 0, newYork),
+			loc:           newYork,
+			expectedStart: time.Date(2025, 3, 9, 0, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 3, 10, 0, 0, 0, 0, newYork),
+			expectedHours: 23,
+		},
+		{
+			name:          "day_fall_back",
+			period:        model.BillPeriodDay,
+			at:            time.Date(2025, 11, 2, 12, 0, 0, 0, newYork),
+			loc:           newYork,
+			expectedStart: time.Date(2025, 11, 2, 0, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 11, 3, 0, 0, 0, 0, newYork),
+			expectedHours: 25,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := resolvePeriod(tc.period, tc.at, tc.loc)
+
+			require.NoError(t, err)
+			assert.True(t, start.Equal(tc.expectedStart), "start %s, expected %s", start, tc.expectedStart)
+			assert.True(t, end.Equal(tc.expectedEnd), "end %s, expected %s", end, tc.expectedEnd)
+			assert.Equal(t, tc.expectedHours, end.Sub(start).Hours())
+		})
+	}
+}
+
+func TestResolvePeriod_UnknownPeriod(t *testing.T) {
+	_, _, err := resolvePeriod(model.BillPeriod("fortnight"), time.Now(), time.UTC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package bill
+
+import (
+	"math"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// maxQuantityPlaces matches the scale of line_items.quantity
+const maxQuantityPlaces = 6
+
+// parseQuantity parses a line item quantity, which defaults to 1 for flat amounts
+func parseQuantity(quantity string) (decimal.Decimal, error) {
+	if quantity == "" {
+		return decimal.NewFromInt(1), nil
+	}
+
+	q, err := decimal.NewFromString(quantity)
+	if err != nil || !q.IsPositive() {
+		return decimal.Decimal{}, &errs.Error{Code: errs.InvalidArgument, Message: "quantity must be a positive decimal"}
+	}
+	if q.Exponent() < -maxQuantityPlaces && !q.Equal(q.Truncate(maxQuantityPlaces)) {
+		return decimal.Decimal{}, &errs.Error{Code: errs.InvalidArgument, Message: "quantity supports at most 6 decimal places"}
+	}
+
+	return q, nil
+}
+
+// extendedAmount multiplies a unit amount by the quantity, rounding half away from zero to the cent
+func extendedAmount(unitAmountCents int64, quantity decimal.Decimal) (int64, error) {
+	amount := decimal.NewFromInt(unitAmountCents).Mul(quantity).Round(0)
+	if amount.Abs().GreaterThan(decimal.NewFromInt(math.MaxInt64)) {
+		return 0, &errs.Error{Code: errs.InvalidArgument, Message: "line item amount is too large"}
+	}
+
+	return amount.IntPart(), nil
+}
+
+// quantityToNumeric converts a quantity for storage
+func quantityToNumeric(quantity decimal.Decimal) pgtype.Numeric {
+	return pgtype.Numeric{Int: quantity.Coefficient(), Exp: quantity.Exponent(), Valid: true}
+}
+
+// numericToQuantity converts a stored quantity, treating a missing one as a single unit
+func numericToQuantity(quantity pgtype.Numeric) decimal.Decimal {
+	if !quantity.Valid || quantity.Int == nil {
+		return decimal.NewFromInt(1)
+	}
+
+	return decimal.NewFromBigInt(quantity.Int, quantity.Exp)
+}
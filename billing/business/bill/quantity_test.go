@@ -0,0 +1,69 @@
+package bill
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuantity(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{name: "default_single_unit", input: "", expected: "1"},
+		{name: "whole", input: "37", expected: "37"},
+		{name: "fractional", input: "1.5", expected: "1.5"},
+		{name: "trailing_zeros_within_scale", input: "2.5000000", expected: "2.5"},
+		{name: "zero", input: "0", expectErr: true},
+		{name: "negative", input: "-1", expectErr: true},
+		{name: "not_a_number", input: "many", expectErr: true},
+		{name: "too_many_places", input: "0.0000001", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quantity, err := parseQuantity(tc.input)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, quantity.String())
+		})
+	}
+}
+
+func TestExtendedAmount(t *testing.T) {
+	testCases := []struct {
+		name       string
+		unitAmount int64
+		quantity   string
+		expected   int64
+	}{
+		{name: "wire_transfers", unitAmount: 250, quantity: "37", expected: 9250},
+		{name: "half_cent_rounds_up", unitAmount: 333, quantity: "1.5", expected: 500},
+		{name: "negative_rounds_symmetrically", unitAmount: -333, quantity: "1.5", expected: -500},
+		{name: "fractional_units", unitAmount: 1000, quantity: "0.125", expected: 125},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, err := extendedAmount(tc.unitAmount, decimal.RequireFromString(tc.quantity))
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, amount)
+		})
+	}
+}
+
+func TestQuantityNumericRoundTrip(t *testing.T) {
+	quantity := decimal.RequireFromString("12.345")
+
+	assert.True(t, quantity.Equal(numericToQuantity(quantityToNumeric(quantity))))
+}
@@ -0,0 +1,122 @@
+package bill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/payments"
+)
+
+// RecordPayment records a payment against a closed bill. The payment may be partial and in any
+// enabled currency; it is converted into the bill currency and added to the amount paid.
+func (b *business) RecordPayment(ctx context.Context, billID int32, payment *model.Payment, actor string) (*model.Payment, error) {
+	if payment.AmountCents <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "payment amount must be positive"}
+	}
+
+	var result *model.Payment
+
+	err := b.stateMachine.GetBillWithLock(ctx, billID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if currentBill.Status != string(model.BillStatusClosed) {
+			return &errs.Error{Code: errs.FailedPrecondition, Message: "payments can only be recorded against closed bills"}
+		}
+
+		paidAt := payment.PaidAt
+		if paidAt.IsZero() {
+			paidAt = time.Now()
+		}
+
+		conversion, err := b.currencyService.ConvertAmount(ctx, payment.Currency, currentBill.Currency, payment.AmountCents, paidAt)
+		if err != nil {
+			return err
+		}
+
+		var metadataJSON []byte
+		if conversion.Metadata != nil {
+			metadataJSON, err = json.Marshal(conversion.Metadata)
+			if err != nil {
+				return &errs.Error{Code: errs.Internal, Message: "failed to marshal metadata"}
+			}
+		}
+
+		dbPayment, err := tx.Payments.CreatePayment(ctx, payments.CreatePaymentParams{
+			BillID:         currentBill.ID,
+			AmountCents:    conversion.ConvertedAmount,
+			Currency:       currentBill.Currency,
+			Metadata:       metadataJSON,
+			Reference:      pgtype.Text{String: payment.Reference, Valid: payment.Reference != ""},
+			PaidAt:         pgtype.Timestamptz{Time: paidAt, Valid: true},
+			IdempotencyKey: payment.IdempotencyKey,
+		})
+		if err != nil {
+			var e *pgconn.PgError
+			if errors.As(err, &e) && e.Code == pgerrcode.UniqueViolation {
+				return &errs.Error{Code: errs.AlreadyExists, Message: "payment already exists"}
+			}
+			return &errs.Error{Code: errs.Internal, Message: "failed to create payment"}
+		}
+
+		if _, err := tx.Bills.AddBillPayment(ctx, bills.AddBillPaymentParams{
+			ID:              currentBill.ID,
+			AmountPaidCents: dbPayment.AmountCents,
+		}); err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to update amount paid"}
+		}
+
+		result = convertDBPaymentToModel(dbPayment)
+		return b.recordAudit(ctx, tx, currentBill.ID, model.AuditActionPaymentRecorded, actor, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListPayments returns the payments recorded against a bill, oldest first
+func (b *business) ListPayments(ctx context.Context, billID int32) ([]model.Payment, error) {
+	dbPayments, err := b.paymentRepo.ListPaymentsByBill(ctx, billID)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get payments"}
+	}
+
+	result := make([]model.Payment, len(dbPayments))
+	for i, dbPayment := range dbPayments {
+		result[i] = *convertDBPaymentToModel(dbPayment)
+	}
+
+	return result, nil
+}
+
+// convertDBPaymentToModel converts database Payment to domain model Payment
+func convertDBPaymentToModel(dbPayment payments.Payment) *model.Payment {
+	payment := &model.Payment{
+		ID:             dbPayment.ID,
+		BillID:         dbPayment.BillID,
+		AmountCents:    dbPayment.AmountCents,
+		Currency:       dbPayment.Currency,
+		Reference:      dbPayment.Reference.String,
+		PaidAt:         dbPayment.PaidAt.Time,
+		IdempotencyKey: dbPayment.IdempotencyKey,
+		CreatedAt:      dbPayment.CreatedAt.Time,
+	}
+
+	if len(dbPayment.Metadata) > 0 {
+		var metadata model.CurrencyMetadata
+		if err := json.Unmarshal(dbPayment.Metadata, &metadata); err == nil {
+			payment.Metadata = &metadata
+		}
+	}
+
+	return payment
+}
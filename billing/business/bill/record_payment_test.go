@@ -0,0 +1,177 @@
+package bill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/mocks/repository/bill_repo"
+	"encore.app/billing/mocks/repository/payment_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/payments"
+)
+
+func TestRecordPayment(t *testing.T) {
+	const actor = "account:7"
+
+	testCases := []struct {
+		name          string
+		status        model.BillStatus
+		payment       *model.Payment
+		conversion    *model.ConversionResult
+		createErr     error
+		noLock        bool
+		expectedError string
+		expectedPaid  int64
+		expectedMeta  bool
+	}{
+		{
+			name:         "partial_payment_in_bill_currency",
+			status:       model.BillStatusClosed,
+			payment:      &model.Payment{AmountCents: 400, Currency: "USD", IdempotencyKey: "pay-1"},
+			conversion:   &model.ConversionResult{ConvertedAmount: 400},
+			expectedPaid: 400,
+		},
+		{
+			name:    "payment_in_other_currency_is_converted",
+			status:  model.BillStatusClosed,
+			payment: &model.Payment{AmountCents: 1000, Currency: "EUR", IdempotencyKey: "pay-2", PaidAt: time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC)},
+			conversion: &model.ConversionResult{
+				ConvertedAmount: 1100,
+				Metadata:        &model.CurrencyMetadata{OriginalAmountCents: 1000, OriginalCurrency: "EUR", ExchangeRate: 1.1},
+			},
+			expectedPaid: 1100,
+			expectedMeta: true,
+		},
+		{
+			name:          "duplicate_payment",
+			status:        model.BillStatusClosed,
+			payment:       &model.Payment{AmountCents: 400, Currency: "USD", IdempotencyKey: "pay-1"},
+			conversion:    &model.ConversionResult{ConvertedAmount: 400},
+			createErr:     &pgconn.PgError{Code: pgerrcode.UniqueViolation},
+			expectedError: "payment already exists",
+		},
+		{
+			name:          "bill_not_closed",
+			status:        model.BillStatusActive,
+			payment:       &model.Payment{AmountCents: 400, Currency: "USD", IdempotencyKey: "pay-3"},
+			expectedError: "payments can only be recorded against closed bills",
+		},
+		{
+			name:          "non_positive_amount",
+			payment:       &model.Payment{AmountCents: 0, Currency: "USD", IdempotencyKey: "pay-4"},
+			noLock:        true,
+			expectedError: "payment amount must be positive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+			mockBillRepo := bill_repo.NewMockQuerier(ctrl)
+			mockPaymentRepo := payment_repo.NewMockQuerier(ctrl)
+			business := &business{stateMachine: mockStateMachine, currencyService: mockCurrencyService}
+			txScope := &domain.TxScope{Bills: mockBillRepo, Payments: mockPaymentRepo}
+
+			if !tc.noLock {
+				mockStateMachine.EXPECT().
+					GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+						return businessLogic(txScope, bills.Bill{ID: billID, Status: string(tc.status), Currency: "USD"})
+					})
+			}
+			// A payment is converted at the rates of when it was received
+			paidAtMatcher := gomock.Any()
+			if !tc.payment.PaidAt.IsZero() {
+				paidAtMatcher = gomock.Eq(tc.payment.PaidAt)
+			}
+			if tc.conversion != nil {
+				mockCurrencyService.EXPECT().
+					ConvertAmount(gomock.Any(), tc.payment.Currency, "USD", tc.payment.AmountCents, paidAtMatcher).
+					Return(tc.conversion, nil)
+				mockPaymentRepo.EXPECT().
+					CreatePayment(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params payments.CreatePaymentParams) (payments.Payment, error) {
+						assert.Equal(t, "USD", params.Currency)
+						assert.Equal(t, tc.conversion.ConvertedAmount, params.AmountCents)
+						assert.True(t, params.PaidAt.Valid)
+						assert.Equal(t, tc.expectedMeta, len(params.Metadata) > 0)
+						return payments.Payment{
+							ID:             5,
+							BillID:         params.BillID,
+							AmountCents:    params.AmountCents,
+							Currency:       params.Currency,
+							Metadata:       params.Metadata,
+							PaidAt:         params.PaidAt,
+							IdempotencyKey: params.IdempotencyKey,
+						}, tc.createErr
+					})
+			}
+			if tc.expectedError == "" {
+				mockBillRepo.EXPECT().
+					AddBillPayment(gomock.Any(), bills.AddBillPaymentParams{ID: 1, AmountPaidCents: tc.expectedPaid}).
+					Return(bills.Bill{}, nil)
+				mockStateMachine.EXPECT().
+					RecordAuditTx(gomock.Any(), txScope, int32(1), model.AuditActionPaymentRecorded, actor, gomock.Any()).
+					Return(nil)
+			}
+
+			result, err := business.RecordPayment(context.Background(), 1, tc.payment, actor)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedError, errorMessage(err))
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPaid, result.AmountCents)
+			assert.Equal(t, "USD", result.Currency)
+			assert.Equal(t, tc.expectedMeta, result.Metadata != nil)
+		})
+	}
+}
+
+func TestConvertDBBillToModel_PaymentStatus(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         model.BillStatus
+		paid           int64
+		expectedStatus model.PaymentStatus
+	}{
+		{name: "unpaid", status: model.BillStatusClosed, paid: 0, expectedStatus: model.PaymentStatusUnpaid},
+		{name: "partially_paid", status: model.BillStatusClosed, paid: 400, expectedStatus: model.PaymentStatusPartiallyPaid},
+		{name: "paid", status: model.BillStatusClosed, paid: 1000, expectedStatus: model.PaymentStatusPaid},
+		{name: "overpaid", status: model.BillStatusClosed, paid: 1200, expectedStatus: model.PaymentStatusOverpaid},
+		{name: "open_bill_has_no_payment_status", status: model.BillStatusActive, paid: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bill := convertDBBillToModel(bills.Bill{
+				Status:           string(tc.status),
+				TotalAmountCents: pgtype.Int8{Int64: 1000, Valid: true},
+				AmountPaidCents:  tc.paid,
+			})
+
+			assert.Equal(t, tc.paid, bill.AmountPaidCents)
+			assert.Equal(t, 1000-tc.paid, bill.BalanceCents)
+			assert.Equal(t, tc.expectedStatus, bill.PaymentStatus)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package bill
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+const (
+	// Close reasons recorded when the operator gives no justification
+	reasonFinalizationRetried = "finalization_retried"
+	reasonReactivated         = "reactivated"
+)
+
+// RecoverBill moves a bill out of attention_required. Retrying finalization and force closing
+// both close the bill; reactivating returns it to active. Every step is recorded in the bill
+// history and audit log under the given actor.
+func (b *business) RecoverBill(ctx context.Context, id int32, recovery model.BillRecovery, actor string) error {
+	if recovery.Action == model.BillRecoveryForceClose {
+		if recovery.TotalAmountCents == nil {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "total_amount_cents is required to force close a bill"}
+		}
+		if recovery.Justification == "" {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "justification is required to force close a bill"}
+		}
+	}
+
+	var finalizeErr error
+	err := b.stateMachine.GetBillWithLock(ctx, id, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if currentBill.Status != string(model.BillStatusAttentionRequired) {
+			return &errs.Error{Code: errs.FailedPrecondition, Message: "only bills in attention_required can be recovered"}
+		}
+
+		switch recovery.Action {
+		case model.BillRecoveryRetry:
+			if err := b.stateMachine.UpdateBillTotalTx(ctx, tx, id, actor); err != nil {
+				finalizeErr = err
+				return err
+			}
+			return b.stateMachine.TransitionToClosedTx(ctx, tx, id, reasonOrDefault(recovery.Justification, reasonFinalizationRetried), actor)
+
+		case model.BillRecoveryForceClose:
+			total := *recovery.TotalAmountCents
+			if total < 0 && !currentBill.AllowNegativeTotal {
+				return &errs.Error{Code: errs.InvalidArgument, Message: "bill total cannot go negative"}
+			}
+			if err := b.stateMachine.OverrideBillTotalTx(ctx, tx, id, total, recovery.Justification, actor); err != nil {
+				return err
+			}
+			return b.stateMachine.TransitionToClosedTx(ctx, tx, id, recovery.Justification, actor)
+
+		case model.BillRecoveryReactivate:
+			return b.stateMachine.ReactivateTx(ctx, tx, id, reasonOrDefault(recovery.Justification, reasonReactivated), actor)
+
+		default:
+			return &errs.Error{Code: errs.InvalidArgument, Message: "invalid recovery action"}
+		}
+	})
+	if finalizeErr != nil {
+		// The bill stays in attention_required, so there is nothing to record beyond the error
+		return &errs.Error{Code: errs.Internal, Message: "failed to calculate final bill total: " + errorMessage(finalizeErr)}
+	}
+	return err
+}
+
+func reasonOrDefault(reason, fallback string) string {
+	if reason == "" {
+		return fallback
+	}
+	return reason
+}
@@ -0,0 +1,137 @@
+package bill
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+func TestRecoverBill(t *testing.T) {
+	const actor = "admin:alice"
+	total := int64(1250)
+	negative := int64(-300)
+
+	testCases := []struct {
+		name          string
+		status        model.BillStatus
+		allowNegative bool
+		recovery      model.BillRecovery
+		expect        func(m *state_machine.MockStateMachine, tx *domain.TxScope)
+		noLock        bool
+		expectedError string
+	}{
+		{
+			name:     "retry_closes_bill",
+			status:   model.BillStatusAttentionRequired,
+			recovery: model.BillRecovery{Action: model.BillRecoveryRetry},
+			expect: func(m *state_machine.MockStateMachine, tx *domain.TxScope) {
+				gomock.InOrder(
+					m.EXPECT().UpdateBillTotalTx(gomock.Any(), tx, int32(1), actor).Return(nil),
+					m.EXPECT().TransitionToClosedTx(gomock.Any(), tx, int32(1), "finalization_retried", actor).Return(nil),
+				)
+			},
+		},
+		{
+			name:     "retry_fails_again",
+			status:   model.BillStatusAttentionRequired,
+			recovery: model.BillRecovery{Action: model.BillRecoveryRetry},
+			expect: func(m *state_machine.MockStateMachine, tx *domain.TxScope) {
+				m.EXPECT().UpdateBillTotalTx(gomock.Any(), tx, int32(1), actor).Return(errors.New("database error"))
+			},
+			expectedError: "failed to calculate final bill total: database error",
+		},
+		{
+			name:     "force_close_overrides_total",
+			status:   model.BillStatusAttentionRequired,
+			recovery: model.BillRecovery{Action: model.BillRecoveryForceClose, TotalAmountCents: &total, Justification: "agreed with customer"},
+			expect: func(m *state_machine.MockStateMachine, tx *domain.TxScope) {
+				gomock.InOrder(
+					m.EXPECT().OverrideBillTotalTx(gomock.Any(), tx, int32(1), total, "agreed with customer", actor).Return(nil),
+					m.EXPECT().TransitionToClosedTx(gomock.Any(), tx, int32(1), "agreed with customer", actor).Return(nil),
+				)
+			},
+		},
+		{
+			name:          "force_close_requires_total",
+			recovery:      model.BillRecovery{Action: model.BillRecoveryForceClose, Justification: "agreed with customer"},
+			noLock:        true,
+			expectedError: "total_amount_cents is required to force close a bill",
+		},
+		{
+			name:          "force_close_requires_justification",
+			recovery:      model.BillRecovery{Action: model.BillRecoveryForceClose, TotalAmountCents: &total},
+			noLock:        true,
+			expectedError: "justification is required to force close a bill",
+		},
+		{
+			name:          "force_close_rejects_negative_total",
+			status:        model.BillStatusAttentionRequired,
+			recovery:      model.BillRecovery{Action: model.BillRecoveryForceClose, TotalAmountCents: &negative, Justification: "refund"},
+			expectedError: "bill total cannot go negative",
+		},
+		{
+			name:          "force_close_allows_negative_total_when_bill_does",
+			status:        model.BillStatusAttentionRequired,
+			allowNegative: true,
+			recovery:      model.BillRecovery{Action: model.BillRecoveryForceClose, TotalAmountCents: &negative, Justification: "refund"},
+			expect: func(m *state_machine.MockStateMachine, tx *domain.TxScope) {
+				m.EXPECT().OverrideBillTotalTx(gomock.Any(), tx, int32(1), negative, "refund", actor).Return(nil)
+				m.EXPECT().TransitionToClosedTx(gomock.Any(), tx, int32(1), "refund", actor).Return(nil)
+			},
+		},
+		{
+			name:     "reactivate_returns_bill_to_active",
+			status:   model.BillStatusAttentionRequired,
+			recovery: model.BillRecovery{Action: model.BillRecoveryReactivate, Justification: "missing usage"},
+			expect: func(m *state_machine.MockStateMachine, tx *domain.TxScope) {
+				m.EXPECT().ReactivateTx(gomock.Any(), tx, int32(1), "missing usage", actor).Return(nil)
+			},
+		},
+		{
+			name:          "bill_not_in_attention_required",
+			status:        model.BillStatusActive,
+			recovery:      model.BillRecovery{Action: model.BillRecoveryReactivate},
+			expectedError: "only bills in attention_required can be recovered",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			business := &business{stateMachine: mockStateMachine}
+			txScope := &domain.TxScope{}
+
+			if !tc.noLock {
+				mockStateMachine.EXPECT().
+					GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+						return businessLogic(txScope, bills.Bill{ID: billID, Status: string(tc.status), AllowNegativeTotal: tc.allowNegative})
+					})
+			}
+			if tc.expect != nil {
+				tc.expect(mockStateMachine, txScope)
+			}
+
+			err := business.RecoverBill(context.Background(), 1, tc.recovery, actor)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.expectedError, errorMessage(err))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package bill
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/beta/errs"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+// UpdateBillPeriod extends or shortens the billing period of a pending or active bill by moving
+// its end time. The new end must be after the period start and still in the future; a bill
+// created for a calendar period keeps only the explicit end time afterwards.
+func (b *business) UpdateBillPeriod(ctx context.Context, id int32, endTime time.Time, reason, actor string) error {
+	if !endTime.After(time.Now()) {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "end_time must be in the future"}
+	}
+
+	return b.stateMachine.GetBillWithLock(ctx, id, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if currentBill.Status != string(model.BillStatusPending) && currentBill.Status != string(model.BillStatusActive) {
+			return &errs.Error{Code: errs.FailedPrecondition, Message: "only pending or active bills can change their period"}
+		}
+		if !endTime.After(currentBill.StartTime.Time) {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "end_time must be after start_time"}
+		}
+
+		return b.stateMachine.UpdateEndTimeTx(ctx, tx, id, endTime, reason, actor)
+	})
+}
@@ -0,0 +1,73 @@
+package bill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+)
+
+func TestUpdateBillPeriod(t *testing.T) {
+	start := time.Now().Add(-24 * time.Hour)
+	future := time.Now().Add(48 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		status        model.BillStatus
+		startTime     time.Time
+		endTime       time.Time
+		noLock        bool
+		expectUpdate  bool
+		expectedError string
+	}{
+		{name: "extend_active_bill", status: model.BillStatusActive, startTime: start, endTime: future, expectUpdate: true},
+		{name: "shorten_pending_bill", status: model.BillStatusPending, startTime: time.Now().Add(time.Hour), endTime: time.Now().Add(2 * time.Hour), expectUpdate: true},
+		{name: "end_time_in_past", endTime: time.Now().Add(-time.Minute), noLock: true, expectedError: "end_time must be in the future"},
+		{name: "end_time_before_start", status: model.BillStatusPending, startTime: future.Add(time.Hour), endTime: future, expectedError: "end_time must be after start_time"},
+		{name: "closed_bill", status: model.BillStatusClosed, startTime: start, endTime: future, expectedError: "only pending or active bills can change their period"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			business := &business{stateMachine: mockStateMachine}
+			txScope := &domain.TxScope{}
+
+			if !tc.noLock {
+				mockStateMachine.EXPECT().
+					GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+						return businessLogic(txScope, bills.Bill{
+							ID:        billID,
+							Status:    string(tc.status),
+							StartTime: pgtype.Timestamptz{Time: tc.startTime, Valid: true},
+						})
+					})
+			}
+			if tc.expectUpdate {
+				mockStateMachine.EXPECT().
+					UpdateEndTimeTx(gomock.Any(), txScope, int32(1), tc.endTime, "contract extended", "admin").
+					Return(nil)
+			}
+
+			err := business.UpdateBillPeriod(context.Background(), 1, tc.endTime, "contract extended", "admin")
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tc.expectedError, errorMessage(err))
+			}
+		})
+	}
+}
@@ -3,15 +3,22 @@ package bill
 import (
 	"context"
 
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
 	"encore.app/billing/repository/bills"
 )
 
 // UpdateBillTotal recalculates and updates the total amount for a bill based on its line items
 // Uses row-level locking to prevent race conditions when multiple line items are added concurrently
-func (b *business) UpdateBillTotal(ctx context.Context, billID int32) error {
-	return b.stateMachine.GetBillWithLock(ctx, billID, func(currentBill bills.Bill) error {
+func (b *business) UpdateBillTotal(ctx context.Context, billID int32, actor string) error {
+	return b.stateMachine.GetBillWithLock(ctx, billID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		// A cancelled bill owes nothing whatever its line items add up to
+		if currentBill.Status == string(model.BillStatusCancelled) {
+			return nil
+		}
+
 		// The actual total calculation happens in the database using UpdateBillTotal
 		// This ensures the calculation is atomic and uses the latest line items
-		return b.stateMachine.UpdateBillTotalTx(ctx, billID)
+		return b.stateMachine.UpdateBillTotalTx(ctx, tx, billID, actor)
 	})
 }
@@ -0,0 +1,178 @@
+package bill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+// UpdateLineItem corrects the amount, quantity, currency or description of a line item on an
+// active bill. The original item is voided and kept, and a corrected item referencing it is
+// added: a new unit price or currency is converted into the bill currency again, the extended
+// amount is recomputed, and the bill total is recalculated in the same transaction.
+func (b *business) UpdateLineItem(ctx context.Context, billID, lineItemID int32, update *model.LineItemUpdate, actor string) (*model.LineItem, error) {
+	var result *model.LineItem
+
+	err := b.stateMachine.GetBillWithLock(ctx, billID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if currentBill.Status != string(model.BillStatusActive) {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "bill is not in active state for editing line items"}
+		}
+
+		dbLineItem, err := getActiveLineItem(ctx, tx, billID, lineItemID)
+		if err != nil {
+			return err
+		}
+
+		params := lineitems.CreateLineItemParams{
+			BillID:             dbLineItem.BillID,
+			Currency:           dbLineItem.Currency,
+			Description:        dbLineItem.Description,
+			IncurredAt:         dbLineItem.IncurredAt,
+			ReferenceID:        dbLineItem.ReferenceID,
+			Metadata:           dbLineItem.Metadata,
+			IdempotencyKey:     correctionIdempotencyKey(lineItemID),
+			Kind:               dbLineItem.Kind,
+			UnitAmountCents:    dbLineItem.UnitAmountCents,
+			ReplacesLineItemID: pgtype.Int4{Int32: lineItemID, Valid: true},
+		}
+		if update.Description != nil {
+			params.Description = pgtype.Text{String: *update.Description, Valid: true}
+		}
+
+		quantity := numericToQuantity(dbLineItem.Quantity)
+		if update.Quantity != nil {
+			if quantity, err = parseQuantity(*update.Quantity); err != nil {
+				return err
+			}
+		}
+
+		// A flat amount replaces the unit price and resets the quantity to a single unit
+		unitAmountCents := update.UnitAmountCents
+		if update.AmountCents != nil {
+			unitAmountCents = update.AmountCents
+			quantity = decimal.NewFromInt(1)
+		}
+
+		reconverted := unitAmountCents != nil || update.Currency != nil
+		if reconverted {
+			// Start from the unit price as originally submitted, not the converted one
+			current := convertDBLineItemToModel(dbLineItem)
+			amountCents, currencyCode := current.UnitAmountCents, current.Currency
+			if current.Metadata != nil {
+				amountCents, currencyCode = current.Metadata.OriginalAmountCents, current.Metadata.OriginalCurrency
+			}
+			if unitAmountCents != nil {
+				if err := validateLineItemAmount(current.Kind, *unitAmountCents); err != nil {
+					return err
+				}
+				amountCents = current.Kind.SignedAmount(*unitAmountCents)
+			}
+			if update.Currency != nil {
+				currencyCode = *update.Currency
+			}
+
+			// The rates of when the item was incurred still apply to the corrected amount
+			conversion, err := b.currencyService.ConvertAmount(ctx, currencyCode, currentBill.Currency, amountCents, dbLineItem.IncurredAt.Time)
+			if err != nil {
+				return err
+			}
+
+			params.UnitAmountCents = conversion.ConvertedAmount
+			params.Metadata = nil
+			if conversion.Metadata != nil {
+				params.Metadata, err = json.Marshal(conversion.Metadata)
+				if err != nil {
+					return &errs.Error{Code: errs.Internal, Message: "failed to marshal metadata"}
+				}
+			}
+		}
+
+		params.Quantity = quantityToNumeric(quantity)
+		if params.AmountCents, err = extendedAmount(params.UnitAmountCents, quantity); err != nil {
+			return err
+		}
+
+		voided, err := tx.LineItems.VoidLineItem(ctx, lineitems.VoidLineItemParams{
+			ID:         lineItemID,
+			VoidReason: pgtype.Text{String: lineItemCorrectedReason, Valid: true},
+		})
+		if err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to void line item"}
+		}
+
+		corrected, err := tx.LineItems.CreateLineItem(ctx, params)
+		if err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to create corrected line item"}
+		}
+
+		if err := checkBillTotal(ctx, tx, currentBill); err != nil {
+			return err
+		}
+
+		result = convertDBLineItemToModel(corrected)
+		result.SetBillWorkflowID(currentBill.WorkflowID.String)
+
+		err = b.recordAudit(ctx, tx, billID, model.AuditActionLineItemUpdated, actor, lineItemChange{
+			Before: convertDBLineItemToModel(voided),
+			After:  result,
+		})
+		if err != nil {
+			return err
+		}
+		if err := b.recordConversion(ctx, tx, result, actor); err != nil {
+			return err
+		}
+
+		if err := b.stateMachine.UpdateBillTotalTx(ctx, tx, billID, actor); err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to update bill total"}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// lineItemCorrectedReason is the void reason of a line item replaced by a correction
+const lineItemCorrectedReason = "corrected"
+
+// correctionIdempotencyKey keys the item that corrects the given line item. A line item is
+// voided when corrected, so it can be corrected only once.
+func correctionIdempotencyKey(lineItemID int32) string {
+	return fmt.Sprintf("correction:%d", lineItemID)
+}
+
+// getActiveLineItem loads a line item of the given bill that has not been voided
+func getActiveLineItem(ctx context.Context, tx *domain.TxScope, billID, lineItemID int32) (lineitems.LineItem, error) {
+	dbLineItem, err := tx.LineItems.GetLineItem(ctx, lineItemID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return lineitems.LineItem{}, &errs.Error{Code: errs.NotFound, Message: "line item not found"}
+		}
+		return lineitems.LineItem{}, &errs.Error{Code: errs.Internal, Message: "failed to get line item"}
+	}
+
+	if dbLineItem.BillID.Int32 != billID {
+		return lineitems.LineItem{}, &errs.Error{Code: errs.NotFound, Message: "line item not found"}
+	}
+
+	if dbLineItem.VoidedAt.Valid {
+		return lineitems.LineItem{}, &errs.Error{Code: errs.FailedPrecondition, Message: "line item is voided"}
+	}
+
+	return dbLineItem, nil
+}
@@ -0,0 +1,245 @@
+package bill
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/mocks/repository/lineitem_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+func int64Ptr(v int64) *int64    { return &v }
+func stringPtr(v string) *string { return &v }
+
+func TestUpdateLineItem(t *testing.T) {
+	gelMetadata, _ := json.Marshal(model.CurrencyMetadata{OriginalAmountCents: 1000, OriginalCurrency: "GEL", ExchangeRate: 0.377})
+	// Corrections are converted at the rates of when the item was incurred
+	incurredAt := pgtype.Timestamptz{Time: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	testCases := []struct {
+		name             string
+		billStatus       string
+		update           *model.LineItemUpdate
+		existing         lineitems.LineItem
+		existingErr      error
+		expectConversion []any
+		conversion       *model.ConversionResult
+		expectedAmount   int64
+		expectedError    string
+	}{
+		{
+			name:       "amount_change_reconverts_from_original_currency",
+			billStatus: string(model.BillStatusActive),
+			update:     &model.LineItemUpdate{AmountCents: int64Ptr(2000)},
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, Kind: "charge", AmountCents: 377, UnitAmountCents: 377, Currency: "USD", Metadata: gelMetadata,
+				IncurredAt: incurredAt,
+			},
+			expectConversion: []any{"GEL", "USD", int64(2000)},
+			conversion: &model.ConversionResult{
+				ConvertedAmount: 754,
+				Metadata:        &model.CurrencyMetadata{OriginalAmountCents: 2000, OriginalCurrency: "GEL", ExchangeRate: 0.377},
+			},
+			expectedAmount: 754,
+		},
+		{
+			name:       "credit_amount_is_submitted_positive",
+			billStatus: string(model.BillStatusActive),
+			update:     &model.LineItemUpdate{AmountCents: int64Ptr(500)},
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, Kind: "credit", AmountCents: -1000, UnitAmountCents: -1000, Currency: "USD",
+			},
+			expectConversion: []any{"USD", "USD", int64(-500)},
+			conversion:       &model.ConversionResult{ConvertedAmount: -500},
+			expectedAmount:   -500,
+		},
+		{
+			name:       "quantity_change_recomputes_extended_amount",
+			billStatus: string(model.BillStatusActive),
+			update:     &model.LineItemUpdate{Quantity: stringPtr("37")},
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, Kind: "charge", AmountCents: 250, UnitAmountCents: 250, Currency: "USD",
+			},
+			expectedAmount: 9250,
+		},
+		{
+			name:       "currency_change_reconverts_existing_amount",
+			billStatus: string(model.BillStatusActive),
+			update:     &model.LineItemUpdate{Currency: stringPtr("USD")},
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, Kind: "charge", AmountCents: 377, UnitAmountCents: 377, Currency: "USD", Metadata: gelMetadata,
+			},
+			expectConversion: []any{"USD", "USD", int64(1000)},
+			conversion:       &model.ConversionResult{ConvertedAmount: 1000},
+			expectedAmount:   1000,
+		},
+		{
+			name:       "description_only_keeps_amount",
+			billStatus: string(model.BillStatusActive),
+			update:     &model.LineItemUpdate{Description: stringPtr("renamed")},
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, Kind: "charge", AmountCents: 377, UnitAmountCents: 377, Currency: "USD", Metadata: gelMetadata,
+			},
+			expectedAmount: 377,
+		},
+		{
+			name:          "bill_not_active",
+			billStatus:    string(model.BillStatusClosed),
+			update:        &model.LineItemUpdate{Description: stringPtr("renamed")},
+			expectedError: "bill is not in active state",
+		},
+		{
+			name:          "line_item_not_found",
+			billStatus:    string(model.BillStatusActive),
+			update:        &model.LineItemUpdate{Description: stringPtr("renamed")},
+			existingErr:   pgx.ErrNoRows,
+			expectedError: "line item not found",
+		},
+		{
+			name:       "line_item_of_other_bill",
+			billStatus: string(model.BillStatusActive),
+			update:     &model.LineItemUpdate{Description: stringPtr("renamed")},
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 2, Valid: true}, AmountCents: 377, UnitAmountCents: 377, Currency: "USD",
+			},
+			expectedError: "line item not found",
+		},
+		{
+			name:       "line_item_voided",
+			billStatus: string(model.BillStatusActive),
+			update:     &model.LineItemUpdate{Description: stringPtr("renamed")},
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 377, UnitAmountCents: 377, Currency: "USD",
+				VoidedAt: pgtype.Timestamptz{Valid: true},
+			},
+			expectedError: "line item is voided",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			mockCurrencyService := currency_business.NewMockBusiness(ctrl)
+			mockLineItemRepo := lineitem_repo.NewMockQuerier(ctrl)
+			business := &business{
+				stateMachine:    mockStateMachine,
+				currencyService: mockCurrencyService,
+			}
+			tx := &domain.TxScope{LineItems: mockLineItemRepo}
+
+			mockStateMachine.EXPECT().
+				GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+					return businessLogic(tx, bills.Bill{
+						ID:         1,
+						Status:     tc.billStatus,
+						Currency:   "USD",
+						WorkflowID: pgtype.Text{String: "workflow-123", Valid: true},
+					})
+				})
+
+			if tc.billStatus == string(model.BillStatusActive) {
+				mockLineItemRepo.EXPECT().GetLineItem(gomock.Any(), int32(5)).Return(tc.existing, tc.existingErr)
+			}
+			if tc.expectConversion != nil {
+				mockCurrencyService.EXPECT().
+					ConvertAmount(gomock.Any(), tc.expectConversion[0], tc.expectConversion[1], tc.expectConversion[2], tc.existing.IncurredAt.Time).
+					Return(tc.conversion, nil)
+			}
+			if tc.expectedError == "" {
+				mockLineItemRepo.EXPECT().
+					VoidLineItem(gomock.Any(), lineitems.VoidLineItemParams{
+						ID:         5,
+						VoidReason: pgtype.Text{String: lineItemCorrectedReason, Valid: true},
+					}).
+					DoAndReturn(func(_ context.Context, _ lineitems.VoidLineItemParams) (lineitems.LineItem, error) {
+						voided := tc.existing
+						voided.VoidedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+						voided.VoidReason = pgtype.Text{String: lineItemCorrectedReason, Valid: true}
+						return voided, nil
+					})
+				mockLineItemRepo.EXPECT().
+					CreateLineItem(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params lineitems.CreateLineItemParams) (lineitems.LineItem, error) {
+						assert.Equal(t, pgtype.Int4{Int32: 5, Valid: true}, params.ReplacesLineItemID)
+						assert.Equal(t, "correction:5", params.IdempotencyKey)
+						assert.Equal(t, tc.existing.IncurredAt, params.IncurredAt)
+						assert.Equal(t, tc.expectedAmount, params.AmountCents)
+						return lineitems.LineItem{
+							ID:                 6,
+							BillID:             params.BillID,
+							Kind:               params.Kind,
+							AmountCents:        params.AmountCents,
+							UnitAmountCents:    params.UnitAmountCents,
+							Currency:           params.Currency,
+							Description:        params.Description,
+							Metadata:           params.Metadata,
+							IncurredAt:         params.IncurredAt,
+							IdempotencyKey:     params.IdempotencyKey,
+							ReplacesLineItemID: params.ReplacesLineItemID,
+						}, nil
+					})
+				mockLineItemRepo.EXPECT().GetTotalAmountByBill(gomock.Any(), pgtype.Int4{Int32: 1, Valid: true}).Return(int64(1000), nil)
+				mockStateMachine.EXPECT().UpdateBillTotalTx(gomock.Any(), tx, int32(1), "admin").Return(nil)
+				mockStateMachine.EXPECT().
+					RecordAuditTx(gomock.Any(), tx, int32(1), model.AuditActionLineItemUpdated, "admin", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *domain.TxScope, _ int32, _ model.AuditAction, _ string, payload any) error {
+						change := payload.(lineItemChange)
+						assert.Equal(t, int32(5), change.Before.ID)
+						assert.NotNil(t, change.Before.VoidedAt)
+						assert.Equal(t, tc.existing.AmountCents, change.Before.AmountCents)
+						assert.Equal(t, int32(6), change.After.ID)
+						assert.Equal(t, tc.expectedAmount, change.After.AmountCents)
+						return nil
+					})
+				// The corrected item carries a conversion of its own, reused or redone
+				if tc.conversion != nil && tc.conversion.Metadata != nil || tc.conversion == nil && tc.existing.Metadata != nil {
+					mockStateMachine.EXPECT().
+						RecordAuditTx(gomock.Any(), tx, int32(1), model.AuditActionCurrencyConverted, "admin", gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ *domain.TxScope, _ int32, _ model.AuditAction, _ string, payload any) error {
+							audit := payload.(conversionAudit)
+							assert.Equal(t, int32(6), audit.LineItemID)
+							assert.Equal(t, "USD", audit.Currency)
+							return nil
+						})
+				}
+			}
+
+			result, err := business.UpdateLineItem(context.Background(), 1, 5, tc.update, "admin")
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAmount, result.AmountCents)
+			assert.Equal(t, "workflow-123", result.BillWorkflowID)
+			assert.Equal(t, int32(6), result.ID)
+			assert.Equal(t, int32(5), *result.ReplacesLineItemID)
+			if tc.update.Description != nil {
+				assert.Equal(t, *tc.update.Description, result.Description)
+			}
+			if tc.conversion != nil {
+				assert.Equal(t, tc.conversion.Metadata, result.Metadata)
+			}
+		})
+	}
+}
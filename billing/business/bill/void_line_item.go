@@ -0,0 +1,58 @@
+package bill
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+// VoidLineItem marks a line item on an active bill as voided. The record is kept for audit
+// but no longer counts towards the bill total, which is recalculated in the same transaction.
+func (b *business) VoidLineItem(ctx context.Context, billID, lineItemID int32, reason, actor string) (*model.LineItem, error) {
+	var result *model.LineItem
+
+	err := b.stateMachine.GetBillWithLock(ctx, billID, func(tx *domain.TxScope, currentBill bills.Bill) error {
+		if currentBill.Status != string(model.BillStatusActive) {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "bill is not in active state for voiding line items"}
+		}
+
+		if _, err := getActiveLineItem(ctx, tx, billID, lineItemID); err != nil {
+			return err
+		}
+
+		voided, err := tx.LineItems.VoidLineItem(ctx, lineitems.VoidLineItemParams{
+			ID:         lineItemID,
+			VoidReason: pgtype.Text{String: reason, Valid: reason != ""},
+		})
+		if err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to void line item"}
+		}
+
+		if err := checkBillTotal(ctx, tx, currentBill); err != nil {
+			return err
+		}
+
+		result = convertDBLineItemToModel(voided)
+		result.SetBillWorkflowID(currentBill.WorkflowID.String)
+		if err := b.recordAudit(ctx, tx, billID, model.AuditActionLineItemVoided, actor, result); err != nil {
+			return err
+		}
+
+		if err := b.stateMachine.UpdateBillTotalTx(ctx, tx, billID, actor); err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to update bill total"}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package bill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/mocks/domain/state_machine"
+	"encore.app/billing/mocks/repository/lineitem_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/lineitems"
+)
+
+func TestVoidLineItem(t *testing.T) {
+	voidedAt := time.Now()
+
+	testCases := []struct {
+		name          string
+		billStatus    string
+		existing      lineitems.LineItem
+		expectedError string
+	}{
+		{
+			name:       "happy_case",
+			billStatus: string(model.BillStatusActive),
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 500, Currency: "USD",
+			},
+		},
+		{
+			name:          "bill_not_active",
+			billStatus:    string(model.BillStatusPending),
+			expectedError: "bill is not in active state",
+		},
+		{
+			name:       "already_voided",
+			billStatus: string(model.BillStatusActive),
+			existing: lineitems.LineItem{
+				ID: 5, BillID: pgtype.Int4{Int32: 1, Valid: true}, AmountCents: 500, Currency: "USD",
+				VoidedAt: pgtype.Timestamptz{Time: voidedAt, Valid: true},
+			},
+			expectedError: "line item is voided",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStateMachine := state_machine.NewMockStateMachine(ctrl)
+			mockLineItemRepo := lineitem_repo.NewMockQuerier(ctrl)
+			business := &business{stateMachine: mockStateMachine}
+			tx := &domain.TxScope{LineItems: mockLineItemRepo}
+
+			mockStateMachine.EXPECT().
+				GetBillWithLock(gomock.Any(), int32(1), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
+					return businessLogic(tx, bills.Bill{ID: 1, Status: tc.billStatus, Currency: "USD"})
+				})
+
+			if tc.billStatus == string(model.BillStatusActive) {
+				mockLineItemRepo.EXPECT().GetLineItem(gomock.Any(), int32(5)).Return(tc.existing, nil)
+			}
+			if tc.expectedError == "" {
+				mockLineItemRepo.EXPECT().
+					VoidLineItem(gomock.Any(), lineitems.VoidLineItemParams{ID: 5, VoidReason: pgtype.Text{String: "duplicate", Valid: true}}).
+					DoAndReturn(func(_ context.Context, params lineitems.VoidLineItemParams) (lineitems.LineItem, error) {
+						voided := tc.existing
+						voided.VoidedAt = pgtype.Timestamptz{Time: voidedAt, Valid: true}
+						voided.VoidReason = params.VoidReason
+						return voided, nil
+					})
+				mockLineItemRepo.EXPECT().GetTotalAmountByBill(gomock.Any(), pgtype.Int4{Int32: 1, Valid: true}).Return(int64(1000), nil)
+				mockStateMachine.EXPECT().UpdateBillTotalTx(gomock.Any(), tx, int32(1), "admin").Return(nil)
+				mockStateMachine.EXPECT().
+					RecordAuditTx(gomock.Any(), tx, int32(1), model.AuditActionLineItemVoided, "admin", gomock.Any()).
+					Return(nil)
+			}
+
+			result, err := business.VoidLineItem(context.Background(), 1, 5, "duplicate", "admin")
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			if assert.NotNil(t, result.VoidedAt) {
+				assert.True(t, result.VoidedAt.Equal(voidedAt))
+			}
+			assert.Equal(t, "duplicate", result.VoidReason)
+			// The record is kept with its amount; only the bill total excludes it
+			assert.Equal(t, int64(500), result.AmountCents)
+		})
+	}
+}
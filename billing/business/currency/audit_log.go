@@ -0,0 +1,74 @@
+package currency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+// currencyChange is the audit payload of a currency update
+type currencyChange struct {
+	Previous *model.CurrencyInfo `json:"previous"`
+	Current  *model.CurrencyInfo `json:"current"`
+}
+
+// recordAudit chains a new entry onto the last one of the currency and moves the currency's
+// head hash to it. The caller holds the currency row lock, so entries of a currency are
+// appended one at a time.
+func recordAudit(ctx context.Context, repo currencies.Querier, code string, action model.AuditAction, actor string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to encode audit entry"}
+	}
+
+	seq, prevHash := int32(1), ""
+	last, err := repo.GetLastCurrencyAuditEntry(ctx, code)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return &errs.Error{Code: errs.Internal, Message: "failed to get last audit entry"}
+	default:
+		seq, prevHash = last.Seq+1, last.Hash
+	}
+
+	// Postgres keeps microseconds, so the hash is taken over the time as it will be read back
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+	params := currencies.CreateCurrencyAuditEntryParams{
+		CurrencyCode: code,
+		Seq:          seq,
+		Action:       string(action),
+		Actor:        actor,
+		Payload:      string(data),
+		PrevHash:     prevHash,
+		CreatedAt:    pgtype.Timestamptz{Time: createdAt, Valid: true},
+	}
+	params.Hash = hashAuditEntry(code, seq, params.Action, actor, params.Payload, prevHash, createdAt)
+
+	if _, err := repo.CreateCurrencyAuditEntry(ctx, params); err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to record audit entry"}
+	}
+
+	if err := repo.SetCurrencyAuditHead(ctx, currencies.SetCurrencyAuditHeadParams{CurrencyCode: code, HeadHash: params.Hash}); err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to record audit entry"}
+	}
+	return nil
+}
+
+// hashAuditEntry returns the hex SHA-256 of an entry, encoded as a JSON array in the same way as
+// the entries of a bill's audit chain
+func hashAuditEntry(code string, seq int32, action, actor, payload, prevHash string, createdAt time.Time) string {
+	encoded, _ := json.Marshal([]any{code, seq, action, actor, payload, prevHash, createdAt.UnixMicro()})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
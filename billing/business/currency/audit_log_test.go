@@ -0,0 +1,61 @@
+package currency
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+// auditingRepo keeps the audit entries written through it in memory and passes every other
+// query on to the wrapped repository
+type auditingRepo struct {
+	currencies.Querier
+
+	entries  []currencies.CreateCurrencyAuditEntryParams
+	headHash string
+}
+
+func (r *auditingRepo) GetLastCurrencyAuditEntry(ctx context.Context, code string) (currencies.CurrencyAuditEntry, error) {
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if e := r.entries[i]; e.CurrencyCode == code {
+			return currencies.CurrencyAuditEntry{CurrencyCode: e.CurrencyCode, Seq: e.Seq, Hash: e.Hash}, nil
+		}
+	}
+	return currencies.CurrencyAuditEntry{}, pgx.ErrNoRows
+}
+
+func (r *auditingRepo) CreateCurrencyAuditEntry(ctx context.Context, arg currencies.CreateCurrencyAuditEntryParams) (currencies.CurrencyAuditEntry, error) {
+	r.entries = append(r.entries, arg)
+	return currencies.CurrencyAuditEntry{CurrencyCode: arg.CurrencyCode, Seq: arg.Seq, Hash: arg.Hash}, nil
+}
+
+func (r *auditingRepo) SetCurrencyAuditHead(ctx context.Context, arg currencies.SetCurrencyAuditHeadParams) error {
+	r.headHash = arg.HeadHash
+	return nil
+}
+
+func TestRecordAudit_ChainsEntries(t *testing.T) {
+	repo := &auditingRepo{}
+	for i := 0; i < 3; i++ {
+		err := recordAudit(context.Background(), repo, "GEL", model.AuditActionCurrencyUpdated, "admin", map[string]int{"minor_units": i})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, repo.entries, 3)
+	assert.Empty(t, repo.entries[0].PrevHash)
+	for i, entry := range repo.entries {
+		assert.Equal(t, int32(i+1), entry.Seq)
+		assert.Equal(t, hashAuditEntry(entry.CurrencyCode, entry.Seq, entry.Action, entry.Actor, entry.Payload, entry.PrevHash, entry.CreatedAt.Time), entry.Hash)
+		if i > 0 {
+			assert.Equal(t, repo.entries[i-1].Hash, entry.PrevHash)
+		}
+	}
+	assert.Equal(t, repo.entries[2].Hash, repo.headHash)
+	assert.JSONEq(t, `{"minor_units":2}`, repo.entries[2].Payload)
+}
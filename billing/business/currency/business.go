@@ -2,6 +2,12 @@ package currency
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"encore.dev/beta/errs"
 
 	"encore.app/billing/model"
 	"encore.app/billing/repository/currencies"
@@ -9,15 +15,51 @@ import (
 
 type Business interface {
 	GetCurrency(ctx context.Context, code string) (*model.CurrencyInfo, error)
-	ConvertAmount(ctx context.Context, fromCurrency, toCurrency string, amountCents int64) (*model.ConversionResult, error)
+	GetMinorUnits(ctx context.Context, code string) (int32, error)
+	ListCurrencies(ctx context.Context) ([]*model.CurrencyInfo, error)
+	CreateCurrency(ctx context.Context, currency *model.CurrencyInfo, actor string) (*model.CurrencyInfo, error)
+	UpdateCurrency(ctx context.Context, code string, update *model.CurrencyUpdate, actor string) (*model.CurrencyInfo, error)
+	GetCurrencyRate(ctx context.Context, code string, at time.Time) (*model.CurrencyRate, error)
+	ConvertAmount(ctx context.Context, fromCurrency, toCurrency string, amountCents int64, at time.Time) (*model.ConversionResult, error)
+	SyncRates(ctx context.Context, provider RateProvider, maxChangePercent float64, now time.Time) (*model.RateSyncResult, error)
+}
+
+// txBeginner starts database transactions; satisfied by *pgxpool.Pool
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 type business struct {
 	currencyRepo currencies.Querier
+
+	db txBeginner
+	// newTxRepo binds the currency repository to a transaction
+	newTxRepo func(tx pgx.Tx) currencies.Querier
 }
 
-func NewCurrencyBusiness(currencyRepo currencies.Querier) Business {
+func NewCurrencyBusiness(currencyRepo currencies.Querier, db *pgxpool.Pool) Business {
 	return &business{
 		currencyRepo: currencyRepo,
+		db:           db,
+		newTxRepo:    func(tx pgx.Tx) currencies.Querier { return currencies.New(tx) },
 	}
 }
+
+// withTx runs fn with the currency repository bound to a single transaction
+func (b *business) withTx(ctx context.Context, fn func(repo currencies.Querier) error) error {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to start transaction"}
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(b.newTxRepo(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to commit transaction"}
+	}
+
+	return nil
+}
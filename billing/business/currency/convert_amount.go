@@ -2,13 +2,22 @@ package currency
 
 import (
 	"context"
+	"time"
 
 	"github.com/shopspring/decimal"
 
 	"encore.app/billing/model"
 )
 
-func (s *business) ConvertAmount(ctx context.Context, fromCurrency, toCurrency string, amountCents int64) (*model.ConversionResult, error) {
+// ConvertAmount converts an amount in minor units between currencies at the rates in effect at the
+// given time, so converting the same amount again later gives the same result. The amount is
+// rescaled between the minor units of the two currencies, e.g. from cents to whole yen. Negative
+// amounts (credits) convert symmetrically: converting -x gives exactly the negation of converting x.
+func (s *business) ConvertAmount(ctx context.Context, fromCurrency, toCurrency string, amountCents int64, at time.Time) (*model.ConversionResult, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+
 	if fromCurrency == toCurrency {
 		return &model.ConversionResult{
 			ConvertedAmount: amountCents,
@@ -16,18 +25,19 @@ func (s *business) ConvertAmount(ctx context.Context, fromCurrency, toCurrency s
 		}, nil
 	}
 
-	fromCurr, err := s.GetCurrency(ctx, fromCurrency)
+	fromInfo, fromCurr, err := s.currencyRateAt(ctx, fromCurrency, at)
 	if err != nil {
 		return nil, err
 	}
 
-	toCurr, err := s.GetCurrency(ctx, toCurrency)
+	toInfo, toCurr, err := s.currencyRateAt(ctx, toCurrency, at)
 	if err != nil {
 		return nil, err
 	}
 
 	// Use decimal arithmetic for precise financial calculations
-	// amount_in_from_currency / from_rate * to_rate
+	// amount_in_from_currency / from_rate * to_rate, shifted from the minor units of one currency
+	// to those of the other
 	amount := decimal.NewFromInt(amountCents)
 	fromRate := decimal.NewFromFloat(fromCurr.Rate)
 	toRate := decimal.NewFromFloat(toCurr.Rate)
@@ -35,8 +45,12 @@ func (s *business) ConvertAmount(ctx context.Context, fromCurrency, toCurrency s
 	// Calculate exchange rate: to_rate / from_rate
 	exchangeRate := toRate.Div(fromRate)
 
-	// Convert amount with proper rounding
-	convertedDecimal := amount.Mul(exchangeRate).Round(0)
+	// Convert amount with proper rounding. The magnitude is rounded and the sign restored,
+	// so a credit always offsets the charge it refunds to the minor unit.
+	convertedDecimal := amount.Abs().Mul(exchangeRate).Shift(toInfo.MinorUnits - fromInfo.MinorUnits).Round(0)
+	if amount.IsNegative() {
+		convertedDecimal = convertedDecimal.Neg()
+	}
 	convertedAmount := convertedDecimal.IntPart()
 
 	// Store exchange rate as float64 for compatibility with existing metadata structure
@@ -46,8 +60,12 @@ func (s *business) ConvertAmount(ctx context.Context, fromCurrency, toCurrency s
 		ConvertedAmount: convertedAmount,
 		Metadata: &model.CurrencyMetadata{
 			OriginalAmountCents: amountCents,
+			OriginalAmount:      model.FormatAmount(amountCents, fromInfo.MinorUnits),
 			OriginalCurrency:    fromCurrency,
 			ExchangeRate:        exchangeRateFloat,
+			FromRateID:          fromCurr.ID,
+			ToRateID:            toCurr.ID,
+			RateAt:              &at,
 		},
 	}, nil
 }
@@ -5,11 +5,16 @@ import (
 	"errors"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"encore.dev/beta/errs"
+
 	"encore.app/billing/mocks/repository/currency_repo"
 	"encore.app/billing/model"
 	"encore.app/billing/repository/currencies"
@@ -32,6 +37,7 @@ func TestConvertAmount(t *testing.T) {
 
 	mockCurrencyRepo := currency_repo.NewMockQuerier(ctrl)
 	business := &business{currencyRepo: mockCurrencyRepo}
+	at := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
 
 	testCases := []struct {
 		name                  string
@@ -199,6 +205,34 @@ func TestConvertAmount(t *testing.T) {
 			expectGetFromCurrency: true,
 			expectGetToCurrency:   true,
 		},
+		{
+			name:         "negative_half_cent_rounds_like_positive",
+			fromCurrency: "USD",
+			toCurrency:   "EUR",
+			amountCents:  -10, // 8.5 cents; a credit must offset the 9 cent charge exactly
+			fromCurrencyDBReturn: currencies.Currency{
+				ID:      1,
+				Code:    pgtype.Text{String: "USD", Valid: true},
+				Rate:    createNumericFromFloat(1.0),
+				Enabled: true,
+			},
+			toCurrencyDBReturn: currencies.Currency{
+				ID:      3,
+				Code:    pgtype.Text{String: "EUR", Valid: true},
+				Rate:    createNumericFromFloat(0.85),
+				Enabled: true,
+			},
+			expectedResult: &model.ConversionResult{
+				ConvertedAmount: -9,
+				Metadata: &model.CurrencyMetadata{
+					OriginalAmountCents: -10,
+					OriginalCurrency:    "USD",
+					ExchangeRate:        0.85,
+				},
+			},
+			expectGetFromCurrency: true,
+			expectGetToCurrency:   true,
+		},
 		{
 			name:                  "from_currency_not_found",
 			fromCurrency:          "INVALID",
@@ -289,15 +323,21 @@ func TestConvertAmount(t *testing.T) {
 				mockCurrencyRepo.EXPECT().
 					GetCurrency(gomock.Any(), pgtype.Text{String: tc.fromCurrency, Valid: true}).
 					Return(tc.fromCurrencyDBReturn, tc.fromCurrencyError)
+				if tc.fromCurrencyError == nil {
+					expectRateAt(mockCurrencyRepo, tc.fromCurrencyDBReturn, at)
+				}
 			}
 
 			if tc.expectGetToCurrency && tc.fromCurrencyError == nil {
 				mockCurrencyRepo.EXPECT().
 					GetCurrency(gomock.Any(), pgtype.Text{String: tc.toCurrency, Valid: true}).
 					Return(tc.toCurrencyDBReturn, tc.toCurrencyError)
+				if tc.toCurrencyError == nil {
+					expectRateAt(mockCurrencyRepo, tc.toCurrencyDBReturn, at)
+				}
 			}
 
-			result, err := business.ConvertAmount(context.Background(), tc.fromCurrency, tc.toCurrency, tc.amountCents)
+			result, err := business.ConvertAmount(context.Background(), tc.fromCurrency, tc.toCurrency, tc.amountCents, at)
 
 			if tc.expectedError != "" {
 				assert.Error(t, err)
@@ -315,8 +355,133 @@ func TestConvertAmount(t *testing.T) {
 					assert.Equal(t, tc.expectedResult.Metadata.OriginalAmountCents, result.Metadata.OriginalAmountCents)
 					assert.Equal(t, tc.expectedResult.Metadata.OriginalCurrency, result.Metadata.OriginalCurrency)
 					assert.InDelta(t, tc.expectedResult.Metadata.ExchangeRate, result.Metadata.ExchangeRate, 0.0001)
+					// The rates used are recorded so the conversion can be repeated
+					assert.Equal(t, tc.fromCurrencyDBReturn.ID*100, result.Metadata.FromRateID)
+					assert.Equal(t, tc.toCurrencyDBReturn.ID*100, result.Metadata.ToRateID)
+					assert.Equal(t, &at, result.Metadata.RateAt)
 				}
 			}
 		})
 	}
 }
+
+func TestConvertAmount_RescalesMinorUnits(t *testing.T) {
+	at := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	rows := map[string]currencies.Currency{
+		"USD": {ID: 1, Code: pgtype.Text{String: "USD", Valid: true}, Rate: createNumericFromFloat(1.0), Enabled: true, MinorUnits: 2},
+		"JPY": {ID: 3, Code: pgtype.Text{String: "JPY", Valid: true}, Rate: createNumericFromFloat(150), Enabled: true, MinorUnits: 0},
+		"KWD": {ID: 4, Code: pgtype.Text{String: "KWD", Valid: true}, Rate: createNumericFromFloat(0.307), Enabled: true, MinorUnits: 3},
+	}
+
+	testCases := []struct {
+		name           string
+		from           string
+		to             string
+		amount         int64
+		expected       int64
+		originalAmount string
+	}{
+		{name: "cents_to_yen", from: "USD", to: "JPY", amount: 10000, expected: 15000, originalAmount: "100.00"},
+		{name: "yen_to_cents", from: "JPY", to: "USD", amount: 15000, expected: 10000, originalAmount: "15000"},
+		{name: "cents_to_fils", from: "USD", to: "KWD", amount: 10000, expected: 30700, originalAmount: "100.00"},
+		{name: "fils_to_yen_rounds", from: "KWD", to: "JPY", amount: 1000, expected: 489, originalAmount: "1.000"},
+		{name: "credit_converts_symmetrically", from: "USD", to: "JPY", amount: -1999, expected: -2999, originalAmount: "-19.99"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCurrencyRepo := currency_repo.NewMockQuerier(ctrl)
+			business := &business{currencyRepo: mockCurrencyRepo}
+
+			for _, code := range []string{tc.from, tc.to} {
+				mockCurrencyRepo.EXPECT().GetCurrency(gomock.Any(), rows[code].Code).Return(rows[code], nil)
+				expectRateAt(mockCurrencyRepo, rows[code], at)
+			}
+
+			result, err := business.ConvertAmount(context.Background(), tc.from, tc.to, tc.amount, at)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result.ConvertedAmount)
+			assert.Equal(t, tc.amount, result.Metadata.OriginalAmountCents)
+			assert.Equal(t, tc.originalAmount, result.Metadata.OriginalAmount)
+		})
+	}
+}
+
+// expectRateAt serves the rate of a currency row as the rate in effect at the given time; the
+// rate ID is the currency ID times 100
+func expectRateAt(mockCurrencyRepo *currency_repo.MockQuerier, currency currencies.Currency, at time.Time) {
+	mockCurrencyRepo.EXPECT().
+		GetCurrencyRateAt(gomock.Any(), currencies.GetCurrencyRateAtParams{
+			CurrencyCode:  currency.Code.String,
+			EffectiveFrom: pgtype.Timestamptz{Time: at, Valid: true},
+		}).
+		Return(currencies.CurrencyRate{
+			ID:            currency.ID * 100,
+			CurrencyCode:  currency.Code.String,
+			Rate:          currency.Rate,
+			EffectiveFrom: pgtype.Timestamptz{Time: at.Add(-24 * time.Hour), Valid: true},
+		}, nil)
+}
+
+func TestConvertAmount_UsesRateInEffectAtTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCurrencyRepo := currency_repo.NewMockQuerier(ctrl)
+	business := &business{currencyRepo: mockCurrencyRepo}
+
+	usd := currencies.Currency{ID: 1, Code: pgtype.Text{String: "USD", Valid: true}, Rate: createNumericFromFloat(1.0), Enabled: true}
+	gel := currencies.Currency{ID: 2, Code: pgtype.Text{String: "GEL", Valid: true}, Rate: createNumericFromFloat(2.7), Enabled: true}
+	january := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+
+	mockCurrencyRepo.EXPECT().GetCurrency(gomock.Any(), usd.Code).Return(usd, nil).Times(2)
+	mockCurrencyRepo.EXPECT().GetCurrency(gomock.Any(), gel.Code).Return(gel, nil).Times(2)
+	for _, at := range []time.Time{january, february} {
+		mockCurrencyRepo.EXPECT().
+			GetCurrencyRateAt(gomock.Any(), currencies.GetCurrencyRateAtParams{CurrencyCode: "USD", EffectiveFrom: pgtype.Timestamptz{Time: at, Valid: true}}).
+			Return(currencies.CurrencyRate{ID: 1, CurrencyCode: "USD", Rate: createNumericFromFloat(1.0)}, nil)
+	}
+	mockCurrencyRepo.EXPECT().
+		GetCurrencyRateAt(gomock.Any(), currencies.GetCurrencyRateAtParams{CurrencyCode: "GEL", EffectiveFrom: pgtype.Timestamptz{Time: january, Valid: true}}).
+		Return(currencies.CurrencyRate{ID: 2, CurrencyCode: "GEL", Rate: createNumericFromFloat(2.65)}, nil)
+	mockCurrencyRepo.EXPECT().
+		GetCurrencyRateAt(gomock.Any(), currencies.GetCurrencyRateAtParams{CurrencyCode: "GEL", EffectiveFrom: pgtype.Timestamptz{Time: february, Valid: true}}).
+		Return(currencies.CurrencyRate{ID: 7, CurrencyCode: "GEL", Rate: createNumericFromFloat(2.8)}, nil)
+
+	inJanuary, err := business.ConvertAmount(context.Background(), "USD", "GEL", 10000, january)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(26500), inJanuary.ConvertedAmount)
+	assert.Equal(t, int32(2), inJanuary.Metadata.ToRateID)
+
+	inFebruary, err := business.ConvertAmount(context.Background(), "USD", "GEL", 10000, february)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(28000), inFebruary.ConvertedAmount)
+	assert.Equal(t, int32(7), inFebruary.Metadata.ToRateID)
+	assert.Equal(t, &february, inFebruary.Metadata.RateAt)
+}
+
+func TestGetCurrencyRate_NoRateInEffect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCurrencyRepo := currency_repo.NewMockQuerier(ctrl)
+	business := &business{currencyRepo: mockCurrencyRepo}
+
+	at := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	mockCurrencyRepo.EXPECT().GetCurrency(gomock.Any(), pgtype.Text{String: "GEL", Valid: true}).
+		Return(currencies.Currency{ID: 2, Code: pgtype.Text{String: "GEL", Valid: true}, Rate: createNumericFromFloat(2.7), Enabled: true}, nil)
+	mockCurrencyRepo.EXPECT().GetCurrencyRateAt(gomock.Any(), gomock.Any()).Return(currencies.CurrencyRate{}, pgx.ErrNoRows)
+
+	rate, err := business.GetCurrencyRate(context.Background(), "GEL", at)
+
+	assert.Nil(t, rate)
+	var e *errs.Error
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, errs.FailedPrecondition, e.Code)
+	assert.Equal(t, "no GEL exchange rate in effect at 2019-06-01T00:00:00Z", e.Message)
+}
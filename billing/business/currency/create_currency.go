@@ -0,0 +1,59 @@
+package currency
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+// CreateCurrency adds a currency with its rate against the base currency. The rate is also
+// recorded in the rate history as the first rate, in effect from the epoch so that amounts
+// incurred before the currency was added can still be converted. The currency and its audit
+// entry are written in one transaction.
+func (b *business) CreateCurrency(ctx context.Context, currency *model.CurrencyInfo, actor string) (*model.CurrencyInfo, error) {
+	if currency.Rate <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "rate must be positive"}
+	}
+
+	params := currencies.CreateCurrencyParams{
+		Code:       pgtype.Text{String: currency.Code, Valid: true},
+		Rate:       rateToNumeric(currency.Rate),
+		MinorUnits: currency.MinorUnits,
+		Enabled:    currency.Enabled,
+	}
+	if currency.Symbol != nil {
+		params.Symbol = pgtype.Text{String: *currency.Symbol, Valid: true}
+	}
+
+	var result *model.CurrencyInfo
+	err := b.withTx(ctx, func(repo currencies.Querier) error {
+		dbCurrency, err := repo.CreateCurrency(ctx, params)
+		if err != nil {
+			var e *pgconn.PgError
+			if errors.As(err, &e) && e.Code == pgerrcode.UniqueViolation {
+				return &errs.Error{Code: errs.AlreadyExists, Message: "currency already exists"}
+			}
+			return &errs.Error{Code: errs.Internal, Message: "failed to create currency"}
+		}
+
+		result, err = convertDBCurrencyToModel(dbCurrency)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repo, currency.Code, model.AuditActionCurrencyCreated, actor, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/repository/currency_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+func TestCreateCurrency(t *testing.T) {
+	symbol := "¥"
+
+	t.Run("creates_currency", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := currency_repo.NewMockQuerier(ctrl)
+		repo := &auditingRepo{Querier: mockRepo}
+		tx := &fakeTx{}
+		business := &business{
+			db:        fakeBeginner{tx: tx},
+			newTxRepo: func(pgx.Tx) currencies.Querier { return repo },
+		}
+
+		mockRepo.EXPECT().CreateCurrency(gomock.Any(), currencies.CreateCurrencyParams{
+			Code:       pgtype.Text{String: "JPY", Valid: true},
+			Symbol:     pgtype.Text{String: symbol, Valid: true},
+			Rate:       rateToNumeric(150),
+			MinorUnits: 0,
+			Enabled:    true,
+		}).Return(currencies.Currency{
+			ID:         3,
+			Code:       pgtype.Text{String: "JPY", Valid: true},
+			Symbol:     pgtype.Text{String: symbol, Valid: true},
+			Rate:       createNumericFromFloat(150),
+			Enabled:    true,
+			MinorUnits: 0,
+		}, nil)
+
+		result, err := business.CreateCurrency(context.Background(), &model.CurrencyInfo{
+			Code: "JPY", Symbol: &symbol, Rate: 150, MinorUnits: 0, Enabled: true,
+		}, "admin")
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), result.ID)
+		assert.Equal(t, "JPY", result.Code)
+		assert.Equal(t, symbol, *result.Symbol)
+		assert.Equal(t, 150.0, result.Rate)
+		assert.Equal(t, int32(0), result.MinorUnits)
+		assert.True(t, tx.committed)
+
+		require.Len(t, repo.entries, 1)
+		assert.Equal(t, "JPY", repo.entries[0].CurrencyCode)
+		assert.Equal(t, string(model.AuditActionCurrencyCreated), repo.entries[0].Action)
+		assert.Equal(t, "admin", repo.entries[0].Actor)
+		assert.JSONEq(t, `{"id":3,"code":"JPY","symbol":"¥","rate":150,"enabled":true,"minor_units":0}`, repo.entries[0].Payload)
+		assert.Equal(t, repo.entries[0].Hash, repo.headHash)
+	})
+
+	t.Run("duplicate_code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := currency_repo.NewMockQuerier(ctrl)
+		repo := &auditingRepo{Querier: mockRepo}
+		tx := &fakeTx{}
+		business := &business{
+			db:        fakeBeginner{tx: tx},
+			newTxRepo: func(pgx.Tx) currencies.Querier { return repo },
+		}
+
+		mockRepo.EXPECT().CreateCurrency(gomock.Any(), gomock.Any()).
+			Return(currencies.Currency{}, &pgconn.PgError{Code: pgerrcode.UniqueViolation})
+
+		result, err := business.CreateCurrency(context.Background(), &model.CurrencyInfo{Code: "USD", Rate: 1, MinorUnits: 2}, "admin")
+
+		assert.Nil(t, result)
+		assert.False(t, tx.committed)
+		assert.Empty(t, repo.entries)
+		var e *errs.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, errs.AlreadyExists, e.Code)
+	})
+
+	t.Run("rate_must_be_positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		business := &business{currencyRepo: currency_repo.NewMockQuerier(ctrl)}
+
+		result, err := business.CreateCurrency(context.Background(), &model.CurrencyInfo{Code: "EUR", Rate: 0, MinorUnits: 2}, "admin")
+
+		assert.Nil(t, result)
+		var e *errs.Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, errs.InvalidArgument, e.Code)
+		assert.Equal(t, "rate must be positive", e.Message)
+	})
+}
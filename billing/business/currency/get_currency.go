@@ -8,6 +8,7 @@ import (
 	"encore.dev/beta/errs"
 
 	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
 )
 
 func (b *business) GetCurrency(ctx context.Context, code string) (*model.CurrencyInfo, error) {
@@ -16,16 +17,22 @@ func (b *business) GetCurrency(ctx context.Context, code string) (*model.Currenc
 		return nil, &errs.Error{Code: errs.NotFound, Message: "currency not supported"}
 	}
 
+	return convertDBCurrencyToModel(dbCurrency)
+}
+
+// convertDBCurrencyToModel converts database Currency to domain model CurrencyInfo
+func convertDBCurrencyToModel(dbCurrency currencies.Currency) (*model.CurrencyInfo, error) {
 	rate, err := dbCurrency.Rate.Float64Value()
 	if err != nil && !dbCurrency.Rate.Valid {
 		return nil, &errs.Error{Code: errs.Internal, Message: "invalid currency rate"}
 	}
 
 	currency := &model.CurrencyInfo{
-		ID:      dbCurrency.ID,
-		Code:    dbCurrency.Code.String,
-		Rate:    rate.Float64,
-		Enabled: dbCurrency.Enabled,
+		ID:         dbCurrency.ID,
+		Code:       dbCurrency.Code.String,
+		Rate:       rate.Float64,
+		Enabled:    dbCurrency.Enabled,
+		MinorUnits: dbCurrency.MinorUnits,
 	}
 
 	if dbCurrency.Symbol.Valid {
@@ -0,0 +1,67 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+// GetCurrencyRate returns the rate of an enabled currency that was in effect at the given time
+func (b *business) GetCurrencyRate(ctx context.Context, code string, at time.Time) (*model.CurrencyRate, error) {
+	_, rate, err := b.currencyRateAt(ctx, code, at)
+	return rate, err
+}
+
+// currencyRateAt returns an enabled currency together with its rate in effect at the given time
+func (b *business) currencyRateAt(ctx context.Context, code string, at time.Time) (*model.CurrencyInfo, *model.CurrencyRate, error) {
+	currency, err := b.GetCurrency(ctx, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbRate, err := b.currencyRepo.GetCurrencyRateAt(ctx, currencies.GetCurrencyRateAtParams{
+		CurrencyCode:  code,
+		EffectiveFrom: pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, &errs.Error{
+				Code:    errs.FailedPrecondition,
+				Message: fmt.Sprintf("no %s exchange rate in effect at %s", code, at.UTC().Format(time.RFC3339)),
+			}
+		}
+		return nil, nil, &errs.Error{Code: errs.Internal, Message: "failed to get exchange rate"}
+	}
+
+	rate, err := convertDBCurrencyRateToModel(dbRate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return currency, rate, nil
+}
+
+// convertDBCurrencyRateToModel converts database CurrencyRate to domain model CurrencyRate
+func convertDBCurrencyRateToModel(dbRate currencies.CurrencyRate) (*model.CurrencyRate, error) {
+	rate, err := dbRate.Rate.Float64Value()
+	if err != nil || !rate.Valid {
+		return nil, &errs.Error{Code: errs.Internal, Message: "invalid currency rate"}
+	}
+
+	return &model.CurrencyRate{
+		ID:            dbRate.ID,
+		Code:          dbRate.CurrencyCode,
+		Rate:          rate.Float64,
+		EffectiveFrom: dbRate.EffectiveFrom.Time,
+		CreatedAt:     dbRate.CreatedAt.Time,
+	}, nil
+}
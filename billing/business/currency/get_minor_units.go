@@ -0,0 +1,25 @@
+package currency
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+)
+
+// GetMinorUnits returns the number of decimal places of a currency, enabled or not, so amounts
+// of bills in a currency that was disabled since can still be rendered
+func (b *business) GetMinorUnits(ctx context.Context, code string) (int32, error) {
+	dbCurrency, err := b.currencyRepo.GetCurrencyByCode(ctx, pgtype.Text{String: code, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, &errs.Error{Code: errs.NotFound, Message: "currency not found"}
+		}
+		return 0, &errs.Error{Code: errs.Internal, Message: "failed to get currency"}
+	}
+
+	return dbCurrency.MinorUnits, nil
+}
@@ -0,0 +1,28 @@
+package currency
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+)
+
+// ListCurrencies returns every currency, enabled or not, ordered by code
+func (b *business) ListCurrencies(ctx context.Context) ([]*model.CurrencyInfo, error) {
+	dbCurrencies, err := b.currencyRepo.ListCurrencies(ctx)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to list currencies"}
+	}
+
+	result := make([]*model.CurrencyInfo, len(dbCurrencies))
+	for i, dbCurrency := range dbCurrencies {
+		currency, err := convertDBCurrencyToModel(dbCurrency)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = currency
+	}
+
+	return result, nil
+}
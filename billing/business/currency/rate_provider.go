@@ -0,0 +1,158 @@
+package currency
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"encore.app/billing/model"
+)
+
+// RateProvider fetches the latest exchange rates against the base currency
+type RateProvider interface {
+	FetchRates(ctx context.Context) ([]model.ProvidedRate, error)
+}
+
+// NewRateProvider returns the provider for a rate source: an http(s) URL of a JSON rate feed, or
+// the path of a JSON or CSV file
+func NewRateProvider(source string) RateProvider {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return NewHTTPRateProvider(source, nil)
+	}
+	return NewFileRateProvider(source)
+}
+
+// rateFeed is the JSON rate format read from files and HTTP sources:
+//
+//	{"base": "USD", "effective_from": "2025-03-01T00:00:00Z", "rates": {"GEL": 2.71, "EUR": 0.92}}
+//
+// base and effective_from are optional.
+type rateFeed struct {
+	Base          string             `json:"base"`
+	EffectiveFrom *time.Time         `json:"effective_from"`
+	Rates         map[string]float64 `json:"rates"`
+}
+
+func decodeRateFeed(r io.Reader) ([]model.ProvidedRate, error) {
+	var feed rateFeed
+	if err := json.NewDecoder(r).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decode rates: %w", err)
+	}
+	if feed.Base != "" && feed.Base != string(model.USD) {
+		return nil, fmt.Errorf("rates are against %s, expected %s", feed.Base, model.USD)
+	}
+
+	var effectiveFrom time.Time
+	if feed.EffectiveFrom != nil {
+		effectiveFrom = *feed.EffectiveFrom
+	}
+
+	rates := make([]model.ProvidedRate, 0, len(feed.Rates))
+	for code, rate := range feed.Rates {
+		rates = append(rates, model.ProvidedRate{Code: strings.ToUpper(code), Rate: rate, EffectiveFrom: effectiveFrom})
+	}
+	slices.SortFunc(rates, func(a, b model.ProvidedRate) int { return strings.Compare(a.Code, b.Code) })
+	return rates, nil
+}
+
+// decodeRateCSV reads "code,rate[,effective_from]" records; a header row is skipped
+func decodeRateCSV(r io.Reader) ([]model.ProvidedRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("decode rates: %w", err)
+	}
+
+	var rates []model.ProvidedRate
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "code") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("rates line %d: expected code,rate[,effective_from]", i+1)
+		}
+
+		rate, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("rates line %d: invalid rate %q", i+1, record[1])
+		}
+		provided := model.ProvidedRate{Code: strings.ToUpper(record[0]), Rate: rate}
+		if len(record) == 3 && record[2] != "" {
+			if provided.EffectiveFrom, err = time.Parse(time.RFC3339, record[2]); err != nil {
+				return nil, fmt.Errorf("rates line %d: invalid effective_from %q", i+1, record[2])
+			}
+		}
+		rates = append(rates, provided)
+	}
+	return rates, nil
+}
+
+type fileRateProvider struct {
+	path string
+}
+
+// NewFileRateProvider reads rates from a local file, CSV if its extension is .csv and JSON otherwise
+func NewFileRateProvider(path string) RateProvider {
+	return &fileRateProvider{path: path}
+}
+
+func (p *fileRateProvider) FetchRates(ctx context.Context) ([]model.ProvidedRate, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("open rates file: %w", err)
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(p.path), ".csv") {
+		return decodeRateCSV(f)
+	}
+	return decodeRateFeed(f)
+}
+
+// maxRateFeedBytes bounds the response read from an HTTP rate source
+const maxRateFeedBytes = 1 << 20
+
+type httpRateProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPRateProvider reads rates in the JSON feed format from a URL. A nil client uses one with
+// a 10 second timeout.
+func NewHTTPRateProvider(url string, client *http.Client) RateProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &httpRateProvider{url: url, client: client}
+}
+
+func (p *httpRateProvider) FetchRates(ctx context.Context) ([]model.ProvidedRate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build rates request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch rates: unexpected status %s", resp.Status)
+	}
+	return decodeRateFeed(io.LimitReader(resp.Body, maxRateFeedBytes))
+}
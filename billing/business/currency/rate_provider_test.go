@@ -0,0 +1,115 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"encore.app/billing/model"
+)
+
+func writeRatesFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileRateProvider(t *testing.T) {
+	effectiveFrom := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		file          string
+		content       string
+		expectedRates []model.ProvidedRate
+		expectedError string
+	}{
+		{
+			name:    "json",
+			file:    "rates.json",
+			content: `{"base": "USD", "effective_from": "2025-03-01T00:00:00Z", "rates": {"gel": 2.71, "EUR": 0.92}}`,
+			expectedRates: []model.ProvidedRate{
+				{Code: "EUR", Rate: 0.92, EffectiveFrom: effectiveFrom},
+				{Code: "GEL", Rate: 2.71, EffectiveFrom: effectiveFrom},
+			},
+		},
+		{
+			name:          "json_against_other_base",
+			file:          "rates.json",
+			content:       `{"base": "EUR", "rates": {"GEL": 2.95}}`,
+			expectedError: "rates are against EUR, expected USD",
+		},
+		{
+			name:    "csv_with_header",
+			file:    "rates.csv",
+			content: "code,rate,effective_from\nGEL,2.71,2025-03-01T00:00:00Z\nEUR, 0.92\n",
+			expectedRates: []model.ProvidedRate{
+				{Code: "GEL", Rate: 2.71, EffectiveFrom: effectiveFrom},
+				{Code: "EUR", Rate: 0.92},
+			},
+		},
+		{
+			name:          "csv_invalid_rate",
+			file:          "rates.CSV",
+			content:       "GEL,abc\n",
+			expectedError: `rates line 1: invalid rate "abc"`,
+		},
+		{
+			name:          "missing_file",
+			file:          "",
+			expectedError: "open rates file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "missing.json")
+			if tc.file != "" {
+				path = writeRatesFile(t, tc.file, tc.content)
+			}
+
+			rates, err := NewRateProvider(path).FetchRates(context.Background())
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRates, rates)
+		})
+	}
+}
+
+func TestHTTPRateProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rates":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"base": "USD", "rates": {"GEL": 2.71}}`))
+		case "/broken":
+			_, _ = w.Write([]byte(`{"rates":`))
+		default:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	rates, err := NewRateProvider(server.URL + "/rates").FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []model.ProvidedRate{{Code: "GEL", Rate: 2.71}}, rates)
+
+	_, err = NewHTTPRateProvider(server.URL+"/broken", server.Client()).FetchRates(context.Background())
+	assert.ErrorContains(t, err, "decode rates")
+
+	_, err = NewHTTPRateProvider(server.URL+"/down", server.Client()).FetchRates(context.Background())
+	assert.ErrorContains(t, err, "unexpected status 503")
+}
@@ -0,0 +1,202 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+// SyncRates records the rates of a provider that differ from the rates in effect. A rate that
+// moves more than maxChangePercent from the rate in effect, or that takes effect before the
+// latest recorded rate, is rejected and left for an operator; rates without an effective time
+// take effect at now. Recorded and rejected rates are written to the audit log of their
+// currency with the system actor.
+func (b *business) SyncRates(ctx context.Context, provider RateProvider, maxChangePercent float64, now time.Time) (*model.RateSyncResult, error) {
+	provided, err := provider.FetchRates(ctx)
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Unavailable, Message: fmt.Sprintf("failed to fetch exchange rates: %v", err)}
+	}
+
+	result := &model.RateSyncResult{
+		Updated:   []model.CurrencyRate{},
+		Unchanged: []string{},
+		Rejected:  []model.RejectedRate{},
+		Skipped:   []string{},
+	}
+
+	for _, rate := range provided {
+		effectiveFrom := rate.EffectiveFrom
+		if effectiveFrom.IsZero() {
+			effectiveFrom = now
+		}
+
+		if rate.Rate <= 0 {
+			result.Rejected = append(result.Rejected, model.RejectedRate{Code: rate.Code, ProvidedRate: rate.Rate, Reason: "rate must be positive"})
+			continue
+		}
+
+		current, err := b.GetCurrencyRate(ctx, rate.Code, effectiveFrom)
+		var e *errs.Error
+		switch {
+		case errors.As(err, &e) && e.Code == errs.NotFound:
+			result.Skipped = append(result.Skipped, rate.Code)
+			continue
+		case errors.As(err, &e) && e.Code == errs.FailedPrecondition:
+			// The first rate of a currency has nothing to be compared with
+			current = nil
+		case err != nil:
+			return result, err
+		}
+
+		if current != nil && current.Rate == rate.Rate {
+			result.Unchanged = append(result.Unchanged, rate.Code)
+			continue
+		}
+
+		// A rate older than the latest one recorded would rewrite history that conversions
+		// may already have used
+		latest, err := b.currencyRepo.GetLatestCurrencyRate(ctx, rate.Code)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+		case err != nil:
+			return result, &errs.Error{Code: errs.Internal, Message: "failed to get latest exchange rate"}
+		case effectiveFrom.Before(latest.EffectiveFrom.Time):
+			latestRate, err := convertDBCurrencyRateToModel(latest)
+			if err != nil {
+				return result, err
+			}
+			rejected := model.RejectedRate{
+				Code:         rate.Code,
+				CurrentRate:  latestRate.Rate,
+				ProvidedRate: rate.Rate,
+				Reason: fmt.Sprintf("effective_from is before the latest recorded rate, in effect from %s",
+					latestRate.EffectiveFrom.UTC().Format(time.RFC3339)),
+			}
+			if err := b.auditRejectedRate(ctx, rejected, effectiveFrom); err != nil {
+				return result, err
+			}
+			result.Rejected = append(result.Rejected, rejected)
+			continue
+		}
+
+		if current != nil {
+			if change := rateChangePercent(current.Rate, rate.Rate); change > maxChangePercent {
+				rejected := model.RejectedRate{
+					Code:          rate.Code,
+					CurrentRate:   current.Rate,
+					ProvidedRate:  rate.Rate,
+					ChangePercent: change,
+					Reason:        fmt.Sprintf("rate changes by more than %g%%", maxChangePercent),
+				}
+				if err := b.auditRejectedRate(ctx, rejected, effectiveFrom); err != nil {
+					return result, err
+				}
+				result.Rejected = append(result.Rejected, rejected)
+				continue
+			}
+		}
+
+		updated, err := b.recordRate(ctx, rate.Code, rate.Rate, effectiveFrom)
+		if errors.Is(err, errRateAlreadyRecorded) {
+			result.Unchanged = append(result.Unchanged, rate.Code)
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Updated = append(result.Updated, *updated)
+	}
+
+	return result, nil
+}
+
+// errRateAlreadyRecorded reports a rate with the same effective time recorded by an earlier sync
+var errRateAlreadyRecorded = errors.New("rate already recorded")
+
+// rateRejection is the audit payload of a provided rate that was not recorded
+type rateRejection struct {
+	model.RejectedRate
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+// recordRate inserts a synced rate and its audit entry in one transaction holding the currency row
+func (b *business) recordRate(ctx context.Context, code string, rate float64, effectiveFrom time.Time) (*model.CurrencyRate, error) {
+	var updated *model.CurrencyRate
+	err := b.withTx(ctx, func(repo currencies.Querier) error {
+		if err := lockCurrency(ctx, repo, code); err != nil {
+			return err
+		}
+
+		dbRate, err := repo.CreateCurrencyRate(ctx, currencies.CreateCurrencyRateParams{
+			CurrencyCode:  code,
+			Rate:          rateToNumeric(rate),
+			EffectiveFrom: pgtype.Timestamptz{Time: effectiveFrom, Valid: true},
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				return errRateAlreadyRecorded
+			}
+			return &errs.Error{Code: errs.Internal, Message: "failed to record exchange rate"}
+		}
+
+		updated, err = convertDBCurrencyRateToModel(dbRate)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repo, code, model.AuditActionCurrencyRateRecorded, model.ActorSystem, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// auditRejectedRate records a rejected rate in the audit log of its currency
+func (b *business) auditRejectedRate(ctx context.Context, rejected model.RejectedRate, effectiveFrom time.Time) error {
+	return b.withTx(ctx, func(repo currencies.Querier) error {
+		if err := lockCurrency(ctx, repo, rejected.Code); err != nil {
+			return err
+		}
+
+		payload := rateRejection{RejectedRate: rejected, EffectiveFrom: effectiveFrom}
+		return recordAudit(ctx, repo, rejected.Code, model.AuditActionCurrencyRateRejected, model.ActorSystem, payload)
+	})
+}
+
+// lockCurrency holds the currency row for the rest of the transaction, so entries of its audit
+// log are appended one at a time
+func lockCurrency(ctx context.Context, repo currencies.Querier, code string) error {
+	if _, err := repo.GetCurrencyByCodeForUpdate(ctx, pgtype.Text{String: code, Valid: true}); err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to lock currency"}
+	}
+	return nil
+}
+
+// rateChangePercent is how far a rate moves from the current one, in percent of the current rate
+func rateChangePercent(current, provided float64) float64 {
+	from := decimal.NewFromFloat(current)
+	change := decimal.NewFromFloat(provided).Sub(from).Abs().Div(from).Mul(decimal.NewFromInt(100))
+	percent, _ := change.Round(4).Float64()
+	return percent
+}
+
+// rateToNumeric converts a rate to the scale of the rate columns
+func rateToNumeric(rate float64) pgtype.Numeric {
+	d := decimal.NewFromFloat(rate).Round(8)
+	return pgtype.Numeric{Int: d.Coefficient(), Exp: d.Exponent(), Valid: true}
+}
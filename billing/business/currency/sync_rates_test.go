@@ -0,0 +1,196 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/repository/currency_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+// staticRateProvider serves fixed rates
+type staticRateProvider struct {
+	rates []model.ProvidedRate
+	err   error
+}
+
+func (p staticRateProvider) FetchRates(ctx context.Context) ([]model.ProvidedRate, error) {
+	return p.rates, p.err
+}
+
+func TestSyncRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCurrencyRepo := currency_repo.NewMockQuerier(ctrl)
+	repo := &auditingRepo{Querier: mockCurrencyRepo}
+	business := &business{
+		currencyRepo: mockCurrencyRepo,
+		db:           fakeBeginner{tx: &fakeTx{}},
+		newTxRepo:    func(pgx.Tx) currencies.Querier { return repo },
+	}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := pgtype.Timestamptz{Time: now, Valid: true}
+
+	currentRates := map[string]float64{"GEL": 2.65, "EUR": 0.92, "JPY": 150, "CHF": 0, "SEK": 10.5}
+	for code, rate := range currentRates {
+		mockCurrencyRepo.EXPECT().GetCurrency(gomock.Any(), pgtype.Text{String: code, Valid: true}).
+			Return(currencies.Currency{Code: pgtype.Text{String: code, Valid: true}, Rate: createNumericFromFloat(rate), Enabled: true}, nil)
+		if rate == 0 {
+			// CHF has no rate yet
+			mockCurrencyRepo.EXPECT().GetCurrencyRateAt(gomock.Any(), currencies.GetCurrencyRateAtParams{CurrencyCode: code, EffectiveFrom: at}).
+				Return(currencies.CurrencyRate{}, pgx.ErrNoRows)
+			continue
+		}
+		mockCurrencyRepo.EXPECT().GetCurrencyRateAt(gomock.Any(), currencies.GetCurrencyRateAtParams{CurrencyCode: code, EffectiveFrom: at}).
+			Return(currencies.CurrencyRate{ID: 1, CurrencyCode: code, Rate: createNumericFromFloat(rate)}, nil)
+	}
+	mockCurrencyRepo.EXPECT().GetCurrency(gomock.Any(), pgtype.Text{String: "XXX", Valid: true}).Return(currencies.Currency{}, pgx.ErrNoRows)
+
+	// Rates that are not unchanged are checked against the latest recorded rate
+	earlier := pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}
+	for _, code := range []string{"GEL", "JPY", "SEK"} {
+		mockCurrencyRepo.EXPECT().GetLatestCurrencyRate(gomock.Any(), code).
+			Return(currencies.CurrencyRate{ID: 1, CurrencyCode: code, Rate: createNumericFromFloat(currentRates[code]), EffectiveFrom: earlier}, nil)
+	}
+	mockCurrencyRepo.EXPECT().GetLatestCurrencyRate(gomock.Any(), "CHF").Return(currencies.CurrencyRate{}, pgx.ErrNoRows)
+
+	// Recorded and rejected rates hold their currency row while they are audited
+	for _, code := range []string{"GEL", "JPY", "CHF", "SEK"} {
+		mockCurrencyRepo.EXPECT().GetCurrencyByCodeForUpdate(gomock.Any(), pgtype.Text{String: code, Valid: true}).
+			Return(currencies.Currency{Code: pgtype.Text{String: code, Valid: true}, Enabled: true}, nil)
+	}
+
+	mockCurrencyRepo.EXPECT().
+		CreateCurrencyRate(gomock.Any(), currencies.CreateCurrencyRateParams{CurrencyCode: "GEL", Rate: rateToNumeric(2.7), EffectiveFrom: at}).
+		Return(currencies.CurrencyRate{ID: 20, CurrencyCode: "GEL", Rate: createNumericFromFloat(2.7), EffectiveFrom: at}, nil)
+	mockCurrencyRepo.EXPECT().
+		CreateCurrencyRate(gomock.Any(), currencies.CreateCurrencyRateParams{CurrencyCode: "CHF", Rate: rateToNumeric(0.88), EffectiveFrom: at}).
+		Return(currencies.CurrencyRate{ID: 21, CurrencyCode: "CHF", Rate: createNumericFromFloat(0.88), EffectiveFrom: at}, nil)
+	// SEK was recorded at this time by an earlier run
+	mockCurrencyRepo.EXPECT().
+		CreateCurrencyRate(gomock.Any(), currencies.CreateCurrencyRateParams{CurrencyCode: "SEK", Rate: rateToNumeric(10.6), EffectiveFrom: at}).
+		Return(currencies.CurrencyRate{}, &pgconn.PgError{Code: pgerrcode.UniqueViolation})
+
+	provider := staticRateProvider{rates: []model.ProvidedRate{
+		{Code: "GEL", Rate: 2.7},
+		{Code: "EUR", Rate: 0.92},
+		{Code: "JPY", Rate: 180},
+		{Code: "XXX", Rate: 3},
+		{Code: "CHF", Rate: 0.88},
+		{Code: "SEK", Rate: 10.6},
+		{Code: "NOK", Rate: -1},
+	}}
+
+	result, err := business.SyncRates(context.Background(), provider, 10, now)
+
+	require.NoError(t, err)
+	require.Len(t, result.Updated, 2)
+	assert.Equal(t, model.CurrencyRate{ID: 20, Code: "GEL", Rate: 2.7, EffectiveFrom: now}, result.Updated[0])
+	assert.Equal(t, "CHF", result.Updated[1].Code)
+	assert.Equal(t, []string{"EUR", "SEK"}, result.Unchanged)
+	assert.Equal(t, []string{"XXX"}, result.Skipped)
+	assert.Equal(t, []model.RejectedRate{
+		{Code: "JPY", CurrentRate: 150, ProvidedRate: 180, ChangePercent: 20, Reason: "rate changes by more than 10%"},
+		{Code: "NOK", ProvidedRate: -1, Reason: "rate must be positive"},
+	}, result.Rejected)
+
+	require.Len(t, repo.entries, 3)
+	for i, expected := range []struct {
+		code   string
+		action model.AuditAction
+	}{
+		{"GEL", model.AuditActionCurrencyRateRecorded},
+		{"JPY", model.AuditActionCurrencyRateRejected},
+		{"CHF", model.AuditActionCurrencyRateRecorded},
+	} {
+		assert.Equal(t, expected.code, repo.entries[i].CurrencyCode)
+		assert.Equal(t, string(expected.action), repo.entries[i].Action)
+		assert.Equal(t, model.ActorSystem, repo.entries[i].Actor)
+	}
+	assert.JSONEq(t, `{"code":"JPY","current_rate":150,"provided_rate":180,"change_percent":20,"reason":"rate changes by more than 10%","effective_from":"2025-03-10T12:00:00Z"}`, repo.entries[1].Payload)
+}
+
+func TestSyncRates_RejectsRateBeforeLatestRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCurrencyRepo := currency_repo.NewMockQuerier(ctrl)
+	repo := &auditingRepo{Querier: mockCurrencyRepo}
+	business := &business{
+		currencyRepo: mockCurrencyRepo,
+		db:           fakeBeginner{tx: &fakeTx{}},
+		newTxRepo:    func(pgx.Tx) currencies.Querier { return repo },
+	}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	backdated := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	latestFrom := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	mockCurrencyRepo.EXPECT().GetCurrency(gomock.Any(), pgtype.Text{String: "GEL", Valid: true}).
+		Return(currencies.Currency{Code: pgtype.Text{String: "GEL", Valid: true}, Rate: createNumericFromFloat(2.7), Enabled: true}, nil)
+	mockCurrencyRepo.EXPECT().
+		GetCurrencyRateAt(gomock.Any(), currencies.GetCurrencyRateAtParams{CurrencyCode: "GEL", EffectiveFrom: pgtype.Timestamptz{Time: backdated, Valid: true}}).
+		Return(currencies.CurrencyRate{ID: 1, CurrencyCode: "GEL", Rate: createNumericFromFloat(2.65)}, nil)
+	mockCurrencyRepo.EXPECT().GetLatestCurrencyRate(gomock.Any(), "GEL").
+		Return(currencies.CurrencyRate{ID: 2, CurrencyCode: "GEL", Rate: createNumericFromFloat(2.7), EffectiveFrom: pgtype.Timestamptz{Time: latestFrom, Valid: true}}, nil)
+	mockCurrencyRepo.EXPECT().GetCurrencyByCodeForUpdate(gomock.Any(), pgtype.Text{String: "GEL", Valid: true}).
+		Return(currencies.Currency{Code: pgtype.Text{String: "GEL", Valid: true}, Enabled: true}, nil)
+
+	provider := staticRateProvider{rates: []model.ProvidedRate{{Code: "GEL", Rate: 2.68, EffectiveFrom: backdated}}}
+
+	result, err := business.SyncRates(context.Background(), provider, 10, now)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Updated)
+	assert.Equal(t, []model.RejectedRate{{
+		Code:         "GEL",
+		CurrentRate:  2.7,
+		ProvidedRate: 2.68,
+		Reason:       "effective_from is before the latest recorded rate, in effect from 2025-03-05T00:00:00Z",
+	}}, result.Rejected)
+
+	require.Len(t, repo.entries, 1)
+	assert.Equal(t, string(model.AuditActionCurrencyRateRejected), repo.entries[0].Action)
+	assert.Equal(t, model.ActorSystem, repo.entries[0].Actor)
+	assert.JSONEq(t, `{
+		"code": "GEL",
+		"current_rate": 2.7,
+		"provided_rate": 2.68,
+		"reason": "effective_from is before the latest recorded rate, in effect from 2025-03-05T00:00:00Z",
+		"effective_from": "2025-03-01T00:00:00Z"
+	}`, repo.entries[0].Payload)
+}
+
+func TestSyncRates_ProviderFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	business := &business{currencyRepo: currency_repo.NewMockQuerier(ctrl)}
+
+	result, err := business.SyncRates(context.Background(), staticRateProvider{err: errors.New("connection refused")}, 10, time.Now())
+
+	assert.Nil(t, result)
+	var e *errs.Error
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, errs.Unavailable, e.Code)
+	assert.Equal(t, "failed to fetch exchange rates: connection refused", e.Message)
+}
+
+func TestRateChangePercent(t *testing.T) {
+	assert.Equal(t, 20.0, rateChangePercent(150, 180))
+	assert.Equal(t, 20.0, rateChangePercent(150, 120))
+	assert.Equal(t, 1.8868, rateChangePercent(2.65, 2.7))
+}
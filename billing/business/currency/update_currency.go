@@ -0,0 +1,115 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+// UpdateCurrency changes the symbol, rate, minor units or enabled flag of a currency. A currency
+// cannot be disabled while open bills are billed in it, nor its minor units changed once any
+// amount is recorded in it. The checks, the update and its audit entry run in one transaction
+// holding the currency row.
+func (b *business) UpdateCurrency(ctx context.Context, code string, update *model.CurrencyUpdate, actor string) (*model.CurrencyInfo, error) {
+	if update.Rate != nil && *update.Rate <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "rate must be positive"}
+	}
+
+	var result *model.CurrencyInfo
+	err := b.withTx(ctx, func(repo currencies.Querier) error {
+		dbCurrency, err := repo.GetCurrencyByCodeForUpdate(ctx, pgtype.Text{String: code, Valid: true})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &errs.Error{Code: errs.NotFound, Message: "currency not found"}
+			}
+			return &errs.Error{Code: errs.Internal, Message: "failed to get currency"}
+		}
+
+		previous, err := convertDBCurrencyToModel(dbCurrency)
+		if err != nil {
+			return err
+		}
+
+		if update.MinorUnits != nil && *update.MinorUnits != dbCurrency.MinorUnits {
+			if err := ensureCurrencyUnused(ctx, repo, code); err != nil {
+				return err
+			}
+		}
+		if update.Enabled != nil && !*update.Enabled && dbCurrency.Enabled {
+			if err := ensureNoOpenBills(ctx, repo, code); err != nil {
+				return err
+			}
+		}
+
+		params := currencies.UpdateCurrencyParams{Code: code}
+		if update.Symbol != nil {
+			params.Symbol = pgtype.Text{String: *update.Symbol, Valid: true}
+		}
+		if update.Rate != nil {
+			params.Rate = rateToNumeric(*update.Rate)
+		}
+		if update.MinorUnits != nil {
+			params.MinorUnits = pgtype.Int4{Int32: *update.MinorUnits, Valid: true}
+		}
+		if update.Enabled != nil {
+			params.Enabled = pgtype.Bool{Bool: *update.Enabled, Valid: true}
+		}
+
+		dbCurrency, err = repo.UpdateCurrency(ctx, params)
+		if err != nil {
+			return &errs.Error{Code: errs.Internal, Message: "failed to update currency"}
+		}
+
+		result, err = convertDBCurrencyToModel(dbCurrency)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repo, code, model.AuditActionCurrencyUpdated, actor, currencyChange{Previous: previous, Current: result})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ensureCurrencyUnused fails once a bill, line item, payment or subscription holds an amount in
+// the currency, since changing its minor units would rescale every stored amount
+func ensureCurrencyUnused(ctx context.Context, repo currencies.Querier, code string) error {
+	inUse, err := repo.CurrencyInUse(ctx, code)
+	if err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to check currency usage"}
+	}
+	if inUse {
+		return &errs.Error{
+			Code:    errs.FailedPrecondition,
+			Message: fmt.Sprintf("cannot change the minor units of %s once amounts are recorded in it", code),
+		}
+	}
+	return nil
+}
+
+// ensureNoOpenBills fails when bills that are not closed or cancelled use the currency as their
+// bill currency
+func ensureNoOpenBills(ctx context.Context, repo currencies.Querier, code string) error {
+	count, err := repo.CountOpenBillsByCurrency(ctx, code)
+	if err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to count open bills"}
+	}
+	if count == 0 {
+		return nil
+	}
+	return &errs.Error{
+		Code:    errs.FailedPrecondition,
+		Message: fmt.Sprintf("cannot disable %s while %d open bills use it", code, count),
+	}
+}
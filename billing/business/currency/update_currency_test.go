@@ -0,0 +1,225 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/repository/currency_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/currencies"
+)
+
+func TestUpdateCurrency(t *testing.T) {
+	enabled := true
+	disabled := false
+	zeroUnits := int32(0)
+	twoUnits := int32(2)
+	rate := 2.75
+	negativeRate := -1.0
+
+	gel := currencies.Currency{
+		ID:         2,
+		Code:       pgtype.Text{String: "GEL", Valid: true},
+		Rate:       createNumericFromFloat(2.7),
+		Enabled:    true,
+		MinorUnits: 2,
+	}
+	updated := func(update func(c *currencies.Currency)) currencies.Currency {
+		c := gel
+		update(&c)
+		return c
+	}
+
+	testCases := []struct {
+		name          string
+		update        model.CurrencyUpdate
+		current       *currencies.Currency
+		getError      error
+		openBills     *int64
+		inUse         *bool
+		expectParams  *currencies.UpdateCurrencyParams
+		updateReturn  currencies.Currency
+		expectedCode  errs.ErrCode
+		expectedError string
+	}{
+		{
+			name:         "updates_rate",
+			update:       model.CurrencyUpdate{Rate: &rate},
+			current:      &gel,
+			expectParams: &currencies.UpdateCurrencyParams{Code: "GEL", Rate: rateToNumeric(rate)},
+			updateReturn: updated(func(c *currencies.Currency) { c.Rate = createNumericFromFloat(rate) }),
+		},
+		{
+			name:         "disables_currency_without_open_bills",
+			update:       model.CurrencyUpdate{Enabled: &disabled},
+			current:      &gel,
+			openBills:    int64Ptr(0),
+			expectParams: &currencies.UpdateCurrencyParams{Code: "GEL", Enabled: pgtype.Bool{Bool: false, Valid: true}},
+			updateReturn: updated(func(c *currencies.Currency) { c.Enabled = false }),
+		},
+		{
+			name:          "disable_blocked_by_open_bills",
+			update:        model.CurrencyUpdate{Enabled: &disabled},
+			current:       &gel,
+			openBills:     int64Ptr(3),
+			expectedCode:  errs.FailedPrecondition,
+			expectedError: "cannot disable GEL while 3 open bills use it",
+		},
+		{
+			name:         "changes_minor_units_of_unused_currency",
+			update:       model.CurrencyUpdate{MinorUnits: &zeroUnits},
+			current:      &gel,
+			inUse:        boolPtr(false),
+			expectParams: &currencies.UpdateCurrencyParams{Code: "GEL", MinorUnits: pgtype.Int4{Int32: 0, Valid: true}},
+			updateReturn: updated(func(c *currencies.Currency) { c.MinorUnits = 0 }),
+		},
+		{
+			name:          "minor_units_change_blocked_once_currency_is_used",
+			update:        model.CurrencyUpdate{MinorUnits: &zeroUnits},
+			current:       &gel,
+			inUse:         boolPtr(true),
+			expectedCode:  errs.FailedPrecondition,
+			expectedError: "cannot change the minor units of GEL once amounts are recorded in it",
+		},
+		{
+			name:         "unchanged_minor_units_skip_open_bill_check",
+			update:       model.CurrencyUpdate{MinorUnits: &twoUnits, Enabled: &enabled},
+			current:      &gel,
+			expectParams: &currencies.UpdateCurrencyParams{Code: "GEL", MinorUnits: pgtype.Int4{Int32: 2, Valid: true}, Enabled: pgtype.Bool{Bool: true, Valid: true}},
+			updateReturn: gel,
+		},
+		{
+			name:          "currency_not_found",
+			update:        model.CurrencyUpdate{Enabled: &enabled},
+			getError:      pgx.ErrNoRows,
+			expectedCode:  errs.NotFound,
+			expectedError: "currency not found",
+		},
+		{
+			name:          "rate_must_be_positive",
+			update:        model.CurrencyUpdate{Rate: &negativeRate},
+			expectedCode:  errs.InvalidArgument,
+			expectedError: "rate must be positive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := currency_repo.NewMockQuerier(ctrl)
+			repo := &auditingRepo{Querier: mockRepo}
+			tx := &fakeTx{}
+			business := &business{
+				db:        fakeBeginner{tx: tx},
+				newTxRepo: func(pgx.Tx) currencies.Querier { return repo },
+			}
+
+			if tc.current != nil || tc.getError != nil {
+				current := currencies.Currency{}
+				if tc.current != nil {
+					current = *tc.current
+				}
+				mockRepo.EXPECT().GetCurrencyByCodeForUpdate(gomock.Any(), pgtype.Text{String: "GEL", Valid: true}).Return(current, tc.getError)
+			}
+			if tc.openBills != nil {
+				mockRepo.EXPECT().CountOpenBillsByCurrency(gomock.Any(), "GEL").Return(*tc.openBills, nil)
+			}
+			if tc.inUse != nil {
+				mockRepo.EXPECT().CurrencyInUse(gomock.Any(), "GEL").Return(*tc.inUse, nil)
+			}
+			if tc.expectParams != nil {
+				mockRepo.EXPECT().UpdateCurrency(gomock.Any(), *tc.expectParams).Return(tc.updateReturn, nil)
+			}
+
+			result, err := business.UpdateCurrency(context.Background(), "GEL", &tc.update, "admin")
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "GEL", result.Code)
+				assert.True(t, tx.committed, "the check and the update must commit together")
+				require.Len(t, repo.entries, 1)
+				assert.Equal(t, string(model.AuditActionCurrencyUpdated), repo.entries[0].Action)
+				assert.Equal(t, "admin", repo.entries[0].Actor)
+				return
+			}
+			assert.Nil(t, result)
+			assert.False(t, tx.committed)
+			assert.Empty(t, repo.entries)
+			var e *errs.Error
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tc.expectedCode, e.Code)
+			assert.Equal(t, tc.expectedError, e.Message)
+		})
+	}
+}
+
+func TestUpdateCurrency_AuditsPreviousAndNewValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := currency_repo.NewMockQuerier(ctrl)
+	repo := &auditingRepo{Querier: mockRepo}
+	business := &business{
+		db:        fakeBeginner{tx: &fakeTx{}},
+		newTxRepo: func(pgx.Tx) currencies.Querier { return repo },
+	}
+
+	gel := currencies.Currency{ID: 2, Code: pgtype.Text{String: "GEL", Valid: true}, Rate: createNumericFromFloat(2.7), Enabled: true, MinorUnits: 2}
+	updated := gel
+	updated.Rate = createNumericFromFloat(2.75)
+	mockRepo.EXPECT().GetCurrencyByCodeForUpdate(gomock.Any(), gomock.Any()).Return(gel, nil)
+	mockRepo.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Return(updated, nil)
+
+	rate := 2.75
+	_, err := business.UpdateCurrency(context.Background(), "GEL", &model.CurrencyUpdate{Rate: &rate}, "admin")
+
+	require.NoError(t, err)
+	require.Len(t, repo.entries, 1)
+	assert.JSONEq(t, `{
+		"previous": {"id":2,"code":"GEL","rate":2.7,"enabled":true,"minor_units":2},
+		"current": {"id":2,"code":"GEL","rate":2.75,"enabled":true,"minor_units":2}
+	}`, repo.entries[0].Payload)
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+// fakeTx is a transaction handle that only records whether it was committed
+type fakeTx struct {
+	pgx.Tx
+
+	committed bool
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+type fakeBeginner struct {
+	tx *fakeTx
+}
+
+func (b fakeBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	return b.tx, nil
+}
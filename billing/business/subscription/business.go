@@ -0,0 +1,46 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"encore.app/billing/business/account"
+	"encore.app/billing/business/bill"
+	"encore.app/billing/business/currency"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/subscriptions"
+)
+
+type Business interface {
+	CreateSubscription(ctx context.Context, subscription *model.Subscription) (*model.Subscription, error)
+	GetSubscription(ctx context.Context, id int32) (*model.Subscription, error)
+	UpdateSubscriptionStatus(ctx context.Context, id int32, status model.SubscriptionStatus) (*model.Subscription, error)
+
+	// CreatePeriodBill creates the bill for the earliest period at or after minPeriodIndex that has not
+	// already ended, returning the bill and the index of the period it covers
+	CreatePeriodBill(ctx context.Context, id int32, minPeriodIndex int32) (*model.Bill, int32, error)
+}
+
+type business struct {
+	subscriptionRepo subscriptions.Querier
+	billService      bill.Business
+	accountService   account.Business
+	currencyService  currency.Business
+
+	now func() time.Time
+}
+
+func NewSubscriptionBusiness(
+	subscriptionRepo subscriptions.Querier,
+	billService bill.Business,
+	accountService account.Business,
+	currencyService currency.Business,
+) Business {
+	return &business{
+		subscriptionRepo: subscriptionRepo,
+		billService:      billService,
+		accountService:   accountService,
+		currencyService:  currencyService,
+		now:              time.Now,
+	}
+}
@@ -0,0 +1,63 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/subscriptions"
+)
+
+// CreatePeriodBill creates the next bill of a subscription through the regular bill creation path.
+// Periods that ended while the subscription was paused are skipped rather than billed retroactively.
+// The bill idempotency key is derived from the period, so retries return the bill created earlier.
+func (b *business) CreatePeriodBill(ctx context.Context, id int32, minPeriodIndex int32) (*model.Bill, int32, error) {
+	sub, err := b.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if sub.Status == model.SubscriptionStatusCancelled {
+		return nil, 0, &errs.Error{Code: errs.FailedPrecondition, Message: "subscription is cancelled"}
+	}
+
+	index := periodIndexAt(sub, b.now())
+	if index < minPeriodIndex {
+		index = minPeriodIndex
+	}
+	start, end := periodBounds(sub, index)
+	idempotencyKey := fmt.Sprintf("subscription-%d-period-%d", sub.ID, index)
+
+	bill, err := b.billService.CreateBill(ctx, &model.Bill{
+		AccountID:      sub.AccountID,
+		Currency:       sub.Currency,
+		StartTime:      start,
+		EndTime:        end,
+		IdempotencyKey: idempotencyKey,
+	}, model.ActorSystem)
+	if err != nil {
+		var e *errs.Error
+		if !errors.As(err, &e) || e.Code != errs.AlreadyExists {
+			return nil, 0, err
+		}
+
+		bill, err = b.billService.GetBillByIdempotencyKey(ctx, idempotencyKey)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	_, err = b.subscriptionRepo.UpdateSubscriptionCurrentBill(ctx, subscriptions.UpdateSubscriptionCurrentBillParams{
+		ID:            sub.ID,
+		CurrentBillID: pgtype.Int4{Int32: bill.ID, Valid: true},
+	})
+	if err != nil {
+		return nil, 0, &errs.Error{Code: errs.Internal, Message: "failed to update subscription current bill"}
+	}
+
+	return bill, index, nil
+}
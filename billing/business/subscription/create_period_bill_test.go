@@ -0,0 +1,134 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/mocks/repository/subscription_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/subscriptions"
+)
+
+func TestCreatePeriodBill(t *testing.T) {
+	anchor := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	// Three weeks after the anchor: period 3 is the current one
+	now := anchor.Add(3*7*24*time.Hour + time.Hour)
+
+	testCases := []struct {
+		name             string
+		status           model.SubscriptionStatus
+		minPeriodIndex   int32
+		createError      error
+		expectedIndex    int32
+		expectLookup     bool
+		expectedError    string
+		expectCreateCall bool
+	}{
+		{
+			name:             "current_period_after_pause",
+			status:           model.SubscriptionStatusActive,
+			minPeriodIndex:   1,
+			expectedIndex:    3,
+			expectCreateCall: true,
+		},
+		{
+			name:             "next_period_after_early_close",
+			status:           model.SubscriptionStatusActive,
+			minPeriodIndex:   4,
+			expectedIndex:    4,
+			expectCreateCall: true,
+		},
+		{
+			name:             "retry_returns_existing_bill",
+			status:           model.SubscriptionStatusActive,
+			minPeriodIndex:   4,
+			createError:      &errs.Error{Code: errs.AlreadyExists, Message: "bill is duplicated"},
+			expectedIndex:    4,
+			expectLookup:     true,
+			expectCreateCall: true,
+		},
+		{
+			name:           "cancelled_subscription",
+			status:         model.SubscriptionStatusCancelled,
+			minPeriodIndex: 4,
+			expectedError:  "subscription is cancelled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubscriptionRepo := subscription_repo.NewMockQuerier(ctrl)
+			mockBillService := bill_business.NewMockBusiness(ctrl)
+			business := &business{
+				subscriptionRepo: mockSubscriptionRepo,
+				billService:      mockBillService,
+				now:              func() time.Time { return now },
+			}
+
+			mockSubscriptionRepo.EXPECT().
+				GetSubscription(gomock.Any(), int32(5)).
+				Return(subscriptions.Subscription{
+					ID:              5,
+					AccountID:       9,
+					Currency:        "USD",
+					BillingInterval: string(model.SubscriptionIntervalWeekly),
+					AnchorAt:        pgtype.Timestamptz{Time: anchor, Valid: true},
+					Status:          string(tc.status),
+				}, nil)
+
+			expectedStart := anchor.AddDate(0, 0, 7*int(tc.expectedIndex))
+			expectedKey := fmt.Sprintf("subscription-5-period-%d", tc.expectedIndex)
+			bill := &model.Bill{ID: 50, AccountID: 9, StartTime: expectedStart, EndTime: expectedStart.AddDate(0, 0, 7)}
+
+			if tc.expectCreateCall {
+				mockBillService.EXPECT().
+					CreateBill(gomock.Any(), &model.Bill{
+						AccountID:      9,
+						Currency:       "USD",
+						StartTime:      expectedStart,
+						EndTime:        expectedStart.AddDate(0, 0, 7),
+						IdempotencyKey: expectedKey,
+					}, model.ActorSystem).
+					Return(bill, tc.createError)
+				if tc.createError != nil {
+					bill = nil
+				}
+				mockSubscriptionRepo.EXPECT().
+					UpdateSubscriptionCurrentBill(gomock.Any(), subscriptions.UpdateSubscriptionCurrentBillParams{
+						ID:            5,
+						CurrentBillID: pgtype.Int4{Int32: 50, Valid: true},
+					}).
+					Return(subscriptions.Subscription{ID: 5}, nil)
+			}
+			if tc.expectLookup {
+				mockBillService.EXPECT().
+					GetBillByIdempotencyKey(gomock.Any(), expectedKey).
+					Return(&model.Bill{ID: 50}, nil)
+			}
+
+			result, index, err := business.CreatePeriodBill(context.Background(), 5, tc.minPeriodIndex)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, int32(50), result.ID)
+				assert.Equal(t, tc.expectedIndex, index)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/subscriptions"
+)
+
+// CreateSubscription registers a recurring subscription for an enabled account and currency
+func (b *business) CreateSubscription(ctx context.Context, subscription *model.Subscription) (*model.Subscription, error) {
+	account, err := b.accountService.GetAccount(ctx, subscription.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.Enabled {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "account is disabled"}
+	}
+
+	currency, err := b.currencyService.GetCurrency(ctx, subscription.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if !currency.Enabled {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "currency is not enabled"}
+	}
+
+	intervalSeconds := pgtype.Int8{Valid: false}
+	if subscription.Interval == model.SubscriptionIntervalCustom {
+		if subscription.IntervalSeconds == nil || *subscription.IntervalSeconds <= 0 {
+			return nil, &errs.Error{Code: errs.InvalidArgument, Message: "interval_seconds is required for custom interval"}
+		}
+		intervalSeconds = pgtype.Int8{Int64: *subscription.IntervalSeconds, Valid: true}
+	}
+
+	workflowID := fmt.Sprintf("subscription-%s", subscription.IdempotencyKey)
+
+	dbSubscription, err := b.subscriptionRepo.CreateSubscription(ctx, subscriptions.CreateSubscriptionParams{
+		AccountID:       subscription.AccountID,
+		Currency:        subscription.Currency,
+		BillingInterval: string(subscription.Interval),
+		IntervalSeconds: intervalSeconds,
+		AnchorAt:        pgtype.Timestamptz{Time: subscription.AnchorAt, Valid: true},
+		Status:          string(model.SubscriptionStatusActive),
+		WorkflowID:      pgtype.Text{String: workflowID, Valid: true},
+		IdempotencyKey:  subscription.IdempotencyKey,
+	})
+	if err != nil {
+		var e *pgconn.PgError
+		if errors.As(err, &e) && e.Code == pgerrcode.UniqueViolation {
+			return nil, &errs.Error{Code: errs.AlreadyExists, Message: "subscription is duplicated"}
+		}
+
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to create subscription"}
+	}
+
+	return convertDBSubscriptionToModel(dbSubscription), nil
+}
+
+// convertDBSubscriptionToModel converts a database Subscription to a domain model Subscription
+func convertDBSubscriptionToModel(dbSubscription subscriptions.Subscription) *model.Subscription {
+	subscription := &model.Subscription{
+		ID:             dbSubscription.ID,
+		AccountID:      dbSubscription.AccountID,
+		Currency:       dbSubscription.Currency,
+		Interval:       model.SubscriptionInterval(dbSubscription.BillingInterval),
+		AnchorAt:       dbSubscription.AnchorAt.Time,
+		Status:         model.SubscriptionStatus(dbSubscription.Status),
+		IdempotencyKey: dbSubscription.IdempotencyKey,
+		CreatedAt:      dbSubscription.CreatedAt.Time,
+		UpdatedAt:      dbSubscription.UpdatedAt.Time,
+	}
+
+	if dbSubscription.IntervalSeconds.Valid {
+		subscription.IntervalSeconds = &dbSubscription.IntervalSeconds.Int64
+	}
+
+	if dbSubscription.WorkflowID.Valid {
+		subscription.WorkflowID = &dbSubscription.WorkflowID.String
+	}
+
+	if dbSubscription.CurrentBillID.Valid {
+		subscription.CurrentBillID = &dbSubscription.CurrentBillID.Int32
+	}
+
+	return subscription
+}
@@ -0,0 +1,25 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+)
+
+// GetSubscription retrieves a subscription by ID
+func (b *business) GetSubscription(ctx context.Context, id int32) (*model.Subscription, error) {
+	dbSubscription, err := b.subscriptionRepo.GetSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.Error{Code: errs.NotFound, Message: "subscription not found"}
+		}
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to get subscription"}
+	}
+
+	return convertDBSubscriptionToModel(dbSubscription), nil
+}
@@ -0,0 +1,77 @@
+package subscription
+
+import (
+	"time"
+
+	"encore.app/billing/model"
+)
+
+// periodStart returns the start of the period with the given index. Periods are always
+// derived from the anchor rather than from the previous period so they never drift.
+func periodStart(sub *model.Subscription, index int32) time.Time {
+	switch sub.Interval {
+	case model.SubscriptionIntervalMonthly:
+		return addMonths(sub.AnchorAt, int(index))
+	case model.SubscriptionIntervalWeekly:
+		return sub.AnchorAt.AddDate(0, 0, 7*int(index))
+	default:
+		return sub.AnchorAt.Add(time.Duration(index) * customInterval(sub))
+	}
+}
+
+// periodBounds returns the [start, end) range covered by the period with the given index
+func periodBounds(sub *model.Subscription, index int32) (time.Time, time.Time) {
+	return periodStart(sub, index), periodStart(sub, index+1)
+}
+
+// periodIndexAt returns the index of the period containing t; times before the anchor belong to period 0
+func periodIndexAt(sub *model.Subscription, t time.Time) int32 {
+	if !t.After(sub.AnchorAt) {
+		return 0
+	}
+
+	var index int32
+	switch sub.Interval {
+	case model.SubscriptionIntervalMonthly:
+		ay, am, _ := sub.AnchorAt.Date()
+		ty, tm, _ := t.Date()
+		index = int32((ty-ay)*12 + int(tm-am))
+	case model.SubscriptionIntervalWeekly:
+		index = int32(t.Sub(sub.AnchorAt) / (7 * 24 * time.Hour))
+	default:
+		index = int32(t.Sub(sub.AnchorAt) / customInterval(sub))
+	}
+
+	// The estimate can be off by one around month lengths and DST; settle on the exact period
+	for index > 0 && periodStart(sub, index).After(t) {
+		index--
+	}
+	for !periodStart(sub, index+1).After(t) {
+		index++
+	}
+
+	return index
+}
+
+// addMonths adds months to t, clamping the day to the end of shorter months
+// so that a period anchored on the 31st ends on the last day of February
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day, hour, minute, sec, t.Nanosecond(), t.Location())
+}
+
+func customInterval(sub *model.Subscription) time.Duration {
+	if sub.IntervalSeconds == nil || *sub.IntervalSeconds <= 0 {
+		// Guarded by validation on creation; fall back to a day rather than dividing by zero
+		return 24 * time.Hour
+	}
+	return time.Duration(*sub.IntervalSeconds) * time.Second
+}
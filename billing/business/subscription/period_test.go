@@ -0,0 +1,99 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"encore.app/billing/model"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	custom := int64(90 * 60)
+
+	testCases := []struct {
+		name          string
+		sub           *model.Subscription
+		index         int32
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "monthly_first_period",
+			sub:           &model.Subscription{Interval: model.SubscriptionIntervalMonthly, AnchorAt: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)},
+			index:         0,
+			expectedStart: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 2, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "monthly_anchor_on_31st_clamps_to_month_end",
+			sub:           &model.Subscription{Interval: model.SubscriptionIntervalMonthly, AnchorAt: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+			index:         1,
+			expectedStart: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "monthly_crosses_year",
+			sub:           &model.Subscription{Interval: model.SubscriptionIntervalMonthly, AnchorAt: time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)},
+			index:         3,
+			expectedStart: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "weekly",
+			sub:           &model.Subscription{Interval: model.SubscriptionIntervalWeekly, AnchorAt: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+			index:         2,
+			expectedStart: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "custom",
+			sub:           &model.Subscription{Interval: model.SubscriptionIntervalCustom, IntervalSeconds: &custom, AnchorAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			index:         3,
+			expectedStart: time.Date(2025, 1, 1, 4, 30, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 1, 1, 6, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := periodBounds(tc.sub, tc.index)
+
+			assert.Equal(t, tc.expectedStart, start)
+			assert.Equal(t, tc.expectedEnd, end)
+		})
+	}
+}
+
+func TestPeriodIndexAt(t *testing.T) {
+	monthly := &model.Subscription{Interval: model.SubscriptionIntervalMonthly, AnchorAt: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)}
+	weekly := &model.Subscription{Interval: model.SubscriptionIntervalWeekly, AnchorAt: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)}
+
+	testCases := []struct {
+		name     string
+		sub      *model.Subscription
+		at       time.Time
+		expected int32
+	}{
+		{name: "before_anchor", sub: monthly, at: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), expected: 0},
+		{name: "at_anchor", sub: monthly, at: monthly.AnchorAt, expected: 0},
+		{name: "monthly_clamped_boundary", sub: monthly, at: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), expected: 1},
+		{name: "monthly_just_before_boundary", sub: monthly, at: time.Date(2025, 2, 27, 23, 59, 0, 0, time.UTC), expected: 0},
+		{name: "monthly_month_not_yet_reached_anchor_day", sub: monthly, at: time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC), expected: 1},
+		{name: "weekly_mid_period", sub: weekly, at: time.Date(2025, 1, 22, 12, 0, 0, 0, time.UTC), expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			index := periodIndexAt(tc.sub, tc.at)
+
+			assert.Equal(t, tc.expected, index)
+			start, end := periodBounds(tc.sub, index)
+			if !tc.at.Before(tc.sub.AnchorAt) {
+				assert.False(t, tc.at.Before(start))
+				assert.True(t, tc.at.Before(end))
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package subscription
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/subscriptions"
+)
+
+// UpdateSubscriptionStatus moves a subscription to the given status. Requesting the current
+// status is a no-op, and a cancelled subscription cannot be changed any more.
+func (b *business) UpdateSubscriptionStatus(ctx context.Context, id int32, status model.SubscriptionStatus) (*model.Subscription, error) {
+	current, err := b.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Status == status {
+		return current, nil
+	}
+	if current.Status == model.SubscriptionStatusCancelled {
+		return nil, &errs.Error{Code: errs.FailedPrecondition, Message: "subscription is cancelled"}
+	}
+
+	dbSubscription, err := b.subscriptionRepo.UpdateSubscriptionStatus(ctx, subscriptions.UpdateSubscriptionStatusParams{
+		ID:     id,
+		Status: string(status),
+	})
+	if err != nil {
+		return nil, &errs.Error{Code: errs.Internal, Message: "failed to update subscription status"}
+	}
+
+	return convertDBSubscriptionToModel(dbSubscription), nil
+}
@@ -0,0 +1,84 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/repository/subscription_repo"
+	"encore.app/billing/model"
+	"encore.app/billing/repository/subscriptions"
+)
+
+func TestUpdateSubscriptionStatus(t *testing.T) {
+	testCases := []struct {
+		name          string
+		current       model.SubscriptionStatus
+		target        model.SubscriptionStatus
+		expectUpdate  bool
+		expectedError string
+	}{
+		{
+			name:         "pause_active",
+			current:      model.SubscriptionStatusActive,
+			target:       model.SubscriptionStatusPaused,
+			expectUpdate: true,
+		},
+		{
+			name:         "resume_paused",
+			current:      model.SubscriptionStatusPaused,
+			target:       model.SubscriptionStatusActive,
+			expectUpdate: true,
+		},
+		{
+			name:         "cancel_paused",
+			current:      model.SubscriptionStatusPaused,
+			target:       model.SubscriptionStatusCancelled,
+			expectUpdate: true,
+		},
+		{
+			name:    "pause_already_paused_is_noop",
+			current: model.SubscriptionStatusPaused,
+			target:  model.SubscriptionStatusPaused,
+		},
+		{
+			name:          "resume_cancelled",
+			current:       model.SubscriptionStatusCancelled,
+			target:        model.SubscriptionStatusActive,
+			expectedError: "subscription is cancelled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubscriptionRepo := subscription_repo.NewMockQuerier(ctrl)
+			business := &business{subscriptionRepo: mockSubscriptionRepo}
+
+			mockSubscriptionRepo.EXPECT().
+				GetSubscription(gomock.Any(), int32(5)).
+				Return(subscriptions.Subscription{ID: 5, Status: string(tc.current)}, nil)
+
+			if tc.expectUpdate {
+				mockSubscriptionRepo.EXPECT().
+					UpdateSubscriptionStatus(gomock.Any(), subscriptions.UpdateSubscriptionStatusParams{ID: 5, Status: string(tc.target)}).
+					Return(subscriptions.Subscription{ID: 5, Status: string(tc.target)}, nil)
+			}
+
+			result, err := business.UpdateSubscriptionStatus(context.Background(), 5, tc.target)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.target, result.Status)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type CancelBillRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type CancelBillResponse struct {
+	Bill model.Bill `json:"bill"`
+}
+
+// CancelBill cancels a pending or active bill, for example one created by mistake. The bill
+// keeps its line items and total, owes nothing and its billing period workflow is stopped.
+//
+//encore:api auth path=/v1/bills/:id/cancel method=POST tag:idempotency
+func (s *Service) CancelBill(ctx context.Context, id int32, req *CancelBillRequest) (*CancelBillResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	err := s.business.CancelBill(ctx, id, req.Reason, callerActor())
+	if err != nil {
+		rlog.Error("failed to cancel bill", "error", err, "id", id)
+		return nil, err
+	}
+
+	bill, err := s.business.GetBill(ctx, id, model.GetBillOptions{})
+	if err != nil {
+		rlog.Error("failed to get cancelled bill", "error", err, "id", id)
+		return nil, err
+	}
+
+	if bill.WorkflowID != nil {
+		workflowID := *bill.WorkflowID
+		runAsync("terminate_workflow", func(ctx context.Context) error {
+			return s.terminateWorkflow(ctx, workflowID, "cancelled_via_api")
+		})
+	}
+
+	s.newAmountFormatter(ctx).bill(bill)
+
+	return &CancelBillResponse{
+		Bill: *bill,
+	}, nil
+}
+
+// Validate implements validation for CancelBillRequest
+func (r *CancelBillRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+func TestCancelBill(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	originalRunAsync := runAsync
+	runAsync = func(op string, fn func(ctx context.Context) error) { _ = fn(context.Background()) }
+	defer func() { runAsync = originalRunAsync }()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	mockTemporal := &mocks.Client{}
+	service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
+
+	mockBusiness.EXPECT().CancelBill(gomock.Any(), int32(1), "created by mistake", "admin").Return(nil)
+	mockBusiness.EXPECT().GetBill(gomock.Any(), int32(1), model.GetBillOptions{}).
+		Return(&model.Bill{ID: 1, Status: model.BillStatusCancelled, WorkflowID: stringPtr("bill-1")}, nil)
+	mockTemporal.On("TerminateWorkflow", mock.Anything, "bill-1", "", "cancelled_via_api").Return(nil).Once()
+
+	response, err := service.CancelBill(context.Background(), 1, &CancelBillRequest{Reason: "created by mistake"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.BillStatusCancelled, response.Bill.Status)
+	mockTemporal.AssertExpectations(t)
+}
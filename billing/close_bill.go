@@ -17,19 +17,23 @@ type CloseBillResponse struct {
 	Bill model.Bill `json:"bill"`
 }
 
-// encore:api public path=/v1/bills/:id/close method=POST tag:idempotency
+// encore:api auth path=/v1/bills/:id/close method=POST tag:idempotency
 func (s *Service) CloseBill(ctx context.Context, id int32, req *CloseBillRequest) (*CloseBillResponse, error) {
 	if id <= 0 {
 		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
 	}
 
-	err := s.business.CloseBill(ctx, id, req.Reason)
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	err := s.business.CloseBill(ctx, id, req.Reason, callerActor())
 	if err != nil {
 		rlog.Error("failed to close bill", "error", err, "id", id)
 		return nil, err
 	}
 
-	bill, err := s.business.GetBill(ctx, id)
+	bill, err := s.business.GetBill(ctx, id, model.GetBillOptions{})
 	if err != nil {
 		rlog.Error("failed to get closed bill", "error", err, "id", id)
 		return nil, err
@@ -39,6 +43,12 @@ func (s *Service) CloseBill(ctx context.Context, id int32, req *CloseBillRequest
 	runAsync("terminate_workflow", func(ctx context.Context) error {
 		return s.terminateWorkflow(ctx, *bill.WorkflowID, "manual_close_via_api")
 	})
+	// The terminated workflow no longer starts dunning, so the close does
+	runAsync("start_dunning", func(ctx context.Context) error {
+		return s.startDunningWorkflow(ctx, id)
+	})
+
+	s.newAmountFormatter(ctx).bill(bill)
 
 	return &CloseBillResponse{
 		Bill: *bill,
@@ -3,9 +3,11 @@ package billing
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/mocks"
 	"go.uber.org/mock/gomock"
 
@@ -13,9 +15,11 @@ import (
 
 	"encore.app/billing/mocks/business/bill_business"
 	"encore.app/billing/model"
+	"encore.app/billing/workflow"
 )
 
 func TestCloseBill(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
 
 	testCases := []struct {
 		name                string
@@ -152,18 +156,18 @@ func TestCloseBill(t *testing.T) {
 			mockBusiness := bill_business.NewMockBusiness(ctrl)
 			mockTemporal := mocks.NewClient(t)
 
-			service := &Service{business: mockBusiness, temporal: mockTemporal}
+			service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
 
 			if tc.expectCloseBillCall {
 				mockBusiness.EXPECT().
-					CloseBill(gomock.Any(), tc.billID, tc.request.Reason).
+					CloseBill(gomock.Any(), tc.billID, tc.request.Reason, "admin").
 					Return(tc.mockCloseBillError).
 					Times(1)
 			}
 
 			if tc.expectGetBillCall {
 				mockBusiness.EXPECT().
-					GetBill(gomock.Any(), tc.billID).
+					GetBill(gomock.Any(), tc.billID, model.GetBillOptions{}).
 					Return(tc.mockGetBillReturn, tc.mockGetBillError).
 					Times(1)
 			}
@@ -171,6 +175,14 @@ func TestCloseBill(t *testing.T) {
 			if tc.expectSuccess {
 				// Only successful path triggers workflow termination
 				mockTemporal.On("TerminateWorkflow", mock.Anything, *tc.mockGetBillReturn.WorkflowID, "", "manual_close_via_api").Return(nil).Once()
+				mockTemporal.On("ExecuteWorkflow",
+					mock.Anything,
+					mock.MatchedBy(func(options client.StartWorkflowOptions) bool {
+						return options.ID == workflow.DunningWorkflowID(tc.billID)
+					}),
+					mock.Anything,
+					workflow.DunningWorkflowParams{BillID: tc.billID},
+				).Return(nil, nil).Once()
 			}
 
 			response, err := service.CloseBill(context.Background(), tc.billID, tc.request)
@@ -196,6 +208,43 @@ func TestCloseBill(t *testing.T) {
 	}
 }
 
+func TestCloseBill_StartsDunningWithConfiguredSchedule(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	originalRunAsync := runAsync
+	runAsync = func(op string, fn func(ctx context.Context) error) { _ = fn(context.Background()) }
+	defer func() { runAsync = originalRunAsync }()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	mockTemporal := mocks.NewClient(t)
+
+	schedule := []model.DunningStep{
+		{After: 3 * 24 * time.Hour, Action: model.DunningActionReminder},
+		{After: 10 * 24 * time.Hour, Action: model.DunningActionEscalate},
+	}
+	service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl), dunningSchedule: schedule}
+
+	mockBusiness.EXPECT().CloseBill(gomock.Any(), int32(7), "Closed early", "admin").Return(nil)
+	mockBusiness.EXPECT().GetBill(gomock.Any(), int32(7), model.GetBillOptions{}).
+		Return(&model.Bill{ID: 7, Currency: "USD", Status: model.BillStatusClosed, TotalAmountCents: 5000, WorkflowID: stringPtr("bill-7")}, nil)
+	mockTemporal.On("TerminateWorkflow", mock.Anything, "bill-7", "", "manual_close_via_api").Return(nil).Once()
+	mockTemporal.On("ExecuteWorkflow",
+		mock.Anything,
+		mock.MatchedBy(func(options client.StartWorkflowOptions) bool {
+			return options.ID == "dunning-7" && options.TaskQueue == taskQueue
+		}),
+		mock.Anything,
+		workflow.DunningWorkflowParams{BillID: 7, Schedule: schedule},
+	).Return(nil, nil).Once()
+
+	_, err := service.CloseBill(context.Background(), 7, &CloseBillRequest{Reason: "Closed early"})
+
+	assert.NoError(t, err)
+	mockTemporal.AssertExpectations(t)
+}
+
 // TestCloseBillRequest_Validation tests the validation logic
 func TestCloseBillRequest_Validation(t *testing.T) {
 	testCases := []struct {
@@ -0,0 +1,17 @@
+// Exchange rates are pulled hourly from RateSync.Source, a JSON feed URL or a JSON/CSV file.
+// A rate that moves more than MaxChangePercent from the rate in effect is rejected.
+RateSync: {
+	Source:           string | *""
+	MaxChangePercent: float | *10.0
+}
+
+// Dunning.Schedule replaces the default dunning schedule of closed bills, e.g.
+// {AfterDays: 14, Action: "late_fee", LateFeeBasisPoints: 200}; empty runs the default.
+Dunning: {
+	Schedule: [...{
+		AfterDays:          int
+		Action:             "reminder" | "late_fee" | "escalate"
+		LateFeeCents:       int | *0
+		LateFeeBasisPoints: int | *0
+	}] | *[]
+}
@@ -0,0 +1,48 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type CreateAccountRequest struct {
+	Name  string  `json:"name" validate:"required,max=255"`
+	Email *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+}
+
+type AccountResponse struct {
+	Account model.Account `json:"account"`
+}
+
+//encore:api auth path=/v1/accounts method=POST tag:idempotency
+func (s *Service) CreateAccount(ctx context.Context, req *CreateAccountRequest) (*AccountResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.accounts.CreateAccount(ctx, &model.Account{
+		Name:  req.Name,
+		Email: req.Email,
+	})
+	if err != nil {
+		rlog.Error("failed to create account", "error", err)
+		return nil, err
+	}
+
+	return &AccountResponse{
+		Account: *result,
+	}, nil
+}
+
+// Validate implements validation for CreateAccountRequest
+func (r *CreateAccountRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
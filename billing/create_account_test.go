@@ -0,0 +1,117 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/account_business"
+	"encore.app/billing/model"
+)
+
+func TestCreateAccount(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccounts := account_business.NewMockBusiness(ctrl)
+	service := &Service{accounts: mockAccounts}
+
+	email := "billing@acme.test"
+
+	testCases := []struct {
+		name              string
+		request           *CreateAccountRequest
+		mockAccountReturn *model.Account
+		mockAccountError  error
+		expectedError     string
+	}{
+		{
+			name:    "successful_account_creation",
+			request: &CreateAccountRequest{Name: "Acme Corp", Email: &email},
+			mockAccountReturn: &model.Account{
+				ID:      1,
+				Name:    "Acme Corp",
+				Email:   &email,
+				Enabled: true,
+			},
+		},
+		{
+			name:             "account_creation_fails",
+			request:          &CreateAccountRequest{Name: "Acme Corp"},
+			mockAccountError: &errs.Error{Code: errs.Internal, Message: "failed to create account"},
+			expectedError:    "failed to create account",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAccounts.EXPECT().
+				CreateAccount(gomock.Any(), &model.Account{Name: tc.request.Name, Email: tc.request.Email}).
+				Return(tc.mockAccountReturn, tc.mockAccountError).
+				Times(1)
+
+			response, err := service.CreateAccount(context.Background(), tc.request)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockAccountReturn.ID, response.Account.ID)
+				assert.Equal(t, tc.mockAccountReturn.Name, response.Account.Name)
+				assert.True(t, response.Account.Enabled)
+			}
+		})
+	}
+}
+
+// TestCreateAccountRequest_Validation tests the validation logic
+func TestCreateAccountRequest_Validation(t *testing.T) {
+	validEmail := "billing@acme.test"
+	invalidEmail := "not-an-email"
+
+	testCases := []struct {
+		name          string
+		request       *CreateAccountRequest
+		expectedError string
+	}{
+		{
+			name:    "valid_request",
+			request: &CreateAccountRequest{Name: "Acme Corp", Email: &validEmail},
+		},
+		{
+			name:    "valid_request_without_email",
+			request: &CreateAccountRequest{Name: "Acme Corp"},
+		},
+		{
+			name:          "missing_name",
+			request:       &CreateAccountRequest{Name: ""},
+			expectedError: "required",
+		},
+		{
+			name:          "invalid_email",
+			request:       &CreateAccountRequest{Name: "Acme Corp", Email: &invalidEmail},
+			expectedError: "email",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -17,26 +17,54 @@ import (
 type CreateBillRequest struct {
 	IdempotencyKey string `header:"X-Idempotency-Key" json:"-"`
 
+	AccountID int32     `json:"account_id" validate:"omitempty,min=1"`
 	Currency  string    `json:"currency" validate:"required,len=3,alpha"`
 	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required_without=Period"`
+	// Timezone is the IANA timezone period boundaries are resolved in, UTC by default
+	Timezone string `json:"timezone" validate:"omitempty,timezone"`
+	// Period replaces end_time with the calendar period containing start_time (or now)
+	Period string `json:"period" validate:"omitempty,oneof=calendar_month iso_week day"`
+	// AllowNegativeTotal lets credits and adjustments take the bill total below zero
+	AllowNegativeTotal bool `json:"allow_negative_total"`
+	// GracePeriodSeconds keeps the bill open after end_time for line items incurred during the period
+	GracePeriodSeconds int32 `json:"grace_period_seconds" validate:"min=0,max=86400"`
+	// PaymentTermsDays is the number of days the closed bill has to be paid, the account's terms by default
+	PaymentTermsDays *int32 `json:"payment_terms_days,omitempty" validate:"omitempty,min=0,max=365"`
 }
 
 type BillResponse struct {
 	Bill model.Bill `json:"bill"`
 }
 
-//encore:api public path=/v1/bills method=POST tag:idempotency
+//encore:api auth path=/v1/bills method=POST tag:idempotency
 func (s *Service) CreateBill(ctx context.Context, req *CreateBillRequest) (*BillResponse, error) {
+	accountID, err := resolveAccountID(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.StartTime.IsZero() {
 		req.StartTime = time.Now()
 	}
-	result, err := s.business.CreateBill(ctx, &model.Bill{
+	bill := &model.Bill{
+		AccountID:      accountID,
 		Currency:       req.Currency,
 		StartTime:      req.StartTime,
 		EndTime:        req.EndTime,
+		Timezone:       req.Timezone,
 		IdempotencyKey: req.IdempotencyKey,
-	})
+
+		AllowNegativeTotal: req.AllowNegativeTotal,
+		GracePeriodSeconds: req.GracePeriodSeconds,
+		PaymentTermsDays:   req.PaymentTermsDays,
+	}
+	if req.Period != "" {
+		period := model.BillPeriod(req.Period)
+		bill.Period = &period
+	}
+
+	result, err := s.business.CreateBill(ctx, bill, callerActor())
 	if err != nil {
 		rlog.Error("failed to create bill", "error", err)
 		return nil, err
@@ -48,6 +76,8 @@ func (s *Service) CreateBill(ctx context.Context, req *CreateBillRequest) (*Bill
 		rlog.Error("workflow start issue", "bill_id", result.ID, "workflow_id", fmt.Sprintf("bill-%s", result.IdempotencyKey), "error", wfErr)
 	}
 
+	s.newAmountFormatter(ctx).bill(result)
+
 	return &BillResponse{
 		Bill: *result,
 	}, nil
@@ -59,6 +89,14 @@ func (r *CreateBillRequest) Validate() error {
 		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
 	}
 
+	if r.Period != "" {
+		// The period defines the boundaries; start_time only selects which period to bill
+		if !r.EndTime.IsZero() {
+			return &errs.Error{Code: errs.InvalidArgument, Message: "end_time cannot be combined with period"}
+		}
+		return nil
+	}
+
 	if !r.StartTime.IsZero() {
 		if r.StartTime.Before(time.Now()) {
 			return &errs.Error{Code: errs.InvalidArgument, Message: "start_time must be in the future"}
@@ -94,6 +132,9 @@ func (s *Service) startBillingWorkflow(ctx context.Context, bill *model.Bill) er
 		BillID:    bill.ID,
 		StartTime: bill.StartTime,
 		EndTime:   bill.EndTime,
+
+		GracePeriod:     time.Duration(bill.GracePeriodSeconds) * time.Second,
+		DunningSchedule: s.dunningSchedule,
 	}
 
 	_, err := s.temporal.ExecuteWorkflow(ctx, options, workflow.BillingPeriod, params)
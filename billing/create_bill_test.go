@@ -21,6 +21,8 @@ import (
 // Learn more: https://encore.dev/docs/go/develop/testing
 
 func TestCreateBill(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -28,8 +30,9 @@ func TestCreateBill(t *testing.T) {
 	mockTemporal := mocks.NewClient(t)
 
 	service := &Service{
-		business: mockBusiness,
-		temporal: mockTemporal,
+		business:   mockBusiness,
+		temporal:   mockTemporal,
+		currencies: stubMinorUnits(ctrl),
 	}
 
 	now := time.Now()
@@ -48,6 +51,7 @@ func TestCreateBill(t *testing.T) {
 		{
 			name: "successful_bill_creation_with_workflow",
 			request: &CreateBillRequest{
+				AccountID:      1,
 				IdempotencyKey: "test-key-123",
 				Currency:       "USD",
 				StartTime:      futureTime,
@@ -69,6 +73,7 @@ func TestCreateBill(t *testing.T) {
 		{
 			name: "successful_bill_creation_workflow_fails",
 			request: &CreateBillRequest{
+				AccountID:      1,
 				IdempotencyKey: "test-key-456",
 				Currency:       "EUR",
 				StartTime:      futureTime,
@@ -90,6 +95,7 @@ func TestCreateBill(t *testing.T) {
 		{
 			name: "bill_creation_fails",
 			request: &CreateBillRequest{
+				AccountID:      1,
 				IdempotencyKey: "test-key-789",
 				Currency:       "USD",
 				StartTime:      futureTime,
@@ -104,6 +110,7 @@ func TestCreateBill(t *testing.T) {
 		{
 			name: "zero_start_time_sets_to_now",
 			request: &CreateBillRequest{
+				AccountID:      1,
 				IdempotencyKey: "test-key-now",
 				Currency:       "GEL",
 				StartTime:      time.Time{}, // Zero time
@@ -128,7 +135,7 @@ func TestCreateBill(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Set up business mock expectations
 			mockBusiness.EXPECT().
-				CreateBill(gomock.Any(), gomock.Any()).
+				CreateBill(gomock.Any(), gomock.Any(), "admin").
 				Return(tc.mockBusinessReturn, tc.mockBusinessError).
 				Times(1)
 
@@ -179,6 +186,7 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "valid_request",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "USD",
 				StartTime: futureTime,
 				EndTime:   futureTime.Add(time.Hour),
@@ -187,6 +195,7 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "invalid_currency_too_short",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "US",
 				StartTime: futureTime,
 				EndTime:   futureTime.Add(time.Hour),
@@ -196,6 +205,7 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "invalid_currency_too_long",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "USDD",
 				StartTime: futureTime,
 				EndTime:   futureTime.Add(time.Hour),
@@ -205,6 +215,7 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "invalid_currency_numeric",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "123",
 				StartTime: futureTime,
 				EndTime:   futureTime.Add(time.Hour),
@@ -214,15 +225,27 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "missing_currency",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "",
 				StartTime: futureTime,
 				EndTime:   futureTime.Add(time.Hour),
 			},
 			expectedError: "required",
 		},
+		{
+			name: "invalid_account_id",
+			request: &CreateBillRequest{
+				AccountID: -1,
+				Currency:  "USD",
+				StartTime: futureTime,
+				EndTime:   futureTime.Add(time.Hour),
+			},
+			expectedError: "AccountID",
+		},
 		{
 			name: "missing_end_time",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "USD",
 				StartTime: futureTime,
 				EndTime:   time.Time{},
@@ -232,6 +255,7 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "start_time_in_past",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "USD",
 				StartTime: pastTime,
 				EndTime:   futureTime,
@@ -241,6 +265,7 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "end_time_in_past",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "USD",
 				StartTime: futureTime,
 				EndTime:   pastTime,
@@ -250,12 +275,53 @@ func TestCreateBillRequest_Validation(t *testing.T) {
 		{
 			name: "end_time_before_start_time",
 			request: &CreateBillRequest{
+				AccountID: 1,
 				Currency:  "USD",
 				StartTime: time.Time{}, // Zero time, will be set to now
 				EndTime:   pastTime,
 			},
 			expectedError: "end_time must be after start_time",
 		},
+		{
+			name: "valid_period_with_timezone",
+			request: &CreateBillRequest{
+				AccountID: 1,
+				Currency:  "USD",
+				// The period containing start_time is billed, so it may already have begun
+				StartTime: pastTime,
+				Timezone:  "America/New_York",
+				Period:    "calendar_month",
+			},
+		},
+		{
+			name: "invalid_period",
+			request: &CreateBillRequest{
+				AccountID: 1,
+				Currency:  "USD",
+				Period:    "fortnight",
+			},
+			expectedError: "oneof",
+		},
+		{
+			name: "invalid_timezone",
+			request: &CreateBillRequest{
+				AccountID: 1,
+				Currency:  "USD",
+				Timezone:  "Mars/Olympus_Mons",
+				Period:    "day",
+			},
+			expectedError: "timezone",
+		},
+		{
+			name: "period_with_end_time",
+			request: &CreateBillRequest{
+				AccountID: 1,
+				Currency:  "USD",
+				EndTime:   futureTime,
+				Period:    "iso_week",
+			},
+			expectedError: "end_time cannot be combined with period",
+		},
 	}
 
 	for _, tc := range testCases {
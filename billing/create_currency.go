@@ -0,0 +1,63 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type CreateCurrencyRequest struct {
+	Code   string  `json:"code" validate:"required,len=3,alpha,uppercase"`
+	Symbol *string `json:"symbol,omitempty" validate:"omitempty,max=4"`
+	// Rate is the rate against the base currency (USD)
+	Rate float64 `json:"rate" validate:"gt=0"`
+	// MinorUnits is the number of decimal places of the currency; defaults to 2
+	MinorUnits *int32 `json:"minor_units,omitempty" validate:"omitempty,min=0,max=4"`
+	Enabled    bool   `json:"enabled"`
+}
+
+type CurrencyResponse struct {
+	Currency model.CurrencyInfo `json:"currency"`
+}
+
+// CreateCurrency adds a currency without writing a migration
+//
+//encore:api auth path=/v1/currencies method=POST tag:idempotency
+func (s *Service) CreateCurrency(ctx context.Context, req *CreateCurrencyRequest) (*CurrencyResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	minorUnits := model.DefaultMinorUnits
+	if req.MinorUnits != nil {
+		minorUnits = *req.MinorUnits
+	}
+
+	result, err := s.currencies.CreateCurrency(ctx, &model.CurrencyInfo{
+		Code:       req.Code,
+		Symbol:     req.Symbol,
+		Rate:       req.Rate,
+		Enabled:    req.Enabled,
+		MinorUnits: minorUnits,
+	}, callerActor())
+	if err != nil {
+		rlog.Error("failed to create currency", "error", err, "code", req.Code)
+		return nil, err
+	}
+
+	return &CurrencyResponse{
+		Currency: *result,
+	}, nil
+}
+
+// Validate implements validation for CreateCurrencyRequest
+func (r *CreateCurrencyRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
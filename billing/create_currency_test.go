@@ -0,0 +1,55 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/model"
+)
+
+func TestCreateCurrency(t *testing.T) {
+	t.Run("defaults_to_two_minor_units", func(t *testing.T) {
+		withCaller(t, &AuthData{Admin: true})
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCurrencies := currency_business.NewMockBusiness(ctrl)
+		service := &Service{currencies: mockCurrencies}
+
+		mockCurrencies.EXPECT().CreateCurrency(gomock.Any(), &model.CurrencyInfo{Code: "EUR", Rate: 0.92, MinorUnits: 2, Enabled: true}, "admin").
+			Return(&model.CurrencyInfo{ID: 3, Code: "EUR", Rate: 0.92, MinorUnits: 2, Enabled: true}, nil)
+
+		response, err := service.CreateCurrency(context.Background(), &CreateCurrencyRequest{Code: "EUR", Rate: 0.92, Enabled: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), response.Currency.ID)
+	})
+
+	t.Run("account_caller_is_rejected", func(t *testing.T) {
+		withCaller(t, &AuthData{AccountID: 7})
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := &Service{currencies: currency_business.NewMockBusiness(ctrl)}
+
+		response, err := service.CreateCurrency(context.Background(), &CreateCurrencyRequest{Code: "EUR", Rate: 0.92})
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+	})
+}
+
+func TestCreateCurrencyRequest_Validate(t *testing.T) {
+	threeUnits := int32(3)
+	tooManyUnits := int32(5)
+
+	assert.NoError(t, (&CreateCurrencyRequest{Code: "KWD", Rate: 0.31, MinorUnits: &threeUnits}).Validate())
+	assert.Error(t, (&CreateCurrencyRequest{Code: "kwd", Rate: 0.31}).Validate())
+	assert.Error(t, (&CreateCurrencyRequest{Code: "KWDX", Rate: 0.31}).Validate())
+	assert.Error(t, (&CreateCurrencyRequest{Code: "KWD", Rate: 0}).Validate())
+	assert.Error(t, (&CreateCurrencyRequest{Code: "KWD", Rate: 0.31, MinorUnits: &tooManyUnits}).Validate())
+}
@@ -0,0 +1,100 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+type CreateSubscriptionRequest struct {
+	IdempotencyKey string `header:"X-Idempotency-Key" json:"-"`
+
+	AccountID       int32     `json:"account_id" validate:"omitempty,min=1"`
+	Currency        string    `json:"currency" validate:"required,len=3,alpha"`
+	Interval        string    `json:"interval" validate:"required,oneof=monthly weekly custom"`
+	IntervalSeconds *int64    `json:"interval_seconds,omitempty" validate:"required_if=Interval custom,omitempty,min=60"`
+	AnchorAt        time.Time `json:"anchor_at"`
+}
+
+type SubscriptionResponse struct {
+	Subscription model.Subscription `json:"subscription"`
+}
+
+//encore:api auth path=/v1/subscriptions method=POST tag:idempotency
+func (s *Service) CreateSubscription(ctx context.Context, req *CreateSubscriptionRequest) (*SubscriptionResponse, error) {
+	accountID, err := resolveAccountID(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AnchorAt.IsZero() {
+		req.AnchorAt = time.Now()
+	}
+
+	result, err := s.subscriptions.CreateSubscription(ctx, &model.Subscription{
+		AccountID:       accountID,
+		Currency:        req.Currency,
+		Interval:        model.SubscriptionInterval(req.Interval),
+		IntervalSeconds: req.IntervalSeconds,
+		AnchorAt:        req.AnchorAt,
+		IdempotencyKey:  req.IdempotencyKey,
+	})
+	if err != nil {
+		rlog.Error("failed to create subscription", "error", err)
+		return nil, err
+	}
+
+	if wfErr := s.startSubscriptionWorkflow(ctx, result); wfErr != nil {
+		rlog.Error("workflow start issue", "subscription_id", result.ID, "error", wfErr)
+	}
+
+	return &SubscriptionResponse{
+		Subscription: *result,
+	}, nil
+}
+
+// Validate implements validation for CreateSubscriptionRequest
+func (r *CreateSubscriptionRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	if r.Interval != string(model.SubscriptionIntervalCustom) && r.IntervalSeconds != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "interval_seconds is only allowed for custom interval"}
+	}
+
+	return nil
+}
+
+// startSubscriptionWorkflow starts the long-running Temporal workflow that bills each subscription period
+func (s *Service) startSubscriptionWorkflow(ctx context.Context, subscription *model.Subscription) error {
+	workflowID := *subscription.WorkflowID
+	options := client.StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: taskQueue,
+	}
+
+	params := workflow.SubscriptionWorkflowParams{
+		SubscriptionID:  subscription.ID,
+		DunningSchedule: s.dunningSchedule,
+	}
+
+	_, err := s.temporal.ExecuteWorkflow(ctx, options, workflow.Subscription, params)
+	if err != nil {
+		if temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+			rlog.Info("workflow already started", "subscription_id", subscription.ID, "workflow_id", workflowID)
+			return nil
+		}
+		return fmt.Errorf("execute workflow %s: %w", workflowID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/subscription_business"
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+func TestCreateSubscription(t *testing.T) {
+	withCaller(t, &AuthData{AccountID: 9})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubscriptions := subscription_business.NewMockBusiness(ctrl)
+	mockTemporal := mocks.NewClient(t)
+	service := &Service{subscriptions: mockSubscriptions, temporal: mockTemporal}
+
+	anchor := time.Now().Add(time.Hour)
+	workflowID := "subscription-sub-key"
+
+	mockSubscriptions.EXPECT().
+		CreateSubscription(gomock.Any(), &model.Subscription{
+			AccountID:      9,
+			Currency:       "USD",
+			Interval:       model.SubscriptionIntervalMonthly,
+			AnchorAt:       anchor,
+			IdempotencyKey: "sub-key",
+		}).
+		Return(&model.Subscription{
+			ID:         5,
+			AccountID:  9,
+			Currency:   "USD",
+			Interval:   model.SubscriptionIntervalMonthly,
+			AnchorAt:   anchor,
+			Status:     model.SubscriptionStatusActive,
+			WorkflowID: &workflowID,
+		}, nil)
+
+	mockTemporal.On("ExecuteWorkflow",
+		mock.Anything,
+		mock.MatchedBy(func(options client.StartWorkflowOptions) bool { return options.ID == workflowID }),
+		mock.Anything,
+		workflow.SubscriptionWorkflowParams{SubscriptionID: 5},
+	).Return(nil, nil).Once()
+
+	response, err := service.CreateSubscription(context.Background(), &CreateSubscriptionRequest{
+		IdempotencyKey: "sub-key",
+		Currency:       "USD",
+		Interval:       "monthly",
+		AnchorAt:       anchor,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), response.Subscription.ID)
+	assert.Equal(t, model.SubscriptionStatusActive, response.Subscription.Status)
+}
+
+func TestCreateSubscriptionRequest_Validation(t *testing.T) {
+	seconds := int64(3600)
+	tooShort := int64(1)
+
+	testCases := []struct {
+		name          string
+		request       *CreateSubscriptionRequest
+		expectedError string
+	}{
+		{
+			name:    "valid_monthly",
+			request: &CreateSubscriptionRequest{Currency: "USD", Interval: "monthly"},
+		},
+		{
+			name:    "valid_custom",
+			request: &CreateSubscriptionRequest{Currency: "USD", Interval: "custom", IntervalSeconds: &seconds},
+		},
+		{
+			name:          "unknown_interval",
+			request:       &CreateSubscriptionRequest{Currency: "USD", Interval: "yearly"},
+			expectedError: "Interval",
+		},
+		{
+			name:          "custom_without_seconds",
+			request:       &CreateSubscriptionRequest{Currency: "USD", Interval: "custom"},
+			expectedError: "IntervalSeconds",
+		},
+		{
+			name:          "custom_interval_too_short",
+			request:       &CreateSubscriptionRequest{Currency: "USD", Interval: "custom", IntervalSeconds: &tooShort},
+			expectedError: "IntervalSeconds",
+		},
+		{
+			name:          "seconds_for_weekly",
+			request:       &CreateSubscriptionRequest{Currency: "USD", Interval: "weekly", IntervalSeconds: &seconds},
+			expectedError: "interval_seconds is only allowed for custom interval",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE bills DROP COLUMN IF EXISTS allow_negative_total;
+ALTER TABLE line_items DROP COLUMN IF EXISTS kind;
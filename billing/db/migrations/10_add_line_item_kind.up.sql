@@ -0,0 +1,6 @@
+-- Line item kind: charges add to the total, credits reduce it, adjustments may go either way.
+-- amount_cents is signed, so credits are stored as negative amounts.
+ALTER TABLE line_items ADD COLUMN kind varchar(20) NOT NULL DEFAULT 'charge';
+
+-- Whether credits and adjustments may take the bill total below zero
+ALTER TABLE bills ADD COLUMN allow_negative_total boolean NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+ALTER TABLE line_items DROP COLUMN IF EXISTS unit_amount_cents;
+ALTER TABLE line_items DROP COLUMN IF EXISTS quantity;
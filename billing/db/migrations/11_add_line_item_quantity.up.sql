@@ -0,0 +1,8 @@
+-- Line items are quantity x unit price; amount_cents holds the extended amount.
+-- Existing line items become a single unit of their amount.
+ALTER TABLE line_items ADD COLUMN quantity numeric(20, 6) NOT NULL DEFAULT 1;
+ALTER TABLE line_items ADD COLUMN unit_amount_cents bigint;
+
+UPDATE line_items SET unit_amount_cents = amount_cents;
+
+ALTER TABLE line_items ALTER COLUMN unit_amount_cents SET NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_line_items_bill_id_incurred_at;
@@ -0,0 +1,2 @@
+-- Supports keyset pagination of a bill's line items, newest first
+CREATE INDEX idx_line_items_bill_id_incurred_at ON line_items(bill_id, incurred_at DESC, id DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bills_account_id_status;
+DROP INDEX IF EXISTS idx_bills_account_id_total;
+DROP INDEX IF EXISTS idx_bills_account_id_start_time;
+DROP INDEX IF EXISTS idx_bills_account_id_created_at;
+CREATE INDEX idx_bills_account_id_created_at ON bills(account_id, created_at DESC);
@@ -0,0 +1,6 @@
+-- Bill listings page by (sort key, id) within an account; btree indexes serve both directions
+DROP INDEX IF EXISTS idx_bills_account_id_created_at;
+CREATE INDEX idx_bills_account_id_created_at ON bills(account_id, created_at DESC, id DESC);
+CREATE INDEX idx_bills_account_id_start_time ON bills(account_id, start_time DESC, id DESC);
+CREATE INDEX idx_bills_account_id_total ON bills(account_id, (COALESCE(total_amount_cents, 0)) DESC, id DESC);
+CREATE INDEX idx_bills_account_id_status ON bills(account_id, status);
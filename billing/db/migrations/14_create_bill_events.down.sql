@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bill_events_bill_id;
+DROP TABLE IF EXISTS bill_events;
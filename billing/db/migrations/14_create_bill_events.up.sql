@@ -0,0 +1,12 @@
+-- Append-only history of bill status transitions, written in the same transaction as the transition
+CREATE TABLE IF NOT EXISTS "bill_events" (
+  "id" bigserial PRIMARY KEY,
+  "bill_id" int NOT NULL REFERENCES bills (id),
+  "from_status" varchar(20),
+  "to_status" varchar(20) NOT NULL,
+  "reason" text,
+  "actor" varchar(100) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_bill_events_bill_id ON bill_events(bill_id, id);
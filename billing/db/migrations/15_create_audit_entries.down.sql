@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS audit_entries_no_truncate ON audit_entries;
+DROP TRIGGER IF EXISTS audit_entries_append_only ON audit_entries;
+DROP FUNCTION IF EXISTS reject_audit_entry_change();
+ALTER TABLE bills DROP COLUMN IF EXISTS audit_head_hash;
+DROP INDEX IF EXISTS idx_audit_entries_bill_id_seq;
+DROP TABLE IF EXISTS audit_entries;
@@ -0,0 +1,34 @@
+-- Append-only, hash-chained log of every financial write on a bill. Each entry hashes its own
+-- fields together with the hash of the previous entry of the same bill, so editing, inserting
+-- or removing an entry breaks the chain. The payload is kept as text so it can be rehashed
+-- byte for byte.
+CREATE TABLE IF NOT EXISTS "audit_entries" (
+  "id" bigserial PRIMARY KEY,
+  "bill_id" int NOT NULL REFERENCES bills (id),
+  "seq" int NOT NULL,
+  "action" varchar(50) NOT NULL,
+  "actor" varchar(100) NOT NULL,
+  "payload" text NOT NULL,
+  "prev_hash" varchar(64) NOT NULL,
+  "hash" varchar(64) NOT NULL,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_audit_entries_bill_id_seq ON audit_entries(bill_id, seq);
+
+-- The latest entry hash of each bill, so truncating the tail of a chain is detected too
+ALTER TABLE bills ADD COLUMN audit_head_hash varchar(64);
+
+CREATE OR REPLACE FUNCTION reject_audit_entry_change() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_entries is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_entries_append_only
+  BEFORE UPDATE OR DELETE ON audit_entries
+  FOR EACH ROW EXECUTE FUNCTION reject_audit_entry_change();
+
+CREATE TRIGGER audit_entries_no_truncate
+  BEFORE TRUNCATE ON audit_entries
+  FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_entry_change();
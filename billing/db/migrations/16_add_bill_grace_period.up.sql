@@ -0,0 +1,2 @@
+-- Seconds after the end of the billing period during which late line items are still accepted
+ALTER TABLE bills ADD COLUMN grace_period_seconds int NOT NULL DEFAULT 0;
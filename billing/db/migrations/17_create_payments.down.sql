@@ -0,0 +1,2 @@
+ALTER TABLE bills DROP COLUMN IF EXISTS amount_paid_cents;
+DROP TABLE IF EXISTS payments;
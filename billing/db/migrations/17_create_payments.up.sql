@@ -0,0 +1,18 @@
+-- Payments received against closed bills. amount_cents is in the bill currency; a payment made
+-- in another currency keeps the submitted amount and the applied rate in metadata.
+CREATE TABLE IF NOT EXISTS "payments" (
+  "id" serial PRIMARY KEY,
+  "bill_id" int NOT NULL REFERENCES bills (id),
+  "amount_cents" bigint NOT NULL,
+  "currency" varchar(4) NOT NULL,
+  "metadata" jsonb,
+  "reference" varchar(100),
+  "paid_at" timestamptz NOT NULL,
+  "idempotency_key" text NOT NULL UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payments_bill_id ON payments(bill_id, paid_at);
+
+-- Running sum of the payments of a bill, kept in the same transaction as each payment
+ALTER TABLE bills ADD COLUMN amount_paid_cents bigint NOT NULL DEFAULT 0;
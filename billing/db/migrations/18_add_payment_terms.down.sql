@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bills_due_at;
+ALTER TABLE bills DROP COLUMN IF EXISTS overdue_at;
+ALTER TABLE bills DROP COLUMN IF EXISTS due_at;
+ALTER TABLE bills DROP COLUMN IF EXISTS payment_terms_days;
+ALTER TABLE accounts DROP COLUMN IF EXISTS payment_terms_days;
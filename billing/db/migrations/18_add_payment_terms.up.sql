@@ -0,0 +1,8 @@
+-- Days between closing a bill and its due date; accounts hold the default for their new bills
+ALTER TABLE accounts ADD COLUMN payment_terms_days int NOT NULL DEFAULT 30;
+ALTER TABLE bills ADD COLUMN payment_terms_days int NOT NULL DEFAULT 30;
+ALTER TABLE bills ADD COLUMN due_at timestamptz;
+ALTER TABLE bills ADD COLUMN overdue_at timestamptz;
+
+-- The overdue job scans closed bills past their due date that have not been flagged yet
+CREATE INDEX idx_bills_due_at ON bills(due_at) WHERE status = 'closed' AND overdue_at IS NULL;
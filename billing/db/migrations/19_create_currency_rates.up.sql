@@ -0,0 +1,16 @@
+-- Exchange rates against the base currency over time. A rate applies from effective_from until
+-- the next rate of the same currency, so a conversion can always be repeated with the rate that
+-- was in effect when the amount was incurred.
+CREATE TABLE IF NOT EXISTS "currency_rates" (
+  "id" serial PRIMARY KEY,
+  "currency_code" varchar(4) NOT NULL REFERENCES currencies (code),
+  "rate" decimal(18,8) NOT NULL,
+  "effective_from" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT NOW(),
+  UNIQUE (currency_code, effective_from)
+);
+
+-- The current rates have been in use since the start, so they cover everything billed so far.
+-- CreateCurrency seeds the first rate of a new currency from the epoch in the same way.
+INSERT INTO currency_rates (currency_code, rate, effective_from)
+SELECT code, rate, 'epoch'::timestamptz FROM currencies WHERE code IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bills_currency_status;
+ALTER TABLE currencies DROP COLUMN IF EXISTS minor_units;
@@ -0,0 +1,5 @@
+-- Number of decimal places of the currency's minor unit (ISO 4217 exponent), e.g. 2 for USD
+ALTER TABLE currencies ADD COLUMN minor_units int NOT NULL DEFAULT 2;
+
+-- Open bills are looked up by currency before a currency is disabled
+CREATE INDEX idx_bills_currency_status ON bills(currency, status);
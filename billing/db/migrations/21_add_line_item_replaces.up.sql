@@ -0,0 +1,2 @@
+-- A corrected line item voids the original and points back to it
+ALTER TABLE line_items ADD COLUMN replaces_line_item_id int REFERENCES line_items(id);
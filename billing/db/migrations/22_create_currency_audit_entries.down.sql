@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS currency_audit_entries_no_truncate ON currency_audit_entries;
+DROP TRIGGER IF EXISTS currency_audit_entries_append_only ON currency_audit_entries;
+DROP TABLE IF EXISTS currency_audit_heads;
+DROP INDEX IF EXISTS idx_currency_audit_entries_code_seq;
+DROP TABLE IF EXISTS currency_audit_entries;
@@ -0,0 +1,29 @@
+-- Append-only, hash-chained log of every change to a currency and its rates, chained per
+-- currency the same way audit_entries is chained per bill
+CREATE TABLE IF NOT EXISTS "currency_audit_entries" (
+  "id" bigserial PRIMARY KEY,
+  "currency_code" varchar(4) NOT NULL REFERENCES currencies (code),
+  "seq" int NOT NULL,
+  "action" varchar(50) NOT NULL,
+  "actor" varchar(100) NOT NULL,
+  "payload" text NOT NULL,
+  "prev_hash" varchar(64) NOT NULL,
+  "hash" varchar(64) NOT NULL,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_currency_audit_entries_code_seq ON currency_audit_entries(currency_code, seq);
+
+-- The latest entry hash of each currency, so truncating the tail of a chain is detected too
+CREATE TABLE IF NOT EXISTS "currency_audit_heads" (
+  "currency_code" varchar(4) PRIMARY KEY REFERENCES currencies (code),
+  "head_hash" varchar(64) NOT NULL
+);
+
+CREATE TRIGGER currency_audit_entries_append_only
+  BEFORE UPDATE OR DELETE ON currency_audit_entries
+  FOR EACH ROW EXECUTE FUNCTION reject_audit_entry_change();
+
+CREATE TRIGGER currency_audit_entries_no_truncate
+  BEFORE TRUNCATE ON currency_audit_entries
+  FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_entry_change();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bills_account_id_created_at;
+ALTER TABLE bills DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS accounts;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "accounts" (
+  "id" serial PRIMARY KEY,
+  "name" text NOT NULL,
+  "email" text,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT NOW(),
+  "updated_at" timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- Bills created before accounts existed have no owner
+ALTER TABLE bills ADD COLUMN account_id int REFERENCES accounts (id);
+
+-- Index for listing an account's bills newest first
+CREATE INDEX idx_bills_account_id_created_at ON bills(account_id, created_at DESC);
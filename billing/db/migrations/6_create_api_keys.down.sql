@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_api_keys_account_id;
+DROP TABLE IF EXISTS api_keys;
\ No newline at end of file
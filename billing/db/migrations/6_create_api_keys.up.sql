@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "api_keys" (
+  "id" serial PRIMARY KEY,
+  "account_id" int NOT NULL REFERENCES accounts (id),
+  "name" text NOT NULL,
+  "key_prefix" text NOT NULL,
+  "key_hash" text NOT NULL UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT NOW(),
+  "revoked_at" timestamptz
+);
+
+-- Index for listing the keys issued to an account
+CREATE INDEX idx_api_keys_account_id ON api_keys(account_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_subscriptions_account_id;
+DROP INDEX IF EXISTS idx_subscriptions_idempotency_key;
+DROP TABLE IF EXISTS subscriptions;
\ No newline at end of file
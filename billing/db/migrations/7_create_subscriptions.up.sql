@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS "subscriptions" (
+  "id" serial PRIMARY KEY,
+  "account_id" int NOT NULL REFERENCES accounts (id),
+  "currency" varchar(4) NOT NULL,
+  "billing_interval" varchar(20) NOT NULL,
+  "interval_seconds" bigint,
+  "anchor_at" timestamptz NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "workflow_id" text,
+  "current_bill_id" int REFERENCES bills (id),
+  "idempotency_key" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT NOW(),
+  "updated_at" timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- Unique constraint for idempotency
+CREATE UNIQUE INDEX idx_subscriptions_idempotency_key ON subscriptions(idempotency_key);
+
+-- Index for listing an account's subscriptions
+CREATE INDEX idx_subscriptions_account_id ON subscriptions(account_id);
@@ -0,0 +1,2 @@
+ALTER TABLE bills DROP COLUMN IF EXISTS period;
+ALTER TABLE bills DROP COLUMN IF EXISTS timezone;
\ No newline at end of file
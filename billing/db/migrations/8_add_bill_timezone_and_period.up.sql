@@ -0,0 +1,5 @@
+-- IANA timezone the billing period boundaries were resolved in
+ALTER TABLE bills ADD COLUMN timezone text NOT NULL DEFAULT 'UTC';
+
+-- Calendar period spec (calendar_month, iso_week, day); NULL for explicit start/end ranges
+ALTER TABLE bills ADD COLUMN period varchar(20);
@@ -0,0 +1,2 @@
+ALTER TABLE line_items DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE line_items DROP COLUMN IF EXISTS voided_at;
@@ -0,0 +1,3 @@
+-- Voided line items are kept for audit but no longer count towards the bill total
+ALTER TABLE line_items ADD COLUMN voided_at timestamptz;
+ALTER TABLE line_items ADD COLUMN void_reason text;
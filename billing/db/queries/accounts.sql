@@ -0,0 +1,30 @@
+-- Accounts related queries
+
+-- name: CreateAccount :one
+INSERT INTO accounts (
+    name,
+    email
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetAccount :one
+SELECT * FROM accounts WHERE id = $1;
+
+-- name: ListAccounts :many
+SELECT * FROM accounts 
+ORDER BY id 
+LIMIT $1 OFFSET $2;
+
+-- name: CountAccounts :one
+SELECT COUNT(*) FROM accounts;
+
+-- name: UpdateAccount :one
+UPDATE accounts 
+SET name = COALESCE(sqlc.narg('name'), name),
+    email = COALESCE(sqlc.narg('email'), email),
+    enabled = COALESCE(sqlc.narg('enabled'), enabled),
+    payment_terms_days = COALESCE(sqlc.narg('payment_terms_days'), payment_terms_days),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id') 
+RETURNING *;
@@ -0,0 +1,23 @@
+-- API keys related queries
+
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    account_id,
+    name,
+    key_prefix,
+    key_hash
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetAPIKey :one
+SELECT * FROM api_keys WHERE id = $1;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT * FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys 
+SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = $1 
+RETURNING *;
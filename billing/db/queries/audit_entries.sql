@@ -0,0 +1,35 @@
+-- Audit log related queries
+
+-- name: CreateAuditEntry :one
+INSERT INTO audit_entries (
+    bill_id,
+    seq,
+    action,
+    actor,
+    payload,
+    prev_hash,
+    hash,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: CreateAuditEntries :copyfrom
+INSERT INTO audit_entries (
+    bill_id,
+    seq,
+    action,
+    actor,
+    payload,
+    prev_hash,
+    hash,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: GetLastAuditEntry :one
+SELECT * FROM audit_entries WHERE bill_id = $1 ORDER BY seq DESC LIMIT 1;
+
+-- name: ListAuditEntries :many
+SELECT * FROM audit_entries WHERE bill_id = $1 ORDER BY seq;
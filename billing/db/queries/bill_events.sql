@@ -0,0 +1,15 @@
+-- Bill events related queries
+
+-- name: CreateBillEvent :one
+INSERT INTO bill_events (
+    bill_id,
+    from_status,
+    to_status,
+    reason,
+    actor
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListBillEvents :many
+SELECT * FROM bill_events WHERE bill_id = $1 ORDER BY id;
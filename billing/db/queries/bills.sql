@@ -7,9 +7,15 @@ INSERT INTO bills (
     start_time,
     end_time,
     idempotency_key,
-    workflow_id
+    workflow_id,
+    account_id,
+    timezone,
+    period,
+    allow_negative_total,
+    grace_period_seconds,
+    payment_terms_days
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
 -- name: GetBill :one
@@ -24,14 +30,6 @@ RETURNING *;
 -- name: GetBillByIdempotencyKey :one
 SELECT * FROM bills WHERE idempotency_key = $1;
 
--- name: ListBills :many
-SELECT * FROM bills 
-ORDER BY created_at DESC 
-LIMIT $1 OFFSET $2;
-
--- name: CountBills :one
-SELECT COUNT(*) FROM bills;
-
 -- name: GetBillForUpdate :one
 SELECT * FROM bills WHERE id = $1 FOR UPDATE;
 
@@ -40,7 +38,7 @@ UPDATE bills
 SET total_amount_cents = (
     SELECT COALESCE(SUM(amount_cents), 0) 
     FROM line_items 
-    WHERE bill_id = $1
+    WHERE bill_id = $1 AND voided_at IS NULL
 ), updated_at = NOW()
 WHERE id = $1
 RETURNING *;
@@ -53,3 +51,178 @@ SET status = $2,
     updated_at = NOW()
 WHERE id = $1 
 RETURNING *;
+
+-- name: OverrideBillTotal :one
+UPDATE bills
+SET total_amount_cents = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: AddToBillTotal :one
+UPDATE bills
+SET total_amount_cents = COALESCE(total_amount_cents, 0) + $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: AddBillPayment :one
+UPDATE bills
+SET amount_paid_cents = amount_paid_cents + $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- An explicit end time replaces the calendar period the bill was created for
+-- name: UpdateBillEndTime :one
+UPDATE bills
+SET end_time = $2,
+    period = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- Filtered listings use keyset pagination on (sort key, id). Each sort key and direction
+-- has its own query so that it can be served by an index. A cancelled bill owes nothing, so
+-- it never matches a total filter and is left out of listings ordered by total.
+
+-- name: ListBillsByCreatedAtDesc :many
+SELECT * FROM bills
+WHERE account_id = sqlc.arg('account_id')
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY(sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency'))
+  AND (sqlc.narg('start_from')::timestamptz IS NULL OR start_time >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::timestamptz IS NULL OR start_time < sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::timestamptz IS NULL OR end_time >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::timestamptz IS NULL OR end_time < sqlc.narg('end_to'))
+  AND (sqlc.narg('min_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= sqlc.narg('min_total_cents')))
+  AND (sqlc.narg('max_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= sqlc.narg('max_total_cents')))
+  AND (sqlc.narg('cursor_time')::timestamptz IS NULL
+       OR (created_at, id) < (sqlc.narg('cursor_time'), sqlc.narg('cursor_id')::int))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListBillsByCreatedAtAsc :many
+SELECT * FROM bills
+WHERE account_id = sqlc.arg('account_id')
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY(sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency'))
+  AND (sqlc.narg('start_from')::timestamptz IS NULL OR start_time >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::timestamptz IS NULL OR start_time < sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::timestamptz IS NULL OR end_time >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::timestamptz IS NULL OR end_time < sqlc.narg('end_to'))
+  AND (sqlc.narg('min_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= sqlc.narg('min_total_cents')))
+  AND (sqlc.narg('max_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= sqlc.narg('max_total_cents')))
+  AND (sqlc.narg('cursor_time')::timestamptz IS NULL
+       OR (created_at, id) > (sqlc.narg('cursor_time'), sqlc.narg('cursor_id')::int))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListBillsByStartTimeDesc :many
+SELECT * FROM bills
+WHERE account_id = sqlc.arg('account_id')
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY(sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency'))
+  AND (sqlc.narg('start_from')::timestamptz IS NULL OR start_time >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::timestamptz IS NULL OR start_time < sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::timestamptz IS NULL OR end_time >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::timestamptz IS NULL OR end_time < sqlc.narg('end_to'))
+  AND (sqlc.narg('min_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= sqlc.narg('min_total_cents')))
+  AND (sqlc.narg('max_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= sqlc.narg('max_total_cents')))
+  AND (sqlc.narg('cursor_time')::timestamptz IS NULL
+       OR (start_time, id) < (sqlc.narg('cursor_time'), sqlc.narg('cursor_id')::int))
+ORDER BY start_time DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListBillsByStartTimeAsc :many
+SELECT * FROM bills
+WHERE account_id = sqlc.arg('account_id')
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY(sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency'))
+  AND (sqlc.narg('start_from')::timestamptz IS NULL OR start_time >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::timestamptz IS NULL OR start_time < sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::timestamptz IS NULL OR end_time >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::timestamptz IS NULL OR end_time < sqlc.narg('end_to'))
+  AND (sqlc.narg('min_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= sqlc.narg('min_total_cents')))
+  AND (sqlc.narg('max_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= sqlc.narg('max_total_cents')))
+  AND (sqlc.narg('cursor_time')::timestamptz IS NULL
+       OR (start_time, id) > (sqlc.narg('cursor_time'), sqlc.narg('cursor_id')::int))
+ORDER BY start_time ASC, id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListBillsByTotalDesc :many
+SELECT * FROM bills
+WHERE account_id = sqlc.arg('account_id')
+  AND status <> 'cancelled'
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY(sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency'))
+  AND (sqlc.narg('start_from')::timestamptz IS NULL OR start_time >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::timestamptz IS NULL OR start_time < sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::timestamptz IS NULL OR end_time >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::timestamptz IS NULL OR end_time < sqlc.narg('end_to'))
+  AND (sqlc.narg('min_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= sqlc.narg('min_total_cents')))
+  AND (sqlc.narg('max_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= sqlc.narg('max_total_cents')))
+  AND (sqlc.narg('cursor_total')::bigint IS NULL
+       OR (COALESCE(total_amount_cents, 0), id) < (sqlc.narg('cursor_total'), sqlc.narg('cursor_id')::int))
+ORDER BY COALESCE(total_amount_cents, 0) DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListBillsByTotalAsc :many
+SELECT * FROM bills
+WHERE account_id = sqlc.arg('account_id')
+  AND status <> 'cancelled'
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY(sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency'))
+  AND (sqlc.narg('start_from')::timestamptz IS NULL OR start_time >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::timestamptz IS NULL OR start_time < sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::timestamptz IS NULL OR end_time >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::timestamptz IS NULL OR end_time < sqlc.narg('end_to'))
+  AND (sqlc.narg('min_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= sqlc.narg('min_total_cents')))
+  AND (sqlc.narg('max_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= sqlc.narg('max_total_cents')))
+  AND (sqlc.narg('cursor_total')::bigint IS NULL
+       OR (COALESCE(total_amount_cents, 0), id) > (sqlc.narg('cursor_total'), sqlc.narg('cursor_id')::int))
+ORDER BY COALESCE(total_amount_cents, 0) ASC, id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountFilteredBills :one
+SELECT COUNT(*) FROM bills
+WHERE account_id = sqlc.arg('account_id')
+  AND (sqlc.narg('statuses')::text[] IS NULL OR status = ANY(sqlc.narg('statuses')::text[]))
+  AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency'))
+  AND (sqlc.narg('start_from')::timestamptz IS NULL OR start_time >= sqlc.narg('start_from'))
+  AND (sqlc.narg('start_to')::timestamptz IS NULL OR start_time < sqlc.narg('start_to'))
+  AND (sqlc.narg('end_from')::timestamptz IS NULL OR end_time >= sqlc.narg('end_from'))
+  AND (sqlc.narg('end_to')::timestamptz IS NULL OR end_time < sqlc.narg('end_to'))
+  AND (sqlc.narg('min_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= sqlc.narg('min_total_cents')))
+  AND (sqlc.narg('max_total_cents')::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= sqlc.narg('max_total_cents')))
+  AND (NOT sqlc.arg('exclude_cancelled')::bool OR status <> 'cancelled');
+
+-- name: UpdateBillAuditHead :exec
+UPDATE bills SET audit_head_hash = $2 WHERE id = $1;
+
+-- name: ListBillIDsAfter :many
+SELECT id FROM bills WHERE id > $1 ORDER BY id LIMIT $2;
+
+-- name: SetBillDueAt :one
+UPDATE bills
+SET billed_at = NOW(),
+    due_at = NOW() + make_interval(days => payment_terms_days),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListOverdueBills :many
+SELECT * FROM bills
+WHERE status = 'closed'
+  AND overdue_at IS NULL
+  AND due_at < $1
+  AND amount_paid_cents < COALESCE(total_amount_cents, 0)
+ORDER BY due_at, id
+LIMIT $2;
+
+-- name: MarkBillOverdue :one
+UPDATE bills
+SET overdue_at = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
@@ -2,3 +2,105 @@
 
 -- name: GetCurrency :one
 SELECT * FROM currencies WHERE code = $1 AND enabled = true;
+
+-- name: GetCurrencyRateAt :one
+SELECT * FROM currency_rates
+WHERE currency_code = $1 AND effective_from <= $2
+ORDER BY effective_from DESC
+LIMIT 1;
+
+-- name: GetLatestCurrencyRate :one
+SELECT * FROM currency_rates
+WHERE currency_code = $1
+ORDER BY effective_from DESC
+LIMIT 1;
+
+-- name: CreateCurrencyRate :one
+WITH new_rate AS (
+    INSERT INTO currency_rates (currency_code, rate, effective_from)
+    VALUES ($1, $2, $3)
+    RETURNING *
+), current_rate AS (
+    -- currencies.rate follows the latest rate already in effect
+    UPDATE currencies SET rate = $2
+    WHERE code = $1
+      AND $3 <= NOW()
+      AND NOT EXISTS (
+          SELECT 1 FROM currency_rates
+          WHERE currency_code = $1 AND effective_from > $3 AND effective_from <= NOW()
+      )
+)
+SELECT id, currency_code, rate, effective_from, created_at FROM new_rate;
+
+-- name: GetCurrencyByCode :one
+SELECT * FROM currencies WHERE code = $1;
+
+-- name: GetCurrencyByCodeForUpdate :one
+SELECT * FROM currencies WHERE code = $1 FOR UPDATE;
+
+-- name: GetCurrencyByCodeForShare :one
+-- Held while billing in the currency, so it cannot be disabled or rescaled underneath
+SELECT * FROM currencies WHERE code = $1 FOR SHARE;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies ORDER BY code;
+
+-- name: CreateCurrency :one
+WITH new_currency AS (
+    INSERT INTO currencies (code, symbol, rate, minor_units, enabled)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING *
+), initial_rate AS (
+    -- The first rate covers all time, so backdated amounts in the currency can be converted
+    INSERT INTO currency_rates (currency_code, rate, effective_from)
+    SELECT code, rate, 'epoch'::timestamptz FROM new_currency
+)
+SELECT id, code, symbol, rate, enabled, minor_units FROM new_currency;
+
+-- name: UpdateCurrency :one
+WITH new_rate AS (
+    INSERT INTO currency_rates (currency_code, rate, effective_from)
+    SELECT sqlc.arg('code'), sqlc.narg('rate'), NOW()
+    WHERE sqlc.narg('rate') IS NOT NULL
+)
+UPDATE currencies
+SET symbol = COALESCE(sqlc.narg('symbol'), symbol),
+    rate = COALESCE(sqlc.narg('rate'), rate),
+    minor_units = COALESCE(sqlc.narg('minor_units'), minor_units),
+    enabled = COALESCE(sqlc.narg('enabled'), enabled)
+WHERE code = sqlc.arg('code')
+RETURNING id, code, symbol, rate, enabled, minor_units;
+
+-- name: CountOpenBillsByCurrency :one
+SELECT COUNT(*) FROM bills
+WHERE currency = $1 AND status NOT IN ('closed', 'cancelled');
+
+-- name: CurrencyInUse :one
+-- Amounts are stored in minor units, so any row holding one pins the currency's minor units
+SELECT (
+    EXISTS (SELECT 1 FROM bills WHERE currency = $1)
+    OR EXISTS (SELECT 1 FROM line_items WHERE currency = $1 OR metadata->>'original_currency' = $1)
+    OR EXISTS (SELECT 1 FROM payments WHERE currency = $1 OR metadata->>'original_currency' = $1)
+    OR EXISTS (SELECT 1 FROM subscriptions WHERE currency = $1)
+)::bool AS in_use;
+
+-- name: CreateCurrencyAuditEntry :one
+INSERT INTO currency_audit_entries (
+    currency_code,
+    seq,
+    action,
+    actor,
+    payload,
+    prev_hash,
+    hash,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetLastCurrencyAuditEntry :one
+SELECT * FROM currency_audit_entries WHERE currency_code = $1 ORDER BY seq DESC LIMIT 1;
+
+-- name: SetCurrencyAuditHead :exec
+INSERT INTO currency_audit_heads (currency_code, head_hash) VALUES ($1, $2)
+ON CONFLICT (currency_code) DO UPDATE SET head_hash = EXCLUDED.head_hash;
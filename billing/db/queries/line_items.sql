@@ -9,27 +9,61 @@ INSERT INTO line_items (
     incurred_at,
     reference_id,
     metadata,
-    idempotency_key
+    idempotency_key,
+    kind,
+    quantity,
+    unit_amount_cents,
+    replaces_line_item_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
+-- name: CreateLineItems :copyfrom
+INSERT INTO line_items (
+    bill_id,
+    amount_cents,
+    currency,
+    description,
+    incurred_at,
+    reference_id,
+    metadata,
+    idempotency_key,
+    kind,
+    quantity,
+    unit_amount_cents
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+);
+
 -- name: GetLineItem :one
 SELECT * FROM line_items WHERE id = $1;
 
 -- name: GetLineItemsByBill :many
 SELECT * FROM line_items WHERE bill_id = $1 ORDER BY incurred_at DESC;
 
--- name: UpdateLineItem :one
-UPDATE line_items 
-SET amount_cents = $2, description = $3, updated_at = NOW()
-WHERE id = $1 
+-- name: VoidLineItem :one
+UPDATE line_items
+SET voided_at = NOW(), void_reason = $2, updated_at = NOW()
+WHERE id = $1 AND voided_at IS NULL
 RETURNING *;
 
--- name: DeleteLineItem :exec
-DELETE FROM line_items WHERE id = $1;
-
 -- name: GetTotalAmountByBill :one
-SELECT COALESCE(SUM(amount_cents), 0) as total_amount_cents 
+SELECT COALESCE(SUM(amount_cents), 0)::bigint as total_amount_cents 
 FROM line_items 
-WHERE bill_id = $1;
+WHERE bill_id = $1 AND voided_at IS NULL;
+
+-- name: GetLineItemsByIdempotencyKeys :many
+SELECT * FROM line_items WHERE idempotency_key = ANY($1::text[]);
+
+-- name: ListLineItems :many
+SELECT * FROM line_items
+WHERE bill_id = sqlc.arg('bill_id')
+  AND (sqlc.narg('incurred_from')::timestamptz IS NULL OR incurred_at >= sqlc.narg('incurred_from'))
+  AND (sqlc.narg('incurred_to')::timestamptz IS NULL OR incurred_at < sqlc.narg('incurred_to'))
+  AND (sqlc.narg('currency')::text IS NULL OR COALESCE(metadata->>'original_currency', currency) = sqlc.narg('currency'))
+  AND (sqlc.narg('reference_id')::text IS NULL OR reference_id = sqlc.narg('reference_id'))
+  AND (sqlc.narg('description')::text IS NULL OR strpos(lower(description), lower(sqlc.narg('description'))) > 0)
+  AND (sqlc.narg('cursor_incurred_at')::timestamptz IS NULL
+       OR (incurred_at, id) < (sqlc.narg('cursor_incurred_at'), sqlc.narg('cursor_id')::int))
+ORDER BY incurred_at DESC, id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,20 @@
+-- Payments related queries
+
+-- name: CreatePayment :one
+INSERT INTO payments (
+    bill_id,
+    amount_cents,
+    currency,
+    metadata,
+    reference,
+    paid_at,
+    idempotency_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetPaymentByIdempotencyKey :one
+SELECT * FROM payments WHERE idempotency_key = $1;
+
+-- name: ListPaymentsByBill :many
+SELECT * FROM payments WHERE bill_id = $1 ORDER BY paid_at, id;
@@ -0,0 +1,30 @@
+-- Subscriptions related queries
+
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (
+    account_id,
+    currency,
+    billing_interval,
+    interval_seconds,
+    anchor_at,
+    status,
+    workflow_id,
+    idempotency_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetSubscription :one
+SELECT * FROM subscriptions WHERE id = $1;
+
+-- name: UpdateSubscriptionStatus :one
+UPDATE subscriptions 
+SET status = $2, updated_at = NOW()
+WHERE id = $1 
+RETURNING *;
+
+-- name: UpdateSubscriptionCurrentBill :one
+UPDATE subscriptions 
+SET current_bill_id = $2, updated_at = NOW()
+WHERE id = $1 
+RETURNING *;
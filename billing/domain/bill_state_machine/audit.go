@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/auditlog"
+	"encore.app/billing/repository/bills"
+)
+
+// statusChange is the audit payload of a status transition
+type statusChange struct {
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// totalChange is the audit payload of a bill total recalculation
+type totalChange struct {
+	PreviousTotalCents int64 `json:"previous_total_cents"`
+	TotalCents         int64 `json:"total_cents"`
+}
+
+// periodChange is the audit payload of a new billing period end time
+type periodChange struct {
+	PreviousEndTime time.Time `json:"previous_end_time"`
+	EndTime         time.Time `json:"end_time"`
+	Reason          string    `json:"reason,omitempty"`
+}
+
+// totalOverride is the audit payload of a bill total set by an operator
+type totalOverride struct {
+	PreviousTotalCents int64  `json:"previous_total_cents"`
+	TotalCents         int64  `json:"total_cents"`
+	Justification      string `json:"justification"`
+}
+
+// overdueMark is the audit payload of a bill flagged as overdue
+type overdueMark struct {
+	DueAt        time.Time `json:"due_at"`
+	BalanceCents int64     `json:"balance_cents"`
+}
+
+// RecordAuditTx appends an entry to the audit chain of a bill within the caller's transaction
+func (sm *BillStateMachine) RecordAuditTx(ctx context.Context, tx *TxScope, billID int32, action model.AuditAction, actor string, payload any) error {
+	return recordAudit(ctx, tx, billID, action, actor, payload)
+}
+
+// recordAudit chains a new entry onto the last one of the bill and moves the bill's head hash
+// to it. The caller holds the bill row lock, so entries of a bill are appended one at a time.
+func recordAudit(ctx context.Context, tx *TxScope, billID int32, action model.AuditAction, actor string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	seq, prevHash, err := nextAuditSeq(ctx, tx, billID)
+	if err != nil {
+		return err
+	}
+
+	// Postgres keeps microseconds, so the hash is taken over the time as it will be read back
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+	params := auditlog.CreateAuditEntryParams{
+		BillID:    billID,
+		Seq:       seq,
+		Action:    string(action),
+		Actor:     actor,
+		Payload:   string(data),
+		PrevHash:  prevHash,
+		CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+	}
+	params.Hash = hashAuditEntry(billID, seq, params.Action, actor, params.Payload, prevHash, createdAt)
+
+	if _, err := tx.Audit.CreateAuditEntry(ctx, params); err != nil {
+		return err
+	}
+
+	return tx.Bills.UpdateBillAuditHead(ctx, bills.UpdateBillAuditHeadParams{
+		ID:            billID,
+		AuditHeadHash: pgtype.Text{String: params.Hash, Valid: true},
+	})
+}
+
+// AuditRecord is an entry to append to the audit chain of a bill as part of a batch
+type AuditRecord struct {
+	Action  model.AuditAction
+	Payload any
+}
+
+// RecordAuditBatchTx appends entries to the audit chain of a bill in a single write within the
+// caller's transaction
+func (sm *BillStateMachine) RecordAuditBatchTx(ctx context.Context, tx *TxScope, billID int32, actor string, records []AuditRecord) error {
+	return recordAuditBatch(ctx, tx, billID, actor, records)
+}
+
+// recordAuditBatch chains the entries onto the last one of the bill in memory, copies them in at
+// once and moves the bill's head hash to the last of them, so a batch costs the same number of
+// queries whatever its size. The caller holds the bill row lock.
+func recordAuditBatch(ctx context.Context, tx *TxScope, billID int32, actor string, records []AuditRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	seq, prevHash, err := nextAuditSeq(ctx, tx, billID)
+	if err != nil {
+		return err
+	}
+
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+	params := make([]auditlog.CreateAuditEntriesParams, len(records))
+	for i, record := range records {
+		data, err := json.Marshal(record.Payload)
+		if err != nil {
+			return err
+		}
+
+		params[i] = auditlog.CreateAuditEntriesParams{
+			BillID:    billID,
+			Seq:       seq + int32(i),
+			Action:    string(record.Action),
+			Actor:     actor,
+			Payload:   string(data),
+			PrevHash:  prevHash,
+			CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+		}
+		params[i].Hash = hashAuditEntry(billID, params[i].Seq, params[i].Action, actor, params[i].Payload, prevHash, createdAt)
+		prevHash = params[i].Hash
+	}
+
+	if _, err := tx.Audit.CreateAuditEntries(ctx, params); err != nil {
+		return err
+	}
+
+	return tx.Bills.UpdateBillAuditHead(ctx, bills.UpdateBillAuditHeadParams{
+		ID:            billID,
+		AuditHeadHash: pgtype.Text{String: prevHash, Valid: true},
+	})
+}
+
+// nextAuditSeq returns the sequence number of the next entry of a bill and the hash it chains onto
+func nextAuditSeq(ctx context.Context, tx *TxScope, billID int32) (int32, string, error) {
+	last, err := tx.Audit.GetLastAuditEntry(ctx, billID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 1, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	return last.Seq + 1, last.Hash, nil
+}
+
+// hashAuditEntry returns the hex SHA-256 of an entry. The fields are encoded as a JSON array,
+// so no two different entries share an encoding.
+func hashAuditEntry(billID, seq int32, action, actor, payload, prevHash string, createdAt time.Time) string {
+	encoded, _ := json.Marshal([]any{billID, seq, action, actor, payload, prevHash, createdAt.UnixMicro()})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditChain rehashes the entries of a bill, ordered by seq, and checks that they form
+// an unbroken chain ending at the head hash stored on the bill. It reports the first entry
+// that is missing, out of place or modified.
+func VerifyAuditChain(billID int32, entries []auditlog.AuditEntry, headHash string) model.AuditVerification {
+	result := model.AuditVerification{BillID: billID, Entries: len(entries)}
+
+	broken := func(seq int32, problem string) model.AuditVerification {
+		result.BrokenAtSeq = seq
+		result.Problem = problem
+		return result
+	}
+
+	prevHash := ""
+	for i, entry := range entries {
+		seq := int32(i + 1)
+		if entry.Seq != seq {
+			return broken(seq, fmt.Sprintf("entry %d is missing", seq))
+		}
+		if entry.PrevHash != prevHash {
+			return broken(seq, "previous hash does not match the preceding entry")
+		}
+		hash := hashAuditEntry(entry.BillID, entry.Seq, entry.Action, entry.Actor, entry.Payload, entry.PrevHash, entry.CreatedAt.Time)
+		if entry.BillID != billID || entry.Hash != hash {
+			return broken(seq, "entry has been modified")
+		}
+		prevHash = entry.Hash
+	}
+
+	if prevHash != headHash {
+		return broken(int32(len(entries)+1), "chain does not end at the bill's head hash")
+	}
+
+	result.Valid = true
+	result.HeadHash = headHash
+	return result
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/auditlog"
+)
+
+// auditedBill appends n entries to the audit chain of bill 1 and returns them with the head hash
+func auditedBill(t *testing.T, n int) ([]auditlog.AuditEntry, string) {
+	t.Helper()
+
+	bills := &recordingBills{}
+	audit := &recordingAudit{}
+	tx := &TxScope{Bills: bills, Audit: audit}
+	for i := 0; i < n; i++ {
+		err := recordAudit(context.Background(), tx, 1, model.AuditActionLineItemCreated, "admin", map[string]int{"amount_cents": 100 * (i + 1)})
+		require.NoError(t, err)
+	}
+
+	return audit.entries, bills.headHash
+}
+
+func TestRecordAudit_ChainsEntries(t *testing.T) {
+	entries, head := auditedBill(t, 3)
+
+	require.Len(t, entries, 3)
+	assert.Empty(t, entries[0].PrevHash)
+	for i, entry := range entries {
+		assert.Equal(t, int32(i+1), entry.Seq)
+		if i > 0 {
+			assert.Equal(t, entries[i-1].Hash, entry.PrevHash)
+		}
+	}
+	assert.Equal(t, entries[2].Hash, head)
+	assert.JSONEq(t, `{"amount_cents":300}`, entries[2].Payload)
+}
+
+func TestRecordAuditBatch_ContinuesChain(t *testing.T) {
+	bills := &recordingBills{}
+	audit := &recordingAudit{}
+	tx := &TxScope{Bills: bills, Audit: audit}
+	require.NoError(t, recordAudit(context.Background(), tx, 1, model.AuditActionBillCreated, "admin", map[string]string{"currency": "USD"}))
+
+	records := make([]AuditRecord, 3)
+	for i := range records {
+		records[i] = AuditRecord{Action: model.AuditActionLineItemCreated, Payload: map[string]int{"amount_cents": 100 * (i + 1)}}
+	}
+	require.NoError(t, recordAuditBatch(context.Background(), tx, 1, "admin", records))
+
+	require.Len(t, audit.entries, 4)
+	assert.Equal(t, int32(4), audit.entries[3].Seq)
+	assert.JSONEq(t, `{"amount_cents":300}`, audit.entries[3].Payload)
+	assert.True(t, VerifyAuditChain(1, audit.entries, bills.headHash).Valid)
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	testCases := []struct {
+		name          string
+		tamper        func(entries []auditlog.AuditEntry, head string) ([]auditlog.AuditEntry, string)
+		expectValid   bool
+		expectSeq     int32
+		expectProblem string
+	}{
+		{
+			name: "intact_chain",
+			tamper: func(entries []auditlog.AuditEntry, head string) ([]auditlog.AuditEntry, string) {
+				return entries, head
+			},
+			expectValid: true,
+		},
+		{
+			name: "modified_payload",
+			tamper: func(entries []auditlog.AuditEntry, head string) ([]auditlog.AuditEntry, string) {
+				entries[1].Payload = `{"amount_cents":1}`
+				return entries, head
+			},
+			expectSeq:     2,
+			expectProblem: "entry has been modified",
+		},
+		{
+			name: "modified_actor",
+			tamper: func(entries []auditlog.AuditEntry, head string) ([]auditlog.AuditEntry, string) {
+				entries[0].Actor = "account:9"
+				return entries, head
+			},
+			expectSeq:     1,
+			expectProblem: "entry has been modified",
+		},
+		{
+			name: "rehashed_entry_breaks_next_link",
+			tamper: func(entries []auditlog.AuditEntry, head string) ([]auditlog.AuditEntry, string) {
+				e := &entries[1]
+				e.Payload = `{"amount_cents":1}`
+				e.Hash = hashAuditEntry(e.BillID, e.Seq, e.Action, e.Actor, e.Payload, e.PrevHash, e.CreatedAt.Time)
+				return entries, head
+			},
+			expectSeq:     3,
+			expectProblem: "previous hash does not match the preceding entry",
+		},
+		{
+			name: "missing_entry",
+			tamper: func(entries []auditlog.AuditEntry, head string) ([]auditlog.AuditEntry, string) {
+				return append(entries[:1], entries[2:]...), head
+			},
+			expectSeq:     2,
+			expectProblem: "entry 2 is missing",
+		},
+		{
+			name: "truncated_tail",
+			tamper: func(entries []auditlog.AuditEntry, head string) ([]auditlog.AuditEntry, string) {
+				return entries[:2], head
+			},
+			expectSeq:     3,
+			expectProblem: "chain does not end at the bill's head hash",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, head := tc.tamper(auditedBill(t, 3))
+
+			result := VerifyAuditChain(1, entries, head)
+
+			assert.Equal(t, tc.expectValid, result.Valid)
+			assert.Equal(t, tc.expectSeq, result.BrokenAtSeq)
+			assert.Equal(t, tc.expectProblem, result.Problem)
+		})
+	}
+}
+
+func TestVerifyAuditChain_BillWithoutEntries(t *testing.T) {
+	result := VerifyAuditChain(1, nil, "")
+
+	assert.True(t, result.Valid)
+	assert.Zero(t, result.Entries)
+}
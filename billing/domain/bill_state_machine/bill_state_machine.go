@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -12,73 +13,102 @@ import (
 	"encore.dev/beta/errs"
 
 	"encore.app/billing/model"
+	"encore.app/billing/repository/auditlog"
+	"encore.app/billing/repository/billevents"
 	"encore.app/billing/repository/bills"
+	"encore.app/billing/repository/currencies"
 	"encore.app/billing/repository/lineitems"
+	"encore.app/billing/repository/payments"
 )
 
+// TxScope bundles the repositories bound to a single bill transaction.
+// A scope is created per GetBillWithLock call and is only valid inside its callback,
+// so concurrent operations on different bills never share transaction handles.
+type TxScope struct {
+	Bills     bills.Querier
+	LineItems lineitems.Querier
+	Events    billevents.Querier
+	Audit     auditlog.Querier
+	Payments  payments.Querier
+	// Currencies holds the bill currency row for the length of the transaction
+	Currencies currencies.Querier
+}
+
 // StateMachine defines the interface for bill state transitions and transaction management
 type StateMachine interface {
 	// GetBillWithLock performs any operation with proper row-level locking and transaction management
-	GetBillWithLock(ctx context.Context, billID int32, businessLogic func(bills.Bill) error) error
+	GetBillWithLock(ctx context.Context, billID int32, businessLogic func(*TxScope, bills.Bill) error) error
 
-	// GetCurrentTx returns the current transaction for use with other repositories
-	GetCurrentTx() pgx.Tx
+	// WithTx runs the given function in a transaction without locking an existing bill
+	WithTx(ctx context.Context, fn func(*TxScope) error) error
 
-	// State transition methods
-	TransitionToActive(ctx context.Context, id int32) error
-	TransitionToClosingTx(ctx context.Context, id int32, reason string) error
-	TransitionToClosedTx(ctx context.Context, id int32, reason string) error
-	TransitionToFailureStateTx(ctx context.Context, id int32, errorMessage string) error
+	// State transition methods; each one is recorded in the bill history along with its actor
+	TransitionToActive(ctx context.Context, id int32, actor string) error
+	TransitionToClosingGraceTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error
+	TransitionToClosingTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error
+	TransitionToClosedTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error
+	TransitionToFailureStateTx(ctx context.Context, tx *TxScope, id int32, errorMessage, actor string) error
+	ReactivateTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error
+	TransitionToCancelledTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error
 
 	// UpdateBillTotalTx recalculates bill total within transaction
-	UpdateBillTotalTx(ctx context.Context, id int32) error
+	UpdateBillTotalTx(ctx context.Context, tx *TxScope, id int32, actor string) error
+
+	// UpdateEndTimeTx moves the end of the billing period within transaction
+	UpdateEndTimeTx(ctx context.Context, tx *TxScope, id int32, endTime time.Time, reason, actor string) error
+
+	// AddToBillTotalTx adds an amount to the bill total without recalculating it within transaction
+	AddToBillTotalTx(ctx context.Context, tx *TxScope, id int32, amountCents int64, actor string) error
 
-	// GetTxBillRepo returns transaction-aware bill repository
-	GetTxBillRepo() bills.Querier
+	// OverrideBillTotalTx sets the bill total to an operator supplied amount within transaction
+	OverrideBillTotalTx(ctx context.Context, tx *TxScope, id int32, totalCents int64, justification, actor string) error
+
+	// MarkOverdueTx flags a closed bill whose due date passed with a balance left within transaction
+	MarkOverdueTx(ctx context.Context, tx *TxScope, id int32, at time.Time, actor string) error
+
+	// RecordAuditTx appends an entry to the audit chain of a bill within transaction
+	RecordAuditTx(ctx context.Context, tx *TxScope, billID int32, action model.AuditAction, actor string, payload any) error
+
+	// RecordAuditBatchTx appends several entries to the audit chain of a bill at once within transaction
+	RecordAuditBatchTx(ctx context.Context, tx *TxScope, billID int32, actor string, records []AuditRecord) error
+}
 
-	// GetTxLineItemRepo returns transaction-aware line item repository
-	GetTxLineItemRepo() lineitems.Querier
+// txBeginner starts database transactions; satisfied by *pgxpool.Pool
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 // BillStateMachine handles all bill state transitions and complex domain operations
 // Following DDD principles: owns transaction boundaries and repository access
 type BillStateMachine struct {
-	db           *pgxpool.Pool
-	billRepo     bills.Querier
-	lineItemRepo lineitems.Querier
+	db txBeginner
 
-	// Transaction-aware repositories
-	billTx     bills.Querier
-	lineItemTx lineitems.Querier
-	currentTx  pgx.Tx
+	// newScope builds the transaction-aware repositories for a transaction
+	newScope func(tx pgx.Tx) *TxScope
 }
 
-// NewBillStateMachine creates a new bill state machine with database and repository access
-func NewBillStateMachine(db *pgxpool.Pool, billRepo bills.Querier, lineItemRepo lineitems.Querier) *BillStateMachine {
+// NewBillStateMachine creates a new bill state machine with database access
+func NewBillStateMachine(db *pgxpool.Pool) *BillStateMachine {
 	return &BillStateMachine{
-		db:           db,
-		billRepo:     billRepo,
-		lineItemRepo: lineItemRepo,
+		db:       db,
+		newScope: newTxScope,
 	}
 }
 
-// GetTxBillRepo returns transaction-aware bill repository
-func (sm *BillStateMachine) GetTxBillRepo() bills.Querier {
-	return sm.billTx
-}
-
-// GetTxLineItemRepo returns transaction-aware line item repository
-func (sm *BillStateMachine) GetTxLineItemRepo() lineitems.Querier {
-	return sm.lineItemTx
-}
-
-// GetCurrentTx returns the current transaction for use with other repositories
-func (sm *BillStateMachine) GetCurrentTx() pgx.Tx {
-	return sm.currentTx
+// newTxScope binds the sqlc repositories to the given transaction
+func newTxScope(tx pgx.Tx) *TxScope {
+	return &TxScope{
+		Bills:      bills.New(tx),
+		LineItems:  lineitems.New(tx),
+		Events:     billevents.New(tx),
+		Audit:      auditlog.New(tx),
+		Payments:   payments.New(tx),
+		Currencies: currencies.New(tx),
+	}
 }
 
 // transitionWithLock performs a state transition with proper row-level locking and transaction management
-func (sm *BillStateMachine) transitionWithLock(ctx context.Context, id int32, transitionFunc func(bills.Bill) error) error {
+func (sm *BillStateMachine) transitionWithLock(ctx context.Context, id int32, transitionFunc func(*TxScope, bills.Bill) error) error {
 	// Add timeout to prevent hanging on row locks
 	lockCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -89,11 +119,9 @@ func (sm *BillStateMachine) transitionWithLock(ctx context.Context, id int32, tr
 	}
 	defer tx.Rollback(lockCtx)
 
-	sm.currentTx = tx
-	sm.billTx = sm.billRepo.(*bills.Queries).WithTx(tx)
-	sm.lineItemTx = sm.lineItemRepo.(*lineitems.Queries).WithTx(tx)
+	scope := sm.newScope(tx)
 
-	currentBill, err := sm.billTx.GetBillForUpdate(lockCtx, id)
+	currentBill, err := scope.Bills.GetBillForUpdate(lockCtx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &errs.Error{Code: errs.NotFound, Message: "bill not found"}
@@ -104,7 +132,7 @@ func (sm *BillStateMachine) transitionWithLock(ctx context.Context, id int32, tr
 		return &errs.Error{Code: errs.Internal, Message: "failed to lock bill for state transition"}
 	}
 
-	err = transitionFunc(currentBill)
+	err = transitionFunc(scope, currentBill)
 	if err != nil {
 		return err
 	}
@@ -120,8 +148,8 @@ func (sm *BillStateMachine) transitionWithLock(ctx context.Context, id int32, tr
 }
 
 // TransitionToActive updates bill status to active with row locking
-func (sm *BillStateMachine) TransitionToActive(ctx context.Context, id int32) error {
-	return sm.transitionWithLock(ctx, id, func(currentBill bills.Bill) error {
+func (sm *BillStateMachine) TransitionToActive(ctx context.Context, id int32, actor string) error {
+	return sm.transitionWithLock(ctx, id, func(tx *TxScope, currentBill bills.Bill) error {
 		if currentBill.Status != string(model.BillStatusPending) {
 			return &errs.Error{
 				Code:    errs.InvalidArgument,
@@ -129,55 +157,258 @@ func (sm *BillStateMachine) TransitionToActive(ctx context.Context, id int32) er
 			}
 		}
 
-		_, err := sm.billTx.UpdateBillStatus(ctx, bills.UpdateBillStatusParams{
+		_, err := tx.Bills.UpdateBillStatus(ctx, bills.UpdateBillStatusParams{
 			ID:     id,
 			Status: string(model.BillStatusActive),
 		})
-		return err
+		if err != nil {
+			return err
+		}
+
+		return recordTransition(ctx, tx, id, currentBill.Status, model.BillStatusActive, "", actor)
 	})
 }
 
+// TransitionToClosingGraceTx starts the grace window of a bill whose period has ended
+func (sm *BillStateMachine) TransitionToClosingGraceTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error {
+	return updateClosure(ctx, tx, bills.UpdateBillClosureParams{
+		ID:           id,
+		Status:       string(model.BillStatusClosingGrace),
+		CloseReason:  pgtype.Text{String: reason, Valid: true},
+		ErrorMessage: pgtype.Text{Valid: false},
+	}, reason, actor)
+}
+
 // TransitionToClosing updates bill status to closing with close reason and row locking
-func (sm *BillStateMachine) TransitionToClosingTx(ctx context.Context, id int32, reason string) error {
-	_, err := sm.billTx.UpdateBillClosure(ctx, bills.UpdateBillClosureParams{
+func (sm *BillStateMachine) TransitionToClosingTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error {
+	return updateClosure(ctx, tx, bills.UpdateBillClosureParams{
 		ID:           id,
 		Status:       string(model.BillStatusClosing),
 		CloseReason:  pgtype.Text{String: reason, Valid: true},
 		ErrorMessage: pgtype.Text{Valid: false},
-	})
-	return err
+	}, reason, actor)
 }
 
-// TransitionToClosed updates bill status to closed with close reason and row locking
-func (sm *BillStateMachine) TransitionToClosedTx(ctx context.Context, id int32, reason string) error {
-	_, err := sm.billTx.UpdateBillClosure(ctx, bills.UpdateBillClosureParams{
+// TransitionToClosed updates bill status to closed with close reason and row locking.
+// The bill is stamped as billed and falls due after its payment terms.
+func (sm *BillStateMachine) TransitionToClosedTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error {
+	err := updateClosure(ctx, tx, bills.UpdateBillClosureParams{
 		ID:           id,
 		Status:       string(model.BillStatusClosed),
 		CloseReason:  pgtype.Text{String: reason, Valid: true},
 		ErrorMessage: pgtype.Text{Valid: false},
-	})
+	}, reason, actor)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Bills.SetBillDueAt(ctx, id)
 	return err
 }
 
 // TransitionToFailureState updates bill to failed or attention_required with error details and row locking
-func (sm *BillStateMachine) TransitionToFailureStateTx(ctx context.Context, id int32, errorMessage string) error {
-	_, err := sm.billTx.UpdateBillClosure(ctx, bills.UpdateBillClosureParams{
+func (sm *BillStateMachine) TransitionToFailureStateTx(ctx context.Context, tx *TxScope, id int32, errorMessage, actor string) error {
+	return updateClosure(ctx, tx, bills.UpdateBillClosureParams{
 		ID:           id,
 		Status:       string(model.BillStatusAttentionRequired),
 		CloseReason:  pgtype.Text{Valid: false},
 		ErrorMessage: pgtype.Text{String: errorMessage, Valid: true},
+	}, errorMessage, actor)
+}
+
+// ReactivateTx returns a bill to active, clearing its close reason and error details
+func (sm *BillStateMachine) ReactivateTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error {
+	return updateClosure(ctx, tx, bills.UpdateBillClosureParams{
+		ID:           id,
+		Status:       string(model.BillStatusActive),
+		CloseReason:  pgtype.Text{Valid: false},
+		ErrorMessage: pgtype.Text{Valid: false},
+	}, reason, actor)
+}
+
+// TransitionToCancelledTx cancels a bill. The line items and the total are kept as they were;
+// a cancelled bill owes nothing and is left out of anything that counts what is owed.
+func (sm *BillStateMachine) TransitionToCancelledTx(ctx context.Context, tx *TxScope, id int32, reason, actor string) error {
+	return updateClosure(ctx, tx, bills.UpdateBillClosureParams{
+		ID:           id,
+		Status:       string(model.BillStatusCancelled),
+		CloseReason:  pgtype.Text{String: reason, Valid: true},
+		ErrorMessage: pgtype.Text{Valid: false},
+	}, reason, actor)
+}
+
+// updateClosure applies a status change within the caller's transaction and records it.
+// The bill row is already locked by the caller, so the status read here is the one replaced.
+func updateClosure(ctx context.Context, tx *TxScope, params bills.UpdateBillClosureParams, reason, actor string) error {
+	currentBill, err := tx.Bills.GetBill(ctx, params.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Bills.UpdateBillClosure(ctx, params); err != nil {
+		return err
+	}
+
+	return recordTransition(ctx, tx, params.ID, currentBill.Status, model.BillStatus(params.Status), reason, actor)
+}
+
+// recordTransition appends a status transition to the bill history and the audit log
+func recordTransition(ctx context.Context, tx *TxScope, id int32, from string, to model.BillStatus, reason, actor string) error {
+	_, err := tx.Events.CreateBillEvent(ctx, billevents.CreateBillEventParams{
+		BillID:     id,
+		FromStatus: pgtype.Text{String: from, Valid: from != ""},
+		ToStatus:   string(to),
+		Reason:     pgtype.Text{String: reason, Valid: reason != ""},
+		Actor:      actor,
+	})
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, tx, id, model.AuditActionBillStatusChanged, actor, statusChange{
+		FromStatus: from,
+		ToStatus:   string(to),
+		Reason:     reason,
 	})
-	return err
 }
 
 // GetBillWithLock performs any operation with proper row-level locking and transaction management
 // This is the main callback pattern method that allows business logic to be executed within a transaction
-func (sm *BillStateMachine) GetBillWithLock(ctx context.Context, id int32, businessLogic func(bills.Bill) error) error {
+func (sm *BillStateMachine) GetBillWithLock(ctx context.Context, id int32, businessLogic func(*TxScope, bills.Bill) error) error {
 	return sm.transitionWithLock(ctx, id, businessLogic)
 }
 
-// UpdateBillTotalTx recalculates bill total within transaction
-func (sm *BillStateMachine) UpdateBillTotalTx(ctx context.Context, id int32) error {
-	_, err := sm.billTx.UpdateBillTotal(ctx, pgtype.Int4{Int32: id, Valid: true})
-	return err
+// WithTx runs fn in a transaction for writes that have no existing bill row to lock, such as
+// creating a bill together with its first audit entry
+func (sm *BillStateMachine) WithTx(ctx context.Context, fn func(*TxScope) error) error {
+	tx, err := sm.db.Begin(ctx)
+	if err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to start transaction"}
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(sm.newScope(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return &errs.Error{Code: errs.Internal, Message: "failed to commit transaction"}
+	}
+
+	return nil
+}
+
+// UpdateBillTotalTx recalculates bill total within transaction and records the change
+func (sm *BillStateMachine) UpdateBillTotalTx(ctx context.Context, tx *TxScope, id int32, actor string) error {
+	previous, err := tx.Bills.GetBill(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := tx.Bills.UpdateBillTotal(ctx, pgtype.Int4{Int32: id, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, tx, id, model.AuditActionBillTotalRecalculated, actor, totalChange{
+		PreviousTotalCents: previous.TotalAmountCents.Int64,
+		TotalCents:         updated.TotalAmountCents.Int64,
+	})
+}
+
+// AddToBillTotalTx adds amountCents to the bill total within transaction and records the change.
+// Unlike a recalculation it keeps a total an operator overrode.
+func (sm *BillStateMachine) AddToBillTotalTx(ctx context.Context, tx *TxScope, id int32, amountCents int64, actor string) error {
+	updated, err := tx.Bills.AddToBillTotal(ctx, bills.AddToBillTotalParams{
+		ID:               id,
+		TotalAmountCents: pgtype.Int8{Int64: amountCents, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, tx, id, model.AuditActionBillTotalRecalculated, actor, totalChange{
+		PreviousTotalCents: updated.TotalAmountCents.Int64 - amountCents,
+		TotalCents:         updated.TotalAmountCents.Int64,
+	})
+}
+
+// OverrideBillTotalTx sets the bill total to totalCents within transaction and records the
+// change along with the operator's justification
+func (sm *BillStateMachine) OverrideBillTotalTx(ctx context.Context, tx *TxScope, id int32, totalCents int64, justification, actor string) error {
+	previous, err := tx.Bills.GetBill(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Bills.OverrideBillTotal(ctx, bills.OverrideBillTotalParams{
+		ID:               id,
+		TotalAmountCents: pgtype.Int8{Int64: totalCents, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, tx, id, model.AuditActionBillTotalOverridden, actor, totalOverride{
+		PreviousTotalCents: previous.TotalAmountCents.Int64,
+		TotalCents:         totalCents,
+		Justification:      justification,
+	})
+}
+
+// MarkOverdueTx flags a bill as overdue at the given time within transaction. The status is
+// unchanged, so the flag is recorded in the audit log only.
+func (sm *BillStateMachine) MarkOverdueTx(ctx context.Context, tx *TxScope, id int32, at time.Time, actor string) error {
+	updated, err := tx.Bills.MarkBillOverdue(ctx, bills.MarkBillOverdueParams{
+		ID:        id,
+		OverdueAt: pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, tx, id, model.AuditActionBillOverdue, actor, overdueMark{
+		DueAt:        updated.DueAt.Time,
+		BalanceCents: updated.TotalAmountCents.Int64 - updated.AmountPaidCents,
+	})
+}
+
+// UpdateEndTimeTx moves the end of the billing period within transaction. The change is written
+// to the bill history as an event that keeps the status, with the old and new end time in its
+// reason, and to the audit log.
+func (sm *BillStateMachine) UpdateEndTimeTx(ctx context.Context, tx *TxScope, id int32, endTime time.Time, reason, actor string) error {
+	previous, err := tx.Bills.GetBill(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Bills.UpdateBillEndTime(ctx, bills.UpdateBillEndTimeParams{
+		ID:      id,
+		EndTime: pgtype.Timestamptz{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	change := fmt.Sprintf("end_time changed from %s to %s",
+		previous.EndTime.Time.UTC().Format(time.RFC3339), endTime.UTC().Format(time.RFC3339))
+	if reason != "" {
+		change += ": " + reason
+	}
+	_, err = tx.Events.CreateBillEvent(ctx, billevents.CreateBillEventParams{
+		BillID:     id,
+		FromStatus: pgtype.Text{String: previous.Status, Valid: true},
+		ToStatus:   previous.Status,
+		Reason:     pgtype.Text{String: change, Valid: true},
+		Actor:      actor,
+	})
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, tx, id, model.AuditActionBillPeriodChanged, actor, periodChange{
+		PreviousEndTime: previous.EndTime.Time.UTC(),
+		EndTime:         endTime.UTC(),
+		Reason:          reason,
+	})
 }
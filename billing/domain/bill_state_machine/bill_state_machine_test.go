@@ -0,0 +1,416 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"encore.app/billing/model"
+	"encore.app/billing/repository/auditlog"
+	"encore.app/billing/repository/billevents"
+	"encore.app/billing/repository/bills"
+)
+
+// fakeTx is a transaction handle that only records its outcome
+type fakeTx struct {
+	pgx.Tx
+
+	mu        sync.Mutex
+	committed bool
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+type fakeBeginner struct{}
+
+func (fakeBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+// recordingBills is a transaction-bound bill repository that records every bill ID it writes to
+type recordingBills struct {
+	bills.Querier
+
+	mu       sync.Mutex
+	lockedID int32
+	status   string
+	headHash string
+	writes   []int32
+}
+
+func (r *recordingBills) GetBillForUpdate(ctx context.Context, id int32) (bills.Bill, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lockedID = id
+	r.status = string(model.BillStatusActive)
+	return bills.Bill{ID: id, Status: r.status}, nil
+}
+
+func (r *recordingBills) GetBill(ctx context.Context, id int32) (bills.Bill, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return bills.Bill{ID: id, Status: r.status}, nil
+}
+
+func (r *recordingBills) UpdateBillClosure(ctx context.Context, arg bills.UpdateBillClosureParams) (bills.Bill, error) {
+	r.record(arg.ID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = arg.Status
+	return bills.Bill{ID: arg.ID, Status: arg.Status}, nil
+}
+
+func (r *recordingBills) UpdateBillTotal(ctx context.Context, billID pgtype.Int4) (bills.Bill, error) {
+	r.record(billID.Int32)
+	return bills.Bill{ID: billID.Int32}, nil
+}
+
+func (r *recordingBills) OverrideBillTotal(ctx context.Context, arg bills.OverrideBillTotalParams) (bills.Bill, error) {
+	r.record(arg.ID)
+	return bills.Bill{ID: arg.ID, TotalAmountCents: arg.TotalAmountCents}, nil
+}
+
+func (r *recordingBills) UpdateBillEndTime(ctx context.Context, arg bills.UpdateBillEndTimeParams) (bills.Bill, error) {
+	r.record(arg.ID)
+	return bills.Bill{ID: arg.ID, EndTime: arg.EndTime}, nil
+}
+
+func (r *recordingBills) SetBillDueAt(ctx context.Context, id int32) (bills.Bill, error) {
+	r.record(id)
+	return bills.Bill{ID: id}, nil
+}
+
+func (r *recordingBills) MarkBillOverdue(ctx context.Context, arg bills.MarkBillOverdueParams) (bills.Bill, error) {
+	r.record(arg.ID)
+	return bills.Bill{
+		ID:               arg.ID,
+		TotalAmountCents: pgtype.Int8{Int64: 1000, Valid: true},
+		AmountPaidCents:  400,
+		DueAt:            pgtype.Timestamptz{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		OverdueAt:        arg.OverdueAt,
+	}, nil
+}
+
+func (r *recordingBills) UpdateBillAuditHead(ctx context.Context, arg bills.UpdateBillAuditHeadParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.headHash = arg.AuditHeadHash.String
+	return nil
+}
+
+func (r *recordingBills) record(id int32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writes = append(r.writes, id)
+}
+
+// recordingEvents is a transaction-bound bill event repository that keeps the events written
+type recordingEvents struct {
+	billevents.Querier
+
+	events []billevents.CreateBillEventParams
+}
+
+func (r *recordingEvents) CreateBillEvent(ctx context.Context, arg billevents.CreateBillEventParams) (billevents.BillEvent, error) {
+	r.events = append(r.events, arg)
+	return billevents.BillEvent{BillID: arg.BillID, ToStatus: arg.ToStatus}, nil
+}
+
+// recordingAudit is a transaction-bound audit log that keeps the entries appended
+type recordingAudit struct {
+	auditlog.Querier
+
+	entries []auditlog.AuditEntry
+}
+
+func (r *recordingAudit) GetLastAuditEntry(ctx context.Context, billID int32) (auditlog.AuditEntry, error) {
+	if len(r.entries) == 0 {
+		return auditlog.AuditEntry{}, pgx.ErrNoRows
+	}
+	return r.entries[len(r.entries)-1], nil
+}
+
+func (r *recordingAudit) CreateAuditEntry(ctx context.Context, arg auditlog.CreateAuditEntryParams) (auditlog.AuditEntry, error) {
+	entry := auditlog.AuditEntry{
+		ID:        int64(len(r.entries) + 1),
+		BillID:    arg.BillID,
+		Seq:       arg.Seq,
+		Action:    arg.Action,
+		Actor:     arg.Actor,
+		Payload:   arg.Payload,
+		PrevHash:  arg.PrevHash,
+		Hash:      arg.Hash,
+		CreatedAt: arg.CreatedAt,
+	}
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func (r *recordingAudit) CreateAuditEntries(ctx context.Context, arg []auditlog.CreateAuditEntriesParams) (int64, error) {
+	for _, row := range arg {
+		if _, err := r.CreateAuditEntry(ctx, auditlog.CreateAuditEntryParams(row)); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(arg)), nil
+}
+
+func TestGetBillWithLock_ConcurrentBillsUseOwnScope(t *testing.T) {
+	const numBills = 64
+
+	var (
+		mu     sync.Mutex
+		scopes []*recordingBills
+		events = map[*recordingBills]*recordingEvents{}
+		audits = map[*recordingBills]*recordingAudit{}
+	)
+
+	sm := &BillStateMachine{
+		db: fakeBeginner{},
+		newScope: func(tx pgx.Tx) *TxScope {
+			repo := &recordingBills{}
+			eventRepo := &recordingEvents{}
+			auditRepo := &recordingAudit{}
+			mu.Lock()
+			scopes = append(scopes, repo)
+			events[repo] = eventRepo
+			audits[repo] = auditRepo
+			mu.Unlock()
+			return &TxScope{Bills: repo, Events: eventRepo, Audit: auditRepo}
+		},
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errCh := make(chan error, numBills)
+
+	for i := 1; i <= numBills; i++ {
+		wg.Add(1)
+		go func(billID int32) {
+			defer wg.Done()
+			err := sm.GetBillWithLock(ctx, billID, func(tx *TxScope, currentBill bills.Bill) error {
+				if currentBill.ID != billID {
+					return fmt.Errorf("callback for bill %d received bill %d", billID, currentBill.ID)
+				}
+				if err := sm.TransitionToClosingTx(ctx, tx, billID, "concurrent", "admin"); err != nil {
+					return err
+				}
+				// Yield between transitions so other bills interleave with this transaction
+				runtime.Gosched()
+				if err := sm.UpdateBillTotalTx(ctx, tx, billID, "admin"); err != nil {
+					return err
+				}
+				runtime.Gosched()
+				return sm.TransitionToClosedTx(ctx, tx, billID, "concurrent", "admin")
+			})
+			if err != nil {
+				errCh <- err
+			}
+		}(int32(i))
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		t.Fatal(err)
+	}
+
+	require.Len(t, scopes, numBills)
+	seen := make(map[int32]bool, numBills)
+	for _, repo := range scopes {
+		assert.False(t, seen[repo.lockedID], "bill %d locked by more than one scope", repo.lockedID)
+		seen[repo.lockedID] = true
+		// Closing, the total, closed and the due date
+		assert.Equal(t, []int32{repo.lockedID, repo.lockedID, repo.lockedID, repo.lockedID}, repo.writes,
+			"scope for bill %d received writes for other bills", repo.lockedID)
+
+		// Both transitions are recorded in the history of the same bill
+		recorded := events[repo].events
+		require.Len(t, recorded, 2)
+		assert.Equal(t, billevents.CreateBillEventParams{
+			BillID:     repo.lockedID,
+			FromStatus: pgtype.Text{String: string(model.BillStatusActive), Valid: true},
+			ToStatus:   string(model.BillStatusClosing),
+			Reason:     pgtype.Text{String: "concurrent", Valid: true},
+			Actor:      "admin",
+		}, recorded[0])
+		assert.Equal(t, string(model.BillStatusClosing), recorded[1].FromStatus.String)
+		assert.Equal(t, string(model.BillStatusClosed), recorded[1].ToStatus)
+
+		// Every write is chained into the audit log of the same bill
+		entries := audits[repo].entries
+		require.Len(t, entries, 3)
+		assert.Equal(t, string(model.AuditActionBillStatusChanged), entries[0].Action)
+		assert.Equal(t, string(model.AuditActionBillTotalRecalculated), entries[1].Action)
+		assert.Equal(t, string(model.AuditActionBillStatusChanged), entries[2].Action)
+		verification := VerifyAuditChain(repo.lockedID, entries, repo.headHash)
+		assert.True(t, verification.Valid, verification.Problem)
+	}
+}
+
+func TestTransitionToActive_UsesTransactionScope(t *testing.T) {
+	repo := &pendingBills{}
+	eventRepo := &recordingEvents{}
+	auditRepo := &recordingAudit{}
+	tx := &fakeTx{}
+	sm := &BillStateMachine{
+		db: beginnerFunc(func(ctx context.Context) (pgx.Tx, error) { return tx, nil }),
+		newScope: func(pgx.Tx) *TxScope {
+			return &TxScope{Bills: repo, Events: eventRepo, Audit: auditRepo}
+		},
+	}
+
+	err := sm.TransitionToActive(context.Background(), 7, model.ActorSystem)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), repo.updatedID)
+	assert.Equal(t, string(model.BillStatusActive), repo.updatedStatus)
+	assert.True(t, tx.committed)
+	assert.Equal(t, []billevents.CreateBillEventParams{{
+		BillID:     7,
+		FromStatus: pgtype.Text{String: string(model.BillStatusPending), Valid: true},
+		ToStatus:   string(model.BillStatusActive),
+		Actor:      model.ActorSystem,
+	}}, eventRepo.events)
+	require.Len(t, auditRepo.entries, 1)
+	assert.JSONEq(t, `{"from_status":"pending","to_status":"active"}`, auditRepo.entries[0].Payload)
+	assert.Equal(t, auditRepo.entries[0].Hash, repo.headHash)
+}
+
+func TestRecoveryTransitions_RecordOperator(t *testing.T) {
+	const operator = "admin:alice"
+	ctx := context.Background()
+	repo := &recordingBills{status: string(model.BillStatusAttentionRequired)}
+	eventRepo := &recordingEvents{}
+	auditRepo := &recordingAudit{}
+	tx := &TxScope{Bills: repo, Events: eventRepo, Audit: auditRepo}
+	sm := &BillStateMachine{}
+
+	require.NoError(t, sm.OverrideBillTotalTx(ctx, tx, 9, 4200, "agreed with customer", operator))
+	require.NoError(t, sm.ReactivateTx(ctx, tx, 9, "missing usage", operator))
+
+	assert.Equal(t, []int32{9, 9}, repo.writes)
+	assert.Equal(t, string(model.BillStatusActive), repo.status)
+	assert.Equal(t, []billevents.CreateBillEventParams{{
+		BillID:     9,
+		FromStatus: pgtype.Text{String: string(model.BillStatusAttentionRequired), Valid: true},
+		ToStatus:   string(model.BillStatusActive),
+		Reason:     pgtype.Text{String: "missing usage", Valid: true},
+		Actor:      operator,
+	}}, eventRepo.events)
+
+	require.Len(t, auditRepo.entries, 2)
+	assert.Equal(t, string(model.AuditActionBillTotalOverridden), auditRepo.entries[0].Action)
+	assert.Equal(t, operator, auditRepo.entries[0].Actor)
+	assert.JSONEq(t, `{"previous_total_cents":0,"total_cents":4200,"justification":"agreed with customer"}`, auditRepo.entries[0].Payload)
+	assert.True(t, VerifyAuditChain(9, auditRepo.entries, repo.headHash).Valid)
+}
+
+func TestTransitionToCancelledTx_KeepsTotal(t *testing.T) {
+	ctx := context.Background()
+	repo := &recordingBills{status: string(model.BillStatusActive)}
+	eventRepo := &recordingEvents{}
+	auditRepo := &recordingAudit{}
+	tx := &TxScope{Bills: repo, Events: eventRepo, Audit: auditRepo}
+	sm := &BillStateMachine{}
+
+	require.NoError(t, sm.TransitionToCancelledTx(ctx, tx, 4, "created by mistake", "account:3"))
+
+	assert.Equal(t, string(model.BillStatusCancelled), repo.status)
+	// Only the status is written; the total is not overridden
+	assert.Equal(t, []int32{4}, repo.writes)
+	require.Len(t, eventRepo.events, 1)
+	assert.Equal(t, string(model.BillStatusCancelled), eventRepo.events[0].ToStatus)
+	require.Len(t, auditRepo.entries, 1)
+	assert.Equal(t, string(model.AuditActionBillStatusChanged), auditRepo.entries[0].Action)
+}
+
+func TestMarkOverdueTx_RecordsAuditOnly(t *testing.T) {
+	ctx := context.Background()
+	repo := &recordingBills{status: string(model.BillStatusClosed)}
+	eventRepo := &recordingEvents{}
+	auditRepo := &recordingAudit{}
+	tx := &TxScope{Bills: repo, Events: eventRepo, Audit: auditRepo}
+	sm := &BillStateMachine{}
+
+	require.NoError(t, sm.MarkOverdueTx(ctx, tx, 6, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), model.ActorSystem))
+
+	assert.Equal(t, []int32{6}, repo.writes)
+	assert.Empty(t, eventRepo.events)
+	require.Len(t, auditRepo.entries, 1)
+	assert.Equal(t, string(model.AuditActionBillOverdue), auditRepo.entries[0].Action)
+	assert.Equal(t, model.ActorSystem, auditRepo.entries[0].Actor)
+	assert.JSONEq(t, `{"due_at":"2025-03-01T00:00:00Z","balance_cents":600}`, auditRepo.entries[0].Payload)
+}
+
+func TestUpdateEndTimeTx_RecordsChangeInHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := &recordingBills{status: string(model.BillStatusActive)}
+	eventRepo := &recordingEvents{}
+	auditRepo := &recordingAudit{}
+	tx := &TxScope{Bills: repo, Events: eventRepo, Audit: auditRepo}
+	sm := &BillStateMachine{}
+	endTime := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, sm.UpdateEndTimeTx(ctx, tx, 5, endTime, "contract extended", "account:3"))
+
+	assert.Equal(t, []int32{5}, repo.writes)
+	assert.Equal(t, []billevents.CreateBillEventParams{{
+		BillID:     5,
+		FromStatus: pgtype.Text{String: string(model.BillStatusActive), Valid: true},
+		ToStatus:   string(model.BillStatusActive),
+		Reason:     pgtype.Text{String: "end_time changed from 0001-01-01T00:00:00Z to 2025-04-01T00:00:00Z: contract extended", Valid: true},
+		Actor:      "account:3",
+	}}, eventRepo.events)
+	require.Len(t, auditRepo.entries, 1)
+	assert.Equal(t, string(model.AuditActionBillPeriodChanged), auditRepo.entries[0].Action)
+	assert.JSONEq(t, `{"previous_end_time":"0001-01-01T00:00:00Z","end_time":"2025-04-01T00:00:00Z","reason":"contract extended"}`, auditRepo.entries[0].Payload)
+}
+
+type beginnerFunc func(ctx context.Context) (pgx.Tx, error)
+
+func (f beginnerFunc) Begin(ctx context.Context) (pgx.Tx, error) {
+	return f(ctx)
+}
+
+// pendingBills serves a pending bill and records the status update applied to it
+type pendingBills struct {
+	bills.Querier
+
+	updatedID     int32
+	updatedStatus string
+	headHash      string
+}
+
+func (r *pendingBills) GetBillForUpdate(ctx context.Context, id int32) (bills.Bill, error) {
+	return bills.Bill{ID: id, Status: string(model.BillStatusPending)}, nil
+}
+
+func (r *pendingBills) UpdateBillStatus(ctx context.Context, arg bills.UpdateBillStatusParams) (bills.Bill, error) {
+	r.updatedID = arg.ID
+	r.updatedStatus = arg.Status
+	return bills.Bill{ID: arg.ID, Status: arg.Status}, nil
+}
+
+func (r *pendingBills) UpdateBillAuditHead(ctx context.Context, arg bills.UpdateBillAuditHeadParams) error {
+	r.headHash = arg.AuditHeadHash.String
+	return nil
+}
@@ -0,0 +1,86 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+// DunningConfig configures the dunning schedule of closed bills
+type DunningConfig struct {
+	// Schedule replaces the default dunning schedule; empty runs workflow.DefaultDunningSchedule
+	Schedule []DunningStepConfig
+}
+
+// DunningStepConfig is one step of the configured dunning schedule, AfterDays after the due date
+type DunningStepConfig struct {
+	AfterDays          int
+	Action             string
+	LateFeeCents       int64
+	LateFeeBasisPoints int64
+}
+
+// dunningSchedule returns the configured dunning schedule, or nil to run the default one
+func dunningSchedule() []model.DunningStep {
+	var schedule []model.DunningStep
+	for _, step := range cfg.Dunning.Schedule {
+		schedule = append(schedule, model.DunningStep{
+			After:              time.Duration(step.AfterDays) * 24 * time.Hour,
+			Action:             model.DunningAction(step.Action),
+			LateFeeCents:       step.LateFeeCents,
+			LateFeeBasisPoints: step.LateFeeBasisPoints,
+		})
+	}
+	return schedule
+}
+
+// BillDunningEvent is published for every dunning step applied to an unpaid bill: a reminder,
+// a late fee or the final escalation
+type BillDunningEvent struct {
+	model.DunningNotice
+}
+
+// BillDunningTopic carries dunning steps to whoever contacts the customer
+var BillDunningTopic = pubsub.NewTopic[*BillDunningEvent]("bill-dunning", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+// publishDunningNotice is the dunning notifier of the workflow activities
+var publishDunningNotice = func(ctx context.Context, notice model.DunningNotice) error {
+	_, err := BillDunningTopic.Publish(ctx, &BillDunningEvent{DunningNotice: notice})
+	return err
+}
+
+// startDunningWorkflow starts dunning a bill closed through the API. Closing it terminates the
+// billing period workflow, which would otherwise have started dunning; a bill with nothing due
+// ends the workflow at once.
+func (s *Service) startDunningWorkflow(ctx context.Context, billID int32) error {
+	workflowID := workflow.DunningWorkflowID(billID)
+	options := client.StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: taskQueue,
+	}
+
+	params := workflow.DunningWorkflowParams{
+		BillID:   billID,
+		Schedule: s.dunningSchedule,
+	}
+
+	_, err := s.temporal.ExecuteWorkflow(ctx, options, workflow.Dunning, params)
+	if err != nil {
+		if temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+			rlog.Info("workflow already started", "bill_id", billID, "workflow_id", workflowID)
+			return nil
+		}
+		return fmt.Errorf("execute workflow %s: %w", workflowID, err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+//encore:api auth path=/v1/accounts/:id method=GET
+func (s *Service) GetAccount(ctx context.Context, id int32) (*AccountResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid account ID"}
+	}
+
+	result, err := s.accounts.GetAccount(ctx, id)
+	if err != nil {
+		rlog.Error("failed to get account", "error", err, "id", id)
+		return nil, err
+	}
+
+	return &AccountResponse{
+		Account: *result,
+	}, nil
+}
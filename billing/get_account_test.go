@@ -0,0 +1,76 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/account_business"
+	"encore.app/billing/model"
+)
+
+func TestGetAccount(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccounts := account_business.NewMockBusiness(ctrl)
+	service := &Service{accounts: mockAccounts}
+
+	testCases := []struct {
+		name              string
+		accountID         int32
+		mockAccountReturn *model.Account
+		mockAccountError  error
+		expectedError     string
+		expectAccountCall bool
+	}{
+		{
+			name:              "successful_account_retrieval",
+			accountID:         1,
+			mockAccountReturn: &model.Account{ID: 1, Name: "Acme Corp", Enabled: true},
+			expectAccountCall: true,
+		},
+		{
+			name:              "invalid_account_id_zero",
+			accountID:         0,
+			expectedError:     "invalid account ID",
+			expectAccountCall: false,
+		},
+		{
+			name:              "account_not_found",
+			accountID:         404,
+			mockAccountError:  &errs.Error{Code: errs.NotFound, Message: "account not found"},
+			expectedError:     "account not found",
+			expectAccountCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectAccountCall {
+				mockAccounts.EXPECT().
+					GetAccount(gomock.Any(), tc.accountID).
+					Return(tc.mockAccountReturn, tc.mockAccountError).
+					Times(1)
+			}
+
+			response, err := service.GetAccount(context.Background(), tc.accountID)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockAccountReturn.ID, response.Account.ID)
+				assert.Equal(t, tc.mockAccountReturn.Name, response.Account.Name)
+			}
+		})
+	}
+}
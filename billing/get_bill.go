@@ -5,21 +5,49 @@ import (
 
 	"encore.dev/beta/errs"
 	"encore.dev/rlog"
+
+	"encore.app/billing/model"
 )
 
-// encore:api public path=/v1/bills/:id method=GET
-func (s *Service) GetBill(ctx context.Context, id int) (*BillResponse, error) {
+type GetBillRequest struct {
+	// OmitLineItems leaves the line items out of the bill
+	OmitLineItems bool `query:"omit_line_items"`
+	// LineItemsLimit embeds only the newest line items; the rest are paged through
+	// /v1/bills/:id/line_items starting at line_items_next_cursor
+	LineItemsLimit int `query:"line_items_limit" validate:"omitempty,min=1,max=500"`
+}
+
+// encore:api auth path=/v1/bills/:id method=GET
+func (s *Service) GetBill(ctx context.Context, id int, req *GetBillRequest) (*BillResponse, error) {
 	if id <= 0 {
 		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
 	}
 
-	result, err := s.business.GetBill(ctx, int32(id))
+	if err := s.authorizeBill(ctx, int32(id)); err != nil {
+		return nil, err
+	}
+
+	result, err := s.business.GetBill(ctx, int32(id), model.GetBillOptions{
+		OmitLineItems: req.OmitLineItems,
+		LineItemLimit: int32(req.LineItemsLimit),
+	})
 	if err != nil {
 		rlog.Error("failed to get bill", "error", err, "id", id)
 		return nil, err
 	}
 
+	s.newAmountFormatter(ctx).bill(result)
+
 	return &BillResponse{
 		Bill: *result,
 	}, nil
 }
+
+// Validate implements validation for GetBillRequest
+func (r *GetBillRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type BillHistoryResponse struct {
+	Events []model.BillEvent `json:"events"`
+}
+
+// encore:api auth path=/v1/bills/:id/history method=GET
+func (s *Service) GetBillHistory(ctx context.Context, id int32) (*BillHistoryResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	events, err := s.business.GetBillHistory(ctx, id)
+	if err != nil {
+		rlog.Error("failed to get bill history", "error", err, "id", id)
+		return nil, err
+	}
+
+	return &BillHistoryResponse{
+		Events: events,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+func TestGetBillHistory(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness}
+
+	t.Run("returns_events", func(t *testing.T) {
+		events := []model.BillEvent{
+			{ID: 1, BillID: 1, FromStatus: model.BillStatusPending, ToStatus: model.BillStatusActive, Actor: model.ActorSystem},
+		}
+		mockBusiness.EXPECT().GetBillHistory(gomock.Any(), int32(1)).Return(events, nil)
+
+		response, err := service.GetBillHistory(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, events, response.Events)
+	})
+
+	t.Run("invalid_bill_id", func(t *testing.T) {
+		response, err := service.GetBillHistory(context.Background(), 0)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid bill ID")
+		assert.Nil(t, response)
+	})
+}
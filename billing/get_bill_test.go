@@ -16,6 +16,8 @@ import (
 )
 
 func TestGetBill(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -23,8 +25,9 @@ func TestGetBill(t *testing.T) {
 	mockTemporal := mocks.NewClient(t)
 
 	service := &Service{
-		business: mockBusiness,
-		temporal: mockTemporal,
+		business:   mockBusiness,
+		temporal:   mockTemporal,
+		currencies: stubMinorUnits(ctrl),
 	}
 
 	now := time.Now()
@@ -190,13 +193,13 @@ func TestGetBill(t *testing.T) {
 			// Set up business mock expectations for GetBill
 			if tc.expectGetBillCall {
 				mockBusiness.EXPECT().
-					GetBill(gomock.Any(), int32(tc.billID)).
+					GetBill(gomock.Any(), int32(tc.billID), model.GetBillOptions{}).
 					Return(tc.mockGetBillReturn, tc.mockGetBillError).
 					Times(1)
 			}
 
 			// Execute the API call
-			response, err := service.GetBill(context.Background(), tc.billID)
+			response, err := service.GetBill(context.Background(), tc.billID, &GetBillRequest{})
 
 			// Verify results
 			if tc.expectedError != "" {
@@ -212,6 +215,7 @@ func TestGetBill(t *testing.T) {
 					assert.Equal(t, tc.mockGetBillReturn.Currency, response.Bill.Currency)
 					assert.Equal(t, tc.mockGetBillReturn.Status, response.Bill.Status)
 					assert.Equal(t, tc.mockGetBillReturn.TotalAmountCents, response.Bill.TotalAmountCents)
+					assert.Equal(t, model.FormatAmount(tc.mockGetBillReturn.TotalAmountCents, model.DefaultMinorUnits), response.Bill.TotalAmount)
 					assert.Equal(t, tc.mockGetBillReturn.IdempotencyKey, response.Bill.IdempotencyKey)
 					assert.Equal(t, tc.mockGetBillReturn.StartTime, response.Bill.StartTime)
 					assert.Equal(t, tc.mockGetBillReturn.EndTime, response.Bill.EndTime)
@@ -250,6 +254,8 @@ func TestGetBill(t *testing.T) {
 
 // TestGetBill_ParameterValidation tests parameter validation
 func TestGetBill_ParameterValidation(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -257,8 +263,9 @@ func TestGetBill_ParameterValidation(t *testing.T) {
 	mockTemporal := mocks.NewClient(t)
 
 	service := &Service{
-		business: mockBusiness,
-		temporal: mockTemporal,
+		business:   mockBusiness,
+		temporal:   mockTemporal,
+		currencies: stubMinorUnits(ctrl),
 	}
 
 	invalidIDs := []struct {
@@ -273,7 +280,7 @@ func TestGetBill_ParameterValidation(t *testing.T) {
 
 	for _, tc := range invalidIDs {
 		t.Run(tc.name, func(t *testing.T) {
-			response, err := service.GetBill(context.Background(), tc.id)
+			response, err := service.GetBill(context.Background(), tc.id, &GetBillRequest{})
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid bill ID")
@@ -0,0 +1,23 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+)
+
+//encore:api auth path=/v1/subscriptions/:id method=GET
+func (s *Service) GetSubscription(ctx context.Context, id int32) (*SubscriptionResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid subscription ID"}
+	}
+
+	result, err := s.authorizeSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubscriptionResponse{
+		Subscription: *result,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type GetAccountsRequest struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+type GetAccountsResponse struct {
+	Accounts   []model.Account `json:"accounts"`
+	TotalCount int64           `json:"total_count"`
+	Limit      int             `json:"limit"`
+	Offset     int             `json:"offset"`
+}
+
+//encore:api auth path=/v1/accounts method=GET
+func (s *Service) ListAccounts(ctx context.Context, req *GetAccountsRequest) (*GetAccountsResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	accounts, totalCount, err := s.accounts.ListAccounts(ctx, int32(req.Limit), int32(req.Offset))
+	if err != nil {
+		rlog.Error("failed to list accounts", "error", err)
+		return nil, err
+	}
+
+	response := &GetAccountsResponse{
+		Accounts:   make([]model.Account, len(accounts)),
+		TotalCount: totalCount,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	}
+
+	for i, account := range accounts {
+		response.Accounts[i] = *account
+	}
+
+	return response, nil
+}
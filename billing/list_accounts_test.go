@@ -0,0 +1,54 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/account_business"
+	"encore.app/billing/model"
+)
+
+func TestListAccounts_ParameterValidation(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccounts := account_business.NewMockBusiness(ctrl)
+	service := &Service{accounts: mockAccounts}
+
+	parameterTests := []struct {
+		name           string
+		inputLimit     int
+		inputOffset    int
+		expectedLimit  int32
+		expectedOffset int32
+	}{
+		{"zero_limit", 0, 0, 10, 0},
+		{"negative_limit", -1, 0, 10, 0},
+		{"over_max_limit", 500, 20, 100, 20},
+	}
+
+	for _, tc := range parameterTests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAccounts.EXPECT().
+				ListAccounts(gomock.Any(), tc.expectedLimit, tc.expectedOffset).
+				Return([]*model.Account{{ID: 1, Name: "Acme Corp", Enabled: true}}, int64(1), nil).
+				Times(1)
+
+			response, err := service.ListAccounts(context.Background(), &GetAccountsRequest{
+				Limit:  tc.inputLimit,
+				Offset: tc.inputOffset,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, int(tc.expectedLimit), response.Limit)
+			assert.Equal(t, int(tc.expectedOffset), response.Offset)
+			assert.Equal(t, int64(1), response.TotalCount)
+			assert.Len(t, response.Accounts, 1)
+		})
+	}
+}
@@ -2,26 +2,52 @@ package billing
 
 import (
 	"context"
+	"strconv"
+	"time"
 
+	"encore.dev/beta/errs"
 	"encore.dev/rlog"
 
 	"encore.app/billing/model"
 )
 
 type GetBillsRequest struct {
-	Limit  int `query:"limit"`
-	Offset int `query:"offset"`
+	AccountID int32 `query:"account_id" validate:"omitempty,min=1"`
+	Limit     int   `query:"limit"`
+	// Offset is kept for older clients; new clients page with Cursor
+	Offset int    `query:"offset"`
+	Cursor string `query:"cursor"`
+
+	Status        []string  `query:"status" validate:"dive,oneof=pending active closing_grace closing closed attention_required cancelled"`
+	Currency      string    `query:"currency" validate:"omitempty,len=3,alpha"`
+	StartFrom     time.Time `query:"start_from"`
+	StartTo       time.Time `query:"start_to"`
+	EndFrom       time.Time `query:"end_from"`
+	EndTo         time.Time `query:"end_to"`
+	MinTotalCents string    `query:"min_total_cents"`
+	MaxTotalCents string    `query:"max_total_cents"`
+
+	SortBy    string `query:"sort_by" validate:"omitempty,oneof=created_at start_time total_amount_cents"`
+	SortOrder string `query:"sort_order" validate:"omitempty,oneof=asc desc"`
+	// SkipTotalCount leaves out total_count, saving the query that counts every matching bill
+	SkipTotalCount bool `query:"skip_total_count"`
 }
 
 type GetBillsResponse struct {
 	Bills      []model.Bill `json:"bills"`
-	TotalCount int64        `json:"total_count"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	TotalCount *int64       `json:"total_count,omitempty"`
 	Limit      int          `json:"limit"`
 	Offset     int          `json:"offset"`
 }
 
-// encore:api public path=/v1/bills method=GET
+// encore:api auth path=/v1/bills method=GET
 func (s *Service) ListBills(ctx context.Context, req *GetBillsRequest) (*GetBillsResponse, error) {
+	accountID, err := resolveAccountID(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.Limit <= 0 {
 		req.Limit = 10
 	}
@@ -29,22 +55,100 @@ func (s *Service) ListBills(ctx context.Context, req *GetBillsRequest) (*GetBill
 		req.Limit = 100
 	}
 
-	bills, totalCount, err := s.business.ListBills(ctx, int32(req.Limit), int32(req.Offset))
+	minTotal, maxTotal, err := req.totalRange()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.BillFilter{
+		Currency:          req.Currency,
+		StartFrom:         optionalTime(req.StartFrom),
+		StartTo:           optionalTime(req.StartTo),
+		EndFrom:           optionalTime(req.EndFrom),
+		EndTo:             optionalTime(req.EndTo),
+		MinTotalCents:     minTotal,
+		MaxTotalCents:     maxTotal,
+		SortBy:            model.BillSortKey(req.SortBy),
+		Ascending:         req.SortOrder == "asc",
+		Cursor:            req.Cursor,
+		Limit:             int32(req.Limit),
+		Offset:            int32(req.Offset),
+		IncludeTotalCount: !req.SkipTotalCount,
+	}
+	for _, status := range req.Status {
+		filter.Statuses = append(filter.Statuses, model.BillStatus(status))
+	}
+
+	page, err := s.business.ListBills(ctx, accountID, filter)
 	if err != nil {
 		rlog.Error("failed to get bills", "error", err)
 		return nil, err
 	}
 
 	response := &GetBillsResponse{
-		Bills:      make([]model.Bill, len(bills)),
-		TotalCount: totalCount,
+		Bills:      make([]model.Bill, len(page.Bills)),
+		NextCursor: page.NextCursor,
+		TotalCount: page.TotalCount,
 		Limit:      req.Limit,
 		Offset:     req.Offset,
 	}
 
-	for i, bill := range bills {
+	amounts := s.newAmountFormatter(ctx)
+	for i, bill := range page.Bills {
+		amounts.bill(bill)
 		response.Bills[i] = *bill
 	}
 
 	return response, nil
 }
+
+// Validate implements validation for GetBillsRequest
+func (r *GetBillsRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	if r.Cursor != "" && r.Offset != 0 {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "cursor cannot be combined with offset"}
+	}
+	if !r.StartFrom.IsZero() && !r.StartTo.IsZero() && !r.StartTo.After(r.StartFrom) {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "start_to must be after start_from"}
+	}
+	if !r.EndFrom.IsZero() && !r.EndTo.IsZero() && !r.EndTo.After(r.EndFrom) {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "end_to must be after end_from"}
+	}
+	minTotal, maxTotal, err := r.totalRange()
+	if err != nil {
+		return err
+	}
+	if minTotal != nil && maxTotal != nil && *maxTotal < *minTotal {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "max_total_cents must not be below min_total_cents"}
+	}
+
+	return nil
+}
+
+// totalRange parses the optional total amount bounds
+func (r *GetBillsRequest) totalRange() (*int64, *int64, error) {
+	var bounds [2]*int64
+	for i, raw := range []string{r.MinTotalCents, r.MaxTotalCents} {
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, nil, &errs.Error{Code: errs.InvalidArgument, Message: "total amount bounds must be integers"}
+		}
+		bounds[i] = &value
+	}
+
+	return bounds[0], bounds[1], nil
+}
+
+// optionalTime maps an unset time query parameter to nil
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
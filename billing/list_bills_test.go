@@ -16,6 +16,8 @@ import (
 )
 
 func TestListBills(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -23,8 +25,9 @@ func TestListBills(t *testing.T) {
 	mockTemporal := mocks.NewClient(t)
 
 	service := &Service{
-		business: mockBusiness,
-		temporal: mockTemporal,
+		business:   mockBusiness,
+		temporal:   mockTemporal,
+		currencies: stubMinorUnits(ctrl),
 	}
 
 	now := time.Now()
@@ -45,8 +48,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "successful_bills_listing_default_limit",
 			request: &GetBillsRequest{
-				Limit:  0, // Should default to 10
-				Offset: 0,
+				AccountID: 1,
+				Limit:     0, // Should default to 10
+				Offset:    0,
 			},
 			mockListBillsReturn: []*model.Bill{
 				{
@@ -82,8 +86,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "successful_bills_listing_custom_limit",
 			request: &GetBillsRequest{
-				Limit:  5,
-				Offset: 10,
+				AccountID: 1,
+				Limit:     5,
+				Offset:    10,
 			},
 			mockListBillsReturn: []*model.Bill{
 				{
@@ -110,8 +115,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "limit_exceeds_maximum_capped_to_100",
 			request: &GetBillsRequest{
-				Limit:  150, // Should be capped to 100
-				Offset: 0,
+				AccountID: 1,
+				Limit:     150, // Should be capped to 100
+				Offset:    0,
 			},
 			mockListBillsReturn: []*model.Bill{
 				{
@@ -136,8 +142,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "negative_limit_defaults_to_10",
 			request: &GetBillsRequest{
-				Limit:  -5, // Should default to 10
-				Offset: 0,
+				AccountID: 1,
+				Limit:     -5, // Should default to 10
+				Offset:    0,
 			},
 			mockListBillsReturn: []*model.Bill{},
 			mockTotalCount:      0,
@@ -150,8 +157,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "empty_results",
 			request: &GetBillsRequest{
-				Limit:  10,
-				Offset: 1000, // Far beyond available data
+				AccountID: 1,
+				Limit:     10,
+				Offset:    1000, // Far beyond available data
 			},
 			mockListBillsReturn: []*model.Bill{},
 			mockTotalCount:      5,
@@ -164,8 +172,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "business_logic_error",
 			request: &GetBillsRequest{
-				Limit:  10,
-				Offset: 0,
+				AccountID: 1,
+				Limit:     10,
+				Offset:    0,
 			},
 			mockListBillsError:  &errs.Error{Code: errs.Internal, Message: "database connection error"},
 			expectedError:       "database connection error",
@@ -177,8 +186,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "bills_with_line_items",
 			request: &GetBillsRequest{
-				Limit:  2,
-				Offset: 0,
+				AccountID: 1,
+				Limit:     2,
+				Offset:    0,
 			},
 			mockListBillsReturn: []*model.Bill{
 				{
@@ -235,8 +245,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "bills_with_different_statuses",
 			request: &GetBillsRequest{
-				Limit:  4,
-				Offset: 0,
+				AccountID: 1,
+				Limit:     4,
+				Offset:    0,
 			},
 			mockListBillsReturn: []*model.Bill{
 				{
@@ -297,8 +308,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "access_denied_error",
 			request: &GetBillsRequest{
-				Limit:  10,
-				Offset: 0,
+				AccountID: 1,
+				Limit:     10,
+				Offset:    0,
 			},
 			mockListBillsError:  &errs.Error{Code: errs.PermissionDenied, Message: "access denied"},
 			expectedError:       "access denied",
@@ -314,8 +326,12 @@ func TestListBills(t *testing.T) {
 			// Set up business mock expectations for ListBills
 			if tc.expectListBillsCall {
 				mockBusiness.EXPECT().
-					ListBills(gomock.Any(), int32(tc.expectedLimit), int32(tc.expectedOffset)).
-					Return(tc.mockListBillsReturn, tc.mockTotalCount, tc.mockListBillsError).
+					ListBills(gomock.Any(), int32(1), model.BillFilter{
+						Limit:             int32(tc.expectedLimit),
+						Offset:            int32(tc.expectedOffset),
+						IncludeTotalCount: true,
+					}).
+					Return(&model.BillPage{Bills: tc.mockListBillsReturn, TotalCount: &tc.mockTotalCount}, tc.mockListBillsError).
 					Times(1)
 			}
 
@@ -333,7 +349,7 @@ func TestListBills(t *testing.T) {
 
 				if tc.expectSuccess {
 					// Check response metadata
-					assert.Equal(t, tc.mockTotalCount, response.TotalCount)
+					assert.Equal(t, &tc.mockTotalCount, response.TotalCount)
 					assert.Equal(t, tc.expectedLimit, response.Limit)
 					assert.Equal(t, tc.expectedOffset, response.Offset)
 					assert.Equal(t, len(tc.mockListBillsReturn), len(response.Bills))
@@ -383,6 +399,8 @@ func TestListBills(t *testing.T) {
 
 // TestListBills_EdgeCases tests edge cases and error conditions
 func TestListBills_EdgeCases(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -390,8 +408,9 @@ func TestListBills_EdgeCases(t *testing.T) {
 	mockTemporal := mocks.NewClient(t)
 
 	service := &Service{
-		business: mockBusiness,
-		temporal: mockTemporal,
+		business:   mockBusiness,
+		temporal:   mockTemporal,
+		currencies: stubMinorUnits(ctrl),
 	}
 
 	t.Run("context_cancellation", func(t *testing.T) {
@@ -399,11 +418,11 @@ func TestListBills_EdgeCases(t *testing.T) {
 		cancel() // Cancel immediately
 
 		mockBusiness.EXPECT().
-			ListBills(gomock.Any(), int32(10), int32(0)).
-			Return(nil, int64(0), context.Canceled).
+			ListBills(gomock.Any(), int32(1), model.BillFilter{Limit: 10, IncludeTotalCount: true}).
+			Return(nil, context.Canceled).
 			Times(1)
 
-		request := &GetBillsRequest{Limit: 10, Offset: 0}
+		request := &GetBillsRequest{AccountID: 1, Limit: 10, Offset: 0}
 		response, err := service.ListBills(ctx, request)
 
 		assert.Error(t, err)
@@ -428,18 +447,19 @@ func TestListBills_EdgeCases(t *testing.T) {
 			}
 		}
 
+		totalCount := int64(10000)
 		mockBusiness.EXPECT().
-			ListBills(gomock.Any(), int32(100), int32(5000)).
-			Return(bills, int64(10000), nil).
+			ListBills(gomock.Any(), int32(1), model.BillFilter{Limit: 100, Offset: 5000, IncludeTotalCount: true}).
+			Return(&model.BillPage{Bills: bills, TotalCount: &totalCount}, nil).
 			Times(1)
 
-		request := &GetBillsRequest{Limit: 100, Offset: 5000}
+		request := &GetBillsRequest{AccountID: 1, Limit: 100, Offset: 5000}
 		response, err := service.ListBills(context.Background(), request)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
 		assert.Equal(t, 100, len(response.Bills))
-		assert.Equal(t, int64(10000), response.TotalCount)
+		assert.Equal(t, &totalCount, response.TotalCount)
 		assert.Equal(t, 100, response.Limit)
 		assert.Equal(t, 5000, response.Offset)
 	})
@@ -463,20 +483,21 @@ func TestListBills_EdgeCases(t *testing.T) {
 
 		// Multiple calls should work
 		mockBusiness.EXPECT().
-			ListBills(gomock.Any(), int32(10), int32(0)).
-			Return(mockBills, int64(1), nil).
+			ListBills(gomock.Any(), int32(1), model.BillFilter{Limit: 10}).
+			Return(&model.BillPage{Bills: mockBills, NextCursor: "next"}, nil).
 			Times(3)
 
 		// Make multiple concurrent calls
 		done := make(chan bool, 3)
 		for i := 0; i < 3; i++ {
 			go func() {
-				request := &GetBillsRequest{Limit: 10, Offset: 0}
+				request := &GetBillsRequest{AccountID: 1, Limit: 10, Offset: 0, SkipTotalCount: true}
 				response, err := service.ListBills(context.Background(), request)
 				assert.NoError(t, err)
 				assert.NotNil(t, response)
 				assert.Equal(t, 1, len(response.Bills))
-				assert.Equal(t, int64(1), response.TotalCount)
+				assert.Equal(t, "next", response.NextCursor)
+				assert.Nil(t, response.TotalCount)
 				done <- true
 			}()
 		}
@@ -490,6 +511,8 @@ func TestListBills_EdgeCases(t *testing.T) {
 
 // TestListBills_ParameterValidation tests parameter validation and normalization
 func TestListBills_ParameterValidation(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -497,8 +520,9 @@ func TestListBills_ParameterValidation(t *testing.T) {
 	mockTemporal := mocks.NewClient(t)
 
 	service := &Service{
-		business: mockBusiness,
-		temporal: mockTemporal,
+		business:   mockBusiness,
+		temporal:   mockTemporal,
+		currencies: stubMinorUnits(ctrl),
 	}
 
 	parameterTests := []struct {
@@ -520,13 +544,14 @@ func TestListBills_ParameterValidation(t *testing.T) {
 	for _, tc := range parameterTests {
 		t.Run(tc.name, func(t *testing.T) {
 			mockBusiness.EXPECT().
-				ListBills(gomock.Any(), tc.expectedLimit, tc.expectedOffset).
-				Return([]*model.Bill{}, int64(0), nil).
+				ListBills(gomock.Any(), int32(1), model.BillFilter{Limit: tc.expectedLimit, Offset: tc.expectedOffset, IncludeTotalCount: true}).
+				Return(&model.BillPage{Bills: []*model.Bill{}}, nil).
 				Times(1)
 
 			request := &GetBillsRequest{
-				Limit:  tc.inputLimit,
-				Offset: tc.inputOffset,
+				AccountID: 1,
+				Limit:     tc.inputLimit,
+				Offset:    tc.inputOffset,
 			}
 			response, err := service.ListBills(context.Background(), request)
 
@@ -537,3 +562,107 @@ func TestListBills_ParameterValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestListBills_Filters tests that filters, sorting and cursors reach the business layer
+func TestListBills_Filters(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness, currencies: stubMinorUnits(ctrl)}
+
+	startFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	minTotal := int64(-500)
+
+	mockBusiness.EXPECT().
+		ListBills(gomock.Any(), int32(1), model.BillFilter{
+			Statuses:      []model.BillStatus{model.BillStatusActive, model.BillStatusClosed},
+			Currency:      "USD",
+			StartFrom:     &startFrom,
+			MinTotalCents: &minTotal,
+			SortBy:        model.BillSortTotal,
+			Ascending:     true,
+			Cursor:        "abc",
+			Limit:         25,
+		}).
+		Return(&model.BillPage{Bills: []*model.Bill{{ID: 7}}, NextCursor: "def"}, nil)
+
+	response, err := service.ListBills(context.Background(), &GetBillsRequest{
+		AccountID:      1,
+		Limit:          25,
+		Cursor:         "abc",
+		Status:         []string{"active", "closed"},
+		Currency:       "USD",
+		StartFrom:      startFrom,
+		MinTotalCents:  "-500",
+		SortBy:         "total_amount_cents",
+		SortOrder:      "asc",
+		SkipTotalCount: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), response.Bills[0].ID)
+	assert.Equal(t, "def", response.NextCursor)
+	assert.Nil(t, response.TotalCount)
+}
+
+// TestGetBillsRequest_Validation tests the validation of filters and paging parameters
+func TestGetBillsRequest_Validation(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		request       *GetBillsRequest
+		expectedError string
+	}{
+		{
+			name:    "valid_request",
+			request: &GetBillsRequest{Status: []string{"active"}, SortBy: "start_time", SortOrder: "desc", MinTotalCents: "0", MaxTotalCents: "100"},
+		},
+		{
+			name:          "invalid_status",
+			request:       &GetBillsRequest{Status: []string{"active", "archived"}},
+			expectedError: "oneof",
+		},
+		{
+			name:          "invalid_sort_key",
+			request:       &GetBillsRequest{SortBy: "currency"},
+			expectedError: "oneof",
+		},
+		{
+			name:          "cursor_with_offset",
+			request:       &GetBillsRequest{Cursor: "abc", Offset: 10},
+			expectedError: "cursor cannot be combined with offset",
+		},
+		{
+			name:          "empty_start_range",
+			request:       &GetBillsRequest{StartFrom: from, StartTo: from},
+			expectedError: "start_to must be after start_from",
+		},
+		{
+			name:          "non_integer_total",
+			request:       &GetBillsRequest{MinTotalCents: "1.5"},
+			expectedError: "total amount bounds must be integers",
+		},
+		{
+			name:          "inverted_total_range",
+			request:       &GetBillsRequest{MinTotalCents: "100", MaxTotalCents: "10"},
+			expectedError: "max_total_cents must not be below min_total_cents",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
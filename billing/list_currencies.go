@@ -0,0 +1,37 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type ListCurrenciesResponse struct {
+	Currencies []model.CurrencyInfo `json:"currencies"`
+}
+
+// ListCurrencies returns every currency, including disabled ones
+//
+//encore:api auth path=/v1/currencies method=GET
+func (s *Service) ListCurrencies(ctx context.Context) (*ListCurrenciesResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	currencies, err := s.currencies.ListCurrencies(ctx)
+	if err != nil {
+		rlog.Error("failed to list currencies", "error", err)
+		return nil, err
+	}
+
+	response := &ListCurrenciesResponse{
+		Currencies: make([]model.CurrencyInfo, len(currencies)),
+	}
+	for i, currency := range currencies {
+		response.Currencies[i] = *currency
+	}
+
+	return response, nil
+}
@@ -0,0 +1,86 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type ListLineItemsRequest struct {
+	// IncurredFrom is inclusive and IncurredTo exclusive
+	IncurredFrom time.Time `query:"incurred_from"`
+	IncurredTo   time.Time `query:"incurred_to"`
+	// Currency filters on the currency the line item was submitted in
+	Currency    string `query:"currency" validate:"omitempty,len=3,alpha"`
+	ReferenceID string `query:"reference_id" validate:"omitempty,max=100"`
+	// Description matches a case-insensitive substring
+	Description string `query:"description" validate:"omitempty,max=255"`
+	Cursor      string `query:"cursor"`
+	Limit       int    `query:"limit"`
+}
+
+type ListLineItemsResponse struct {
+	LineItems  []model.LineItem `json:"line_items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// encore:api auth path=/v1/bills/:id/line_items method=GET
+func (s *Service) ListLineItems(ctx context.Context, id int32, req *ListLineItemsRequest) (*ListLineItemsResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 50
+	}
+	if req.Limit > 500 {
+		req.Limit = 500
+	}
+
+	filter := model.LineItemFilter{
+		Currency:     req.Currency,
+		ReferenceID:  req.ReferenceID,
+		Description:  req.Description,
+		Cursor:       req.Cursor,
+		Limit:        int32(req.Limit),
+		IncurredFrom: optionalTime(req.IncurredFrom),
+		IncurredTo:   optionalTime(req.IncurredTo),
+	}
+
+	page, err := s.business.ListLineItems(ctx, id, filter)
+	if err != nil {
+		rlog.Error("failed to list line items", "error", err, "bill_id", id)
+		return nil, err
+	}
+
+	amounts := s.newAmountFormatter(ctx)
+	for i := range page.LineItems {
+		amounts.lineItem(&page.LineItems[i])
+	}
+
+	return &ListLineItemsResponse{
+		LineItems:  page.LineItems,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// Validate implements validation for ListLineItemsRequest
+func (r *ListLineItemsRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	if !r.IncurredFrom.IsZero() && !r.IncurredTo.IsZero() && !r.IncurredTo.After(r.IncurredFrom) {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "incurred_to must be after incurred_from"}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+func TestListLineItems(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness, currencies: stubMinorUnits(ctrl)}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name               string
+		billID             int32
+		request            *ListLineItemsRequest
+		expectedFilter     model.LineItemFilter
+		mockReturn         *model.LineItemPage
+		expectedError      string
+		expectBusinessCall bool
+	}{
+		{
+			name:               "default_limit",
+			billID:             1,
+			request:            &ListLineItemsRequest{},
+			expectedFilter:     model.LineItemFilter{Limit: 50},
+			mockReturn:         &model.LineItemPage{LineItems: []model.LineItem{{ID: 1}}, NextCursor: "next"},
+			expectBusinessCall: true,
+		},
+		{
+			name:    "filters_and_capped_limit",
+			billID:  1,
+			request: &ListLineItemsRequest{IncurredFrom: from, Currency: "GEL", Description: "fee", Cursor: "abc", Limit: 1000},
+			expectedFilter: model.LineItemFilter{
+				IncurredFrom: &from,
+				Currency:     "GEL",
+				Description:  "fee",
+				Cursor:       "abc",
+				Limit:        500,
+			},
+			mockReturn:         &model.LineItemPage{LineItems: []model.LineItem{}},
+			expectBusinessCall: true,
+		},
+		{
+			name:               "invalid_bill_id",
+			billID:             0,
+			request:            &ListLineItemsRequest{},
+			expectedError:      "invalid bill ID",
+			expectBusinessCall: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectBusinessCall {
+				mockBusiness.EXPECT().
+					ListLineItems(gomock.Any(), tc.billID, tc.expectedFilter).
+					Return(tc.mockReturn, nil).
+					Times(1)
+			}
+
+			response, err := service.ListLineItems(context.Background(), tc.billID, tc.request)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockReturn.LineItems, response.LineItems)
+				assert.Equal(t, tc.mockReturn.NextCursor, response.NextCursor)
+			}
+		})
+	}
+}
+
+func TestListLineItemsRequest_Validation(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		request       *ListLineItemsRequest
+		expectedError string
+	}{
+		{
+			name:    "valid_request",
+			request: &ListLineItemsRequest{IncurredFrom: from, IncurredTo: from.Add(time.Hour), Currency: "USD"},
+		},
+		{
+			name:          "invalid_currency",
+			request:       &ListLineItemsRequest{Currency: "US"},
+			expectedError: "len",
+		},
+		{
+			name:          "empty_range",
+			request:       &ListLineItemsRequest{IncurredFrom: from, IncurredTo: from},
+			expectedError: "incurred_to must be after incurred_from",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
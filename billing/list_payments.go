@@ -0,0 +1,42 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type PaymentsResponse struct {
+	Payments []model.Payment `json:"payments"`
+}
+
+// ListPayments returns the payments recorded against a bill, oldest first
+//
+//encore:api auth path=/v1/bills/:id/payments method=GET
+func (s *Service) ListPayments(ctx context.Context, id int32) (*PaymentsResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	payments, err := s.business.ListPayments(ctx, id)
+	if err != nil {
+		rlog.Error("failed to list payments", "error", err, "bill_id", id)
+		return nil, err
+	}
+
+	amounts := s.newAmountFormatter(ctx)
+	for i := range payments {
+		amounts.payment(&payments[i])
+	}
+
+	return &PaymentsResponse{
+		Payments: payments,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/cron"
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+// BillOverdueEvent is published once for every closed bill that passes its due date with a
+// balance left to pay
+type BillOverdueEvent struct {
+	BillID       int32     `json:"bill_id"`
+	AccountID    int32     `json:"account_id"`
+	Currency     string    `json:"currency"`
+	BalanceCents int64     `json:"balance_cents"`
+	DueAt        time.Time `json:"due_at"`
+	OverdueAt    time.Time `json:"overdue_at"`
+}
+
+// BillOverdueTopic carries overdue bills to collections
+var BillOverdueTopic = pubsub.NewTopic[*BillOverdueEvent]("bill-overdue", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+var _ = cron.NewJob("mark-overdue-bills", cron.JobConfig{
+	Title:    "Mark unpaid bills past their due date as overdue",
+	Every:    1 * cron.Hour,
+	Endpoint: MarkOverdueBills,
+})
+
+// publishBillOverdue is an indirection over the topic so tests can capture published events
+var publishBillOverdue = func(ctx context.Context, event *BillOverdueEvent) error {
+	_, err := BillOverdueTopic.Publish(ctx, event)
+	return err
+}
+
+type MarkOverdueBillsResponse struct {
+	Marked int `json:"marked"`
+}
+
+// MarkOverdueBills flags closed bills that are past their due date and not fully paid, and
+// publishes a BillOverdueEvent for each. Run hourly by the mark-overdue-bills cron job.
+//
+//encore:api private
+func (s *Service) MarkOverdueBills(ctx context.Context) (*MarkOverdueBillsResponse, error) {
+	marked, err := s.business.MarkOverdueBills(ctx, time.Now(), func(ctx context.Context, bill model.Bill) error {
+		return publishBillOverdue(ctx, newBillOverdueEvent(bill))
+	})
+	if err != nil {
+		rlog.Error("failed to mark overdue bills", "error", err, "marked", marked)
+		return nil, err
+	}
+
+	rlog.Info("marked overdue bills", "marked", marked)
+	return &MarkOverdueBillsResponse{
+		Marked: marked,
+	}, nil
+}
+
+func newBillOverdueEvent(bill model.Bill) *BillOverdueEvent {
+	event := &BillOverdueEvent{
+		BillID:       bill.ID,
+		AccountID:    bill.AccountID,
+		Currency:     bill.Currency,
+		BalanceCents: bill.BalanceCents,
+	}
+	if bill.DueAt != nil {
+		event.DueAt = *bill.DueAt
+	}
+	if bill.OverdueAt != nil {
+		event.OverdueAt = *bill.OverdueAt
+	}
+	return event
+}
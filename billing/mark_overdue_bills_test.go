@@ -0,0 +1,72 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+func TestMarkOverdueBills(t *testing.T) {
+	dueAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	overdueAt := dueAt.Add(time.Hour)
+
+	t.Run("publishes_event_per_flagged_bill", func(t *testing.T) {
+		var published []*BillOverdueEvent
+		originalPublish := publishBillOverdue
+		publishBillOverdue = func(ctx context.Context, event *BillOverdueEvent) error {
+			published = append(published, event)
+			return nil
+		}
+		defer func() { publishBillOverdue = originalPublish }()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockBusiness := bill_business.NewMockBusiness(ctrl)
+		service := &Service{business: mockBusiness}
+
+		mockBusiness.EXPECT().
+			MarkOverdueBills(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, now time.Time, notify func(context.Context, model.Bill) error) (int, error) {
+				err := notify(ctx, model.Bill{ID: 1, AccountID: 7, Currency: "USD", BalanceCents: 600, DueAt: &dueAt, OverdueAt: &overdueAt})
+				return 1, err
+			})
+
+		response, err := service.MarkOverdueBills(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Marked)
+		assert.Equal(t, []*BillOverdueEvent{{
+			BillID:       1,
+			AccountID:    7,
+			Currency:     "USD",
+			BalanceCents: 600,
+			DueAt:        dueAt,
+			OverdueAt:    overdueAt,
+		}}, published)
+	})
+
+	t.Run("business_error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockBusiness := bill_business.NewMockBusiness(ctrl)
+		service := &Service{business: mockBusiness}
+
+		mockBusiness.EXPECT().
+			MarkOverdueBills(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(0, errors.New("database error"))
+
+		response, err := service.MarkOverdueBills(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+	})
+}
@@ -17,7 +17,7 @@ var IdempotencyCluster = cache.NewCluster("idempotency-cluster", cache.ClusterCo
 var IdempotencyCache = cache.NewStructKeyspace[model.IdempotencyKey, model.IdempotencyCacheEntry](
 	IdempotencyCluster,
 	cache.KeyspaceConfig{
-		KeyPattern:    "idempotency/:Resource/:Key",
+		KeyPattern:    "idempotency/:Caller/:Resource/:Key",
 		DefaultExpiry: cache.ExpireIn(24 * time.Hour), // 24 hour expiry
 	},
 )
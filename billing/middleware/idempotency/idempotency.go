@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"encore.dev/beta/auth"
 	"encore.dev/beta/errs"
 	"encore.dev/middleware"
 	"encore.dev/rlog"
@@ -32,7 +33,9 @@ func IdempotencyMiddleware(req middleware.Request, next middleware.Next) middlew
 	bodyHash := generateBodyHash(req)
 
 	// Create cache key
+	caller, _ := auth.UserID()
 	cacheKey := model.IdempotencyKey{
+		Caller:   string(caller),
 		Resource: req.Data().Path,
 		Key:      idempotencyKey,
 	}
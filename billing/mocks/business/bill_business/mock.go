@@ -12,6 +12,7 @@ package bill_business
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "encore.app/billing/model"
 	gomock "go.uber.org/mock/gomock"
@@ -56,62 +57,165 @@ func (mr *MockBusinessMockRecorder) ActivateBill(ctx, billID any) *gomock.Call {
 }
 
 // AddLineItemToBill mocks base method.
-func (m *MockBusiness) AddLineItemToBill(ctx context.Context, billID int32, lineItem *model.LineItem) (*model.LineItem, error) {
+func (m *MockBusiness) AddLineItemToBill(ctx context.Context, billID int32, lineItem *model.LineItem, actor string) (*model.LineItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddLineItemToBill", ctx, billID, lineItem)
+	ret := m.ctrl.Call(m, "AddLineItemToBill", ctx, billID, lineItem, actor)
 	ret0, _ := ret[0].(*model.LineItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddLineItemToBill indicates an expected call of AddLineItemToBill.
-func (mr *MockBusinessMockRecorder) AddLineItemToBill(ctx, billID, lineItem any) *gomock.Call {
+func (mr *MockBusinessMockRecorder) AddLineItemToBill(ctx, billID, lineItem, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLineItemToBill", reflect.TypeOf((*MockBusiness)(nil).AddLineItemToBill), ctx, billID, lineItem)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLineItemToBill", reflect.TypeOf((*MockBusiness)(nil).AddLineItemToBill), ctx, billID, lineItem, actor)
+}
+
+// AddLineItemsToBill mocks base method.
+func (m *MockBusiness) AddLineItemsToBill(ctx context.Context, billID int32, items []*model.LineItem, mode model.LineItemBatchMode, actor string) ([]model.LineItemBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLineItemsToBill", ctx, billID, items, mode, actor)
+	ret0, _ := ret[0].([]model.LineItemBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLineItemsToBill indicates an expected call of AddLineItemsToBill.
+func (mr *MockBusinessMockRecorder) AddLineItemsToBill(ctx, billID, items, mode, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLineItemsToBill", reflect.TypeOf((*MockBusiness)(nil).AddLineItemsToBill), ctx, billID, items, mode, actor)
+}
+
+// ApplyDunningStep mocks base method.
+func (m *MockBusiness) ApplyDunningStep(ctx context.Context, billID int32, stepIndex int, step model.DunningStep) (*model.DunningNotice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyDunningStep", ctx, billID, stepIndex, step)
+	ret0, _ := ret[0].(*model.DunningNotice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyDunningStep indicates an expected call of ApplyDunningStep.
+func (mr *MockBusinessMockRecorder) ApplyDunningStep(ctx, billID, stepIndex, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyDunningStep", reflect.TypeOf((*MockBusiness)(nil).ApplyDunningStep), ctx, billID, stepIndex, step)
+}
+
+// CancelBill mocks base method.
+func (m *MockBusiness) CancelBill(ctx context.Context, id int32, reason, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelBill", ctx, id, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelBill indicates an expected call of CancelBill.
+func (mr *MockBusinessMockRecorder) CancelBill(ctx, id, reason, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBill", reflect.TypeOf((*MockBusiness)(nil).CancelBill), ctx, id, reason, actor)
+}
+
+// CheckBillOwnership mocks base method.
+func (m *MockBusiness) CheckBillOwnership(ctx context.Context, billID, accountID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckBillOwnership", ctx, billID, accountID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckBillOwnership indicates an expected call of CheckBillOwnership.
+func (mr *MockBusinessMockRecorder) CheckBillOwnership(ctx, billID, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBillOwnership", reflect.TypeOf((*MockBusiness)(nil).CheckBillOwnership), ctx, billID, accountID)
 }
 
 // CloseBill mocks base method.
-func (m *MockBusiness) CloseBill(ctx context.Context, id int32, reason string) error {
+func (m *MockBusiness) CloseBill(ctx context.Context, id int32, reason, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseBill", ctx, id, reason)
+	ret := m.ctrl.Call(m, "CloseBill", ctx, id, reason, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CloseBill indicates an expected call of CloseBill.
-func (mr *MockBusinessMockRecorder) CloseBill(ctx, id, reason any) *gomock.Call {
+func (mr *MockBusinessMockRecorder) CloseBill(ctx, id, reason, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseBill", reflect.TypeOf((*MockBusiness)(nil).CloseBill), ctx, id, reason)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseBill", reflect.TypeOf((*MockBusiness)(nil).CloseBill), ctx, id, reason, actor)
 }
 
 // CreateBill mocks base method.
-func (m *MockBusiness) CreateBill(ctx context.Context, bill *model.Bill) (*model.Bill, error) {
+func (m *MockBusiness) CreateBill(ctx context.Context, bill *model.Bill, actor string) (*model.Bill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBill", ctx, bill)
+	ret := m.ctrl.Call(m, "CreateBill", ctx, bill, actor)
 	ret0, _ := ret[0].(*model.Bill)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateBill indicates an expected call of CreateBill.
-func (mr *MockBusinessMockRecorder) CreateBill(ctx, bill any) *gomock.Call {
+func (mr *MockBusinessMockRecorder) CreateBill(ctx, bill, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBill", reflect.TypeOf((*MockBusiness)(nil).CreateBill), ctx, bill)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBill", reflect.TypeOf((*MockBusiness)(nil).CreateBill), ctx, bill, actor)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockBusiness) GetAuditLog(ctx context.Context, billID int32) ([]model.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", ctx, billID)
+	ret0, _ := ret[0].([]model.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockBusinessMockRecorder) GetAuditLog(ctx, billID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockBusiness)(nil).GetAuditLog), ctx, billID)
 }
 
 // GetBill mocks base method.
-func (m *MockBusiness) GetBill(ctx context.Context, id int32) (*model.Bill, error) {
+func (m *MockBusiness) GetBill(ctx context.Context, id int32, opts model.GetBillOptions) (*model.Bill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBill", ctx, id)
+	ret := m.ctrl.Call(m, "GetBill", ctx, id, opts)
 	ret0, _ := ret[0].(*model.Bill)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBill indicates an expected call of GetBill.
-func (mr *MockBusinessMockRecorder) GetBill(ctx, id any) *gomock.Call {
+func (mr *MockBusinessMockRecorder) GetBill(ctx, id, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBill", reflect.TypeOf((*MockBusiness)(nil).GetBill), ctx, id, opts)
+}
+
+// GetBillByIdempotencyKey mocks base method.
+func (m *MockBusiness) GetBillByIdempotencyKey(ctx context.Context, idempotencyKey string) (*model.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBillByIdempotencyKey", ctx, idempotencyKey)
+	ret0, _ := ret[0].(*model.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBillByIdempotencyKey indicates an expected call of GetBillByIdempotencyKey.
+func (mr *MockBusinessMockRecorder) GetBillByIdempotencyKey(ctx, idempotencyKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBillByIdempotencyKey", reflect.TypeOf((*MockBusiness)(nil).GetBillByIdempotencyKey), ctx, idempotencyKey)
+}
+
+// GetBillHistory mocks base method.
+func (m *MockBusiness) GetBillHistory(ctx context.Context, billID int32) ([]model.BillEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBillHistory", ctx, billID)
+	ret0, _ := ret[0].([]model.BillEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBillHistory indicates an expected call of GetBillHistory.
+func (mr *MockBusinessMockRecorder) GetBillHistory(ctx, billID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBill", reflect.TypeOf((*MockBusiness)(nil).GetBill), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBillHistory", reflect.TypeOf((*MockBusiness)(nil).GetBillHistory), ctx, billID)
 }
 
 // GetLineItemsByBill mocks base method.
@@ -130,31 +234,192 @@ func (mr *MockBusinessMockRecorder) GetLineItemsByBill(ctx, billID any) *gomock.
 }
 
 // ListBills mocks base method.
-func (m *MockBusiness) ListBills(ctx context.Context, limit, offset int32) ([]*model.Bill, int64, error) {
+func (m *MockBusiness) ListBills(ctx context.Context, accountID int32, filter model.BillFilter) (*model.BillPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBills", ctx, limit, offset)
-	ret0, _ := ret[0].([]*model.Bill)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "ListBills", ctx, accountID, filter)
+	ret0, _ := ret[0].(*model.BillPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ListBills indicates an expected call of ListBills.
-func (mr *MockBusinessMockRecorder) ListBills(ctx, limit, offset any) *gomock.Call {
+func (mr *MockBusinessMockRecorder) ListBills(ctx, accountID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBills", reflect.TypeOf((*MockBusiness)(nil).ListBills), ctx, accountID, filter)
+}
+
+// ListLineItems mocks base method.
+func (m *MockBusiness) ListLineItems(ctx context.Context, billID int32, filter model.LineItemFilter) (*model.LineItemPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLineItems", ctx, billID, filter)
+	ret0, _ := ret[0].(*model.LineItemPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLineItems indicates an expected call of ListLineItems.
+func (mr *MockBusinessMockRecorder) ListLineItems(ctx, billID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLineItems", reflect.TypeOf((*MockBusiness)(nil).ListLineItems), ctx, billID, filter)
+}
+
+// ListPayments mocks base method.
+func (m *MockBusiness) ListPayments(ctx context.Context, billID int32) ([]model.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayments", ctx, billID)
+	ret0, _ := ret[0].([]model.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayments indicates an expected call of ListPayments.
+func (mr *MockBusinessMockRecorder) ListPayments(ctx, billID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBills", reflect.TypeOf((*MockBusiness)(nil).ListBills), ctx, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayments", reflect.TypeOf((*MockBusiness)(nil).ListPayments), ctx, billID)
+}
+
+// MarkOverdueBills mocks base method.
+func (m *MockBusiness) MarkOverdueBills(ctx context.Context, now time.Time, notify func(context.Context, model.Bill) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOverdueBills", ctx, now, notify)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkOverdueBills indicates an expected call of MarkOverdueBills.
+func (mr *MockBusinessMockRecorder) MarkOverdueBills(ctx, now, notify any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOverdueBills", reflect.TypeOf((*MockBusiness)(nil).MarkOverdueBills), ctx, now, notify)
+}
+
+// RecordPayment mocks base method.
+func (m *MockBusiness) RecordPayment(ctx context.Context, billID int32, payment *model.Payment, actor string) (*model.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPayment", ctx, billID, payment, actor)
+	ret0, _ := ret[0].(*model.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordPayment indicates an expected call of RecordPayment.
+func (mr *MockBusinessMockRecorder) RecordPayment(ctx, billID, payment, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPayment", reflect.TypeOf((*MockBusiness)(nil).RecordPayment), ctx, billID, payment, actor)
+}
+
+// RecoverBill mocks base method.
+func (m *MockBusiness) RecoverBill(ctx context.Context, id int32, recovery model.BillRecovery, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverBill", ctx, id, recovery, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecoverBill indicates an expected call of RecoverBill.
+func (mr *MockBusinessMockRecorder) RecoverBill(ctx, id, recovery, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverBill", reflect.TypeOf((*MockBusiness)(nil).RecoverBill), ctx, id, recovery, actor)
+}
+
+// StartClosingGrace mocks base method.
+func (m *MockBusiness) StartClosingGrace(ctx context.Context, id int32, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartClosingGrace", ctx, id, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartClosingGrace indicates an expected call of StartClosingGrace.
+func (mr *MockBusinessMockRecorder) StartClosingGrace(ctx, id, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartClosingGrace", reflect.TypeOf((*MockBusiness)(nil).StartClosingGrace), ctx, id, actor)
+}
+
+// UpdateBillPeriod mocks base method.
+func (m *MockBusiness) UpdateBillPeriod(ctx context.Context, id int32, endTime time.Time, reason, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBillPeriod", ctx, id, endTime, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBillPeriod indicates an expected call of UpdateBillPeriod.
+func (mr *MockBusinessMockRecorder) UpdateBillPeriod(ctx, id, endTime, reason, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillPeriod", reflect.TypeOf((*MockBusiness)(nil).UpdateBillPeriod), ctx, id, endTime, reason, actor)
 }
 
 // UpdateBillTotal mocks base method.
-func (m *MockBusiness) UpdateBillTotal(ctx context.Context, billID int32) error {
+func (m *MockBusiness) UpdateBillTotal(ctx context.Context, billID int32, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBillTotal", ctx, billID)
+	ret := m.ctrl.Call(m, "UpdateBillTotal", ctx, billID, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateBillTotal indicates an expected call of UpdateBillTotal.
-func (mr *MockBusinessMockRecorder) UpdateBillTotal(ctx, billID any) *gomock.Call {
+func (mr *MockBusinessMockRecorder) UpdateBillTotal(ctx, billID, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillTotal", reflect.TypeOf((*MockBusiness)(nil).UpdateBillTotal), ctx, billID, actor)
+}
+
+// UpdateLineItem mocks base method.
+func (m *MockBusiness) UpdateLineItem(ctx context.Context, billID, lineItemID int32, update *model.LineItemUpdate, actor string) (*model.LineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLineItem", ctx, billID, lineItemID, update, actor)
+	ret0, _ := ret[0].(*model.LineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLineItem indicates an expected call of UpdateLineItem.
+func (mr *MockBusinessMockRecorder) UpdateLineItem(ctx, billID, lineItemID, update, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLineItem", reflect.TypeOf((*MockBusiness)(nil).UpdateLineItem), ctx, billID, lineItemID, update, actor)
+}
+
+// VerifyAuditLog mocks base method.
+func (m *MockBusiness) VerifyAuditLog(ctx context.Context, billID int32) (*model.AuditVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditLog", ctx, billID)
+	ret0, _ := ret[0].(*model.AuditVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAuditLog indicates an expected call of VerifyAuditLog.
+func (mr *MockBusinessMockRecorder) VerifyAuditLog(ctx, billID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditLog", reflect.TypeOf((*MockBusiness)(nil).VerifyAuditLog), ctx, billID)
+}
+
+// VerifyAuditLogs mocks base method.
+func (m *MockBusiness) VerifyAuditLogs(ctx context.Context) (*model.AuditReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditLogs", ctx)
+	ret0, _ := ret[0].(*model.AuditReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAuditLogs indicates an expected call of VerifyAuditLogs.
+func (mr *MockBusinessMockRecorder) VerifyAuditLogs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditLogs", reflect.TypeOf((*MockBusiness)(nil).VerifyAuditLogs), ctx)
+}
+
+// VoidLineItem mocks base method.
+func (m *MockBusiness) VoidLineItem(ctx context.Context, billID, lineItemID int32, reason, actor string) (*model.LineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidLineItem", ctx, billID, lineItemID, reason, actor)
+	ret0, _ := ret[0].(*model.LineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidLineItem indicates an expected call of VoidLineItem.
+func (mr *MockBusinessMockRecorder) VoidLineItem(ctx, billID, lineItemID, reason, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillTotal", reflect.TypeOf((*MockBusiness)(nil).UpdateBillTotal), ctx, billID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidLineItem", reflect.TypeOf((*MockBusiness)(nil).VoidLineItem), ctx, billID, lineItemID, reason, actor)
 }
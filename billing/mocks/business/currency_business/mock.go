@@ -12,8 +12,11 @@ package currency_business
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	currency "encore.app/billing/business/currency"
 	model "encore.app/billing/model"
+	pgx "github.com/jackc/pgx/v5"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,18 +45,33 @@ func (m *MockBusiness) EXPECT() *MockBusinessMockRecorder {
 }
 
 // ConvertAmount mocks base method.
-func (m *MockBusiness) ConvertAmount(ctx context.Context, fromCurrency, toCurrency string, amountCents int64) (*model.ConversionResult, error) {
+func (m *MockBusiness) ConvertAmount(ctx context.Context, fromCurrency, toCurrency string, amountCents int64, at time.Time) (*model.ConversionResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ConvertAmount", ctx, fromCurrency, toCurrency, amountCents)
+	ret := m.ctrl.Call(m, "ConvertAmount", ctx, fromCurrency, toCurrency, amountCents, at)
 	ret0, _ := ret[0].(*model.ConversionResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ConvertAmount indicates an expected call of ConvertAmount.
-func (mr *MockBusinessMockRecorder) ConvertAmount(ctx, fromCurrency, toCurrency, amountCents any) *gomock.Call {
+func (mr *MockBusinessMockRecorder) ConvertAmount(ctx, fromCurrency, toCurrency, amountCents, at any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertAmount", reflect.TypeOf((*MockBusiness)(nil).ConvertAmount), ctx, fromCurrency, toCurrency, amountCents)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertAmount", reflect.TypeOf((*MockBusiness)(nil).ConvertAmount), ctx, fromCurrency, toCurrency, amountCents, at)
+}
+
+// CreateCurrency mocks base method.
+func (m *MockBusiness) CreateCurrency(ctx context.Context, arg1 *model.CurrencyInfo, actor string) (*model.CurrencyInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCurrency", ctx, arg1, actor)
+	ret0, _ := ret[0].(*model.CurrencyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCurrency indicates an expected call of CreateCurrency.
+func (mr *MockBusinessMockRecorder) CreateCurrency(ctx, arg1, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCurrency", reflect.TypeOf((*MockBusiness)(nil).CreateCurrency), ctx, arg1, actor)
 }
 
 // GetCurrency mocks base method.
@@ -70,3 +88,117 @@ func (mr *MockBusinessMockRecorder) GetCurrency(ctx, code any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrency", reflect.TypeOf((*MockBusiness)(nil).GetCurrency), ctx, code)
 }
+
+// GetCurrencyRate mocks base method.
+func (m *MockBusiness) GetCurrencyRate(ctx context.Context, code string, at time.Time) (*model.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyRate", ctx, code, at)
+	ret0, _ := ret[0].(*model.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyRate indicates an expected call of GetCurrencyRate.
+func (mr *MockBusinessMockRecorder) GetCurrencyRate(ctx, code, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyRate", reflect.TypeOf((*MockBusiness)(nil).GetCurrencyRate), ctx, code, at)
+}
+
+// GetMinorUnits mocks base method.
+func (m *MockBusiness) GetMinorUnits(ctx context.Context, code string) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMinorUnits", ctx, code)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMinorUnits indicates an expected call of GetMinorUnits.
+func (mr *MockBusinessMockRecorder) GetMinorUnits(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMinorUnits", reflect.TypeOf((*MockBusiness)(nil).GetMinorUnits), ctx, code)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockBusiness) ListCurrencies(ctx context.Context) ([]*model.CurrencyInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", ctx)
+	ret0, _ := ret[0].([]*model.CurrencyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockBusinessMockRecorder) ListCurrencies(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockBusiness)(nil).ListCurrencies), ctx)
+}
+
+// SyncRates mocks base method.
+func (m *MockBusiness) SyncRates(ctx context.Context, provider currency.RateProvider, maxChangePercent float64, now time.Time) (*model.RateSyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncRates", ctx, provider, maxChangePercent, now)
+	ret0, _ := ret[0].(*model.RateSyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncRates indicates an expected call of SyncRates.
+func (mr *MockBusinessMockRecorder) SyncRates(ctx, provider, maxChangePercent, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncRates", reflect.TypeOf((*MockBusiness)(nil).SyncRates), ctx, provider, maxChangePercent, now)
+}
+
+// UpdateCurrency mocks base method.
+func (m *MockBusiness) UpdateCurrency(ctx context.Context, code string, update *model.CurrencyUpdate, actor string) (*model.CurrencyInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCurrency", ctx, code, update, actor)
+	ret0, _ := ret[0].(*model.CurrencyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCurrency indicates an expected call of UpdateCurrency.
+func (mr *MockBusinessMockRecorder) UpdateCurrency(ctx, code, update, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCurrency", reflect.TypeOf((*MockBusiness)(nil).UpdateCurrency), ctx, code, update, actor)
+}
+
+// MocktxBeginner is a mock of txBeginner interface.
+type MocktxBeginner struct {
+	ctrl     *gomock.Controller
+	recorder *MocktxBeginnerMockRecorder
+	isgomock struct{}
+}
+
+// MocktxBeginnerMockRecorder is the mock recorder for MocktxBeginner.
+type MocktxBeginnerMockRecorder struct {
+	mock *MocktxBeginner
+}
+
+// NewMocktxBeginner creates a new mock instance.
+func NewMocktxBeginner(ctrl *gomock.Controller) *MocktxBeginner {
+	mock := &MocktxBeginner{ctrl: ctrl}
+	mock.recorder = &MocktxBeginnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktxBeginner) EXPECT() *MocktxBeginnerMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MocktxBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(pgx.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MocktxBeginnerMockRecorder) Begin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MocktxBeginner)(nil).Begin), ctx)
+}
@@ -12,9 +12,11 @@ package state_machine
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	domain "encore.app/billing/domain/bill_state_machine"
+	model "encore.app/billing/model"
 	bills "encore.app/billing/repository/bills"
-	lineitems "encore.app/billing/repository/lineitems"
 	pgx "github.com/jackc/pgx/v5"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -43,8 +45,22 @@ func (m *MockStateMachine) EXPECT() *MockStateMachineMockRecorder {
 	return m.recorder
 }
 
+// AddToBillTotalTx mocks base method.
+func (m *MockStateMachine) AddToBillTotalTx(ctx context.Context, tx *domain.TxScope, id int32, amountCents int64, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToBillTotalTx", ctx, tx, id, amountCents, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToBillTotalTx indicates an expected call of AddToBillTotalTx.
+func (mr *MockStateMachineMockRecorder) AddToBillTotalTx(ctx, tx, id, amountCents, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToBillTotalTx", reflect.TypeOf((*MockStateMachine)(nil).AddToBillTotalTx), ctx, tx, id, amountCents, actor)
+}
+
 // GetBillWithLock mocks base method.
-func (m *MockStateMachine) GetBillWithLock(ctx context.Context, billID int32, businessLogic func(bills.Bill) error) error {
+func (m *MockStateMachine) GetBillWithLock(ctx context.Context, billID int32, businessLogic func(*domain.TxScope, bills.Bill) error) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBillWithLock", ctx, billID, businessLogic)
 	ret0, _ := ret[0].(error)
@@ -57,114 +73,237 @@ func (mr *MockStateMachineMockRecorder) GetBillWithLock(ctx, billID, businessLog
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBillWithLock", reflect.TypeOf((*MockStateMachine)(nil).GetBillWithLock), ctx, billID, businessLogic)
 }
 
-// GetCurrentTx mocks base method.
-func (m *MockStateMachine) GetCurrentTx() pgx.Tx {
+// MarkOverdueTx mocks base method.
+func (m *MockStateMachine) MarkOverdueTx(ctx context.Context, tx *domain.TxScope, id int32, at time.Time, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCurrentTx")
-	ret0, _ := ret[0].(pgx.Tx)
+	ret := m.ctrl.Call(m, "MarkOverdueTx", ctx, tx, id, at, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOverdueTx indicates an expected call of MarkOverdueTx.
+func (mr *MockStateMachineMockRecorder) MarkOverdueTx(ctx, tx, id, at, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOverdueTx", reflect.TypeOf((*MockStateMachine)(nil).MarkOverdueTx), ctx, tx, id, at, actor)
+}
+
+// OverrideBillTotalTx mocks base method.
+func (m *MockStateMachine) OverrideBillTotalTx(ctx context.Context, tx *domain.TxScope, id int32, totalCents int64, justification, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OverrideBillTotalTx", ctx, tx, id, totalCents, justification, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OverrideBillTotalTx indicates an expected call of OverrideBillTotalTx.
+func (mr *MockStateMachineMockRecorder) OverrideBillTotalTx(ctx, tx, id, totalCents, justification, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OverrideBillTotalTx", reflect.TypeOf((*MockStateMachine)(nil).OverrideBillTotalTx), ctx, tx, id, totalCents, justification, actor)
+}
+
+// ReactivateTx mocks base method.
+func (m *MockStateMachine) ReactivateTx(ctx context.Context, tx *domain.TxScope, id int32, reason, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReactivateTx", ctx, tx, id, reason, actor)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// GetCurrentTx indicates an expected call of GetCurrentTx.
-func (mr *MockStateMachineMockRecorder) GetCurrentTx() *gomock.Call {
+// ReactivateTx indicates an expected call of ReactivateTx.
+func (mr *MockStateMachineMockRecorder) ReactivateTx(ctx, tx, id, reason, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentTx", reflect.TypeOf((*MockStateMachine)(nil).GetCurrentTx))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReactivateTx", reflect.TypeOf((*MockStateMachine)(nil).ReactivateTx), ctx, tx, id, reason, actor)
 }
 
-// GetTxBillRepo mocks base method.
-func (m *MockStateMachine) GetTxBillRepo() bills.Querier {
+// RecordAuditBatchTx mocks base method.
+func (m *MockStateMachine) RecordAuditBatchTx(ctx context.Context, tx *domain.TxScope, billID int32, actor string, records []domain.AuditRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTxBillRepo")
-	ret0, _ := ret[0].(bills.Querier)
+	ret := m.ctrl.Call(m, "RecordAuditBatchTx", ctx, tx, billID, actor, records)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// GetTxBillRepo indicates an expected call of GetTxBillRepo.
-func (mr *MockStateMachineMockRecorder) GetTxBillRepo() *gomock.Call {
+// RecordAuditBatchTx indicates an expected call of RecordAuditBatchTx.
+func (mr *MockStateMachineMockRecorder) RecordAuditBatchTx(ctx, tx, billID, actor, records any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxBillRepo", reflect.TypeOf((*MockStateMachine)(nil).GetTxBillRepo))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAuditBatchTx", reflect.TypeOf((*MockStateMachine)(nil).RecordAuditBatchTx), ctx, tx, billID, actor, records)
 }
 
-// GetTxLineItemRepo mocks base method.
-func (m *MockStateMachine) GetTxLineItemRepo() lineitems.Querier {
+// RecordAuditTx mocks base method.
+func (m *MockStateMachine) RecordAuditTx(ctx context.Context, tx *domain.TxScope, billID int32, action model.AuditAction, actor string, payload any) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTxLineItemRepo")
-	ret0, _ := ret[0].(lineitems.Querier)
+	ret := m.ctrl.Call(m, "RecordAuditTx", ctx, tx, billID, action, actor, payload)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// GetTxLineItemRepo indicates an expected call of GetTxLineItemRepo.
-func (mr *MockStateMachineMockRecorder) GetTxLineItemRepo() *gomock.Call {
+// RecordAuditTx indicates an expected call of RecordAuditTx.
+func (mr *MockStateMachineMockRecorder) RecordAuditTx(ctx, tx, billID, action, actor, payload any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxLineItemRepo", reflect.TypeOf((*MockStateMachine)(nil).GetTxLineItemRepo))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAuditTx", reflect.TypeOf((*MockStateMachine)(nil).RecordAuditTx), ctx, tx, billID, action, actor, payload)
 }
 
 // TransitionToActive mocks base method.
-func (m *MockStateMachine) TransitionToActive(ctx context.Context, id int32) error {
+func (m *MockStateMachine) TransitionToActive(ctx context.Context, id int32, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransitionToActive", ctx, id)
+	ret := m.ctrl.Call(m, "TransitionToActive", ctx, id, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TransitionToActive indicates an expected call of TransitionToActive.
-func (mr *MockStateMachineMockRecorder) TransitionToActive(ctx, id any) *gomock.Call {
+func (mr *MockStateMachineMockRecorder) TransitionToActive(ctx, id, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToActive", reflect.TypeOf((*MockStateMachine)(nil).TransitionToActive), ctx, id, actor)
+}
+
+// TransitionToCancelledTx mocks base method.
+func (m *MockStateMachine) TransitionToCancelledTx(ctx context.Context, tx *domain.TxScope, id int32, reason, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionToCancelledTx", ctx, tx, id, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransitionToCancelledTx indicates an expected call of TransitionToCancelledTx.
+func (mr *MockStateMachineMockRecorder) TransitionToCancelledTx(ctx, tx, id, reason, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToActive", reflect.TypeOf((*MockStateMachine)(nil).TransitionToActive), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToCancelledTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToCancelledTx), ctx, tx, id, reason, actor)
 }
 
 // TransitionToClosedTx mocks base method.
-func (m *MockStateMachine) TransitionToClosedTx(ctx context.Context, id int32, reason string) error {
+func (m *MockStateMachine) TransitionToClosedTx(ctx context.Context, tx *domain.TxScope, id int32, reason, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransitionToClosedTx", ctx, id, reason)
+	ret := m.ctrl.Call(m, "TransitionToClosedTx", ctx, tx, id, reason, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TransitionToClosedTx indicates an expected call of TransitionToClosedTx.
-func (mr *MockStateMachineMockRecorder) TransitionToClosedTx(ctx, id, reason any) *gomock.Call {
+func (mr *MockStateMachineMockRecorder) TransitionToClosedTx(ctx, tx, id, reason, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToClosedTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToClosedTx), ctx, tx, id, reason, actor)
+}
+
+// TransitionToClosingGraceTx mocks base method.
+func (m *MockStateMachine) TransitionToClosingGraceTx(ctx context.Context, tx *domain.TxScope, id int32, reason, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionToClosingGraceTx", ctx, tx, id, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransitionToClosingGraceTx indicates an expected call of TransitionToClosingGraceTx.
+func (mr *MockStateMachineMockRecorder) TransitionToClosingGraceTx(ctx, tx, id, reason, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToClosedTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToClosedTx), ctx, id, reason)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToClosingGraceTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToClosingGraceTx), ctx, tx, id, reason, actor)
 }
 
 // TransitionToClosingTx mocks base method.
-func (m *MockStateMachine) TransitionToClosingTx(ctx context.Context, id int32, reason string) error {
+func (m *MockStateMachine) TransitionToClosingTx(ctx context.Context, tx *domain.TxScope, id int32, reason, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransitionToClosingTx", ctx, id, reason)
+	ret := m.ctrl.Call(m, "TransitionToClosingTx", ctx, tx, id, reason, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TransitionToClosingTx indicates an expected call of TransitionToClosingTx.
-func (mr *MockStateMachineMockRecorder) TransitionToClosingTx(ctx, id, reason any) *gomock.Call {
+func (mr *MockStateMachineMockRecorder) TransitionToClosingTx(ctx, tx, id, reason, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToClosingTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToClosingTx), ctx, id, reason)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToClosingTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToClosingTx), ctx, tx, id, reason, actor)
 }
 
 // TransitionToFailureStateTx mocks base method.
-func (m *MockStateMachine) TransitionToFailureStateTx(ctx context.Context, id int32, errorMessage string) error {
+func (m *MockStateMachine) TransitionToFailureStateTx(ctx context.Context, tx *domain.TxScope, id int32, errorMessage, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransitionToFailureStateTx", ctx, id, errorMessage)
+	ret := m.ctrl.Call(m, "TransitionToFailureStateTx", ctx, tx, id, errorMessage, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TransitionToFailureStateTx indicates an expected call of TransitionToFailureStateTx.
-func (mr *MockStateMachineMockRecorder) TransitionToFailureStateTx(ctx, id, errorMessage any) *gomock.Call {
+func (mr *MockStateMachineMockRecorder) TransitionToFailureStateTx(ctx, tx, id, errorMessage, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToFailureStateTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToFailureStateTx), ctx, id, errorMessage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionToFailureStateTx", reflect.TypeOf((*MockStateMachine)(nil).TransitionToFailureStateTx), ctx, tx, id, errorMessage, actor)
 }
 
 // UpdateBillTotalTx mocks base method.
-func (m *MockStateMachine) UpdateBillTotalTx(ctx context.Context, id int32) error {
+func (m *MockStateMachine) UpdateBillTotalTx(ctx context.Context, tx *domain.TxScope, id int32, actor string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBillTotalTx", ctx, id)
+	ret := m.ctrl.Call(m, "UpdateBillTotalTx", ctx, tx, id, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateBillTotalTx indicates an expected call of UpdateBillTotalTx.
-func (mr *MockStateMachineMockRecorder) UpdateBillTotalTx(ctx, id any) *gomock.Call {
+func (mr *MockStateMachineMockRecorder) UpdateBillTotalTx(ctx, tx, id, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillTotalTx", reflect.TypeOf((*MockStateMachine)(nil).UpdateBillTotalTx), ctx, tx, id, actor)
+}
+
+// UpdateEndTimeTx mocks base method.
+func (m *MockStateMachine) UpdateEndTimeTx(ctx context.Context, tx *domain.TxScope, id int32, endTime time.Time, reason, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEndTimeTx", ctx, tx, id, endTime, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEndTimeTx indicates an expected call of UpdateEndTimeTx.
+func (mr *MockStateMachineMockRecorder) UpdateEndTimeTx(ctx, tx, id, endTime, reason, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEndTimeTx", reflect.TypeOf((*MockStateMachine)(nil).UpdateEndTimeTx), ctx, tx, id, endTime, reason, actor)
+}
+
+// WithTx mocks base method.
+func (m *MockStateMachine) WithTx(ctx context.Context, fn func(*domain.TxScope) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockStateMachineMockRecorder) WithTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockStateMachine)(nil).WithTx), ctx, fn)
+}
+
+// MocktxBeginner is a mock of txBeginner interface.
+type MocktxBeginner struct {
+	ctrl     *gomock.Controller
+	recorder *MocktxBeginnerMockRecorder
+	isgomock struct{}
+}
+
+// MocktxBeginnerMockRecorder is the mock recorder for MocktxBeginner.
+type MocktxBeginnerMockRecorder struct {
+	mock *MocktxBeginner
+}
+
+// NewMocktxBeginner creates a new mock instance.
+func NewMocktxBeginner(ctrl *gomock.Controller) *MocktxBeginner {
+	mock := &MocktxBeginner{ctrl: ctrl}
+	mock.recorder = &MocktxBeginnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktxBeginner) EXPECT() *MocktxBeginnerMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MocktxBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(pgx.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MocktxBeginnerMockRecorder) Begin(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillTotalTx", reflect.TypeOf((*MockStateMachine)(nil).UpdateBillTotalTx), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MocktxBeginner)(nil).Begin), ctx)
 }
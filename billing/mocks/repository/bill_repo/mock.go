@@ -42,19 +42,49 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 	return m.recorder
 }
 
-// CountBills mocks base method.
-func (m *MockQuerier) CountBills(ctx context.Context) (int64, error) {
+// AddBillPayment mocks base method.
+func (m *MockQuerier) AddBillPayment(ctx context.Context, arg bills.AddBillPaymentParams) (bills.Bill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountBills", ctx)
+	ret := m.ctrl.Call(m, "AddBillPayment", ctx, arg)
+	ret0, _ := ret[0].(bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBillPayment indicates an expected call of AddBillPayment.
+func (mr *MockQuerierMockRecorder) AddBillPayment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBillPayment", reflect.TypeOf((*MockQuerier)(nil).AddBillPayment), ctx, arg)
+}
+
+// AddToBillTotal mocks base method.
+func (m *MockQuerier) AddToBillTotal(ctx context.Context, arg bills.AddToBillTotalParams) (bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToBillTotal", ctx, arg)
+	ret0, _ := ret[0].(bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToBillTotal indicates an expected call of AddToBillTotal.
+func (mr *MockQuerierMockRecorder) AddToBillTotal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToBillTotal", reflect.TypeOf((*MockQuerier)(nil).AddToBillTotal), ctx, arg)
+}
+
+// CountFilteredBills mocks base method.
+func (m *MockQuerier) CountFilteredBills(ctx context.Context, arg bills.CountFilteredBillsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFilteredBills", ctx, arg)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CountBills indicates an expected call of CountBills.
-func (mr *MockQuerierMockRecorder) CountBills(ctx any) *gomock.Call {
+// CountFilteredBills indicates an expected call of CountFilteredBills.
+func (mr *MockQuerierMockRecorder) CountFilteredBills(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBills", reflect.TypeOf((*MockQuerier)(nil).CountBills), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFilteredBills", reflect.TypeOf((*MockQuerier)(nil).CountFilteredBills), ctx, arg)
 }
 
 // CreateBill mocks base method.
@@ -117,19 +147,183 @@ func (mr *MockQuerierMockRecorder) GetBillForUpdate(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBillForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetBillForUpdate), ctx, id)
 }
 
-// ListBills mocks base method.
-func (m *MockQuerier) ListBills(ctx context.Context, arg bills.ListBillsParams) ([]bills.Bill, error) {
+// ListBillIDsAfter mocks base method.
+func (m *MockQuerier) ListBillIDsAfter(ctx context.Context, arg bills.ListBillIDsAfterParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBillIDsAfter", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBillIDsAfter indicates an expected call of ListBillIDsAfter.
+func (mr *MockQuerierMockRecorder) ListBillIDsAfter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillIDsAfter", reflect.TypeOf((*MockQuerier)(nil).ListBillIDsAfter), ctx, arg)
+}
+
+// ListBillsByCreatedAtAsc mocks base method.
+func (m *MockQuerier) ListBillsByCreatedAtAsc(ctx context.Context, arg bills.ListBillsByCreatedAtAscParams) ([]bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBillsByCreatedAtAsc", ctx, arg)
+	ret0, _ := ret[0].([]bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBillsByCreatedAtAsc indicates an expected call of ListBillsByCreatedAtAsc.
+func (mr *MockQuerierMockRecorder) ListBillsByCreatedAtAsc(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillsByCreatedAtAsc", reflect.TypeOf((*MockQuerier)(nil).ListBillsByCreatedAtAsc), ctx, arg)
+}
+
+// ListBillsByCreatedAtDesc mocks base method.
+func (m *MockQuerier) ListBillsByCreatedAtDesc(ctx context.Context, arg bills.ListBillsByCreatedAtDescParams) ([]bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBillsByCreatedAtDesc", ctx, arg)
+	ret0, _ := ret[0].([]bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBillsByCreatedAtDesc indicates an expected call of ListBillsByCreatedAtDesc.
+func (mr *MockQuerierMockRecorder) ListBillsByCreatedAtDesc(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillsByCreatedAtDesc", reflect.TypeOf((*MockQuerier)(nil).ListBillsByCreatedAtDesc), ctx, arg)
+}
+
+// ListBillsByStartTimeAsc mocks base method.
+func (m *MockQuerier) ListBillsByStartTimeAsc(ctx context.Context, arg bills.ListBillsByStartTimeAscParams) ([]bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBillsByStartTimeAsc", ctx, arg)
+	ret0, _ := ret[0].([]bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBillsByStartTimeAsc indicates an expected call of ListBillsByStartTimeAsc.
+func (mr *MockQuerierMockRecorder) ListBillsByStartTimeAsc(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillsByStartTimeAsc", reflect.TypeOf((*MockQuerier)(nil).ListBillsByStartTimeAsc), ctx, arg)
+}
+
+// ListBillsByStartTimeDesc mocks base method.
+func (m *MockQuerier) ListBillsByStartTimeDesc(ctx context.Context, arg bills.ListBillsByStartTimeDescParams) ([]bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBillsByStartTimeDesc", ctx, arg)
+	ret0, _ := ret[0].([]bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBillsByStartTimeDesc indicates an expected call of ListBillsByStartTimeDesc.
+func (mr *MockQuerierMockRecorder) ListBillsByStartTimeDesc(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillsByStartTimeDesc", reflect.TypeOf((*MockQuerier)(nil).ListBillsByStartTimeDesc), ctx, arg)
+}
+
+// ListBillsByTotalAsc mocks base method.
+func (m *MockQuerier) ListBillsByTotalAsc(ctx context.Context, arg bills.ListBillsByTotalAscParams) ([]bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBillsByTotalAsc", ctx, arg)
+	ret0, _ := ret[0].([]bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBillsByTotalAsc indicates an expected call of ListBillsByTotalAsc.
+func (mr *MockQuerierMockRecorder) ListBillsByTotalAsc(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillsByTotalAsc", reflect.TypeOf((*MockQuerier)(nil).ListBillsByTotalAsc), ctx, arg)
+}
+
+// ListBillsByTotalDesc mocks base method.
+func (m *MockQuerier) ListBillsByTotalDesc(ctx context.Context, arg bills.ListBillsByTotalDescParams) ([]bills.Bill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBills", ctx, arg)
+	ret := m.ctrl.Call(m, "ListBillsByTotalDesc", ctx, arg)
 	ret0, _ := ret[0].([]bills.Bill)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListBills indicates an expected call of ListBills.
-func (mr *MockQuerierMockRecorder) ListBills(ctx, arg any) *gomock.Call {
+// ListBillsByTotalDesc indicates an expected call of ListBillsByTotalDesc.
+func (mr *MockQuerierMockRecorder) ListBillsByTotalDesc(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBills", reflect.TypeOf((*MockQuerier)(nil).ListBills), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillsByTotalDesc", reflect.TypeOf((*MockQuerier)(nil).ListBillsByTotalDesc), ctx, arg)
+}
+
+// ListOverdueBills mocks base method.
+func (m *MockQuerier) ListOverdueBills(ctx context.Context, arg bills.ListOverdueBillsParams) ([]bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueBills", ctx, arg)
+	ret0, _ := ret[0].([]bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueBills indicates an expected call of ListOverdueBills.
+func (mr *MockQuerierMockRecorder) ListOverdueBills(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueBills", reflect.TypeOf((*MockQuerier)(nil).ListOverdueBills), ctx, arg)
+}
+
+// MarkBillOverdue mocks base method.
+func (m *MockQuerier) MarkBillOverdue(ctx context.Context, arg bills.MarkBillOverdueParams) (bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkBillOverdue", ctx, arg)
+	ret0, _ := ret[0].(bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkBillOverdue indicates an expected call of MarkBillOverdue.
+func (mr *MockQuerierMockRecorder) MarkBillOverdue(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBillOverdue", reflect.TypeOf((*MockQuerier)(nil).MarkBillOverdue), ctx, arg)
+}
+
+// OverrideBillTotal mocks base method.
+func (m *MockQuerier) OverrideBillTotal(ctx context.Context, arg bills.OverrideBillTotalParams) (bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OverrideBillTotal", ctx, arg)
+	ret0, _ := ret[0].(bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OverrideBillTotal indicates an expected call of OverrideBillTotal.
+func (mr *MockQuerierMockRecorder) OverrideBillTotal(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OverrideBillTotal", reflect.TypeOf((*MockQuerier)(nil).OverrideBillTotal), ctx, arg)
+}
+
+// SetBillDueAt mocks base method.
+func (m *MockQuerier) SetBillDueAt(ctx context.Context, id int32) (bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBillDueAt", ctx, id)
+	ret0, _ := ret[0].(bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBillDueAt indicates an expected call of SetBillDueAt.
+func (mr *MockQuerierMockRecorder) SetBillDueAt(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBillDueAt", reflect.TypeOf((*MockQuerier)(nil).SetBillDueAt), ctx, id)
+}
+
+// UpdateBillAuditHead mocks base method.
+func (m *MockQuerier) UpdateBillAuditHead(ctx context.Context, arg bills.UpdateBillAuditHeadParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBillAuditHead", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBillAuditHead indicates an expected call of UpdateBillAuditHead.
+func (mr *MockQuerierMockRecorder) UpdateBillAuditHead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillAuditHead", reflect.TypeOf((*MockQuerier)(nil).UpdateBillAuditHead), ctx, arg)
 }
 
 // UpdateBillClosure mocks base method.
@@ -147,6 +341,21 @@ func (mr *MockQuerierMockRecorder) UpdateBillClosure(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillClosure", reflect.TypeOf((*MockQuerier)(nil).UpdateBillClosure), ctx, arg)
 }
 
+// UpdateBillEndTime mocks base method.
+func (m *MockQuerier) UpdateBillEndTime(ctx context.Context, arg bills.UpdateBillEndTimeParams) (bills.Bill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBillEndTime", ctx, arg)
+	ret0, _ := ret[0].(bills.Bill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBillEndTime indicates an expected call of UpdateBillEndTime.
+func (mr *MockQuerierMockRecorder) UpdateBillEndTime(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillEndTime", reflect.TypeOf((*MockQuerier)(nil).UpdateBillEndTime), ctx, arg)
+}
+
 // UpdateBillStatus mocks base method.
 func (m *MockQuerier) UpdateBillStatus(ctx context.Context, arg bills.UpdateBillStatusParams) (bills.Bill, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: billing/repository/billevents/querier.go
+//
+// Generated by this command:
+//
+//	mockgen -source=billing/repository/billevents/querier.go -destination=billing/mocks/repository/billevent_repo/mock.go -package=billevent_repo
+//
+
+// Package billevent_repo is a generated GoMock package.
+package billevent_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	billevents "encore.app/billing/repository/billevents"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuerier is a mock of Querier interface.
+type MockQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuerierMockRecorder
+	isgomock struct{}
+}
+
+// MockQuerierMockRecorder is the mock recorder for MockQuerier.
+type MockQuerierMockRecorder struct {
+	mock *MockQuerier
+}
+
+// NewMockQuerier creates a new mock instance.
+func NewMockQuerier(ctrl *gomock.Controller) *MockQuerier {
+	mock := &MockQuerier{ctrl: ctrl}
+	mock.recorder = &MockQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
+	return m.recorder
+}
+
+// CreateBillEvent mocks base method.
+func (m *MockQuerier) CreateBillEvent(ctx context.Context, arg billevents.CreateBillEventParams) (billevents.BillEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBillEvent", ctx, arg)
+	ret0, _ := ret[0].(billevents.BillEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBillEvent indicates an expected call of CreateBillEvent.
+func (mr *MockQuerierMockRecorder) CreateBillEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBillEvent", reflect.TypeOf((*MockQuerier)(nil).CreateBillEvent), ctx, arg)
+}
+
+// ListBillEvents mocks base method.
+func (m *MockQuerier) ListBillEvents(ctx context.Context, billID int32) ([]billevents.BillEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBillEvents", ctx, billID)
+	ret0, _ := ret[0].([]billevents.BillEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBillEvents indicates an expected call of ListBillEvents.
+func (mr *MockQuerierMockRecorder) ListBillEvents(ctx, billID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBillEvents", reflect.TypeOf((*MockQuerier)(nil).ListBillEvents), ctx, billID)
+}
@@ -42,6 +42,81 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 	return m.recorder
 }
 
+// CountOpenBillsByCurrency mocks base method.
+func (m *MockQuerier) CountOpenBillsByCurrency(ctx context.Context, currency string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenBillsByCurrency", ctx, currency)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenBillsByCurrency indicates an expected call of CountOpenBillsByCurrency.
+func (mr *MockQuerierMockRecorder) CountOpenBillsByCurrency(ctx, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenBillsByCurrency", reflect.TypeOf((*MockQuerier)(nil).CountOpenBillsByCurrency), ctx, currency)
+}
+
+// CreateCurrency mocks base method.
+func (m *MockQuerier) CreateCurrency(ctx context.Context, arg currencies.CreateCurrencyParams) (currencies.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCurrency", ctx, arg)
+	ret0, _ := ret[0].(currencies.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCurrency indicates an expected call of CreateCurrency.
+func (mr *MockQuerierMockRecorder) CreateCurrency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCurrency", reflect.TypeOf((*MockQuerier)(nil).CreateCurrency), ctx, arg)
+}
+
+// CreateCurrencyAuditEntry mocks base method.
+func (m *MockQuerier) CreateCurrencyAuditEntry(ctx context.Context, arg currencies.CreateCurrencyAuditEntryParams) (currencies.CurrencyAuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCurrencyAuditEntry", ctx, arg)
+	ret0, _ := ret[0].(currencies.CurrencyAuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCurrencyAuditEntry indicates an expected call of CreateCurrencyAuditEntry.
+func (mr *MockQuerierMockRecorder) CreateCurrencyAuditEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCurrencyAuditEntry", reflect.TypeOf((*MockQuerier)(nil).CreateCurrencyAuditEntry), ctx, arg)
+}
+
+// CreateCurrencyRate mocks base method.
+func (m *MockQuerier) CreateCurrencyRate(ctx context.Context, arg currencies.CreateCurrencyRateParams) (currencies.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCurrencyRate", ctx, arg)
+	ret0, _ := ret[0].(currencies.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCurrencyRate indicates an expected call of CreateCurrencyRate.
+func (mr *MockQuerierMockRecorder) CreateCurrencyRate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCurrencyRate", reflect.TypeOf((*MockQuerier)(nil).CreateCurrencyRate), ctx, arg)
+}
+
+// CurrencyInUse mocks base method.
+func (m *MockQuerier) CurrencyInUse(ctx context.Context, currency string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrencyInUse", ctx, currency)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CurrencyInUse indicates an expected call of CurrencyInUse.
+func (mr *MockQuerierMockRecorder) CurrencyInUse(ctx, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrencyInUse", reflect.TypeOf((*MockQuerier)(nil).CurrencyInUse), ctx, currency)
+}
+
 // GetCurrency mocks base method.
 func (m *MockQuerier) GetCurrency(ctx context.Context, code pgtype.Text) (currencies.Currency, error) {
 	m.ctrl.T.Helper()
@@ -56,3 +131,137 @@ func (mr *MockQuerierMockRecorder) GetCurrency(ctx, code any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrency", reflect.TypeOf((*MockQuerier)(nil).GetCurrency), ctx, code)
 }
+
+// GetCurrencyByCode mocks base method.
+func (m *MockQuerier) GetCurrencyByCode(ctx context.Context, code pgtype.Text) (currencies.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyByCode", ctx, code)
+	ret0, _ := ret[0].(currencies.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyByCode indicates an expected call of GetCurrencyByCode.
+func (mr *MockQuerierMockRecorder) GetCurrencyByCode(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyByCode", reflect.TypeOf((*MockQuerier)(nil).GetCurrencyByCode), ctx, code)
+}
+
+// GetCurrencyByCodeForShare mocks base method.
+func (m *MockQuerier) GetCurrencyByCodeForShare(ctx context.Context, code pgtype.Text) (currencies.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyByCodeForShare", ctx, code)
+	ret0, _ := ret[0].(currencies.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyByCodeForShare indicates an expected call of GetCurrencyByCodeForShare.
+func (mr *MockQuerierMockRecorder) GetCurrencyByCodeForShare(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyByCodeForShare", reflect.TypeOf((*MockQuerier)(nil).GetCurrencyByCodeForShare), ctx, code)
+}
+
+// GetCurrencyByCodeForUpdate mocks base method.
+func (m *MockQuerier) GetCurrencyByCodeForUpdate(ctx context.Context, code pgtype.Text) (currencies.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyByCodeForUpdate", ctx, code)
+	ret0, _ := ret[0].(currencies.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyByCodeForUpdate indicates an expected call of GetCurrencyByCodeForUpdate.
+func (mr *MockQuerierMockRecorder) GetCurrencyByCodeForUpdate(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyByCodeForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetCurrencyByCodeForUpdate), ctx, code)
+}
+
+// GetCurrencyRateAt mocks base method.
+func (m *MockQuerier) GetCurrencyRateAt(ctx context.Context, arg currencies.GetCurrencyRateAtParams) (currencies.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyRateAt", ctx, arg)
+	ret0, _ := ret[0].(currencies.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyRateAt indicates an expected call of GetCurrencyRateAt.
+func (mr *MockQuerierMockRecorder) GetCurrencyRateAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyRateAt", reflect.TypeOf((*MockQuerier)(nil).GetCurrencyRateAt), ctx, arg)
+}
+
+// GetLastCurrencyAuditEntry mocks base method.
+func (m *MockQuerier) GetLastCurrencyAuditEntry(ctx context.Context, currencyCode string) (currencies.CurrencyAuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastCurrencyAuditEntry", ctx, currencyCode)
+	ret0, _ := ret[0].(currencies.CurrencyAuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastCurrencyAuditEntry indicates an expected call of GetLastCurrencyAuditEntry.
+func (mr *MockQuerierMockRecorder) GetLastCurrencyAuditEntry(ctx, currencyCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastCurrencyAuditEntry", reflect.TypeOf((*MockQuerier)(nil).GetLastCurrencyAuditEntry), ctx, currencyCode)
+}
+
+// GetLatestCurrencyRate mocks base method.
+func (m *MockQuerier) GetLatestCurrencyRate(ctx context.Context, currencyCode string) (currencies.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestCurrencyRate", ctx, currencyCode)
+	ret0, _ := ret[0].(currencies.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestCurrencyRate indicates an expected call of GetLatestCurrencyRate.
+func (mr *MockQuerierMockRecorder) GetLatestCurrencyRate(ctx, currencyCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestCurrencyRate", reflect.TypeOf((*MockQuerier)(nil).GetLatestCurrencyRate), ctx, currencyCode)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockQuerier) ListCurrencies(ctx context.Context) ([]currencies.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", ctx)
+	ret0, _ := ret[0].([]currencies.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockQuerierMockRecorder) ListCurrencies(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockQuerier)(nil).ListCurrencies), ctx)
+}
+
+// SetCurrencyAuditHead mocks base method.
+func (m *MockQuerier) SetCurrencyAuditHead(ctx context.Context, arg currencies.SetCurrencyAuditHeadParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCurrencyAuditHead", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCurrencyAuditHead indicates an expected call of SetCurrencyAuditHead.
+func (mr *MockQuerierMockRecorder) SetCurrencyAuditHead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrencyAuditHead", reflect.TypeOf((*MockQuerier)(nil).SetCurrencyAuditHead), ctx, arg)
+}
+
+// UpdateCurrency mocks base method.
+func (m *MockQuerier) UpdateCurrency(ctx context.Context, arg currencies.UpdateCurrencyParams) (currencies.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCurrency", ctx, arg)
+	ret0, _ := ret[0].(currencies.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCurrency indicates an expected call of UpdateCurrency.
+func (mr *MockQuerierMockRecorder) UpdateCurrency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCurrency", reflect.TypeOf((*MockQuerier)(nil).UpdateCurrency), ctx, arg)
+}
@@ -57,18 +57,19 @@ func (mr *MockQuerierMockRecorder) CreateLineItem(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLineItem", reflect.TypeOf((*MockQuerier)(nil).CreateLineItem), ctx, arg)
 }
 
-// DeleteLineItem mocks base method.
-func (m *MockQuerier) DeleteLineItem(ctx context.Context, id int32) error {
+// CreateLineItems mocks base method.
+func (m *MockQuerier) CreateLineItems(ctx context.Context, arg []lineitems.CreateLineItemsParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLineItem", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateLineItems", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteLineItem indicates an expected call of DeleteLineItem.
-func (mr *MockQuerierMockRecorder) DeleteLineItem(ctx, id any) *gomock.Call {
+// CreateLineItems indicates an expected call of CreateLineItems.
+func (mr *MockQuerierMockRecorder) CreateLineItems(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLineItem", reflect.TypeOf((*MockQuerier)(nil).DeleteLineItem), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLineItems", reflect.TypeOf((*MockQuerier)(nil).CreateLineItems), ctx, arg)
 }
 
 // GetLineItem mocks base method.
@@ -101,11 +102,26 @@ func (mr *MockQuerierMockRecorder) GetLineItemsByBill(ctx, billID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLineItemsByBill", reflect.TypeOf((*MockQuerier)(nil).GetLineItemsByBill), ctx, billID)
 }
 
+// GetLineItemsByIdempotencyKeys mocks base method.
+func (m *MockQuerier) GetLineItemsByIdempotencyKeys(ctx context.Context, idempotencyKeys []string) ([]lineitems.LineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLineItemsByIdempotencyKeys", ctx, idempotencyKeys)
+	ret0, _ := ret[0].([]lineitems.LineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLineItemsByIdempotencyKeys indicates an expected call of GetLineItemsByIdempotencyKeys.
+func (mr *MockQuerierMockRecorder) GetLineItemsByIdempotencyKeys(ctx, idempotencyKeys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLineItemsByIdempotencyKeys", reflect.TypeOf((*MockQuerier)(nil).GetLineItemsByIdempotencyKeys), ctx, idempotencyKeys)
+}
+
 // GetTotalAmountByBill mocks base method.
-func (m *MockQuerier) GetTotalAmountByBill(ctx context.Context, billID pgtype.Int4) (any, error) {
+func (m *MockQuerier) GetTotalAmountByBill(ctx context.Context, billID pgtype.Int4) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTotalAmountByBill", ctx, billID)
-	ret0, _ := ret[0].(any)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -116,17 +132,32 @@ func (mr *MockQuerierMockRecorder) GetTotalAmountByBill(ctx, billID any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalAmountByBill", reflect.TypeOf((*MockQuerier)(nil).GetTotalAmountByBill), ctx, billID)
 }
 
-// UpdateLineItem mocks base method.
-func (m *MockQuerier) UpdateLineItem(ctx context.Context, arg lineitems.UpdateLineItemParams) (lineitems.LineItem, error) {
+// ListLineItems mocks base method.
+func (m *MockQuerier) ListLineItems(ctx context.Context, arg lineitems.ListLineItemsParams) ([]lineitems.LineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLineItems", ctx, arg)
+	ret0, _ := ret[0].([]lineitems.LineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLineItems indicates an expected call of ListLineItems.
+func (mr *MockQuerierMockRecorder) ListLineItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLineItems", reflect.TypeOf((*MockQuerier)(nil).ListLineItems), ctx, arg)
+}
+
+// VoidLineItem mocks base method.
+func (m *MockQuerier) VoidLineItem(ctx context.Context, arg lineitems.VoidLineItemParams) (lineitems.LineItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateLineItem", ctx, arg)
+	ret := m.ctrl.Call(m, "VoidLineItem", ctx, arg)
 	ret0, _ := ret[0].(lineitems.LineItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateLineItem indicates an expected call of UpdateLineItem.
-func (mr *MockQuerierMockRecorder) UpdateLineItem(ctx, arg any) *gomock.Call {
+// VoidLineItem indicates an expected call of VoidLineItem.
+func (mr *MockQuerierMockRecorder) VoidLineItem(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLineItem", reflect.TypeOf((*MockQuerier)(nil).UpdateLineItem), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidLineItem", reflect.TypeOf((*MockQuerier)(nil).VoidLineItem), ctx, arg)
 }
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+type Account struct {
+	ID      int32   `json:"id"`
+	Name    string  `json:"name"`
+	Email   *string `json:"email,omitempty"`
+	Enabled bool    `json:"enabled"`
+	// PaymentTermsDays is the default payment terms of the account's new bills, e.g. 30 for net 30
+	PaymentTermsDays int32     `json:"payment_terms_days"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// AccountUpdate holds the account attributes to change; nil fields are left untouched
+type AccountUpdate struct {
+	Name    *string
+	Email   *string
+	Enabled *bool
+	// PaymentTermsDays applies to bills created afterwards
+	PaymentTermsDays *int32
+}
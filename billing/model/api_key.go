@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// APIKey describes a key issued to an account; the secret itself is never stored
+type APIKey struct {
+	ID        int32      `json:"id"`
+	AccountID int32      `json:"account_id"`
+	Name      string     `json:"name"`
+	KeyPrefix string     `json:"key_prefix"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IssuedAPIKey carries the plaintext key, which is only available at issuance
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
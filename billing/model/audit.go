@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction names a financial write recorded in the audit log
+type AuditAction string
+
+const (
+	AuditActionBillCreated           AuditAction = "bill.created"
+	AuditActionBillStatusChanged     AuditAction = "bill.status_changed"
+	AuditActionBillTotalRecalculated AuditAction = "bill.total_recalculated"
+	AuditActionLineItemCreated       AuditAction = "line_item.created"
+	AuditActionLineItemUpdated       AuditAction = "line_item.updated"
+	AuditActionLineItemVoided        AuditAction = "line_item.voided"
+	// AuditActionCurrencyConverted records the rate applied when a line item was converted
+	// into the bill currency
+	AuditActionCurrencyConverted AuditAction = "currency.converted"
+	// AuditActionBillTotalOverridden records a total set by an operator instead of calculated
+	AuditActionBillTotalOverridden AuditAction = "bill.total_overridden"
+	// AuditActionBillPeriodChanged records a new end time for the billing period of a bill
+	AuditActionBillPeriodChanged AuditAction = "bill.period_changed"
+	// AuditActionPaymentRecorded records a payment received against a closed bill
+	AuditActionPaymentRecorded AuditAction = "payment.recorded"
+	// AuditActionBillOverdue records that a closed bill passed its due date with a balance left
+	AuditActionBillOverdue AuditAction = "bill.overdue"
+	// AuditActionDunningStep records a reminder, late fee or escalation applied to an unpaid bill
+	AuditActionDunningStep AuditAction = "bill.dunning_step"
+	// AuditActionCurrencyCreated records a currency added with its first rate
+	AuditActionCurrencyCreated AuditAction = "currency.created"
+	// AuditActionCurrencyUpdated records a change to the symbol, rate, minor units or enabled
+	// flag of a currency
+	AuditActionCurrencyUpdated AuditAction = "currency.updated"
+	// AuditActionCurrencyRateRecorded records a rate taken from the exchange rate provider
+	AuditActionCurrencyRateRecorded AuditAction = "currency.rate_recorded"
+	// AuditActionCurrencyRateRejected records a provided rate left for an operator because it moves
+	// too far or takes effect before the latest recorded rate
+	AuditActionCurrencyRateRejected AuditAction = "currency.rate_rejected"
+)
+
+// AuditEntry is one link of a bill's audit chain. Hash covers the entry fields and PrevHash,
+// the hash of the entry before it; the first entry of a bill has an empty PrevHash.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	BillID    int32           `json:"bill_id"`
+	Seq       int32           `json:"seq"`
+	Action    AuditAction     `json:"action"`
+	Actor     string          `json:"actor"`
+	Payload   json.RawMessage `json:"payload"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditVerification is the outcome of rehashing a bill's audit chain
+type AuditVerification struct {
+	BillID   int32  `json:"bill_id"`
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`
+	HeadHash string `json:"head_hash,omitempty"`
+	// BrokenAtSeq is the first sequence number that fails to verify; one past the last entry
+	// when entries are missing from the end of the chain
+	BrokenAtSeq int32  `json:"broken_at_seq,omitempty"`
+	Problem     string `json:"problem,omitempty"`
+}
+
+// AuditReport is the outcome of verifying the audit chains of all bills
+type AuditReport struct {
+	BillsChecked int                 `json:"bills_checked"`
+	Failures     []AuditVerification `json:"failures"`
+}
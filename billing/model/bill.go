@@ -6,19 +6,100 @@ import (
 
 type Bill struct {
 	ID               int32      `json:"id"`
+	AccountID        int32      `json:"account_id"`
 	Currency         string     `json:"currency"`
 	Status           BillStatus `json:"status"`
 	CloseReason      *string    `json:"close_reason,omitempty"`
 	ErrorMessage     *string    `json:"error_message,omitempty"`
 	TotalAmountCents int64      `json:"total_amount_cents"`
-	StartTime        time.Time  `json:"start_time"`
-	EndTime          time.Time  `json:"end_time"`
-	BilledAt         *time.Time `json:"billed_at,omitempty"`
-	IdempotencyKey   string     `json:"idempotency_key"`
-	WorkflowID       *string    `json:"workflow_id,omitempty"`
-	LineItems        []LineItem `json:"line_items,omitempty"`
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
+	// AmountPaidCents and BalanceCents are in the bill currency; PaymentStatus is set once the
+	// bill is closed
+	AmountPaidCents int64         `json:"amount_paid_cents"`
+	BalanceCents    int64         `json:"balance_cents"`
+	PaymentStatus   PaymentStatus `json:"payment_status,omitempty"`
+	// TotalAmount, AmountPaid and Balance are the amounts above as decimal strings in the bill
+	// currency; they are set on API responses
+	TotalAmount string      `json:"total_amount,omitempty"`
+	AmountPaid  string      `json:"amount_paid,omitempty"`
+	Balance     string      `json:"balance,omitempty"`
+	StartTime   time.Time   `json:"start_time"`
+	EndTime     time.Time   `json:"end_time"`
+	Timezone    string      `json:"timezone"`
+	Period      *BillPeriod `json:"period,omitempty"`
+	// AllowNegativeTotal lets credits and adjustments take the total below zero
+	AllowNegativeTotal bool `json:"allow_negative_total"`
+	// GracePeriodSeconds keeps the bill open for late line items after the period ends
+	GracePeriodSeconds int32 `json:"grace_period_seconds"`
+	// PaymentTermsDays is the number of days a closed bill has to be paid; defaults to the
+	// account's terms when creating a bill
+	PaymentTermsDays *int32 `json:"payment_terms_days"`
+	// DueAt is set when the bill closes; OverdueAt is set once it is past due with a balance left
+	DueAt          *time.Time `json:"due_at,omitempty"`
+	OverdueAt      *time.Time `json:"overdue_at,omitempty"`
+	BilledAt       *time.Time `json:"billed_at,omitempty"`
+	IdempotencyKey string     `json:"idempotency_key"`
+	WorkflowID     *string    `json:"workflow_id,omitempty"`
+	LineItems      []LineItem `json:"line_items,omitempty"`
+	// LineItemsNextCursor is set when LineItems was capped and more line items follow
+	LineItemsNextCursor string    `json:"line_items_next_cursor,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// SetDecimalAmounts fills in the decimal-string amounts of the bill and its line items
+func (b *Bill) SetDecimalAmounts(minorUnits int32) {
+	b.TotalAmount = FormatAmount(b.TotalAmountCents, minorUnits)
+	b.AmountPaid = FormatAmount(b.AmountPaidCents, minorUnits)
+	b.Balance = FormatAmount(b.BalanceCents, minorUnits)
+	for i := range b.LineItems {
+		b.LineItems[i].SetDecimalAmounts(minorUnits)
+	}
+}
+
+// GetBillOptions controls the line items embedded in a bill; the zero value embeds all of them
+type GetBillOptions struct {
+	OmitLineItems bool
+	// LineItemLimit caps the embedded line items, newest first, when positive
+	LineItemLimit int32
+}
+
+// BillFilter narrows and orders a listing of an account's bills; empty fields do not filter.
+// Time ranges include the start and exclude the end; total bounds are inclusive.
+type BillFilter struct {
+	Statuses      []BillStatus
+	Currency      string
+	StartFrom     *time.Time
+	StartTo       *time.Time
+	EndFrom       *time.Time
+	EndTo         *time.Time
+	MinTotalCents *int64
+	MaxTotalCents *int64
+	// SortBy defaults to created_at, newest first unless Ascending is set
+	SortBy    BillSortKey
+	Ascending bool
+	// Cursor is the NextCursor of the previous page; Offset is kept for older clients
+	Cursor string
+	Offset int32
+	Limit  int32
+	// IncludeTotalCount counts every matching bill, which costs an extra query
+	IncludeTotalCount bool
+}
+
+// BillSortKey is a bill attribute listings can be ordered by
+type BillSortKey string
+
+const (
+	BillSortCreatedAt BillSortKey = "created_at"
+	BillSortStartTime BillSortKey = "start_time"
+	BillSortTotal     BillSortKey = "total_amount_cents"
+)
+
+// BillPage is one page of bills; NextCursor is empty on the last page and TotalCount is only
+// set when it was asked for
+type BillPage struct {
+	Bills      []*Bill
+	NextCursor string
+	TotalCount *int64
 }
 
 type BillStatus string
@@ -29,4 +110,54 @@ const (
 	BillStatusClosing           BillStatus = "closing"
 	BillStatusClosed            BillStatus = "closed"
 	BillStatusAttentionRequired BillStatus = "attention_required"
+	// BillStatusCancelled is terminal; the bill keeps its line items and total but owes nothing
+	BillStatusCancelled BillStatus = "cancelled"
+	// BillStatusClosingGrace is the window after the period end in which line items incurred
+	// during the period are still accepted
+	BillStatusClosingGrace BillStatus = "closing_grace"
 )
+
+// BillRecoveryAction is an operator action that moves a bill out of attention_required
+type BillRecoveryAction string
+
+const (
+	// BillRecoveryRetry runs finalization again and closes the bill if it succeeds
+	BillRecoveryRetry BillRecoveryAction = "retry"
+	// BillRecoveryForceClose closes the bill with an operator supplied total
+	BillRecoveryForceClose BillRecoveryAction = "force_close"
+	// BillRecoveryReactivate returns the bill to active so it can be corrected and closed again
+	BillRecoveryReactivate BillRecoveryAction = "reactivate"
+)
+
+// BillRecovery describes how to recover a bill in attention_required. TotalAmountCents and
+// Justification are required to force close.
+type BillRecovery struct {
+	Action           BillRecoveryAction
+	TotalAmountCents *int64
+	Justification    string
+}
+
+// BillPeriod is a calendar period spec whose boundaries fall on local midnight in the bill timezone
+type BillPeriod string
+
+const (
+	BillPeriodCalendarMonth BillPeriod = "calendar_month"
+	// BillPeriodISOWeek runs Monday to Monday
+	BillPeriodISOWeek BillPeriod = "iso_week"
+	BillPeriodDay     BillPeriod = "day"
+)
+
+// ActorSystem is the actor recorded for transitions made by workflows rather than API callers
+const ActorSystem = "system"
+
+// BillEvent is one status transition in the history of a bill. FromStatus is empty for the
+// first recorded transition of bills created before history was kept.
+type BillEvent struct {
+	ID         int64      `json:"id"`
+	BillID     int32      `json:"bill_id"`
+	FromStatus BillStatus `json:"from_status,omitempty"`
+	ToStatus   BillStatus `json:"to_status"`
+	Reason     string     `json:"reason,omitempty"`
+	Actor      string     `json:"actor"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -1,5 +1,11 @@
 package model
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type Currency string
 
 const (
@@ -7,12 +13,68 @@ const (
 	GEL Currency = "GEL"
 )
 
+// DefaultMinorUnits is the number of decimal places of a currency unless configured otherwise
+const DefaultMinorUnits int32 = 2
+
+// FormatAmount renders an amount in minor units as a decimal string with the given number of
+// decimal places, e.g. 1250 as "12.50" with 2 minor units and as "1250" with 0
+func FormatAmount(amount int64, minorUnits int32) string {
+	return decimal.New(amount, -minorUnits).StringFixed(minorUnits)
+}
+
 type CurrencyInfo struct {
 	ID      int32   `json:"id"`
 	Code    string  `json:"code"`
 	Symbol  *string `json:"symbol,omitempty"`
 	Rate    float64 `json:"rate"`
 	Enabled bool    `json:"enabled"`
+	// MinorUnits is the number of decimal places of the currency, e.g. 2 for USD and 0 for JPY
+	MinorUnits int32 `json:"minor_units"`
+}
+
+// CurrencyUpdate holds the currency attributes to change; nil fields are left untouched. A new
+// rate takes effect immediately.
+type CurrencyUpdate struct {
+	Symbol     *string
+	Rate       *float64
+	MinorUnits *int32
+	Enabled    *bool
+}
+
+// CurrencyRate is the rate of a currency against the base currency from EffectiveFrom until the
+// next rate of that currency takes effect
+type CurrencyRate struct {
+	ID            int32     `json:"id"`
+	Code          string    `json:"code"`
+	Rate          float64   `json:"rate"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ProvidedRate is a rate fetched from an exchange rate provider; a zero EffectiveFrom means the
+// rate takes effect when it is synced
+type ProvidedRate struct {
+	Code          string    `json:"code"`
+	Rate          float64   `json:"rate"`
+	EffectiveFrom time.Time `json:"effective_from,omitempty"`
+}
+
+// RejectedRate is a provided rate the sync refused to record
+type RejectedRate struct {
+	Code          string  `json:"code"`
+	CurrentRate   float64 `json:"current_rate,omitempty"`
+	ProvidedRate  float64 `json:"provided_rate"`
+	ChangePercent float64 `json:"change_percent,omitempty"`
+	Reason        string  `json:"reason"`
+}
+
+// RateSyncResult is the outcome of syncing the rates of a provider. Skipped lists provided
+// currencies that are unknown or disabled.
+type RateSyncResult struct {
+	Updated   []CurrencyRate `json:"updated"`
+	Unchanged []string       `json:"unchanged"`
+	Rejected  []RejectedRate `json:"rejected"`
+	Skipped   []string       `json:"skipped"`
 }
 
 type ConversionResult struct {
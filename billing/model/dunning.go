@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+// DunningAction is what a dunning step does to a bill that is still unpaid
+type DunningAction string
+
+const (
+	// DunningActionReminder sends a payment reminder
+	DunningActionReminder DunningAction = "reminder"
+	// DunningActionLateFee adds a late fee line item to the bill
+	DunningActionLateFee DunningAction = "late_fee"
+	// DunningActionEscalate hands the bill over to collections; it is the last step of a schedule
+	DunningActionEscalate DunningAction = "escalate"
+)
+
+// DunningStep is one step of a dunning schedule. After is counted from the bill's due date.
+// A late fee is LateFeeCents in the bill currency plus LateFeeBasisPoints of the balance.
+type DunningStep struct {
+	After              time.Duration `json:"after"`
+	Action             DunningAction `json:"action"`
+	LateFeeCents       int64         `json:"late_fee_cents,omitempty"`
+	LateFeeBasisPoints int64         `json:"late_fee_basis_points,omitempty"`
+}
+
+// LateFee is the fee charged by a late fee step on the given balance, rounded half up
+func (s DunningStep) LateFee(balanceCents int64) int64 {
+	return s.LateFeeCents + (balanceCents*s.LateFeeBasisPoints+5000)/10000
+}
+
+// DunningNotice describes a dunning step applied to a bill; BalanceCents includes any late fee
+// the step added
+type DunningNotice struct {
+	BillID       int32         `json:"bill_id"`
+	AccountID    int32         `json:"account_id"`
+	Currency     string        `json:"currency"`
+	Step         int           `json:"step"`
+	Action       DunningAction `json:"action"`
+	LateFeeCents int64         `json:"late_fee_cents,omitempty"`
+	BalanceCents int64         `json:"balance_cents"`
+	DueAt        time.Time     `json:"due_at"`
+}
@@ -7,6 +7,8 @@ import (
 
 // IdempotencyKey represents the cache key structure
 type IdempotencyKey struct {
+	// Caller scopes keys per authenticated caller so accounts cannot replay each other's responses
+	Caller   string
 	Resource string
 	Key      string
 }
@@ -5,27 +5,130 @@ import (
 )
 
 type LineItem struct {
-	ID             int32             `json:"id"`
-	BillID         int32             `json:"bill_id"`
-	AmountCents    int64             `json:"amount_cents"`
+	ID     int32        `json:"id"`
+	BillID int32        `json:"bill_id"`
+	Kind   LineItemKind `json:"kind"`
+	// Quantity is a decimal string; AmountCents is Quantity x UnitAmountCents rounded to the cent
+	Quantity        string `json:"quantity"`
+	UnitAmountCents int64  `json:"unit_amount_cents"`
+	AmountCents     int64  `json:"amount_cents"`
+	// UnitAmount and Amount are the amounts above as decimal strings; they are set on API responses
+	UnitAmount     string            `json:"unit_amount,omitempty"`
+	Amount         string            `json:"amount,omitempty"`
 	Currency       string            `json:"currency"`
 	Description    string            `json:"description"`
 	IncurredAt     time.Time         `json:"incurred_at"`
 	ReferenceID    string            `json:"reference_id"`
 	Metadata       *CurrencyMetadata `json:"metadata,omitempty"`
 	IdempotencyKey string            `json:"idempotency_key"`
-	CreatedAt      time.Time         `json:"created_at"`
-	UpdatedAt      time.Time         `json:"updated_at"`
+	VoidedAt       *time.Time        `json:"voided_at,omitempty"`
+	VoidReason     string            `json:"void_reason,omitempty"`
+	// ReplacesLineItemID is the voided line item this one corrects
+	ReplacesLineItemID *int32    `json:"replaces_line_item_id,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 
 	BillWorkflowID string `json:"-"`
 }
 
+// LineItemUpdate holds the line item attributes to change; nil fields are left untouched.
+// Amounts and Currency are in the original (pre-conversion) currency, and amounts are given
+// the way the line item kind is submitted. AmountCents sets a flat amount of quantity 1.
+type LineItemUpdate struct {
+	AmountCents     *int64
+	UnitAmountCents *int64
+	Quantity        *string
+	Currency        *string
+	Description     *string
+}
+
+// LineItemFilter narrows a listing of a bill's line items; empty fields do not filter
+type LineItemFilter struct {
+	// IncurredFrom is inclusive and IncurredTo exclusive
+	IncurredFrom *time.Time
+	IncurredTo   *time.Time
+	// Currency matches the original currency the line item was submitted in
+	Currency    string
+	ReferenceID string
+	// Description matches a case-insensitive substring
+	Description string
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	Limit  int32
+}
+
+// LineItemPage is one page of line items, newest first; NextCursor is empty on the last page
+type LineItemPage struct {
+	LineItems  []LineItem
+	NextCursor string
+}
+
+// LineItemKind tells how a line item affects the bill total
+type LineItemKind string
+
+const (
+	LineItemKindCharge LineItemKind = "charge"
+	// LineItemKindCredit reduces the bill total; its amount is stored negated
+	LineItemKindCredit LineItemKind = "credit"
+	// LineItemKindAdjustment carries a signed amount and may raise or lower the total
+	LineItemKindAdjustment LineItemKind = "adjustment"
+)
+
+// SignedAmount returns the amount as it counts towards the bill total, given the amount
+// a client submitted for this kind: credits are submitted as positive amounts
+func (k LineItemKind) SignedAmount(amountCents int64) int64 {
+	if k == LineItemKindCredit {
+		return -amountCents
+	}
+	return amountCents
+}
+
 func (li *LineItem) SetBillWorkflowID(id string) {
 	li.BillWorkflowID = id
 }
 
+// SetDecimalAmounts fills in the decimal-string amounts of the line item
+func (li *LineItem) SetDecimalAmounts(minorUnits int32) {
+	li.UnitAmount = FormatAmount(li.UnitAmountCents, minorUnits)
+	li.Amount = FormatAmount(li.AmountCents, minorUnits)
+}
+
+// CurrencyMetadata records how an amount was converted. FromRateID and ToRateID are the currency
+// rates that were in effect at RateAt; they are unset on amounts converted before rates were kept.
 type CurrencyMetadata struct {
-	OriginalAmountCents int64   `json:"original_amount_cents"`
-	OriginalCurrency    string  `json:"original_currency"`
-	ExchangeRate        float64 `json:"exchange_rate"`
+	OriginalAmountCents int64 `json:"original_amount_cents"`
+	// OriginalAmount is OriginalAmountCents as a decimal string in the original currency
+	OriginalAmount   string     `json:"original_amount,omitempty"`
+	OriginalCurrency string     `json:"original_currency"`
+	ExchangeRate     float64    `json:"exchange_rate"`
+	FromRateID       int32      `json:"from_rate_id,omitempty"`
+	ToRateID         int32      `json:"to_rate_id,omitempty"`
+	RateAt           *time.Time `json:"rate_at,omitempty"`
+}
+
+// LineItemBatchMode controls how a batch of line items handles invalid items
+type LineItemBatchMode string
+
+const (
+	// LineItemBatchModeAtomic adds every item or none of them
+	LineItemBatchModeAtomic LineItemBatchMode = "atomic"
+	// LineItemBatchModePartial adds the valid items and reports the others as failed
+	LineItemBatchModePartial LineItemBatchMode = "partial"
+)
+
+type LineItemBatchStatus string
+
+const (
+	LineItemBatchStatusCreated LineItemBatchStatus = "created"
+	// LineItemBatchStatusDuplicate means the idempotency key was already used on this bill
+	LineItemBatchStatusDuplicate LineItemBatchStatus = "duplicate"
+	LineItemBatchStatusFailed    LineItemBatchStatus = "failed"
+)
+
+// LineItemBatchResult is the outcome of one item of a batch, in request order
+type LineItemBatchResult struct {
+	Index    int                 `json:"index"`
+	Status   LineItemBatchStatus `json:"status"`
+	LineItem *LineItem           `json:"line_item,omitempty"`
+	Error    string              `json:"error,omitempty"`
 }
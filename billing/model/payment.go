@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+)
+
+// Payment is money received against a closed bill. AmountCents and Currency are in the bill
+// currency; a payment made in another currency keeps the submitted amount and rate in Metadata.
+type Payment struct {
+	ID          int32 `json:"id"`
+	BillID      int32 `json:"bill_id"`
+	AmountCents int64 `json:"amount_cents"`
+	// Amount is AmountCents as a decimal string; it is set on API responses
+	Amount         string            `json:"amount,omitempty"`
+	Currency       string            `json:"currency"`
+	Metadata       *CurrencyMetadata `json:"metadata,omitempty"`
+	Reference      string            `json:"reference,omitempty"`
+	PaidAt         time.Time         `json:"paid_at"`
+	IdempotencyKey string            `json:"idempotency_key"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// SetDecimalAmounts fills in the decimal-string amount of the payment
+func (p *Payment) SetDecimalAmounts(minorUnits int32) {
+	p.Amount = FormatAmount(p.AmountCents, minorUnits)
+}
+
+// PaymentStatus is how far the payments of a closed bill cover its total
+type PaymentStatus string
+
+const (
+	PaymentStatusUnpaid        PaymentStatus = "unpaid"
+	PaymentStatusPartiallyPaid PaymentStatus = "partially_paid"
+	PaymentStatusPaid          PaymentStatus = "paid"
+	PaymentStatusOverpaid      PaymentStatus = "overpaid"
+)
+
+// SettlementStatus derives the payment status of a bill from its total and the amount paid
+func SettlementStatus(totalCents, paidCents int64) PaymentStatus {
+	balance := totalCents - paidCents
+	switch {
+	case balance < 0:
+		return PaymentStatusOverpaid
+	case balance == 0:
+		return PaymentStatusPaid
+	case paidCents > 0:
+		return PaymentStatusPartiallyPaid
+	default:
+		return PaymentStatusUnpaid
+	}
+}
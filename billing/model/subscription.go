@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+type Subscription struct {
+	ID              int32                `json:"id"`
+	AccountID       int32                `json:"account_id"`
+	Currency        string               `json:"currency"`
+	Interval        SubscriptionInterval `json:"interval"`
+	IntervalSeconds *int64               `json:"interval_seconds,omitempty"`
+	AnchorAt        time.Time            `json:"anchor_at"`
+	Status          SubscriptionStatus   `json:"status"`
+	WorkflowID      *string              `json:"workflow_id,omitempty"`
+	CurrentBillID   *int32               `json:"current_bill_id,omitempty"`
+	IdempotencyKey  string               `json:"idempotency_key"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+}
+
+type SubscriptionInterval string
+
+const (
+	SubscriptionIntervalMonthly SubscriptionInterval = "monthly"
+	SubscriptionIntervalWeekly  SubscriptionInterval = "weekly"
+	// SubscriptionIntervalCustom repeats every IntervalSeconds
+	SubscriptionIntervalCustom SubscriptionInterval = "custom"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive    SubscriptionStatus = "active"
+	SubscriptionStatusPaused    SubscriptionStatus = "paused"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+)
@@ -0,0 +1,96 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"go.temporal.io/api/serviceerror"
+
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+type RecordPaymentRequest struct {
+	IdempotencyKey string `header:"X-Idempotency-Key" json:"-"`
+
+	// Currency may be any enabled currency; the amount is converted into the bill currency
+	AmountCents int64  `json:"amount_cents" validate:"required,min=1"`
+	Currency    string `json:"currency" validate:"required,len=3,alpha"`
+	Reference   string `json:"reference" validate:"max=100"`
+	// PaidAt is when the money was received, now by default
+	PaidAt time.Time `json:"paid_at"`
+}
+
+type PaymentResponse struct {
+	Payment model.Payment `json:"payment"`
+}
+
+// RecordPayment records a full or partial payment against a closed bill
+//
+//encore:api auth path=/v1/bills/:id/payments method=POST tag:idempotency
+func (s *Service) RecordPayment(ctx context.Context, id int32, req *RecordPaymentRequest) (*PaymentResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	payment := &model.Payment{
+		BillID:         id,
+		AmountCents:    req.AmountCents,
+		Currency:       req.Currency,
+		Reference:      req.Reference,
+		PaidAt:         req.PaidAt,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+
+	result, err := s.business.RecordPayment(ctx, id, payment, callerActor())
+	if err != nil {
+		rlog.Error("failed to record payment", "error", err, "bill_id", id)
+		return nil, err
+	}
+
+	// Tell the dunning workflow of the bill what is left to pay, so a settled bill stops being dunned
+	runAsync("signal_payment_received", func(ctx context.Context) error {
+		return s.signalPaymentReceived(ctx, id, result.ID)
+	})
+
+	s.newAmountFormatter(ctx).payment(result)
+
+	return &PaymentResponse{
+		Payment: *result,
+	}, nil
+}
+
+// signalPaymentReceived sends the balance of a bill to its dunning workflow. Bills that are not
+// being dunned have no workflow to signal.
+func (s *Service) signalPaymentReceived(ctx context.Context, billID, paymentID int32) error {
+	bill, err := s.business.GetBill(ctx, billID, model.GetBillOptions{OmitLineItems: true})
+	if err != nil {
+		return err
+	}
+
+	err = s.temporal.SignalWorkflow(ctx, workflow.DunningWorkflowID(billID), "", workflow.PaymentReceivedSignalName, workflow.PaymentReceivedSignal{
+		PaymentID:    paymentID,
+		BalanceCents: bill.BalanceCents,
+	})
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	return err
+}
+
+// Validate implements validation for RecordPaymentRequest
+func (r *RecordPaymentRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
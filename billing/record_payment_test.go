@@ -0,0 +1,125 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+func TestRecordPayment(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+	originalRunAsync := runAsync
+	runAsync = func(op string, fn func(ctx context.Context) error) { _ = fn(context.Background()) }
+	defer func() { runAsync = originalRunAsync }()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	mockTemporal := &mocks.Client{}
+	service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
+
+	testCases := []struct {
+		name               string
+		billID             int32
+		request            *RecordPaymentRequest
+		mockReturn         *model.Payment
+		mockError          error
+		expectedError      string
+		expectBusinessCall bool
+	}{
+		{
+			name:               "record_payment",
+			billID:             1,
+			request:            &RecordPaymentRequest{IdempotencyKey: "pay-1", AmountCents: 400, Currency: "EUR", Reference: "wire-42"},
+			mockReturn:         &model.Payment{ID: 3, BillID: 1, AmountCents: 440, Currency: "USD", Reference: "wire-42"},
+			expectBusinessCall: true,
+		},
+		{
+			name:               "invalid_bill_id",
+			billID:             0,
+			request:            &RecordPaymentRequest{AmountCents: 400, Currency: "USD"},
+			expectedError:      "invalid bill ID",
+			expectBusinessCall: false,
+		},
+		{
+			name:               "bill_not_closed",
+			billID:             1,
+			request:            &RecordPaymentRequest{IdempotencyKey: "pay-2", AmountCents: 400, Currency: "USD"},
+			mockError:          &errs.Error{Code: errs.FailedPrecondition, Message: "payments can only be recorded against closed bills"},
+			expectedError:      "payments can only be recorded against closed bills",
+			expectBusinessCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectBusinessCall {
+				mockBusiness.EXPECT().
+					RecordPayment(gomock.Any(), tc.billID, &model.Payment{
+						BillID:         tc.billID,
+						AmountCents:    tc.request.AmountCents,
+						Currency:       tc.request.Currency,
+						Reference:      tc.request.Reference,
+						IdempotencyKey: tc.request.IdempotencyKey,
+					}, "admin").
+					Return(tc.mockReturn, tc.mockError).
+					Times(1)
+			}
+			if tc.mockReturn != nil {
+				mockBusiness.EXPECT().GetBill(gomock.Any(), tc.billID, model.GetBillOptions{OmitLineItems: true}).
+					Return(&model.Bill{ID: tc.billID, BalanceCents: 560}, nil)
+				mockTemporal.On("SignalWorkflow", mock.Anything, "dunning-1", "", workflow.PaymentReceivedSignalName,
+					workflow.PaymentReceivedSignal{PaymentID: tc.mockReturn.ID, BalanceCents: 560}).Return(nil).Once()
+			}
+
+			response, err := service.RecordPayment(context.Background(), tc.billID, tc.request)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockReturn.AmountCents, response.Payment.AmountCents)
+				assert.Equal(t, "USD", response.Payment.Currency)
+			}
+			mockTemporal.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSignalPaymentReceived_BillNotInDunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	mockTemporal := &mocks.Client{}
+	service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
+
+	mockBusiness.EXPECT().GetBill(gomock.Any(), int32(4), model.GetBillOptions{OmitLineItems: true}).
+		Return(&model.Bill{ID: 4}, nil)
+	mockTemporal.On("SignalWorkflow", mock.Anything, "dunning-4", "", workflow.PaymentReceivedSignalName, mock.Anything).
+		Return(serviceerror.NewNotFound("workflow not found")).Once()
+
+	assert.NoError(t, service.signalPaymentReceived(context.Background(), 4, 9))
+	mockTemporal.AssertExpectations(t)
+}
+
+func TestRecordPaymentRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&RecordPaymentRequest{AmountCents: 400, Currency: "USD"}).Validate())
+	assert.Error(t, (&RecordPaymentRequest{AmountCents: 0, Currency: "USD"}).Validate())
+	assert.Error(t, (&RecordPaymentRequest{AmountCents: -5, Currency: "USD"}).Validate())
+	assert.Error(t, (&RecordPaymentRequest{AmountCents: 400, Currency: "US"}).Validate())
+}
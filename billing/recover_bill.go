@@ -0,0 +1,93 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+type RecoverBillRequest struct {
+	Action string `json:"action" validate:"required,oneof=retry force_close reactivate"`
+	// TotalAmountCents is the final total of a force closed bill
+	TotalAmountCents *int64 `json:"total_amount_cents,omitempty" validate:"required_if=Action force_close"`
+	Justification    string `json:"justification" validate:"required_if=Action force_close,max=500"`
+	// Operator names the person acting; it is recorded along with the caller in the bill history
+	Operator string `json:"operator" validate:"required,max=80"`
+}
+
+type RecoverBillResponse struct {
+	Bill model.Bill `json:"bill"`
+}
+
+// RecoverBill moves a bill out of attention_required by retrying finalization, force closing
+// it with an override total, or returning it to active. Admin only.
+//
+//encore:api auth path=/v1/bills/:id/recover method=POST tag:idempotency
+func (s *Service) RecoverBill(ctx context.Context, id int32, req *RecoverBillRequest) (*RecoverBillResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	recovery := model.BillRecovery{
+		Action:           model.BillRecoveryAction(req.Action),
+		TotalAmountCents: req.TotalAmountCents,
+		Justification:    req.Justification,
+	}
+	actor := callerActor() + ":" + req.Operator
+
+	err := s.business.RecoverBill(ctx, id, recovery, actor)
+	if err != nil {
+		rlog.Error("failed to recover bill", "error", err, "id", id, "action", req.Action)
+		return nil, err
+	}
+
+	bill, err := s.business.GetBill(ctx, id, model.GetBillOptions{})
+	if err != nil {
+		rlog.Error("failed to get recovered bill", "error", err, "id", id)
+		return nil, err
+	}
+
+	if bill.WorkflowID != nil {
+		workflowID := *bill.WorkflowID
+		if bill.Status == model.BillStatusClosed {
+			runAsync("terminate_workflow", func(ctx context.Context) error {
+				return s.terminateWorkflow(ctx, workflowID, "recovered_via_api")
+			})
+			runAsync("start_dunning", func(ctx context.Context) error {
+				return s.startDunningWorkflow(ctx, id)
+			})
+		} else {
+			// Stop the workflow from retrying finalization of a bill the operator reopened and
+			// have it close the bill at its end time again
+			endTime := bill.EndTime
+			runAsync("signal_bill_reactivated", func(ctx context.Context) error {
+				return s.temporal.SignalWorkflow(ctx, workflowID, "", workflow.BillReactivatedSignalName, workflow.BillReactivatedSignal{
+					EndTime: endTime,
+				})
+			})
+		}
+	}
+
+	s.newAmountFormatter(ctx).bill(bill)
+
+	return &RecoverBillResponse{
+		Bill: *bill,
+	}, nil
+}
+
+// Validate implements validation for RecoverBillRequest
+func (r *RecoverBillRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+func TestRecoverBill(t *testing.T) {
+	originalRunAsync := runAsync
+	runAsync = func(op string, fn func(ctx context.Context) error) { _ = fn(context.Background()) }
+	defer func() { runAsync = originalRunAsync }()
+
+	total := int64(1250)
+
+	t.Run("force_close_terminates_workflow_and_starts_dunning", func(t *testing.T) {
+		withCaller(t, &AuthData{Admin: true})
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockBusiness := bill_business.NewMockBusiness(ctrl)
+		mockTemporal := &mocks.Client{}
+		service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
+
+		mockBusiness.EXPECT().RecoverBill(gomock.Any(), int32(1), model.BillRecovery{
+			Action:           model.BillRecoveryForceClose,
+			TotalAmountCents: &total,
+			Justification:    "agreed with customer",
+		}, "admin:alice").Return(nil)
+		mockBusiness.EXPECT().GetBill(gomock.Any(), int32(1), model.GetBillOptions{}).
+			Return(&model.Bill{ID: 1, Status: model.BillStatusClosed, WorkflowID: stringPtr("bill-1")}, nil)
+		mockTemporal.On("TerminateWorkflow", mock.Anything, "bill-1", "", "recovered_via_api").Return(nil).Once()
+		mockTemporal.On("ExecuteWorkflow",
+			mock.Anything,
+			mock.MatchedBy(func(options client.StartWorkflowOptions) bool { return options.ID == "dunning-1" }),
+			mock.Anything,
+			workflow.DunningWorkflowParams{BillID: 1},
+		).Return(nil, nil).Once()
+
+		response, err := service.RecoverBill(context.Background(), 1, &RecoverBillRequest{
+			Action:           "force_close",
+			TotalAmountCents: &total,
+			Justification:    "agreed with customer",
+			Operator:         "alice",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.BillStatusClosed, response.Bill.Status)
+		mockTemporal.AssertExpectations(t)
+	})
+
+	t.Run("reactivate_signals_workflow", func(t *testing.T) {
+		withCaller(t, &AuthData{Admin: true})
+		endTime := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockBusiness := bill_business.NewMockBusiness(ctrl)
+		mockTemporal := &mocks.Client{}
+		service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
+
+		mockBusiness.EXPECT().RecoverBill(gomock.Any(), int32(2), model.BillRecovery{Action: model.BillRecoveryReactivate}, "admin:bob").Return(nil)
+		mockBusiness.EXPECT().GetBill(gomock.Any(), int32(2), model.GetBillOptions{}).
+			Return(&model.Bill{ID: 2, Status: model.BillStatusActive, EndTime: endTime, WorkflowID: stringPtr("bill-2")}, nil)
+		mockTemporal.On("SignalWorkflow", mock.Anything, "bill-2", "", workflow.BillReactivatedSignalName,
+			workflow.BillReactivatedSignal{EndTime: endTime}).Return(nil).Once()
+
+		response, err := service.RecoverBill(context.Background(), 2, &RecoverBillRequest{Action: "reactivate", Operator: "bob"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.BillStatusActive, response.Bill.Status)
+		mockTemporal.AssertExpectations(t)
+	})
+
+	t.Run("account_caller_is_rejected", func(t *testing.T) {
+		withCaller(t, &AuthData{AccountID: 7})
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := &Service{business: bill_business.NewMockBusiness(ctrl)}
+
+		response, err := service.RecoverBill(context.Background(), 1, &RecoverBillRequest{Action: "retry", Operator: "alice"})
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+	})
+}
+
+func TestRecoverBillRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&RecoverBillRequest{Action: "retry", Operator: "alice"}).Validate())
+	assert.Error(t, (&RecoverBillRequest{Action: "force_close", Operator: "alice"}).Validate())
+	assert.Error(t, (&RecoverBillRequest{Action: "delete", Operator: "alice"}).Validate())
+	assert.Error(t, (&RecoverBillRequest{Action: "retry"}).Validate())
+}
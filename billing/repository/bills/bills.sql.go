@@ -11,12 +11,134 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const countBills = `-- name: CountBills :one
+const addBillPayment = `-- name: AddBillPayment :one
+UPDATE bills
+SET amount_paid_cents = amount_paid_cents + $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
+`
+
+type AddBillPaymentParams struct {
+	ID              int32
+	AmountPaidCents int64
+}
+
+func (q *Queries) AddBillPayment(ctx context.Context, arg AddBillPaymentParams) (Bill, error) {
+	row := q.db.QueryRow(ctx, addBillPayment, arg.ID, arg.AmountPaidCents)
+	var i Bill
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Status,
+		&i.CloseReason,
+		&i.ErrorMessage,
+		&i.TotalAmountCents,
+		&i.StartTime,
+		&i.EndTime,
+		&i.BilledAt,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
+	)
+	return i, err
+}
+
+const addToBillTotal = `-- name: AddToBillTotal :one
+UPDATE bills
+SET total_amount_cents = COALESCE(total_amount_cents, 0) + $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
+`
+
+type AddToBillTotalParams struct {
+	ID               int32
+	TotalAmountCents pgtype.Int8
+}
+
+func (q *Queries) AddToBillTotal(ctx context.Context, arg AddToBillTotalParams) (Bill, error) {
+	row := q.db.QueryRow(ctx, addToBillTotal, arg.ID, arg.TotalAmountCents)
+	var i Bill
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Status,
+		&i.CloseReason,
+		&i.ErrorMessage,
+		&i.TotalAmountCents,
+		&i.StartTime,
+		&i.EndTime,
+		&i.BilledAt,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
+	)
+	return i, err
+}
+
+const countFilteredBills = `-- name: CountFilteredBills :one
 SELECT COUNT(*) FROM bills
+WHERE account_id = $1
+  AND ($2::text[] IS NULL OR status = ANY($2::text[]))
+  AND ($3::text IS NULL OR currency = $3)
+  AND ($4::timestamptz IS NULL OR start_time >= $4)
+  AND ($5::timestamptz IS NULL OR start_time < $5)
+  AND ($6::timestamptz IS NULL OR end_time >= $6)
+  AND ($7::timestamptz IS NULL OR end_time < $7)
+  AND ($8::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))
+  AND ($9::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))
+  AND (NOT $10::bool OR status <> 'cancelled')
 `
 
-func (q *Queries) CountBills(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countBills)
+type CountFilteredBillsParams struct {
+	AccountID        pgtype.Int4
+	Statuses         []string
+	Currency         pgtype.Text
+	StartFrom        pgtype.Timestamptz
+	StartTo          pgtype.Timestamptz
+	EndFrom          pgtype.Timestamptz
+	EndTo            pgtype.Timestamptz
+	MinTotalCents    pgtype.Int8
+	MaxTotalCents    pgtype.Int8
+	ExcludeCancelled bool
+}
+
+func (q *Queries) CountFilteredBills(ctx context.Context, arg CountFilteredBillsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countFilteredBills,
+		arg.AccountID,
+		arg.Statuses,
+		arg.Currency,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.MinTotalCents,
+		arg.MaxTotalCents,
+		arg.ExcludeCancelled,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -30,19 +152,31 @@ INSERT INTO bills (
     start_time,
     end_time,
     idempotency_key,
-    workflow_id
+    workflow_id,
+    account_id,
+    timezone,
+    period,
+    allow_negative_total,
+    grace_period_seconds,
+    payment_terms_days
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
 `
 
 type CreateBillParams struct {
-	Currency       string
-	Status         string
-	StartTime      pgtype.Timestamptz
-	EndTime        pgtype.Timestamptz
-	IdempotencyKey string
-	WorkflowID     pgtype.Text
+	Currency           string
+	Status             string
+	StartTime          pgtype.Timestamptz
+	EndTime            pgtype.Timestamptz
+	IdempotencyKey     string
+	WorkflowID         pgtype.Text
+	AccountID          pgtype.Int4
+	Timezone           string
+	Period             pgtype.Text
+	AllowNegativeTotal bool
+	GracePeriodSeconds int32
+	PaymentTermsDays   int32
 }
 
 // Bills related queries
@@ -54,6 +188,12 @@ func (q *Queries) CreateBill(ctx context.Context, arg CreateBillParams) (Bill, e
 		arg.EndTime,
 		arg.IdempotencyKey,
 		arg.WorkflowID,
+		arg.AccountID,
+		arg.Timezone,
+		arg.Period,
+		arg.AllowNegativeTotal,
+		arg.GracePeriodSeconds,
+		arg.PaymentTermsDays,
 	)
 	var i Bill
 	err := row.Scan(
@@ -70,12 +210,22 @@ func (q *Queries) CreateBill(ctx context.Context, arg CreateBillParams) (Bill, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
 	)
 	return i, err
 }
 
 const getBill = `-- name: GetBill :one
-SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id FROM bills WHERE id = $1
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills WHERE id = $1
 `
 
 func (q *Queries) GetBill(ctx context.Context, id int32) (Bill, error) {
@@ -95,12 +245,22 @@ func (q *Queries) GetBill(ctx context.Context, id int32) (Bill, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
 	)
 	return i, err
 }
 
 const getBillByIdempotencyKey = `-- name: GetBillByIdempotencyKey :one
-SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id FROM bills WHERE idempotency_key = $1
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills WHERE idempotency_key = $1
 `
 
 func (q *Queries) GetBillByIdempotencyKey(ctx context.Context, idempotencyKey string) (Bill, error) {
@@ -120,12 +280,22 @@ func (q *Queries) GetBillByIdempotencyKey(ctx context.Context, idempotencyKey st
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
 	)
 	return i, err
 }
 
 const getBillForUpdate = `-- name: GetBillForUpdate :one
-SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id FROM bills WHERE id = $1 FOR UPDATE
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills WHERE id = $1 FOR UPDATE
 `
 
 func (q *Queries) GetBillForUpdate(ctx context.Context, id int32) (Bill, error) {
@@ -145,23 +315,98 @@ func (q *Queries) GetBillForUpdate(ctx context.Context, id int32) (Bill, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
 	)
 	return i, err
 }
 
-const listBills = `-- name: ListBills :many
-SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id FROM bills 
-ORDER BY created_at DESC 
-LIMIT $1 OFFSET $2
+const listBillIDsAfter = `-- name: ListBillIDsAfter :many
+SELECT id FROM bills WHERE id > $1 ORDER BY id LIMIT $2
+`
+
+type ListBillIDsAfterParams struct {
+	ID    int32
+	Limit int32
+}
+
+func (q *Queries) ListBillIDsAfter(ctx context.Context, arg ListBillIDsAfterParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listBillIDsAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBillsByCreatedAtAsc = `-- name: ListBillsByCreatedAtAsc :many
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills
+WHERE account_id = $1
+  AND ($2::text[] IS NULL OR status = ANY($2::text[]))
+  AND ($3::text IS NULL OR currency = $3)
+  AND ($4::timestamptz IS NULL OR start_time >= $4)
+  AND ($5::timestamptz IS NULL OR start_time < $5)
+  AND ($6::timestamptz IS NULL OR end_time >= $6)
+  AND ($7::timestamptz IS NULL OR end_time < $7)
+  AND ($8::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))
+  AND ($9::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))
+  AND ($10::timestamptz IS NULL
+       OR (created_at, id) > ($10, $11::int))
+ORDER BY created_at ASC, id ASC
+LIMIT $12 OFFSET $13
 `
 
-type ListBillsParams struct {
-	Limit  int32
-	Offset int32
+type ListBillsByCreatedAtAscParams struct {
+	AccountID     pgtype.Int4
+	Statuses      []string
+	Currency      pgtype.Text
+	StartFrom     pgtype.Timestamptz
+	StartTo       pgtype.Timestamptz
+	EndFrom       pgtype.Timestamptz
+	EndTo         pgtype.Timestamptz
+	MinTotalCents pgtype.Int8
+	MaxTotalCents pgtype.Int8
+	CursorTime    pgtype.Timestamptz
+	CursorID      pgtype.Int4
+	Limit         int32
+	Offset        int32
 }
 
-func (q *Queries) ListBills(ctx context.Context, arg ListBillsParams) ([]Bill, error) {
-	rows, err := q.db.Query(ctx, listBills, arg.Limit, arg.Offset)
+func (q *Queries) ListBillsByCreatedAtAsc(ctx context.Context, arg ListBillsByCreatedAtAscParams) ([]Bill, error) {
+	rows, err := q.db.Query(ctx, listBillsByCreatedAtAsc,
+		arg.AccountID,
+		arg.Statuses,
+		arg.Currency,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.MinTotalCents,
+		arg.MaxTotalCents,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -183,6 +428,16 @@ func (q *Queries) ListBills(ctx context.Context, arg ListBillsParams) ([]Bill, e
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.WorkflowID,
+			&i.AccountID,
+			&i.Timezone,
+			&i.Period,
+			&i.AllowNegativeTotal,
+			&i.AuditHeadHash,
+			&i.GracePeriodSeconds,
+			&i.AmountPaidCents,
+			&i.PaymentTermsDays,
+			&i.DueAt,
+			&i.OverdueAt,
 		); err != nil {
 			return nil, err
 		}
@@ -194,6 +449,667 @@ func (q *Queries) ListBills(ctx context.Context, arg ListBillsParams) ([]Bill, e
 	return items, nil
 }
 
+const listBillsByCreatedAtDesc = `-- name: ListBillsByCreatedAtDesc :many
+
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills
+WHERE account_id = $1
+  AND ($2::text[] IS NULL OR status = ANY($2::text[]))
+  AND ($3::text IS NULL OR currency = $3)
+  AND ($4::timestamptz IS NULL OR start_time >= $4)
+  AND ($5::timestamptz IS NULL OR start_time < $5)
+  AND ($6::timestamptz IS NULL OR end_time >= $6)
+  AND ($7::timestamptz IS NULL OR end_time < $7)
+  AND ($8::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))
+  AND ($9::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))
+  AND ($10::timestamptz IS NULL
+       OR (created_at, id) < ($10, $11::int))
+ORDER BY created_at DESC, id DESC
+LIMIT $12 OFFSET $13
+`
+
+type ListBillsByCreatedAtDescParams struct {
+	AccountID     pgtype.Int4
+	Statuses      []string
+	Currency      pgtype.Text
+	StartFrom     pgtype.Timestamptz
+	StartTo       pgtype.Timestamptz
+	EndFrom       pgtype.Timestamptz
+	EndTo         pgtype.Timestamptz
+	MinTotalCents pgtype.Int8
+	MaxTotalCents pgtype.Int8
+	CursorTime    pgtype.Timestamptz
+	CursorID      pgtype.Int4
+	Limit         int32
+	Offset        int32
+}
+
+// Filtered listings use keyset pagination on (sort key, id). Each sort key and direction
+// has its own query so that it can be served by an index.
+func (q *Queries) ListBillsByCreatedAtDesc(ctx context.Context, arg ListBillsByCreatedAtDescParams) ([]Bill, error) {
+	rows, err := q.db.Query(ctx, listBillsByCreatedAtDesc,
+		arg.AccountID,
+		arg.Statuses,
+		arg.Currency,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.MinTotalCents,
+		arg.MaxTotalCents,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bill
+	for rows.Next() {
+		var i Bill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Status,
+			&i.CloseReason,
+			&i.ErrorMessage,
+			&i.TotalAmountCents,
+			&i.StartTime,
+			&i.EndTime,
+			&i.BilledAt,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkflowID,
+			&i.AccountID,
+			&i.Timezone,
+			&i.Period,
+			&i.AllowNegativeTotal,
+			&i.AuditHeadHash,
+			&i.GracePeriodSeconds,
+			&i.AmountPaidCents,
+			&i.PaymentTermsDays,
+			&i.DueAt,
+			&i.OverdueAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBillsByStartTimeAsc = `-- name: ListBillsByStartTimeAsc :many
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills
+WHERE account_id = $1
+  AND ($2::text[] IS NULL OR status = ANY($2::text[]))
+  AND ($3::text IS NULL OR currency = $3)
+  AND ($4::timestamptz IS NULL OR start_time >= $4)
+  AND ($5::timestamptz IS NULL OR start_time < $5)
+  AND ($6::timestamptz IS NULL OR end_time >= $6)
+  AND ($7::timestamptz IS NULL OR end_time < $7)
+  AND ($8::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))
+  AND ($9::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))
+  AND ($10::timestamptz IS NULL
+       OR (start_time, id) > ($10, $11::int))
+ORDER BY start_time ASC, id ASC
+LIMIT $12 OFFSET $13
+`
+
+type ListBillsByStartTimeAscParams struct {
+	AccountID     pgtype.Int4
+	Statuses      []string
+	Currency      pgtype.Text
+	StartFrom     pgtype.Timestamptz
+	StartTo       pgtype.Timestamptz
+	EndFrom       pgtype.Timestamptz
+	EndTo         pgtype.Timestamptz
+	MinTotalCents pgtype.Int8
+	MaxTotalCents pgtype.Int8
+	CursorTime    pgtype.Timestamptz
+	CursorID      pgtype.Int4
+	Limit         int32
+	Offset        int32
+}
+
+func (q *Queries) ListBillsByStartTimeAsc(ctx context.Context, arg ListBillsByStartTimeAscParams) ([]Bill, error) {
+	rows, err := q.db.Query(ctx, listBillsByStartTimeAsc,
+		arg.AccountID,
+		arg.Statuses,
+		arg.Currency,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.MinTotalCents,
+		arg.MaxTotalCents,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bill
+	for rows.Next() {
+		var i Bill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Status,
+			&i.CloseReason,
+			&i.ErrorMessage,
+			&i.TotalAmountCents,
+			&i.StartTime,
+			&i.EndTime,
+			&i.BilledAt,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkflowID,
+			&i.AccountID,
+			&i.Timezone,
+			&i.Period,
+			&i.AllowNegativeTotal,
+			&i.AuditHeadHash,
+			&i.GracePeriodSeconds,
+			&i.AmountPaidCents,
+			&i.PaymentTermsDays,
+			&i.DueAt,
+			&i.OverdueAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBillsByStartTimeDesc = `-- name: ListBillsByStartTimeDesc :many
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills
+WHERE account_id = $1
+  AND ($2::text[] IS NULL OR status = ANY($2::text[]))
+  AND ($3::text IS NULL OR currency = $3)
+  AND ($4::timestamptz IS NULL OR start_time >= $4)
+  AND ($5::timestamptz IS NULL OR start_time < $5)
+  AND ($6::timestamptz IS NULL OR end_time >= $6)
+  AND ($7::timestamptz IS NULL OR end_time < $7)
+  AND ($8::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))
+  AND ($9::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))
+  AND ($10::timestamptz IS NULL
+       OR (start_time, id) < ($10, $11::int))
+ORDER BY start_time DESC, id DESC
+LIMIT $12 OFFSET $13
+`
+
+type ListBillsByStartTimeDescParams struct {
+	AccountID     pgtype.Int4
+	Statuses      []string
+	Currency      pgtype.Text
+	StartFrom     pgtype.Timestamptz
+	StartTo       pgtype.Timestamptz
+	EndFrom       pgtype.Timestamptz
+	EndTo         pgtype.Timestamptz
+	MinTotalCents pgtype.Int8
+	MaxTotalCents pgtype.Int8
+	CursorTime    pgtype.Timestamptz
+	CursorID      pgtype.Int4
+	Limit         int32
+	Offset        int32
+}
+
+func (q *Queries) ListBillsByStartTimeDesc(ctx context.Context, arg ListBillsByStartTimeDescParams) ([]Bill, error) {
+	rows, err := q.db.Query(ctx, listBillsByStartTimeDesc,
+		arg.AccountID,
+		arg.Statuses,
+		arg.Currency,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.MinTotalCents,
+		arg.MaxTotalCents,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bill
+	for rows.Next() {
+		var i Bill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Status,
+			&i.CloseReason,
+			&i.ErrorMessage,
+			&i.TotalAmountCents,
+			&i.StartTime,
+			&i.EndTime,
+			&i.BilledAt,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkflowID,
+			&i.AccountID,
+			&i.Timezone,
+			&i.Period,
+			&i.AllowNegativeTotal,
+			&i.AuditHeadHash,
+			&i.GracePeriodSeconds,
+			&i.AmountPaidCents,
+			&i.PaymentTermsDays,
+			&i.DueAt,
+			&i.OverdueAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBillsByTotalAsc = `-- name: ListBillsByTotalAsc :many
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills
+WHERE account_id = $1
+  AND status <> 'cancelled'
+  AND ($2::text[] IS NULL OR status = ANY($2::text[]))
+  AND ($3::text IS NULL OR currency = $3)
+  AND ($4::timestamptz IS NULL OR start_time >= $4)
+  AND ($5::timestamptz IS NULL OR start_time < $5)
+  AND ($6::timestamptz IS NULL OR end_time >= $6)
+  AND ($7::timestamptz IS NULL OR end_time < $7)
+  AND ($8::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))
+  AND ($9::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))
+  AND ($10::bigint IS NULL
+       OR (COALESCE(total_amount_cents, 0), id) > ($10, $11::int))
+ORDER BY COALESCE(total_amount_cents, 0) ASC, id ASC
+LIMIT $12 OFFSET $13
+`
+
+type ListBillsByTotalAscParams struct {
+	AccountID     pgtype.Int4
+	Statuses      []string
+	Currency      pgtype.Text
+	StartFrom     pgtype.Timestamptz
+	StartTo       pgtype.Timestamptz
+	EndFrom       pgtype.Timestamptz
+	EndTo         pgtype.Timestamptz
+	MinTotalCents pgtype.Int8
+	MaxTotalCents pgtype.Int8
+	CursorTotal   pgtype.Int8
+	CursorID      pgtype.Int4
+	Limit         int32
+	Offset        int32
+}
+
+func (q *Queries) ListBillsByTotalAsc(ctx context.Context, arg ListBillsByTotalAscParams) ([]Bill, error) {
+	rows, err := q.db.Query(ctx, listBillsByTotalAsc,
+		arg.AccountID,
+		arg.Statuses,
+		arg.Currency,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.MinTotalCents,
+		arg.MaxTotalCents,
+		arg.CursorTotal,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bill
+	for rows.Next() {
+		var i Bill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Status,
+			&i.CloseReason,
+			&i.ErrorMessage,
+			&i.TotalAmountCents,
+			&i.StartTime,
+			&i.EndTime,
+			&i.BilledAt,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkflowID,
+			&i.AccountID,
+			&i.Timezone,
+			&i.Period,
+			&i.AllowNegativeTotal,
+			&i.AuditHeadHash,
+			&i.GracePeriodSeconds,
+			&i.AmountPaidCents,
+			&i.PaymentTermsDays,
+			&i.DueAt,
+			&i.OverdueAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBillsByTotalDesc = `-- name: ListBillsByTotalDesc :many
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills
+WHERE account_id = $1
+  AND status <> 'cancelled'
+  AND ($2::text[] IS NULL OR status = ANY($2::text[]))
+  AND ($3::text IS NULL OR currency = $3)
+  AND ($4::timestamptz IS NULL OR start_time >= $4)
+  AND ($5::timestamptz IS NULL OR start_time < $5)
+  AND ($6::timestamptz IS NULL OR end_time >= $6)
+  AND ($7::timestamptz IS NULL OR end_time < $7)
+  AND ($8::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))
+  AND ($9::bigint IS NULL OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))
+  AND ($10::bigint IS NULL
+       OR (COALESCE(total_amount_cents, 0), id) < ($10, $11::int))
+ORDER BY COALESCE(total_amount_cents, 0) DESC, id DESC
+LIMIT $12 OFFSET $13
+`
+
+type ListBillsByTotalDescParams struct {
+	AccountID     pgtype.Int4
+	Statuses      []string
+	Currency      pgtype.Text
+	StartFrom     pgtype.Timestamptz
+	StartTo       pgtype.Timestamptz
+	EndFrom       pgtype.Timestamptz
+	EndTo         pgtype.Timestamptz
+	MinTotalCents pgtype.Int8
+	MaxTotalCents pgtype.Int8
+	CursorTotal   pgtype.Int8
+	CursorID      pgtype.Int4
+	Limit         int32
+	Offset        int32
+}
+
+func (q *Queries) ListBillsByTotalDesc(ctx context.Context, arg ListBillsByTotalDescParams) ([]Bill, error) {
+	rows, err := q.db.Query(ctx, listBillsByTotalDesc,
+		arg.AccountID,
+		arg.Statuses,
+		arg.Currency,
+		arg.StartFrom,
+		arg.StartTo,
+		arg.EndFrom,
+		arg.EndTo,
+		arg.MinTotalCents,
+		arg.MaxTotalCents,
+		arg.CursorTotal,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bill
+	for rows.Next() {
+		var i Bill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Status,
+			&i.CloseReason,
+			&i.ErrorMessage,
+			&i.TotalAmountCents,
+			&i.StartTime,
+			&i.EndTime,
+			&i.BilledAt,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkflowID,
+			&i.AccountID,
+			&i.Timezone,
+			&i.Period,
+			&i.AllowNegativeTotal,
+			&i.AuditHeadHash,
+			&i.GracePeriodSeconds,
+			&i.AmountPaidCents,
+			&i.PaymentTermsDays,
+			&i.DueAt,
+			&i.OverdueAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOverdueBills = `-- name: ListOverdueBills :many
+SELECT id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at FROM bills
+WHERE status = 'closed'
+  AND overdue_at IS NULL
+  AND due_at < $1
+  AND amount_paid_cents < COALESCE(total_amount_cents, 0)
+ORDER BY due_at, id
+LIMIT $2
+`
+
+type ListOverdueBillsParams struct {
+	DueAt pgtype.Timestamptz
+	Limit int32
+}
+
+func (q *Queries) ListOverdueBills(ctx context.Context, arg ListOverdueBillsParams) ([]Bill, error) {
+	rows, err := q.db.Query(ctx, listOverdueBills, arg.DueAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bill
+	for rows.Next() {
+		var i Bill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Status,
+			&i.CloseReason,
+			&i.ErrorMessage,
+			&i.TotalAmountCents,
+			&i.StartTime,
+			&i.EndTime,
+			&i.BilledAt,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkflowID,
+			&i.AccountID,
+			&i.Timezone,
+			&i.Period,
+			&i.AllowNegativeTotal,
+			&i.AuditHeadHash,
+			&i.GracePeriodSeconds,
+			&i.AmountPaidCents,
+			&i.PaymentTermsDays,
+			&i.DueAt,
+			&i.OverdueAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markBillOverdue = `-- name: MarkBillOverdue :one
+UPDATE bills
+SET overdue_at = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
+`
+
+type MarkBillOverdueParams struct {
+	ID        int32
+	OverdueAt pgtype.Timestamptz
+}
+
+func (q *Queries) MarkBillOverdue(ctx context.Context, arg MarkBillOverdueParams) (Bill, error) {
+	row := q.db.QueryRow(ctx, markBillOverdue, arg.ID, arg.OverdueAt)
+	var i Bill
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Status,
+		&i.CloseReason,
+		&i.ErrorMessage,
+		&i.TotalAmountCents,
+		&i.StartTime,
+		&i.EndTime,
+		&i.BilledAt,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
+	)
+	return i, err
+}
+
+const overrideBillTotal = `-- name: OverrideBillTotal :one
+UPDATE bills
+SET total_amount_cents = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
+`
+
+type OverrideBillTotalParams struct {
+	ID               int32
+	TotalAmountCents pgtype.Int8
+}
+
+func (q *Queries) OverrideBillTotal(ctx context.Context, arg OverrideBillTotalParams) (Bill, error) {
+	row := q.db.QueryRow(ctx, overrideBillTotal, arg.ID, arg.TotalAmountCents)
+	var i Bill
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Status,
+		&i.CloseReason,
+		&i.ErrorMessage,
+		&i.TotalAmountCents,
+		&i.StartTime,
+		&i.EndTime,
+		&i.BilledAt,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
+	)
+	return i, err
+}
+
+const setBillDueAt = `-- name: SetBillDueAt :one
+UPDATE bills
+SET billed_at = NOW(),
+    due_at = NOW() + make_interval(days => payment_terms_days),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
+`
+
+func (q *Queries) SetBillDueAt(ctx context.Context, id int32) (Bill, error) {
+	row := q.db.QueryRow(ctx, setBillDueAt, id)
+	var i Bill
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Status,
+		&i.CloseReason,
+		&i.ErrorMessage,
+		&i.TotalAmountCents,
+		&i.StartTime,
+		&i.EndTime,
+		&i.BilledAt,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
+	)
+	return i, err
+}
+
+const updateBillAuditHead = `-- name: UpdateBillAuditHead :exec
+UPDATE bills SET audit_head_hash = $2 WHERE id = $1
+`
+
+type UpdateBillAuditHeadParams struct {
+	ID            int32
+	AuditHeadHash pgtype.Text
+}
+
+func (q *Queries) UpdateBillAuditHead(ctx context.Context, arg UpdateBillAuditHeadParams) error {
+	_, err := q.db.Exec(ctx, updateBillAuditHead, arg.ID, arg.AuditHeadHash)
+	return err
+}
+
 const updateBillClosure = `-- name: UpdateBillClosure :one
 UPDATE bills 
 SET status = $2, 
@@ -201,7 +1117,7 @@ SET status = $2,
     error_message = $4,
     updated_at = NOW()
 WHERE id = $1 
-RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
 `
 
 type UpdateBillClosureParams struct {
@@ -233,6 +1149,62 @@ func (q *Queries) UpdateBillClosure(ctx context.Context, arg UpdateBillClosurePa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
+	)
+	return i, err
+}
+
+const updateBillEndTime = `-- name: UpdateBillEndTime :one
+UPDATE bills
+SET end_time = $2,
+    period = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
+`
+
+type UpdateBillEndTimeParams struct {
+	ID      int32
+	EndTime pgtype.Timestamptz
+}
+
+// An explicit end time replaces the calendar period the bill was created for
+func (q *Queries) UpdateBillEndTime(ctx context.Context, arg UpdateBillEndTimeParams) (Bill, error) {
+	row := q.db.QueryRow(ctx, updateBillEndTime, arg.ID, arg.EndTime)
+	var i Bill
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Status,
+		&i.CloseReason,
+		&i.ErrorMessage,
+		&i.TotalAmountCents,
+		&i.StartTime,
+		&i.EndTime,
+		&i.BilledAt,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
 	)
 	return i, err
 }
@@ -241,7 +1213,7 @@ const updateBillStatus = `-- name: UpdateBillStatus :one
 UPDATE bills 
 SET status = $2, updated_at = NOW()
 WHERE id = $1 
-RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
 `
 
 type UpdateBillStatusParams struct {
@@ -266,6 +1238,16 @@ func (q *Queries) UpdateBillStatus(ctx context.Context, arg UpdateBillStatusPara
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
 	)
 	return i, err
 }
@@ -275,10 +1257,10 @@ UPDATE bills
 SET total_amount_cents = (
     SELECT COALESCE(SUM(amount_cents), 0) 
     FROM line_items 
-    WHERE bill_id = $1
+    WHERE bill_id = $1 AND voided_at IS NULL
 ), updated_at = NOW()
 WHERE id = $1
-RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id
+RETURNING id, currency, status, close_reason, error_message, total_amount_cents, start_time, end_time, billed_at, idempotency_key, created_at, updated_at, workflow_id, account_id, timezone, period, allow_negative_total, audit_head_hash, grace_period_seconds, amount_paid_cents, payment_terms_days, due_at, overdue_at
 `
 
 func (q *Queries) UpdateBillTotal(ctx context.Context, billID pgtype.Int4) (Bill, error) {
@@ -298,6 +1280,16 @@ func (q *Queries) UpdateBillTotal(ctx context.Context, billID pgtype.Int4) (Bill
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkflowID,
+		&i.AccountID,
+		&i.Timezone,
+		&i.Period,
+		&i.AllowNegativeTotal,
+		&i.AuditHeadHash,
+		&i.GracePeriodSeconds,
+		&i.AmountPaidCents,
+		&i.PaymentTermsDays,
+		&i.DueAt,
+		&i.OverdueAt,
 	)
 	return i, err
 }
@@ -11,14 +11,31 @@ import (
 )
 
 type Querier interface {
-	CountBills(ctx context.Context) (int64, error)
+	AddBillPayment(ctx context.Context, arg AddBillPaymentParams) (Bill, error)
+	AddToBillTotal(ctx context.Context, arg AddToBillTotalParams) (Bill, error)
+	CountFilteredBills(ctx context.Context, arg CountFilteredBillsParams) (int64, error)
 	// Bills related queries
 	CreateBill(ctx context.Context, arg CreateBillParams) (Bill, error)
 	GetBill(ctx context.Context, id int32) (Bill, error)
 	GetBillByIdempotencyKey(ctx context.Context, idempotencyKey string) (Bill, error)
 	GetBillForUpdate(ctx context.Context, id int32) (Bill, error)
-	ListBills(ctx context.Context, arg ListBillsParams) ([]Bill, error)
+	ListBillIDsAfter(ctx context.Context, arg ListBillIDsAfterParams) ([]int32, error)
+	ListBillsByCreatedAtAsc(ctx context.Context, arg ListBillsByCreatedAtAscParams) ([]Bill, error)
+	// Filtered listings use keyset pagination on (sort key, id). Each sort key and direction
+	// has its own query so that it can be served by an index.
+	ListBillsByCreatedAtDesc(ctx context.Context, arg ListBillsByCreatedAtDescParams) ([]Bill, error)
+	ListBillsByStartTimeAsc(ctx context.Context, arg ListBillsByStartTimeAscParams) ([]Bill, error)
+	ListBillsByStartTimeDesc(ctx context.Context, arg ListBillsByStartTimeDescParams) ([]Bill, error)
+	ListBillsByTotalAsc(ctx context.Context, arg ListBillsByTotalAscParams) ([]Bill, error)
+	ListBillsByTotalDesc(ctx context.Context, arg ListBillsByTotalDescParams) ([]Bill, error)
+	ListOverdueBills(ctx context.Context, arg ListOverdueBillsParams) ([]Bill, error)
+	MarkBillOverdue(ctx context.Context, arg MarkBillOverdueParams) (Bill, error)
+	OverrideBillTotal(ctx context.Context, arg OverrideBillTotalParams) (Bill, error)
+	SetBillDueAt(ctx context.Context, id int32) (Bill, error)
+	UpdateBillAuditHead(ctx context.Context, arg UpdateBillAuditHeadParams) error
 	UpdateBillClosure(ctx context.Context, arg UpdateBillClosureParams) (Bill, error)
+	// An explicit end time replaces the calendar period the bill was created for
+	UpdateBillEndTime(ctx context.Context, arg UpdateBillEndTimeParams) (Bill, error)
 	UpdateBillStatus(ctx context.Context, arg UpdateBillStatusParams) (Bill, error)
 	UpdateBillTotal(ctx context.Context, billID pgtype.Int4) (Bill, error)
 }
@@ -0,0 +1,42 @@
+package bills
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A cancelled bill owes nothing, so every query that filters or orders bills by their total must
+// leave cancelled bills out
+func TestTotalQueriesLeaveOutCancelledBills(t *testing.T) {
+	totalFilters := []string{
+		"OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) >= $8))",
+		"OR (status <> 'cancelled' AND COALESCE(total_amount_cents, 0) <= $9))",
+	}
+
+	testCases := []struct {
+		name  string
+		query string
+		extra string
+	}{
+		{name: "list_by_created_at_desc", query: listBillsByCreatedAtDesc},
+		{name: "list_by_created_at_asc", query: listBillsByCreatedAtAsc},
+		{name: "list_by_start_time_desc", query: listBillsByStartTimeDesc},
+		{name: "list_by_start_time_asc", query: listBillsByStartTimeAsc},
+		{name: "list_by_total_desc", query: listBillsByTotalDesc, extra: "WHERE account_id = $1\n  AND status <> 'cancelled'\n"},
+		{name: "list_by_total_asc", query: listBillsByTotalAsc, extra: "WHERE account_id = $1\n  AND status <> 'cancelled'\n"},
+		{name: "count_filtered", query: countFilteredBills, extra: "AND (NOT $10::bool OR status <> 'cancelled')"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, filter := range totalFilters {
+				assert.True(t, strings.Contains(tc.query, filter), "missing %q", filter)
+			}
+			if tc.extra != "" {
+				assert.True(t, strings.Contains(tc.query, tc.extra), "missing %q", tc.extra)
+			}
+		})
+	}
+}
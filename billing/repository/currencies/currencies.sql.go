@@ -11,9 +11,167 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countOpenBillsByCurrency = `-- name: CountOpenBillsByCurrency :one
+SELECT COUNT(*) FROM bills
+WHERE currency = $1 AND status NOT IN ('closed', 'cancelled')
+`
+
+func (q *Queries) CountOpenBillsByCurrency(ctx context.Context, currency string) (int64, error) {
+	row := q.db.QueryRow(ctx, countOpenBillsByCurrency, currency)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createCurrency = `-- name: CreateCurrency :one
+WITH new_currency AS (
+    INSERT INTO currencies (code, symbol, rate, minor_units, enabled)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING id, code, symbol, rate, enabled, minor_units
+), initial_rate AS (
+    -- The first rate covers all time, so backdated amounts in the currency can be converted
+    INSERT INTO currency_rates (currency_code, rate, effective_from)
+    SELECT code, rate, 'epoch'::timestamptz FROM new_currency
+)
+SELECT id, code, symbol, rate, enabled, minor_units FROM new_currency
+`
+
+type CreateCurrencyParams struct {
+	Code       pgtype.Text
+	Symbol     pgtype.Text
+	Rate       pgtype.Numeric
+	MinorUnits int32
+	Enabled    bool
+}
+
+func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRow(ctx, createCurrency,
+		arg.Code,
+		arg.Symbol,
+		arg.Rate,
+		arg.MinorUnits,
+		arg.Enabled,
+	)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Symbol,
+		&i.Rate,
+		&i.Enabled,
+		&i.MinorUnits,
+	)
+	return i, err
+}
+
+const createCurrencyAuditEntry = `-- name: CreateCurrencyAuditEntry :one
+INSERT INTO currency_audit_entries (
+    currency_code,
+    seq,
+    action,
+    actor,
+    payload,
+    prev_hash,
+    hash,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, currency_code, seq, action, actor, payload, prev_hash, hash, created_at
+`
+
+type CreateCurrencyAuditEntryParams struct {
+	CurrencyCode string
+	Seq          int32
+	Action       string
+	Actor        string
+	Payload      string
+	PrevHash     string
+	Hash         string
+	CreatedAt    pgtype.Timestamptz
+}
+
+func (q *Queries) CreateCurrencyAuditEntry(ctx context.Context, arg CreateCurrencyAuditEntryParams) (CurrencyAuditEntry, error) {
+	row := q.db.QueryRow(ctx, createCurrencyAuditEntry,
+		arg.CurrencyCode,
+		arg.Seq,
+		arg.Action,
+		arg.Actor,
+		arg.Payload,
+		arg.PrevHash,
+		arg.Hash,
+		arg.CreatedAt,
+	)
+	var i CurrencyAuditEntry
+	err := row.Scan(
+		&i.ID,
+		&i.CurrencyCode,
+		&i.Seq,
+		&i.Action,
+		&i.Actor,
+		&i.Payload,
+		&i.PrevHash,
+		&i.Hash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createCurrencyRate = `-- name: CreateCurrencyRate :one
+WITH new_rate AS (
+    INSERT INTO currency_rates (currency_code, rate, effective_from)
+    VALUES ($1, $2, $3)
+    RETURNING id, currency_code, rate, effective_from, created_at
+), current_rate AS (
+    UPDATE currencies SET rate = $2
+    WHERE code = $1
+      AND $3 <= NOW()
+      AND NOT EXISTS (
+          SELECT 1 FROM currency_rates
+          WHERE currency_code = $1 AND effective_from > $3 AND effective_from <= NOW()
+      )
+)
+SELECT id, currency_code, rate, effective_from, created_at FROM new_rate
+`
+
+type CreateCurrencyRateParams struct {
+	CurrencyCode  string
+	Rate          pgtype.Numeric
+	EffectiveFrom pgtype.Timestamptz
+}
+
+func (q *Queries) CreateCurrencyRate(ctx context.Context, arg CreateCurrencyRateParams) (CurrencyRate, error) {
+	row := q.db.QueryRow(ctx, createCurrencyRate, arg.CurrencyCode, arg.Rate, arg.EffectiveFrom)
+	var i CurrencyRate
+	err := row.Scan(
+		&i.ID,
+		&i.CurrencyCode,
+		&i.Rate,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const currencyInUse = `-- name: CurrencyInUse :one
+SELECT (
+    EXISTS (SELECT 1 FROM bills WHERE currency = $1)
+    OR EXISTS (SELECT 1 FROM line_items WHERE currency = $1 OR metadata->>'original_currency' = $1)
+    OR EXISTS (SELECT 1 FROM payments WHERE currency = $1 OR metadata->>'original_currency' = $1)
+    OR EXISTS (SELECT 1 FROM subscriptions WHERE currency = $1)
+)::bool AS in_use
+`
+
+// Amounts are stored in minor units, so any row holding one pins the currency's minor units
+func (q *Queries) CurrencyInUse(ctx context.Context, currency string) (bool, error) {
+	row := q.db.QueryRow(ctx, currencyInUse, currency)
+	var in_use bool
+	err := row.Scan(&in_use)
+	return in_use, err
+}
+
 const getCurrency = `-- name: GetCurrency :one
 
-SELECT id, code, symbol, rate, enabled FROM currencies WHERE code = $1 AND enabled = true
+SELECT id, code, symbol, rate, enabled, minor_units FROM currencies WHERE code = $1 AND enabled = true
 `
 
 // Currencies related queries
@@ -26,6 +184,217 @@ func (q *Queries) GetCurrency(ctx context.Context, code pgtype.Text) (Currency,
 		&i.Symbol,
 		&i.Rate,
 		&i.Enabled,
+		&i.MinorUnits,
+	)
+	return i, err
+}
+
+const getCurrencyByCode = `-- name: GetCurrencyByCode :one
+SELECT id, code, symbol, rate, enabled, minor_units FROM currencies WHERE code = $1
+`
+
+func (q *Queries) GetCurrencyByCode(ctx context.Context, code pgtype.Text) (Currency, error) {
+	row := q.db.QueryRow(ctx, getCurrencyByCode, code)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Symbol,
+		&i.Rate,
+		&i.Enabled,
+		&i.MinorUnits,
+	)
+	return i, err
+}
+
+const getCurrencyByCodeForShare = `-- name: GetCurrencyByCodeForShare :one
+SELECT id, code, symbol, rate, enabled, minor_units FROM currencies WHERE code = $1 FOR SHARE
+`
+
+// Held while billing in the currency, so it cannot be disabled or rescaled underneath
+func (q *Queries) GetCurrencyByCodeForShare(ctx context.Context, code pgtype.Text) (Currency, error) {
+	row := q.db.QueryRow(ctx, getCurrencyByCodeForShare, code)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Symbol,
+		&i.Rate,
+		&i.Enabled,
+		&i.MinorUnits,
+	)
+	return i, err
+}
+
+const getCurrencyByCodeForUpdate = `-- name: GetCurrencyByCodeForUpdate :one
+SELECT id, code, symbol, rate, enabled, minor_units FROM currencies WHERE code = $1 FOR UPDATE
+`
+
+func (q *Queries) GetCurrencyByCodeForUpdate(ctx context.Context, code pgtype.Text) (Currency, error) {
+	row := q.db.QueryRow(ctx, getCurrencyByCodeForUpdate, code)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Symbol,
+		&i.Rate,
+		&i.Enabled,
+		&i.MinorUnits,
+	)
+	return i, err
+}
+
+const getCurrencyRateAt = `-- name: GetCurrencyRateAt :one
+SELECT id, currency_code, rate, effective_from, created_at FROM currency_rates
+WHERE currency_code = $1 AND effective_from <= $2
+ORDER BY effective_from DESC
+LIMIT 1
+`
+
+type GetCurrencyRateAtParams struct {
+	CurrencyCode  string
+	EffectiveFrom pgtype.Timestamptz
+}
+
+func (q *Queries) GetCurrencyRateAt(ctx context.Context, arg GetCurrencyRateAtParams) (CurrencyRate, error) {
+	row := q.db.QueryRow(ctx, getCurrencyRateAt, arg.CurrencyCode, arg.EffectiveFrom)
+	var i CurrencyRate
+	err := row.Scan(
+		&i.ID,
+		&i.CurrencyCode,
+		&i.Rate,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLastCurrencyAuditEntry = `-- name: GetLastCurrencyAuditEntry :one
+SELECT id, currency_code, seq, action, actor, payload, prev_hash, hash, created_at FROM currency_audit_entries WHERE currency_code = $1 ORDER BY seq DESC LIMIT 1
+`
+
+func (q *Queries) GetLastCurrencyAuditEntry(ctx context.Context, currencyCode string) (CurrencyAuditEntry, error) {
+	row := q.db.QueryRow(ctx, getLastCurrencyAuditEntry, currencyCode)
+	var i CurrencyAuditEntry
+	err := row.Scan(
+		&i.ID,
+		&i.CurrencyCode,
+		&i.Seq,
+		&i.Action,
+		&i.Actor,
+		&i.Payload,
+		&i.PrevHash,
+		&i.Hash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestCurrencyRate = `-- name: GetLatestCurrencyRate :one
+SELECT id, currency_code, rate, effective_from, created_at FROM currency_rates
+WHERE currency_code = $1
+ORDER BY effective_from DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestCurrencyRate(ctx context.Context, currencyCode string) (CurrencyRate, error) {
+	row := q.db.QueryRow(ctx, getLatestCurrencyRate, currencyCode)
+	var i CurrencyRate
+	err := row.Scan(
+		&i.ID,
+		&i.CurrencyCode,
+		&i.Rate,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listCurrencies = `-- name: ListCurrencies :many
+SELECT id, code, symbol, rate, enabled, minor_units FROM currencies ORDER BY code
+`
+
+func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := q.db.Query(ctx, listCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Currency
+	for rows.Next() {
+		var i Currency
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Symbol,
+			&i.Rate,
+			&i.Enabled,
+			&i.MinorUnits,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setCurrencyAuditHead = `-- name: SetCurrencyAuditHead :exec
+INSERT INTO currency_audit_heads (currency_code, head_hash) VALUES ($1, $2)
+ON CONFLICT (currency_code) DO UPDATE SET head_hash = EXCLUDED.head_hash
+`
+
+type SetCurrencyAuditHeadParams struct {
+	CurrencyCode string
+	HeadHash     string
+}
+
+func (q *Queries) SetCurrencyAuditHead(ctx context.Context, arg SetCurrencyAuditHeadParams) error {
+	_, err := q.db.Exec(ctx, setCurrencyAuditHead, arg.CurrencyCode, arg.HeadHash)
+	return err
+}
+
+const updateCurrency = `-- name: UpdateCurrency :one
+WITH new_rate AS (
+    INSERT INTO currency_rates (currency_code, rate, effective_from)
+    SELECT $1, $2, NOW()
+    WHERE $2 IS NOT NULL
+)
+UPDATE currencies
+SET symbol = COALESCE($3, symbol),
+    rate = COALESCE($2, rate),
+    minor_units = COALESCE($4, minor_units),
+    enabled = COALESCE($5, enabled)
+WHERE code = $1
+RETURNING id, code, symbol, rate, enabled, minor_units
+`
+
+type UpdateCurrencyParams struct {
+	Code       string
+	Rate       pgtype.Numeric
+	Symbol     pgtype.Text
+	MinorUnits pgtype.Int4
+	Enabled    pgtype.Bool
+}
+
+func (q *Queries) UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRow(ctx, updateCurrency,
+		arg.Code,
+		arg.Rate,
+		arg.Symbol,
+		arg.MinorUnits,
+		arg.Enabled,
+	)
+	var i Currency
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Symbol,
+		&i.Rate,
+		&i.Enabled,
+		&i.MinorUnits,
 	)
 	return i, err
 }
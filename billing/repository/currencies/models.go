@@ -8,40 +8,151 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Bill struct {
+type Account struct {
 	ID               int32
-	Currency         string
-	Status           string
-	CloseReason      pgtype.Text
-	ErrorMessage     pgtype.Text
-	TotalAmountCents pgtype.Int8
-	StartTime        pgtype.Timestamptz
-	EndTime          pgtype.Timestamptz
-	BilledAt         pgtype.Timestamptz
-	IdempotencyKey   string
+	Name             string
+	Email            pgtype.Text
+	Enabled          bool
 	CreatedAt        pgtype.Timestamptz
 	UpdatedAt        pgtype.Timestamptz
-	WorkflowID       pgtype.Text
+	PaymentTermsDays int32
+}
+
+type ApiKey struct {
+	ID        int32
+	AccountID int32
+	Name      string
+	KeyPrefix string
+	KeyHash   string
+	CreatedAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+}
+
+type AuditEntry struct {
+	ID        int64
+	BillID    int32
+	Seq       int32
+	Action    string
+	Actor     string
+	Payload   string
+	PrevHash  string
+	Hash      string
+	CreatedAt pgtype.Timestamptz
+}
+
+type Bill struct {
+	ID                 int32
+	Currency           string
+	Status             string
+	CloseReason        pgtype.Text
+	ErrorMessage       pgtype.Text
+	TotalAmountCents   pgtype.Int8
+	StartTime          pgtype.Timestamptz
+	EndTime            pgtype.Timestamptz
+	BilledAt           pgtype.Timestamptz
+	IdempotencyKey     string
+	CreatedAt          pgtype.Timestamptz
+	UpdatedAt          pgtype.Timestamptz
+	WorkflowID         pgtype.Text
+	AccountID          pgtype.Int4
+	Timezone           string
+	Period             pgtype.Text
+	AllowNegativeTotal bool
+	AuditHeadHash      pgtype.Text
+	GracePeriodSeconds int32
+	AmountPaidCents    int64
+	PaymentTermsDays   int32
+	DueAt              pgtype.Timestamptz
+	OverdueAt          pgtype.Timestamptz
+}
+
+type BillEvent struct {
+	ID         int64
+	BillID     int32
+	FromStatus pgtype.Text
+	ToStatus   string
+	Reason     pgtype.Text
+	Actor      string
+	CreatedAt  pgtype.Timestamptz
 }
 
 type Currency struct {
-	ID      int32
-	Code    pgtype.Text
-	Symbol  pgtype.Text
-	Rate    pgtype.Numeric
-	Enabled bool
+	ID         int32
+	Code       pgtype.Text
+	Symbol     pgtype.Text
+	Rate       pgtype.Numeric
+	Enabled    bool
+	MinorUnits int32
+}
+
+type CurrencyAuditEntry struct {
+	ID           int64
+	CurrencyCode string
+	Seq          int32
+	Action       string
+	Actor        string
+	Payload      string
+	PrevHash     string
+	Hash         string
+	CreatedAt    pgtype.Timestamptz
+}
+
+type CurrencyAuditHead struct {
+	CurrencyCode string
+	HeadHash     string
+}
+
+type CurrencyRate struct {
+	ID            int32
+	CurrencyCode  string
+	Rate          pgtype.Numeric
+	EffectiveFrom pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
 }
 
 type LineItem struct {
+	ID                 int32
+	BillID             pgtype.Int4
+	AmountCents        int64
+	Currency           string
+	Description        pgtype.Text
+	IncurredAt         pgtype.Timestamptz
+	ReferenceID        pgtype.Text
+	IdempotencyKey     string
+	CreatedAt          pgtype.Timestamptz
+	UpdatedAt          pgtype.Timestamptz
+	Metadata           []byte
+	VoidedAt           pgtype.Timestamptz
+	VoidReason         pgtype.Text
+	Kind               string
+	Quantity           pgtype.Numeric
+	UnitAmountCents    int64
+	ReplacesLineItemID pgtype.Int4
+}
+
+type Payment struct {
 	ID             int32
-	BillID         pgtype.Int4
+	BillID         int32
 	AmountCents    int64
 	Currency       string
-	Description    pgtype.Text
-	IncurredAt     pgtype.Timestamptz
-	ReferenceID    pgtype.Text
+	Metadata       []byte
+	Reference      pgtype.Text
+	PaidAt         pgtype.Timestamptz
 	IdempotencyKey string
 	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	Metadata       []byte
+}
+
+type Subscription struct {
+	ID              int32
+	AccountID       int32
+	Currency        string
+	BillingInterval string
+	IntervalSeconds pgtype.Int8
+	AnchorAt        pgtype.Timestamptz
+	Status          string
+	WorkflowID      pgtype.Text
+	CurrentBillID   pgtype.Int4
+	IdempotencyKey  string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
 }
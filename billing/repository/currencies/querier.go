@@ -11,8 +11,24 @@ import (
 )
 
 type Querier interface {
+	CountOpenBillsByCurrency(ctx context.Context, currency string) (int64, error)
+	CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error)
+	CreateCurrencyAuditEntry(ctx context.Context, arg CreateCurrencyAuditEntryParams) (CurrencyAuditEntry, error)
+	CreateCurrencyRate(ctx context.Context, arg CreateCurrencyRateParams) (CurrencyRate, error)
+	// Amounts are stored in minor units, so any row holding one pins the currency's minor units
+	CurrencyInUse(ctx context.Context, currency string) (bool, error)
 	// Currencies related queries
 	GetCurrency(ctx context.Context, code pgtype.Text) (Currency, error)
+	GetCurrencyByCode(ctx context.Context, code pgtype.Text) (Currency, error)
+	// Held while billing in the currency, so it cannot be disabled or rescaled underneath
+	GetCurrencyByCodeForShare(ctx context.Context, code pgtype.Text) (Currency, error)
+	GetCurrencyByCodeForUpdate(ctx context.Context, code pgtype.Text) (Currency, error)
+	GetCurrencyRateAt(ctx context.Context, arg GetCurrencyRateAtParams) (CurrencyRate, error)
+	GetLastCurrencyAuditEntry(ctx context.Context, currencyCode string) (CurrencyAuditEntry, error)
+	GetLatestCurrencyRate(ctx context.Context, currencyCode string) (CurrencyRate, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	SetCurrencyAuditHead(ctx context.Context, arg SetCurrencyAuditHeadParams) error
+	UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error)
 }
 
 var _ Querier = (*Queries)(nil)
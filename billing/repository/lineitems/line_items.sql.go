@@ -21,21 +21,29 @@ INSERT INTO line_items (
     incurred_at,
     reference_id,
     metadata,
-    idempotency_key
+    idempotency_key,
+    kind,
+    quantity,
+    unit_amount_cents,
+    replaces_line_item_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
-) RETURNING id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata, voided_at, void_reason, kind, quantity, unit_amount_cents, replaces_line_item_id
 `
 
 type CreateLineItemParams struct {
-	BillID         pgtype.Int4
-	AmountCents    int64
-	Currency       string
-	Description    pgtype.Text
-	IncurredAt     pgtype.Timestamptz
-	ReferenceID    pgtype.Text
-	Metadata       []byte
-	IdempotencyKey string
+	BillID             pgtype.Int4
+	AmountCents        int64
+	Currency           string
+	Description        pgtype.Text
+	IncurredAt         pgtype.Timestamptz
+	ReferenceID        pgtype.Text
+	Metadata           []byte
+	IdempotencyKey     string
+	Kind               string
+	Quantity           pgtype.Numeric
+	UnitAmountCents    int64
+	ReplacesLineItemID pgtype.Int4
 }
 
 // Line items related queries
@@ -49,6 +57,10 @@ func (q *Queries) CreateLineItem(ctx context.Context, arg CreateLineItemParams)
 		arg.ReferenceID,
 		arg.Metadata,
 		arg.IdempotencyKey,
+		arg.Kind,
+		arg.Quantity,
+		arg.UnitAmountCents,
+		arg.ReplacesLineItemID,
 	)
 	var i LineItem
 	err := row.Scan(
@@ -63,21 +75,32 @@ func (q *Queries) CreateLineItem(ctx context.Context, arg CreateLineItemParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Metadata,
+		&i.VoidedAt,
+		&i.VoidReason,
+		&i.Kind,
+		&i.Quantity,
+		&i.UnitAmountCents,
+		&i.ReplacesLineItemID,
 	)
 	return i, err
 }
 
-const deleteLineItem = `-- name: DeleteLineItem :exec
-DELETE FROM line_items WHERE id = $1
-`
-
-func (q *Queries) DeleteLineItem(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteLineItem, id)
-	return err
+type CreateLineItemsParams struct {
+	BillID          pgtype.Int4
+	AmountCents     int64
+	Currency        string
+	Description     pgtype.Text
+	IncurredAt      pgtype.Timestamptz
+	ReferenceID     pgtype.Text
+	Metadata        []byte
+	IdempotencyKey  string
+	Kind            string
+	Quantity        pgtype.Numeric
+	UnitAmountCents int64
 }
 
 const getLineItem = `-- name: GetLineItem :one
-SELECT id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata FROM line_items WHERE id = $1
+SELECT id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata, voided_at, void_reason, kind, quantity, unit_amount_cents, replaces_line_item_id FROM line_items WHERE id = $1
 `
 
 func (q *Queries) GetLineItem(ctx context.Context, id int32) (LineItem, error) {
@@ -95,12 +118,18 @@ func (q *Queries) GetLineItem(ctx context.Context, id int32) (LineItem, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Metadata,
+		&i.VoidedAt,
+		&i.VoidReason,
+		&i.Kind,
+		&i.Quantity,
+		&i.UnitAmountCents,
+		&i.ReplacesLineItemID,
 	)
 	return i, err
 }
 
 const getLineItemsByBill = `-- name: GetLineItemsByBill :many
-SELECT id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata FROM line_items WHERE bill_id = $1 ORDER BY incurred_at DESC
+SELECT id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata, voided_at, void_reason, kind, quantity, unit_amount_cents, replaces_line_item_id FROM line_items WHERE bill_id = $1 ORDER BY incurred_at DESC
 `
 
 func (q *Queries) GetLineItemsByBill(ctx context.Context, billID pgtype.Int4) ([]LineItem, error) {
@@ -124,6 +153,54 @@ func (q *Queries) GetLineItemsByBill(ctx context.Context, billID pgtype.Int4) ([
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Metadata,
+			&i.VoidedAt,
+			&i.VoidReason,
+			&i.Kind,
+			&i.Quantity,
+			&i.UnitAmountCents,
+			&i.ReplacesLineItemID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLineItemsByIdempotencyKeys = `-- name: GetLineItemsByIdempotencyKeys :many
+SELECT id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata, voided_at, void_reason, kind, quantity, unit_amount_cents, replaces_line_item_id FROM line_items WHERE idempotency_key = ANY($1::text[])
+`
+
+func (q *Queries) GetLineItemsByIdempotencyKeys(ctx context.Context, idempotencyKeys []string) ([]LineItem, error) {
+	rows, err := q.db.Query(ctx, getLineItemsByIdempotencyKeys, idempotencyKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LineItem
+	for rows.Next() {
+		var i LineItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.BillID,
+			&i.AmountCents,
+			&i.Currency,
+			&i.Description,
+			&i.IncurredAt,
+			&i.ReferenceID,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Metadata,
+			&i.VoidedAt,
+			&i.VoidReason,
+			&i.Kind,
+			&i.Quantity,
+			&i.UnitAmountCents,
+			&i.ReplacesLineItemID,
 		); err != nil {
 			return nil, err
 		}
@@ -136,33 +213,106 @@ func (q *Queries) GetLineItemsByBill(ctx context.Context, billID pgtype.Int4) ([
 }
 
 const getTotalAmountByBill = `-- name: GetTotalAmountByBill :one
-SELECT COALESCE(SUM(amount_cents), 0) as total_amount_cents 
+SELECT COALESCE(SUM(amount_cents), 0)::bigint as total_amount_cents 
 FROM line_items 
-WHERE bill_id = $1
+WHERE bill_id = $1 AND voided_at IS NULL
 `
 
-func (q *Queries) GetTotalAmountByBill(ctx context.Context, billID pgtype.Int4) (interface{}, error) {
+func (q *Queries) GetTotalAmountByBill(ctx context.Context, billID pgtype.Int4) (int64, error) {
 	row := q.db.QueryRow(ctx, getTotalAmountByBill, billID)
-	var total_amount_cents interface{}
+	var total_amount_cents int64
 	err := row.Scan(&total_amount_cents)
 	return total_amount_cents, err
 }
 
-const updateLineItem = `-- name: UpdateLineItem :one
-UPDATE line_items 
-SET amount_cents = $2, description = $3, updated_at = NOW()
-WHERE id = $1 
-RETURNING id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata
+const listLineItems = `-- name: ListLineItems :many
+SELECT id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata, voided_at, void_reason, kind, quantity, unit_amount_cents, replaces_line_item_id FROM line_items
+WHERE bill_id = $1
+  AND ($2::timestamptz IS NULL OR incurred_at >= $2)
+  AND ($3::timestamptz IS NULL OR incurred_at < $3)
+  AND ($4::text IS NULL OR COALESCE(metadata->>'original_currency', currency) = $4)
+  AND ($5::text IS NULL OR reference_id = $5)
+  AND ($6::text IS NULL OR strpos(lower(description), lower($6)) > 0)
+  AND ($7::timestamptz IS NULL
+       OR (incurred_at, id) < ($7, $8::int))
+ORDER BY incurred_at DESC, id DESC
+LIMIT $9
+`
+
+type ListLineItemsParams struct {
+	BillID           pgtype.Int4
+	IncurredFrom     pgtype.Timestamptz
+	IncurredTo       pgtype.Timestamptz
+	Currency         pgtype.Text
+	ReferenceID      pgtype.Text
+	Description      pgtype.Text
+	CursorIncurredAt pgtype.Timestamptz
+	CursorID         pgtype.Int4
+	Limit            int32
+}
+
+func (q *Queries) ListLineItems(ctx context.Context, arg ListLineItemsParams) ([]LineItem, error) {
+	rows, err := q.db.Query(ctx, listLineItems,
+		arg.BillID,
+		arg.IncurredFrom,
+		arg.IncurredTo,
+		arg.Currency,
+		arg.ReferenceID,
+		arg.Description,
+		arg.CursorIncurredAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LineItem
+	for rows.Next() {
+		var i LineItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.BillID,
+			&i.AmountCents,
+			&i.Currency,
+			&i.Description,
+			&i.IncurredAt,
+			&i.ReferenceID,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Metadata,
+			&i.VoidedAt,
+			&i.VoidReason,
+			&i.Kind,
+			&i.Quantity,
+			&i.UnitAmountCents,
+			&i.ReplacesLineItemID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const voidLineItem = `-- name: VoidLineItem :one
+UPDATE line_items
+SET voided_at = NOW(), void_reason = $2, updated_at = NOW()
+WHERE id = $1 AND voided_at IS NULL
+RETURNING id, bill_id, amount_cents, currency, description, incurred_at, reference_id, idempotency_key, created_at, updated_at, metadata, voided_at, void_reason, kind, quantity, unit_amount_cents, replaces_line_item_id
 `
 
-type UpdateLineItemParams struct {
-	ID          int32
-	AmountCents int64
-	Description pgtype.Text
+type VoidLineItemParams struct {
+	ID         int32
+	VoidReason pgtype.Text
 }
 
-func (q *Queries) UpdateLineItem(ctx context.Context, arg UpdateLineItemParams) (LineItem, error) {
-	row := q.db.QueryRow(ctx, updateLineItem, arg.ID, arg.AmountCents, arg.Description)
+func (q *Queries) VoidLineItem(ctx context.Context, arg VoidLineItemParams) (LineItem, error) {
+	row := q.db.QueryRow(ctx, voidLineItem, arg.ID, arg.VoidReason)
 	var i LineItem
 	err := row.Scan(
 		&i.ID,
@@ -176,6 +326,12 @@ func (q *Queries) UpdateLineItem(ctx context.Context, arg UpdateLineItemParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Metadata,
+		&i.VoidedAt,
+		&i.VoidReason,
+		&i.Kind,
+		&i.Quantity,
+		&i.UnitAmountCents,
+		&i.ReplacesLineItemID,
 	)
 	return i, err
 }
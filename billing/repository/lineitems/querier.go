@@ -13,11 +13,13 @@ import (
 type Querier interface {
 	// Line items related queries
 	CreateLineItem(ctx context.Context, arg CreateLineItemParams) (LineItem, error)
-	DeleteLineItem(ctx context.Context, id int32) error
+	CreateLineItems(ctx context.Context, arg []CreateLineItemsParams) (int64, error)
 	GetLineItem(ctx context.Context, id int32) (LineItem, error)
 	GetLineItemsByBill(ctx context.Context, billID pgtype.Int4) ([]LineItem, error)
-	GetTotalAmountByBill(ctx context.Context, billID pgtype.Int4) (interface{}, error)
-	UpdateLineItem(ctx context.Context, arg UpdateLineItemParams) (LineItem, error)
+	GetLineItemsByIdempotencyKeys(ctx context.Context, idempotencyKeys []string) ([]LineItem, error)
+	GetTotalAmountByBill(ctx context.Context, billID pgtype.Int4) (int64, error)
+	ListLineItems(ctx context.Context, arg ListLineItemsParams) ([]LineItem, error)
+	VoidLineItem(ctx context.Context, arg VoidLineItemParams) (LineItem, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -3,23 +3,41 @@ package repository
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"encore.app/billing/repository/accounts"
+	"encore.app/billing/repository/apikeys"
+	"encore.app/billing/repository/auditlog"
+	"encore.app/billing/repository/billevents"
 	"encore.app/billing/repository/bills"
 	"encore.app/billing/repository/currencies"
 	"encore.app/billing/repository/lineitems"
+	"encore.app/billing/repository/payments"
+	"encore.app/billing/repository/subscriptions"
 )
 
 // Repository combines all domain-specific repositories
 type Repository struct {
-	Bills      bills.Querier
-	LineItems  lineitems.Querier
-	Currencies currencies.Querier
+	Accounts      accounts.Querier
+	APIKeys       apikeys.Querier
+	AuditLog      auditlog.Querier
+	Bills         bills.Querier
+	BillEvents    billevents.Querier
+	LineItems     lineitems.Querier
+	Payments      payments.Querier
+	Currencies    currencies.Querier
+	Subscriptions subscriptions.Querier
 }
 
 // NewRepository creates a new Repository with all domain queriers
 func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{
-		Bills:      bills.New(db),
-		LineItems:  lineitems.New(db),
-		Currencies: currencies.New(db),
+		Accounts:      accounts.New(db),
+		APIKeys:       apikeys.New(db),
+		AuditLog:      auditlog.New(db),
+		Bills:         bills.New(db),
+		BillEvents:    billevents.New(db),
+		LineItems:     lineitems.New(db),
+		Payments:      payments.New(db),
+		Currencies:    currencies.New(db),
+		Subscriptions: subscriptions.New(db),
 	}
 }
@@ -11,9 +11,13 @@ import (
 	"encore.dev/storage/sqldb"
 	"github.com/go-playground/validator/v10"
 
+	"encore.app/billing/business/account"
+	"encore.app/billing/business/apikey"
 	"encore.app/billing/business/bill"
 	"encore.app/billing/business/currency"
+	"encore.app/billing/business/subscription"
 	domain "encore.app/billing/domain/bill_state_machine"
+	"encore.app/billing/model"
 	"encore.app/billing/repository"
 	"encore.app/billing/workflow"
 )
@@ -29,9 +33,17 @@ var (
 
 //encore:service
 type Service struct {
-	business bill.Business
-	temporal client.Client
-	worker   worker.Worker
+	business      bill.Business
+	accounts      account.Business
+	apiKeys       apikey.Business
+	subscriptions subscription.Business
+	currencies    currency.Business
+	// rateProvider is nil when no rate source is configured
+	rateProvider currency.RateProvider
+	// dunningSchedule is nil to run the default dunning schedule
+	dunningSchedule []model.DunningStep
+	temporal        client.Client
+	worker          worker.Worker
 }
 
 func initService() (*Service, error) {
@@ -43,17 +55,27 @@ func initService() (*Service, error) {
 		return nil, err
 	}
 
-	currencyBusiness := currency.NewCurrencyBusiness(repo.Currencies)
-	billStateMachine := domain.NewBillStateMachine(pgxdb, repo.Bills, repo.LineItems)
-	billService := bill.NewBillBusiness(repo.Bills, repo.LineItems, billStateMachine, currencyBusiness)
+	accountBusiness := account.NewAccountBusiness(repo.Accounts)
+	apiKeyBusiness := apikey.NewAPIKeyBusiness(repo.APIKeys, accountBusiness)
+	currencyBusiness := currency.NewCurrencyBusiness(repo.Currencies, pgxdb)
+	billStateMachine := domain.NewBillStateMachine(pgxdb)
+	billService := bill.NewBillBusiness(repo.Bills, repo.LineItems, repo.BillEvents, repo.AuditLog, repo.Payments, billStateMachine, currencyBusiness, accountBusiness)
+	subscriptionBusiness := subscription.NewSubscriptionBusiness(repo.Subscriptions, billService, accountBusiness, currencyBusiness)
 
 	// Set activity dependencies for Temporal workflows
-	workflow.SetActivityDependencies(billService)
+	workflow.SetActivityDependencies(billService, subscriptionBusiness)
+	workflow.SetDunningNotifier(publishDunningNotice)
 
 	return &Service{
-		business: billService,
-		temporal: temporal,
-		worker:   worker,
+		business:        billService,
+		accounts:        accountBusiness,
+		apiKeys:         apiKeyBusiness,
+		subscriptions:   subscriptionBusiness,
+		currencies:      currencyBusiness,
+		rateProvider:    newRateProvider(),
+		dunningSchedule: dunningSchedule(),
+		temporal:        temporal,
+		worker:          worker,
 	}, nil
 }
 
@@ -69,10 +91,17 @@ func initTemporal() (client.Client, worker.Worker, error) {
 	w := worker.New(c, taskQueue, worker.Options{})
 
 	w.RegisterWorkflow(workflow.BillingPeriod)
+	w.RegisterWorkflow(workflow.Subscription)
+	w.RegisterWorkflow(workflow.Dunning)
 
 	w.RegisterActivity(workflow.CloseBillActivity)
+	w.RegisterActivity(workflow.RetryFinalizationActivity)
+	w.RegisterActivity(workflow.StartClosingGraceActivity)
 	w.RegisterActivity(workflow.ActivateBillActivity)
 	w.RegisterActivity(workflow.UpdateBillTotalActivity)
+	w.RegisterActivity(workflow.CreateSubscriptionBillActivity)
+	w.RegisterActivity(workflow.GetBillDueActivity)
+	w.RegisterActivity(workflow.DunningStepActivity)
 
 	if err = w.Start(); err != nil {
 		c.Close()
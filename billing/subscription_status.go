@@ -0,0 +1,67 @@
+package billing
+
+import (
+	"context"
+	"errors"
+
+	"go.temporal.io/api/serviceerror"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+//encore:api auth path=/v1/subscriptions/:id/pause method=POST
+func (s *Service) PauseSubscription(ctx context.Context, id int32) (*SubscriptionResponse, error) {
+	return s.changeSubscriptionStatus(ctx, id, model.SubscriptionStatusPaused, workflow.PauseSubscriptionSignalName)
+}
+
+//encore:api auth path=/v1/subscriptions/:id/resume method=POST
+func (s *Service) ResumeSubscription(ctx context.Context, id int32) (*SubscriptionResponse, error) {
+	return s.changeSubscriptionStatus(ctx, id, model.SubscriptionStatusActive, workflow.ResumeSubscriptionSignalName)
+}
+
+//encore:api auth path=/v1/subscriptions/:id/cancel method=POST
+func (s *Service) CancelSubscription(ctx context.Context, id int32) (*SubscriptionResponse, error) {
+	return s.changeSubscriptionStatus(ctx, id, model.SubscriptionStatusCancelled, workflow.CancelSubscriptionSignalName)
+}
+
+// changeSubscriptionStatus signals the subscription workflow before persisting the new status,
+// so a failed signal leaves the stored status unchanged and the request can be retried
+func (s *Service) changeSubscriptionStatus(ctx context.Context, id int32, status model.SubscriptionStatus, signalName string) (*SubscriptionResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid subscription ID"}
+	}
+
+	current, err := s.authorizeSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == status {
+		return &SubscriptionResponse{Subscription: *current}, nil
+	}
+	if current.Status == model.SubscriptionStatusCancelled {
+		return nil, &errs.Error{Code: errs.FailedPrecondition, Message: "subscription is cancelled"}
+	}
+
+	if err := s.temporal.SignalWorkflow(ctx, *current.WorkflowID, "", signalName, nil); err != nil {
+		// A workflow that is gone can no longer bill, so cancelling it is still safe
+		var notFound *serviceerror.NotFound
+		if status != model.SubscriptionStatusCancelled || !errors.As(err, &notFound) {
+			rlog.Error("failed to signal subscription workflow", "error", err, "id", id, "signal", signalName)
+			return nil, &errs.Error{Code: errs.Internal, Message: "failed to signal subscription workflow"}
+		}
+	}
+
+	result, err := s.subscriptions.UpdateSubscriptionStatus(ctx, id, status)
+	if err != nil {
+		rlog.Error("failed to update subscription status", "error", err, "id", id, "status", status)
+		return nil, err
+	}
+
+	return &SubscriptionResponse{
+		Subscription: *result,
+	}, nil
+}
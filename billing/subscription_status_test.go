@@ -0,0 +1,124 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/subscription_business"
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+func TestChangeSubscriptionStatus(t *testing.T) {
+	workflowID := "subscription-key-1"
+
+	testCases := []struct {
+		name          string
+		caller        *AuthData
+		current       model.SubscriptionStatus
+		invoke        func(s *Service) (*SubscriptionResponse, error)
+		signalName    string
+		signalError   error
+		target        model.SubscriptionStatus
+		expectUpdate  bool
+		expectedError string
+	}{
+		{
+			name:         "pause_active_subscription",
+			caller:       &AuthData{AccountID: 9},
+			current:      model.SubscriptionStatusActive,
+			invoke:       func(s *Service) (*SubscriptionResponse, error) { return s.PauseSubscription(context.Background(), 5) },
+			signalName:   workflow.PauseSubscriptionSignalName,
+			target:       model.SubscriptionStatusPaused,
+			expectUpdate: true,
+		},
+		{
+			name:         "resume_paused_subscription",
+			caller:       &AuthData{AccountID: 9},
+			current:      model.SubscriptionStatusPaused,
+			invoke:       func(s *Service) (*SubscriptionResponse, error) { return s.ResumeSubscription(context.Background(), 5) },
+			signalName:   workflow.ResumeSubscriptionSignalName,
+			target:       model.SubscriptionStatusActive,
+			expectUpdate: true,
+		},
+		{
+			name:         "cancel_with_finished_workflow",
+			caller:       &AuthData{Admin: true},
+			current:      model.SubscriptionStatusPaused,
+			invoke:       func(s *Service) (*SubscriptionResponse, error) { return s.CancelSubscription(context.Background(), 5) },
+			signalName:   workflow.CancelSubscriptionSignalName,
+			signalError:  serviceerror.NewNotFound("workflow not found"),
+			target:       model.SubscriptionStatusCancelled,
+			expectUpdate: true,
+		},
+		{
+			name:    "pause_already_paused_is_noop",
+			caller:  &AuthData{AccountID: 9},
+			current: model.SubscriptionStatusPaused,
+			invoke:  func(s *Service) (*SubscriptionResponse, error) { return s.PauseSubscription(context.Background(), 5) },
+			target:  model.SubscriptionStatusPaused,
+		},
+		{
+			name:          "signal_failure_keeps_status",
+			caller:        &AuthData{AccountID: 9},
+			current:       model.SubscriptionStatusActive,
+			invoke:        func(s *Service) (*SubscriptionResponse, error) { return s.PauseSubscription(context.Background(), 5) },
+			signalName:    workflow.PauseSubscriptionSignalName,
+			signalError:   errors.New("temporal unavailable"),
+			expectedError: "failed to signal subscription workflow",
+		},
+		{
+			name:          "other_account_subscription",
+			caller:        &AuthData{AccountID: 10},
+			current:       model.SubscriptionStatusActive,
+			invoke:        func(s *Service) (*SubscriptionResponse, error) { return s.PauseSubscription(context.Background(), 5) },
+			expectedError: "subscription not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withCaller(t, tc.caller)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubscriptions := subscription_business.NewMockBusiness(ctrl)
+			mockTemporal := mocks.NewClient(t)
+			service := &Service{subscriptions: mockSubscriptions, temporal: mockTemporal}
+
+			mockSubscriptions.EXPECT().
+				GetSubscription(gomock.Any(), int32(5)).
+				Return(&model.Subscription{ID: 5, AccountID: 9, Status: tc.current, WorkflowID: &workflowID}, nil)
+
+			if tc.signalName != "" {
+				mockTemporal.On("SignalWorkflow", mock.Anything, workflowID, "", tc.signalName, nil).
+					Return(tc.signalError).
+					Once()
+			}
+			if tc.expectUpdate {
+				mockSubscriptions.EXPECT().
+					UpdateSubscriptionStatus(gomock.Any(), int32(5), tc.target).
+					Return(&model.Subscription{ID: 5, AccountID: 9, Status: tc.target, WorkflowID: &workflowID}, nil)
+			}
+
+			response, err := tc.invoke(service)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.target, response.Subscription.Status)
+			}
+		})
+	}
+}
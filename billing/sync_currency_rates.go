@@ -0,0 +1,76 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/config"
+	"encore.dev/cron"
+	"encore.dev/rlog"
+
+	"encore.app/billing/business/currency"
+	"encore.app/billing/model"
+)
+
+// RateSyncConfig configures the scheduled exchange rate sync
+type RateSyncConfig struct {
+	// Source is the http(s) URL of a JSON rate feed or the path of a JSON or CSV rate file;
+	// empty disables the sync
+	Source config.String
+	// MaxChangePercent rejects a provided rate that moves more than this from the rate in effect
+	MaxChangePercent config.Float64
+}
+
+type Config struct {
+	RateSync RateSyncConfig
+	Dunning  DunningConfig
+}
+
+var cfg = config.Load[*Config]()
+
+var _ = cron.NewJob("sync-currency-rates", cron.JobConfig{
+	Title:    "Pull exchange rates from the configured rate provider",
+	Every:    1 * cron.Hour,
+	Endpoint: SyncCurrencyRates,
+})
+
+// newRateProvider returns the provider of the configured rate source, or nil without one
+func newRateProvider() currency.RateProvider {
+	source := cfg.RateSync.Source()
+	if source == "" {
+		return nil
+	}
+	return currency.NewRateProvider(source)
+}
+
+type SyncCurrencyRatesResponse struct {
+	Result model.RateSyncResult `json:"result"`
+}
+
+// SyncCurrencyRates pulls the latest exchange rates from the configured provider into the rates
+// table. Run hourly by the sync-currency-rates cron job.
+//
+//encore:api private
+func (s *Service) SyncCurrencyRates(ctx context.Context) (*SyncCurrencyRatesResponse, error) {
+	if s.rateProvider == nil {
+		return nil, &errs.Error{Code: errs.FailedPrecondition, Message: "no exchange rate source configured"}
+	}
+
+	result, err := s.currencies.SyncRates(ctx, s.rateProvider, cfg.RateSync.MaxChangePercent(), time.Now())
+	if err != nil {
+		rlog.Error("failed to sync currency rates", "error", err)
+		return nil, err
+	}
+
+	for _, rejected := range result.Rejected {
+		rlog.Warn("rejected currency rate", "code", rejected.Code, "current_rate", rejected.CurrentRate,
+			"provided_rate", rejected.ProvidedRate, "change_percent", rejected.ChangePercent, "reason", rejected.Reason)
+	}
+	rlog.Info("synced currency rates", "updated", len(result.Updated), "unchanged", len(result.Unchanged),
+		"rejected", len(result.Rejected), "skipped", len(result.Skipped))
+
+	return &SyncCurrencyRatesResponse{
+		Result: *result,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/business/currency"
+	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/model"
+)
+
+func TestSyncCurrencyRates(t *testing.T) {
+	t.Run("syncs_configured_provider", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCurrencies := currency_business.NewMockBusiness(ctrl)
+		provider := currency.NewFileRateProvider("rates.json")
+		service := &Service{currencies: mockCurrencies, rateProvider: provider}
+
+		mockCurrencies.EXPECT().SyncRates(gomock.Any(), provider, gomock.Any(), gomock.Any()).Return(&model.RateSyncResult{
+			Updated:  []model.CurrencyRate{{ID: 20, Code: "GEL", Rate: 2.7}},
+			Rejected: []model.RejectedRate{{Code: "JPY", CurrentRate: 150, ProvidedRate: 180, ChangePercent: 20, Reason: "rate changes by more than 10%"}},
+		}, nil)
+
+		response, err := service.SyncCurrencyRates(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Result.Updated, 1)
+		assert.Equal(t, "JPY", response.Result.Rejected[0].Code)
+	})
+
+	t.Run("requires_rate_source", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := &Service{currencies: currency_business.NewMockBusiness(ctrl)}
+
+		response, err := service.SyncCurrencyRates(context.Background())
+
+		assert.Nil(t, response)
+		assert.Equal(t, errs.FailedPrecondition, errs.Code(err))
+	})
+}
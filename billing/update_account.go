@@ -0,0 +1,53 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type UpdateAccountRequest struct {
+	Name    *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Email   *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Enabled *bool   `json:"enabled,omitempty"`
+	// PaymentTermsDays sets the payment terms of bills created for the account from now on
+	PaymentTermsDays *int32 `json:"payment_terms_days,omitempty" validate:"omitempty,min=0,max=365"`
+}
+
+//encore:api auth path=/v1/accounts/:id method=PATCH
+func (s *Service) UpdateAccount(ctx context.Context, id int32, req *UpdateAccountRequest) (*AccountResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid account ID"}
+	}
+
+	result, err := s.accounts.UpdateAccount(ctx, id, &model.AccountUpdate{
+		Name:             req.Name,
+		Email:            req.Email,
+		Enabled:          req.Enabled,
+		PaymentTermsDays: req.PaymentTermsDays,
+	})
+	if err != nil {
+		rlog.Error("failed to update account", "error", err, "id", id)
+		return nil, err
+	}
+
+	return &AccountResponse{
+		Account: *result,
+	}, nil
+}
+
+// Validate implements validation for UpdateAccountRequest
+func (r *UpdateAccountRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/account_business"
+	"encore.app/billing/model"
+)
+
+func TestUpdateAccount(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccounts := account_business.NewMockBusiness(ctrl)
+	service := &Service{accounts: mockAccounts}
+
+	disabled := false
+
+	testCases := []struct {
+		name              string
+		accountID         int32
+		request           *UpdateAccountRequest
+		mockAccountReturn *model.Account
+		mockAccountError  error
+		expectedError     string
+		expectAccountCall bool
+	}{
+		{
+			name:              "disable_account",
+			accountID:         1,
+			request:           &UpdateAccountRequest{Enabled: &disabled},
+			mockAccountReturn: &model.Account{ID: 1, Name: "Acme Corp", Enabled: false},
+			expectAccountCall: true,
+		},
+		{
+			name:              "invalid_account_id",
+			accountID:         -1,
+			request:           &UpdateAccountRequest{Enabled: &disabled},
+			expectedError:     "invalid account ID",
+			expectAccountCall: false,
+		},
+		{
+			name:              "account_not_found",
+			accountID:         404,
+			request:           &UpdateAccountRequest{Enabled: &disabled},
+			mockAccountError:  &errs.Error{Code: errs.NotFound, Message: "account not found"},
+			expectedError:     "account not found",
+			expectAccountCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectAccountCall {
+				mockAccounts.EXPECT().
+					UpdateAccount(gomock.Any(), tc.accountID, &model.AccountUpdate{Enabled: tc.request.Enabled}).
+					Return(tc.mockAccountReturn, tc.mockAccountError).
+					Times(1)
+			}
+
+			response, err := service.UpdateAccount(context.Background(), tc.accountID, tc.request)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockAccountReturn.Enabled, response.Account.Enabled)
+			}
+		})
+	}
+}
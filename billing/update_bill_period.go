@@ -0,0 +1,72 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+type UpdateBillPeriodRequest struct {
+	EndTime time.Time `json:"end_time" validate:"required"`
+	Reason  string    `json:"reason" validate:"max=255"`
+}
+
+type UpdateBillPeriodResponse struct {
+	Bill model.Bill `json:"bill"`
+}
+
+// UpdateBillPeriod extends or shortens the billing period of a pending or active bill. The
+// billing period workflow is signalled to close the bill at the new end time.
+//
+//encore:api auth path=/v1/bills/:id/period method=PATCH
+func (s *Service) UpdateBillPeriod(ctx context.Context, id int32, req *UpdateBillPeriodRequest) (*UpdateBillPeriodResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	err := s.business.UpdateBillPeriod(ctx, id, req.EndTime, req.Reason, callerActor())
+	if err != nil {
+		rlog.Error("failed to update bill period", "error", err, "id", id)
+		return nil, err
+	}
+
+	bill, err := s.business.GetBill(ctx, id, model.GetBillOptions{})
+	if err != nil {
+		rlog.Error("failed to get updated bill", "error", err, "id", id)
+		return nil, err
+	}
+
+	if bill.WorkflowID != nil {
+		workflowID := *bill.WorkflowID
+		// Signal workflow asynchronously with supervision (overridable in tests)
+		runAsync("signal_update_period", func(ctx context.Context) error {
+			return s.temporal.SignalWorkflow(ctx, workflowID, "", workflow.UpdatePeriodSignalName, workflow.UpdatePeriodSignal{
+				EndTime: bill.EndTime,
+			})
+		})
+	}
+
+	s.newAmountFormatter(ctx).bill(bill)
+
+	return &UpdateBillPeriodResponse{
+		Bill: *bill,
+	}, nil
+}
+
+// Validate implements validation for UpdateBillPeriodRequest
+func (r *UpdateBillPeriodRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/mock/gomock"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+	"encore.app/billing/workflow"
+)
+
+func TestUpdateBillPeriod(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	originalRunAsync := runAsync
+	runAsync = func(op string, fn func(ctx context.Context) error) { _ = fn(context.Background()) }
+	defer func() { runAsync = originalRunAsync }()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	mockTemporal := &mocks.Client{}
+	service := &Service{business: mockBusiness, temporal: mockTemporal, currencies: stubMinorUnits(ctrl)}
+
+	endTime := time.Now().Add(72 * time.Hour).UTC()
+	mockBusiness.EXPECT().UpdateBillPeriod(gomock.Any(), int32(1), endTime, "contract extended", "admin").Return(nil)
+	mockBusiness.EXPECT().GetBill(gomock.Any(), int32(1), model.GetBillOptions{}).
+		Return(&model.Bill{ID: 1, Status: model.BillStatusActive, EndTime: endTime, WorkflowID: stringPtr("bill-1")}, nil)
+	mockTemporal.On("SignalWorkflow", mock.Anything, "bill-1", "", workflow.UpdatePeriodSignalName,
+		workflow.UpdatePeriodSignal{EndTime: endTime}).Return(nil).Once()
+
+	response, err := service.UpdateBillPeriod(context.Background(), 1, &UpdateBillPeriodRequest{EndTime: endTime, Reason: "contract extended"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, endTime, response.Bill.EndTime)
+	mockTemporal.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type UpdateCurrencyRequest struct {
+	Symbol *string `json:"symbol,omitempty" validate:"omitempty,max=4"`
+	// Rate records a new rate against the base currency, in effect from now
+	Rate       *float64 `json:"rate,omitempty" validate:"omitempty,gt=0"`
+	MinorUnits *int32   `json:"minor_units,omitempty" validate:"omitempty,min=0,max=4"`
+	Enabled    *bool    `json:"enabled,omitempty"`
+}
+
+// UpdateCurrency changes a currency. Disabling it fails while open bills are billed in it, and
+// changing its minor units fails once any amount is recorded in it.
+//
+//encore:api auth path=/v1/currencies/:code method=PATCH
+func (s *Service) UpdateCurrency(ctx context.Context, code string, req *UpdateCurrencyRequest) (*CurrencyResponse, error) {
+	if err := requireAdmin(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.currencies.UpdateCurrency(ctx, code, &model.CurrencyUpdate{
+		Symbol:     req.Symbol,
+		Rate:       req.Rate,
+		MinorUnits: req.MinorUnits,
+		Enabled:    req.Enabled,
+	}, callerActor())
+	if err != nil {
+		rlog.Error("failed to update currency", "error", err, "code", code)
+		return nil, err
+	}
+
+	return &CurrencyResponse{
+		Currency: *result,
+	}, nil
+}
+
+// Validate implements validation for UpdateCurrencyRequest
+func (r *UpdateCurrencyRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
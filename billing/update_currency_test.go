@@ -0,0 +1,62 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/currency_business"
+	"encore.app/billing/model"
+)
+
+func TestUpdateCurrency(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	disabled := false
+	rate := 2.75
+
+	t.Run("updates_rate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCurrencies := currency_business.NewMockBusiness(ctrl)
+		service := &Service{currencies: mockCurrencies}
+
+		mockCurrencies.EXPECT().UpdateCurrency(gomock.Any(), "GEL", &model.CurrencyUpdate{Rate: &rate}, "admin").
+			Return(&model.CurrencyInfo{ID: 2, Code: "GEL", Rate: rate, MinorUnits: 2, Enabled: true}, nil)
+
+		response, err := service.UpdateCurrency(context.Background(), "GEL", &UpdateCurrencyRequest{Rate: &rate})
+
+		assert.NoError(t, err)
+		assert.Equal(t, rate, response.Currency.Rate)
+	})
+
+	t.Run("disable_blocked_by_open_bills", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCurrencies := currency_business.NewMockBusiness(ctrl)
+		service := &Service{currencies: mockCurrencies}
+
+		mockCurrencies.EXPECT().UpdateCurrency(gomock.Any(), "GEL", &model.CurrencyUpdate{Enabled: &disabled}, "admin").
+			Return(nil, &errs.Error{Code: errs.FailedPrecondition, Message: "cannot disable GEL while 3 open bills use it"})
+
+		response, err := service.UpdateCurrency(context.Background(), "GEL", &UpdateCurrencyRequest{Enabled: &disabled})
+
+		assert.Nil(t, response)
+		assert.Equal(t, errs.FailedPrecondition, errs.Code(err))
+	})
+}
+
+func TestUpdateCurrencyRequest_Validate(t *testing.T) {
+	rate := -1.0
+	units := int32(6)
+
+	assert.NoError(t, (&UpdateCurrencyRequest{}).Validate())
+	assert.Error(t, (&UpdateCurrencyRequest{Rate: &rate}).Validate())
+	assert.Error(t, (&UpdateCurrencyRequest{MinorUnits: &units}).Validate())
+}
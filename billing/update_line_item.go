@@ -0,0 +1,66 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+
+	"encore.app/billing/model"
+)
+
+type UpdateLineItemRequest struct {
+	// Amounts and Currency are converted into the bill currency again when either changes.
+	// Amounts follow the line item kind, so they are positive unless the item is an adjustment.
+	// AmountCents sets a flat amount and resets the quantity to 1.
+	Currency        *string `json:"currency,omitempty" validate:"omitempty,len=3,alpha"`
+	AmountCents     *int64  `json:"amount_cents,omitempty" validate:"omitempty,ne=0,excluded_with=UnitAmountCents Quantity"`
+	UnitAmountCents *int64  `json:"unit_amount_cents,omitempty" validate:"omitempty,ne=0"`
+	Quantity        *string `json:"quantity,omitempty" validate:"omitempty,numeric"`
+	Description     *string `json:"description,omitempty" validate:"omitempty,max=255"`
+}
+
+//encore:api auth path=/v1/bills/:id/line_items/:item_id method=PATCH
+func (s *Service) UpdateLineItem(ctx context.Context, id int32, item_id int32, req *UpdateLineItemRequest) (*LineItemResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+	if item_id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid line item ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := s.business.UpdateLineItem(ctx, id, item_id, &model.LineItemUpdate{
+		AmountCents:     req.AmountCents,
+		UnitAmountCents: req.UnitAmountCents,
+		Quantity:        req.Quantity,
+		Currency:        req.Currency,
+		Description:     req.Description,
+	}, callerActor())
+	if err != nil {
+		rlog.Error("failed to update line item", "error", err, "bill_id", id, "line_item_id", item_id)
+		return nil, err
+	}
+
+	s.newAmountFormatter(ctx).lineItem(result)
+
+	return &LineItemResponse{
+		LineItem: *result,
+	}, nil
+}
+
+// Validate implements validation for UpdateLineItemRequest
+func (r *UpdateLineItemRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	if r.Currency == nil && r.AmountCents == nil && r.UnitAmountCents == nil && r.Quantity == nil && r.Description == nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: "at least one field must be provided"}
+	}
+
+	return nil
+}
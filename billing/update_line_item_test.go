@@ -0,0 +1,131 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+func TestUpdateLineItem(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness, currencies: stubMinorUnits(ctrl)}
+
+	amount := int64(2500)
+
+	testCases := []struct {
+		name               string
+		billID             int32
+		lineItemID         int32
+		request            *UpdateLineItemRequest
+		mockUpdateReturn   *model.LineItem
+		mockUpdateError    error
+		expectedError      string
+		expectBusinessCall bool
+	}{
+		{
+			name:               "update_amount",
+			billID:             1,
+			lineItemID:         5,
+			request:            &UpdateLineItemRequest{AmountCents: &amount},
+			mockUpdateReturn:   &model.LineItem{ID: 5, BillID: 1, AmountCents: 2500, Currency: "USD"},
+			expectBusinessCall: true,
+		},
+		{
+			name:               "invalid_line_item_id",
+			billID:             1,
+			lineItemID:         0,
+			request:            &UpdateLineItemRequest{AmountCents: &amount},
+			expectedError:      "invalid line item ID",
+			expectBusinessCall: false,
+		},
+		{
+			name:               "bill_not_active",
+			billID:             1,
+			lineItemID:         5,
+			request:            &UpdateLineItemRequest{AmountCents: &amount},
+			mockUpdateError:    &errs.Error{Code: errs.InvalidArgument, Message: "bill is not in active state for editing line items"},
+			expectedError:      "bill is not in active state",
+			expectBusinessCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectBusinessCall {
+				mockBusiness.EXPECT().
+					UpdateLineItem(gomock.Any(), tc.billID, tc.lineItemID, &model.LineItemUpdate{AmountCents: tc.request.AmountCents}, "admin").
+					Return(tc.mockUpdateReturn, tc.mockUpdateError).
+					Times(1)
+			}
+
+			response, err := service.UpdateLineItem(context.Background(), tc.billID, tc.lineItemID, tc.request)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockUpdateReturn.AmountCents, response.LineItem.AmountCents)
+			}
+		})
+	}
+}
+
+func TestUpdateLineItemRequest_Validation(t *testing.T) {
+	amount := int64(100)
+	zero := int64(0)
+	currency := "GEL"
+	badCurrency := "GE1"
+
+	testCases := []struct {
+		name          string
+		request       *UpdateLineItemRequest
+		expectedError string
+	}{
+		{
+			name:    "valid_request",
+			request: &UpdateLineItemRequest{AmountCents: &amount, Currency: &currency},
+		},
+		{
+			name:          "empty_request",
+			request:       &UpdateLineItemRequest{},
+			expectedError: "at least one field must be provided",
+		},
+		{
+			name:          "zero_amount",
+			request:       &UpdateLineItemRequest{AmountCents: &zero},
+			expectedError: "ne",
+		},
+		{
+			name:          "invalid_currency",
+			request:       &UpdateLineItemRequest{Currency: &badCurrency},
+			expectedError: "alpha",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package billing
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+)
+
+type VoidLineItemRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+//encore:api auth path=/v1/bills/:id/line_items/:item_id/void method=POST
+func (s *Service) VoidLineItem(ctx context.Context, id int32, item_id int32, req *VoidLineItemRequest) (*LineItemResponse, error) {
+	if id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid bill ID"}
+	}
+	if item_id <= 0 {
+		return nil, &errs.Error{Code: errs.InvalidArgument, Message: "invalid line item ID"}
+	}
+
+	if err := s.authorizeBill(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := s.business.VoidLineItem(ctx, id, item_id, req.Reason, callerActor())
+	if err != nil {
+		rlog.Error("failed to void line item", "error", err, "bill_id", id, "line_item_id", item_id)
+		return nil, err
+	}
+
+	s.newAmountFormatter(ctx).lineItem(result)
+
+	return &LineItemResponse{
+		LineItem: *result,
+	}, nil
+}
+
+// Validate implements validation for VoidLineItemRequest
+func (r *VoidLineItemRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return &errs.Error{Code: errs.InvalidArgument, Message: err.Error()}
+	}
+
+	return nil
+}
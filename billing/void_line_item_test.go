@@ -0,0 +1,87 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	"encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+func TestVoidLineItem(t *testing.T) {
+	withCaller(t, &AuthData{Admin: true})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBusiness := bill_business.NewMockBusiness(ctrl)
+	service := &Service{business: mockBusiness, currencies: stubMinorUnits(ctrl)}
+
+	voidedAt := time.Now()
+
+	testCases := []struct {
+		name               string
+		billID             int32
+		lineItemID         int32
+		request            *VoidLineItemRequest
+		mockVoidReturn     *model.LineItem
+		mockVoidError      error
+		expectedError      string
+		expectBusinessCall bool
+	}{
+		{
+			name:               "void_line_item",
+			billID:             1,
+			lineItemID:         5,
+			request:            &VoidLineItemRequest{Reason: "duplicate charge"},
+			mockVoidReturn:     &model.LineItem{ID: 5, BillID: 1, VoidedAt: &voidedAt, VoidReason: "duplicate charge"},
+			expectBusinessCall: true,
+		},
+		{
+			name:               "invalid_bill_id",
+			billID:             0,
+			lineItemID:         5,
+			request:            &VoidLineItemRequest{},
+			expectedError:      "invalid bill ID",
+			expectBusinessCall: false,
+		},
+		{
+			name:               "already_voided",
+			billID:             1,
+			lineItemID:         5,
+			request:            &VoidLineItemRequest{},
+			mockVoidError:      &errs.Error{Code: errs.FailedPrecondition, Message: "line item is voided"},
+			expectedError:      "line item is voided",
+			expectBusinessCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectBusinessCall {
+				mockBusiness.EXPECT().
+					VoidLineItem(gomock.Any(), tc.billID, tc.lineItemID, tc.request.Reason, "admin").
+					Return(tc.mockVoidReturn, tc.mockVoidError).
+					Times(1)
+			}
+
+			response, err := service.VoidLineItem(context.Background(), tc.billID, tc.lineItemID, tc.request)
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.mockVoidReturn.VoidReason, response.LineItem.VoidReason)
+				assert.NotNil(t, response.LineItem.VoidedAt)
+			}
+		})
+	}
+}
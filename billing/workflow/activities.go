@@ -2,39 +2,60 @@ package workflow
 
 import (
 	"context"
+	"errors"
 
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/temporal"
 
+	"encore.dev/beta/errs"
+
 	"encore.app/billing/business/bill"
+	"encore.app/billing/business/subscription"
+	"encore.app/billing/model"
 )
 
 // ActivityDependencies holds the dependencies needed by activities
 // Now using the unified business layer
 type ActivityDependencies struct {
-	BillBusiness bill.Business
+	BillBusiness         bill.Business
+	SubscriptionBusiness subscription.Business
+	// DunningNotifier delivers the notices of applied dunning steps; nil drops them
+	DunningNotifier func(ctx context.Context, notice model.DunningNotice) error
 }
 
 var activityDeps *ActivityDependencies
 
 // SetActivityDependencies sets the dependencies for activities
-func SetActivityDependencies(billBusiness bill.Business) {
+func SetActivityDependencies(billBusiness bill.Business, subscriptionBusiness subscription.Business) {
 	activityDeps = &ActivityDependencies{
-		BillBusiness: billBusiness,
+		BillBusiness:         billBusiness,
+		SubscriptionBusiness: subscriptionBusiness,
+	}
+}
+
+// SetDunningNotifier sets how dunning notices are delivered; call after SetActivityDependencies
+func SetDunningNotifier(notifier func(ctx context.Context, notice model.DunningNotice) error) {
+	if activityDeps != nil {
+		activityDeps.DunningNotifier = notifier
 	}
 }
 
-// CloseBillActivity closes a bill and calculates final amounts
-func CloseBillActivity(ctx context.Context, billID int32, reason string) error {
+// CloseBillActivity closes a bill and calculates final amounts; closedBy is recorded in the
+// bill history and defaults to the system actor
+func CloseBillActivity(ctx context.Context, billID int32, reason, closedBy string) error {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Processing close bill activity", "billID", billID, "reason", reason)
+	logger.Info("Processing close bill activity", "billID", billID, "reason", reason, "closedBy", closedBy)
+
+	if closedBy == "" {
+		closedBy = model.ActorSystem
+	}
 
 	if activityDeps == nil || activityDeps.BillBusiness == nil {
 		logger.Error("Activity dependencies not set")
 		return temporal.NewApplicationError("activity dependencies not initialized", "DependencyError")
 	}
 
-	err := activityDeps.BillBusiness.CloseBill(ctx, billID, reason)
+	err := activityDeps.BillBusiness.CloseBill(ctx, billID, reason, closedBy)
 	if err != nil {
 		logger.Error("Failed to close bill", "billID", billID, "error", err)
 		return err
@@ -44,6 +65,77 @@ func CloseBillActivity(ctx context.Context, billID int32, reason string) error {
 	return nil
 }
 
+// StartClosingGraceActivity moves a bill whose period has ended into its grace window
+func StartClosingGraceActivity(ctx context.Context, billID int32) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Processing start closing grace activity", "billID", billID)
+
+	if activityDeps == nil || activityDeps.BillBusiness == nil {
+		logger.Error("Activity dependencies not set")
+		return temporal.NewApplicationError("activity dependencies not initialized", "DependencyError")
+	}
+
+	err := activityDeps.BillBusiness.StartClosingGrace(ctx, billID, model.ActorSystem)
+	if err != nil {
+		logger.Error("Failed to start closing grace", "billID", billID, "error", err)
+		var e *errs.Error
+		if errors.As(err, &e) && e.Code == errs.FailedPrecondition {
+			return temporal.NewNonRetryableApplicationError("bill cannot enter grace period", "BILL_NOT_ACTIVE", err)
+		}
+		return err
+	}
+
+	logger.Info("Successfully started closing grace", "billID", billID)
+	return nil
+}
+
+// RetryFinalizationActivity finalizes a bill whose close failed earlier. A bill left in
+// attention_required has its finalization retried, a bill still active is closed again and a
+// bill that has been closed or cancelled in the meantime is left alone.
+func RetryFinalizationActivity(ctx context.Context, billID int32, reason, closedBy string) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Processing retry finalization activity", "billID", billID, "reason", reason)
+
+	if closedBy == "" {
+		closedBy = model.ActorSystem
+	}
+
+	if activityDeps == nil || activityDeps.BillBusiness == nil {
+		logger.Error("Activity dependencies not set")
+		return temporal.NewApplicationError("activity dependencies not initialized", "DependencyError")
+	}
+
+	current, err := activityDeps.BillBusiness.GetBill(ctx, billID, model.GetBillOptions{OmitLineItems: true})
+	if err != nil {
+		logger.Error("Failed to get bill for finalization retry", "billID", billID, "error", err)
+		return err
+	}
+
+	switch current.Status {
+	case model.BillStatusClosed, model.BillStatusCancelled:
+		logger.Info("Bill already closed or cancelled, nothing to retry", "billID", billID, "status", current.Status)
+		return nil
+	case model.BillStatusAttentionRequired:
+		err = activityDeps.BillBusiness.RecoverBill(ctx, billID, model.BillRecovery{
+			Action:        model.BillRecoveryRetry,
+			Justification: reason,
+		}, closedBy)
+	default:
+		err = activityDeps.BillBusiness.CloseBill(ctx, billID, reason, closedBy)
+	}
+	if err != nil {
+		logger.Error("Failed to retry bill finalization", "billID", billID, "error", err)
+		var e *errs.Error
+		if errors.As(err, &e) && e.Code == errs.FailedPrecondition {
+			return temporal.NewNonRetryableApplicationError("bill cannot be finalized", "BILL_NOT_FINALIZABLE", err)
+		}
+		return err
+	}
+
+	logger.Info("Successfully finalized bill on retry", "billID", billID)
+	return nil
+}
+
 // ActivateBillActivity transitions a bill to active status when the billing period begins
 func ActivateBillActivity(ctx context.Context, billID int32) error {
 	logger := activity.GetLogger(ctx)
@@ -74,7 +166,7 @@ func UpdateBillTotalActivity(ctx context.Context, billID int32) error {
 		return temporal.NewApplicationError("activity dependencies not initialized", "DependencyError")
 	}
 
-	err := activityDeps.BillBusiness.UpdateBillTotal(ctx, billID)
+	err := activityDeps.BillBusiness.UpdateBillTotal(ctx, billID, model.ActorSystem)
 	if err != nil {
 		logger.Error("Failed to update bill total", "billID", billID, "error", err)
 		return temporal.NewNonRetryableApplicationError("failed to update bill total", "BILL_TOTAL_UPDATE_FAILED", err)
@@ -83,3 +175,99 @@ func UpdateBillTotalActivity(ctx context.Context, billID int32) error {
 	logger.Info("Successfully updated bill total", "billID", billID)
 	return nil
 }
+
+// CreateSubscriptionBillActivity creates the bill for the next period of a subscription
+func CreateSubscriptionBillActivity(ctx context.Context, subscriptionID int32, minPeriodIndex int32) (*SubscriptionPeriodBill, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Processing create subscription bill activity", "subscriptionID", subscriptionID, "minPeriodIndex", minPeriodIndex)
+
+	if activityDeps == nil || activityDeps.SubscriptionBusiness == nil {
+		logger.Error("Activity dependencies not set")
+		return nil, temporal.NewApplicationError("activity dependencies not initialized", "DependencyError")
+	}
+
+	bill, periodIndex, err := activityDeps.SubscriptionBusiness.CreatePeriodBill(ctx, subscriptionID, minPeriodIndex)
+	if err != nil {
+		logger.Error("Failed to create subscription bill", "subscriptionID", subscriptionID, "error", err)
+		var e *errs.Error
+		if errors.As(err, &e) && e.Code == errs.FailedPrecondition {
+			return nil, temporal.NewNonRetryableApplicationError("subscription cannot be billed", "SUBSCRIPTION_NOT_BILLABLE", err)
+		}
+		return nil, err
+	}
+
+	result := &SubscriptionPeriodBill{
+		BillID:      bill.ID,
+		PeriodIndex: periodIndex,
+		StartTime:   bill.StartTime,
+		EndTime:     bill.EndTime,
+	}
+	if bill.WorkflowID != nil {
+		result.WorkflowID = *bill.WorkflowID
+	}
+
+	logger.Info("Successfully created subscription bill", "subscriptionID", subscriptionID, "billID", bill.ID, "periodIndex", periodIndex)
+	return result, nil
+}
+
+// GetBillDueActivity returns the due date and balance of a bill about to be dunned
+func GetBillDueActivity(ctx context.Context, billID int32) (*BillDue, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Processing get bill due activity", "billID", billID)
+
+	if activityDeps == nil || activityDeps.BillBusiness == nil {
+		logger.Error("Activity dependencies not set")
+		return nil, temporal.NewApplicationError("activity dependencies not initialized", "DependencyError")
+	}
+
+	current, err := activityDeps.BillBusiness.GetBill(ctx, billID, model.GetBillOptions{OmitLineItems: true})
+	if err != nil {
+		logger.Error("Failed to get bill", "billID", billID, "error", err)
+		return nil, err
+	}
+
+	result := &BillDue{
+		Closed:       current.Status == model.BillStatusClosed,
+		BalanceCents: current.BalanceCents,
+	}
+	if current.DueAt != nil {
+		result.DueAt = *current.DueAt
+	}
+	return result, nil
+}
+
+// DunningStepActivity applies one step of a dunning schedule and sends its notice. It reports
+// true when the bill no longer needs dunning.
+func DunningStepActivity(ctx context.Context, billID int32, stepIndex int, step model.DunningStep) (bool, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Processing dunning step activity", "billID", billID, "step", stepIndex, "action", step.Action)
+
+	if activityDeps == nil || activityDeps.BillBusiness == nil {
+		logger.Error("Activity dependencies not set")
+		return false, temporal.NewApplicationError("activity dependencies not initialized", "DependencyError")
+	}
+
+	notice, err := activityDeps.BillBusiness.ApplyDunningStep(ctx, billID, stepIndex, step)
+	if err != nil {
+		logger.Error("Failed to apply dunning step", "billID", billID, "step", stepIndex, "error", err)
+		var e *errs.Error
+		if errors.As(err, &e) && (e.Code == errs.InvalidArgument || e.Code == errs.NotFound) {
+			return false, temporal.NewNonRetryableApplicationError("dunning step cannot be applied", "DUNNING_STEP_INVALID", err)
+		}
+		return false, err
+	}
+	if notice == nil {
+		logger.Info("Bill no longer needs dunning", "billID", billID)
+		return true, nil
+	}
+
+	if activityDeps.DunningNotifier != nil {
+		if err := activityDeps.DunningNotifier(ctx, *notice); err != nil {
+			logger.Error("Failed to send dunning notice", "billID", billID, "step", stepIndex, "error", err)
+			return false, err
+		}
+	}
+
+	logger.Info("Successfully applied dunning step", "billID", billID, "step", stepIndex, "balance", notice.BalanceCents)
+	return false, nil
+}
@@ -5,6 +5,16 @@ import (
 
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+
+	"encore.app/billing/model"
+)
+
+const (
+	// finalizationRetryDelay is how long the workflow waits before finalizing a bill again after
+	// its close failed
+	finalizationRetryDelay = 15 * time.Minute
+	// maxFinalizationRetries bounds the retries; after that the bill is left to an operator
+	maxFinalizationRetries = 3
 )
 
 // BillingPeriodWorkflowParams contains parameters for starting the billing workflow
@@ -12,6 +22,11 @@ type BillingPeriodWorkflowParams struct {
 	BillID    int32     `json:"bill_id"`
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
+	// GracePeriod keeps the bill in closing_grace for late line items before it is closed
+	GracePeriod time.Duration `json:"grace_period,omitempty"`
+	// DunningSchedule is run against the closed bill until it is paid; empty runs
+	// DefaultDunningSchedule
+	DunningSchedule []model.DunningStep `json:"dunning_schedule,omitempty"`
 }
 
 // BillingPeriodWorkflow manages the lifecycle of a billing period
@@ -34,13 +49,21 @@ func BillingPeriod(ctx workflow.Context, params BillingPeriodWorkflowParams) err
 	activeDuration := params.EndTime.Sub(params.StartTime)
 	if activeDuration <= 0 {
 		logger.Warn("End time is before start time, closing immediately", "billID", params.BillID)
-		return closeBill(ctx, params.BillID, "invalid_period")
+		return closeBill(ctx, params.BillID, "invalid_period", model.ActorSystem)
 	}
 
-	timer := workflow.NewTimer(ctx, activeDuration)
+	// A period that started in the past (e.g. a resumed subscription) only runs until its end time
+	if remaining := params.EndTime.Sub(workflow.Now(ctx)); remaining < activeDuration {
+		activeDuration = max(remaining, 0)
+	}
+
+	timerCtx, cancelTimer := workflow.WithCancel(ctx)
+	timer := workflow.NewTimer(timerCtx, activeDuration)
 
 	addLineItemCh := workflow.GetSignalChannel(ctx, AddLineItemSignalName)
 	closeBillCh := workflow.GetSignalChannel(ctx, CloseBillSignalName)
+	reactivatedCh := workflow.GetSignalChannel(ctx, BillReactivatedSignalName)
+	updatePeriodCh := workflow.GetSignalChannel(ctx, UpdatePeriodSignalName)
 
 	err := activateBill(ctx, params.BillID)
 	if err != nil {
@@ -49,6 +72,35 @@ func BillingPeriod(ctx workflow.Context, params BillingPeriodWorkflowParams) err
 	}
 
 	billClosed := false
+	timerFired := false
+
+	// A failed close is finalized again on a timer; the reason and actor of the close are kept
+	// so the retry records the same ones
+	var retryTimer workflow.Future
+	finalizationRetries := 0
+	retryReason, retryClosedBy := "", ""
+	scheduleFinalizationRetry := func(reason, closedBy string) {
+		if finalizationRetries >= maxFinalizationRetries {
+			logger.Warn("Giving up on bill finalization, operator recovery required", "billID", params.BillID, "retries", finalizationRetries)
+			return
+		}
+		retryReason, retryClosedBy = reason, closedBy
+		retryTimer = workflow.NewTimer(ctx, finalizationRetryDelay)
+	}
+
+	var graceTimer workflow.Future
+	autoClose := func() {
+		logger.Info("Auto-closing bill due to end time reached", "billID", params.BillID)
+
+		err := closeBill(ctx, params.BillID, "auto_close", model.ActorSystem)
+		if err != nil {
+			logger.Error("Failed to auto-close bill", "error", err)
+			scheduleFinalizationRetry("auto_close", model.ActorSystem)
+		} else {
+			logger.Info("Successfully auto-closed bill", "billID", params.BillID)
+			billClosed = true
+		}
+	}
 
 	logger.Info("Entering active billing period", "billID", params.BillID, "duration", activeDuration)
 
@@ -58,7 +110,7 @@ func BillingPeriod(ctx workflow.Context, params BillingPeriodWorkflowParams) err
 		selector.AddReceive(addLineItemCh, func(c workflow.ReceiveChannel, more bool) {
 			var signal AddLineItemSignal
 			c.Receive(ctx, &signal)
-			logger.Info("Tracking line item addition", "billID", params.BillID, "lineItemID", signal.LineItemID)
+			logger.Info("Tracking line item addition", "billID", params.BillID, "lineItemID", signal.LineItemID, "batchSize", len(signal.LineItemIDs))
 			err := updateBillTotal(ctx, params.BillID)
 			if err != nil {
 				logger.Error("Failed to recalculate bill total after line item addition", "billID", params.BillID, "lineItemID", signal.LineItemID, "error", err)
@@ -72,36 +124,108 @@ func BillingPeriod(ctx workflow.Context, params BillingPeriodWorkflowParams) err
 			c.Receive(ctx, &signal)
 			logger.Info("Received manual close bill signal", "billID", params.BillID, "reason", signal.Reason)
 
-			err := closeBill(ctx, params.BillID, signal.Reason)
+			err := closeBill(ctx, params.BillID, signal.Reason, signal.ClosedBy)
 			if err != nil {
 				logger.Error("Failed to close bill manually", "error", err)
+				if retryTimer == nil {
+					scheduleFinalizationRetry(signal.Reason, signal.ClosedBy)
+				}
 			} else {
 				logger.Info("Successfully closed bill manually", "billID", params.BillID)
 				billClosed = true
 			}
 		})
 
-		selector.AddFuture(timer, func(f workflow.Future) {
-			logger.Info("Auto-closing bill due to end time reached", "billID", params.BillID)
+		selector.AddReceive(reactivatedCh, func(c workflow.ReceiveChannel, more bool) {
+			var signal BillReactivatedSignal
+			c.Receive(ctx, &signal)
+			logger.Info("Bill reactivated by operator, cancelling finalization retries", "billID", params.BillID, "endTime", signal.EndTime)
+			retryTimer = nil
+			finalizationRetries = 0
+			graceTimer = nil
 
-			err := closeBill(ctx, params.BillID, "auto_close")
-			if err != nil {
-				logger.Error("Failed to auto-close bill", "error", err)
-			} else {
-				logger.Info("Successfully auto-closed bill", "billID", params.BillID)
-				billClosed = true
+			// The close timer has usually fired already, so it is set again from the bill's end
+			// time; a period that has ended is closed right away
+			cancelTimer()
+			remaining := signal.EndTime.Sub(workflow.Now(ctx))
+			if remaining <= 0 {
+				timerFired = true
+				autoClose()
+				return
 			}
+			timerCtx, cancelTimer = workflow.WithCancel(ctx)
+			timer = workflow.NewTimer(timerCtx, remaining)
+			timerFired = false
+		})
+
+		selector.AddReceive(updatePeriodCh, func(c workflow.ReceiveChannel, more bool) {
+			var signal UpdatePeriodSignal
+			c.Receive(ctx, &signal)
+			remaining := max(signal.EndTime.Sub(workflow.Now(ctx)), 0)
+			logger.Info("Billing period end time changed, replacing close timer", "billID", params.BillID, "endTime", signal.EndTime, "remaining", remaining)
+
+			cancelTimer()
+			timerCtx, cancelTimer = workflow.WithCancel(ctx)
+			timer = workflow.NewTimer(timerCtx, remaining)
+			timerFired = false
 		})
 
+		// A fired timer stays ready, so it is only selected once
+		if !timerFired {
+			selector.AddFuture(timer, func(f workflow.Future) {
+				timerFired = true
+				if params.GracePeriod > 0 {
+					logger.Info("End time reached, starting grace period", "billID", params.BillID, "gracePeriod", params.GracePeriod)
+					err := startClosingGrace(ctx, params.BillID)
+					if err == nil {
+						graceTimer = workflow.NewTimer(ctx, params.GracePeriod)
+						return
+					}
+					logger.Error("Failed to start grace period, closing bill now", "billID", params.BillID, "error", err)
+				}
+				autoClose()
+			})
+		}
+
+		if graceTimer != nil {
+			selector.AddFuture(graceTimer, func(f workflow.Future) {
+				graceTimer = nil
+				logger.Info("Grace period ended", "billID", params.BillID)
+				autoClose()
+			})
+		}
+
+		if retryTimer != nil {
+			selector.AddFuture(retryTimer, func(f workflow.Future) {
+				retryTimer = nil
+				finalizationRetries++
+				logger.Info("Retrying bill finalization", "billID", params.BillID, "attempt", finalizationRetries)
+
+				err := retryFinalization(ctx, params.BillID, retryReason, retryClosedBy)
+				if err != nil {
+					logger.Error("Failed to retry bill finalization", "billID", params.BillID, "error", err)
+					scheduleFinalizationRetry(retryReason, retryClosedBy)
+				} else {
+					logger.Info("Successfully finalized bill on retry", "billID", params.BillID)
+					billClosed = true
+				}
+			})
+		}
+
 		selector.Select(ctx)
 	}
 
+	// Dunning outlives this workflow; failing to start it must not fail the closed period
+	if err := startDunning(ctx, params.BillID, params.DunningSchedule); err != nil {
+		logger.Error("Failed to start dunning workflow", "billID", params.BillID, "error", err)
+	}
+
 	logger.Info("Billing period workflow completed", "billID", params.BillID)
 	return nil
 }
 
 // closeBill executes the CloseBill activity
-func closeBill(ctx workflow.Context, billID int32, reason string) error {
+func closeBill(ctx workflow.Context, billID int32, reason, closedBy string) error {
 	activityOptions := workflow.ActivityOptions{
 		StartToCloseTimeout: time.Minute,
 		RetryPolicy: &temporal.RetryPolicy{
@@ -112,7 +236,37 @@ func closeBill(ctx workflow.Context, billID int32, reason string) error {
 		},
 	}
 	activityCtx := workflow.WithActivityOptions(ctx, activityOptions)
-	return workflow.ExecuteActivity(activityCtx, CloseBillActivity, billID, reason).Get(ctx, nil)
+	return workflow.ExecuteActivity(activityCtx, CloseBillActivity, billID, reason, closedBy).Get(ctx, nil)
+}
+
+// startClosingGrace executes the StartClosingGrace activity
+func startClosingGrace(ctx workflow.Context, billID int32) error {
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    1 * time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    5,
+		},
+	}
+	activityCtx := workflow.WithActivityOptions(ctx, activityOptions)
+	return workflow.ExecuteActivity(activityCtx, StartClosingGraceActivity, billID).Get(ctx, nil)
+}
+
+// retryFinalization executes the RetryFinalization activity
+func retryFinalization(ctx workflow.Context, billID int32, reason, closedBy string) error {
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    2 * time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    15 * time.Second,
+			MaximumAttempts:    3,
+		},
+	}
+	activityCtx := workflow.WithActivityOptions(ctx, activityOptions)
+	return workflow.ExecuteActivity(activityCtx, RetryFinalizationActivity, billID, reason, closedBy).Get(ctx, nil)
 }
 
 // activateBill executes the ActivateBill activity
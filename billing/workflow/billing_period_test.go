@@ -6,16 +6,25 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.temporal.io/sdk/testsuite"
 	"go.uber.org/mock/gomock"
 
+	"encore.app/billing/business/bill"
 	billmock "encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
 )
 
 // helper to register activities & set dependencies to mock
 func setupMockDeps(ctrl *gomock.Controller, m *billmock.MockBusiness) {
-	SetActivityDependencies(m)
+	SetActivityDependencies(m, nil)
+}
+
+// mockDunning stubs the dunning workflow a closed billing period starts
+func mockDunning(env *testsuite.TestWorkflowEnvironment) {
+	env.RegisterWorkflow(Dunning)
+	env.OnWorkflow(Dunning, mock.Anything, mock.Anything).Return(nil)
 }
 
 func TestBillingPeriodWorkflow_ImmediateActivationAndAutoClose(t *testing.T) {
@@ -26,6 +35,7 @@ func TestBillingPeriodWorkflow_ImmediateActivationAndAutoClose(t *testing.T) {
 
 	var ts testsuite.WorkflowTestSuite
 	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
 	env.RegisterActivity(ActivateBillActivity)
 	env.RegisterActivity(CloseBillActivity)
 	env.RegisterActivity(UpdateBillTotalActivity)
@@ -35,7 +45,7 @@ func TestBillingPeriodWorkflow_ImmediateActivationAndAutoClose(t *testing.T) {
 
 	// Expectations
 	mockBiz.EXPECT().ActivateBill(gomock.Any(), int32(101)).Return(nil).Times(1)
-	mockBiz.EXPECT().CloseBill(gomock.Any(), int32(101), "auto_close").Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), int32(101), "auto_close", model.ActorSystem).Return(nil).Times(1)
 
 	params := BillingPeriodWorkflowParams{BillID: 101, StartTime: start, EndTime: end}
 	env.ExecuteWorkflow(BillingPeriod, params)
@@ -51,6 +61,7 @@ func TestBillingPeriodWorkflow_WaitsUntilStartThenManualClose(t *testing.T) {
 
 	var ts testsuite.WorkflowTestSuite
 	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
 	env.RegisterActivity(ActivateBillActivity)
 	env.RegisterActivity(CloseBillActivity)
 	env.RegisterActivity(UpdateBillTotalActivity)
@@ -60,10 +71,10 @@ func TestBillingPeriodWorkflow_WaitsUntilStartThenManualClose(t *testing.T) {
 	billID := int32(202)
 
 	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
-	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "manual").Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "manual", "admin").Return(nil).Times(1)
 
 	env.RegisterDelayedCallback(func() {
-		env.SignalWorkflow(CloseBillSignalName, CloseBillSignal{Reason: "manual"})
+		env.SignalWorkflow(CloseBillSignalName, CloseBillSignal{Reason: "manual", ClosedBy: "admin"})
 	}, 800*time.Millisecond)
 
 	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: futureStart, EndTime: end}
@@ -80,6 +91,7 @@ func TestBillingPeriodWorkflow_AddLineItemSignalUpdatesTotal(t *testing.T) {
 
 	var ts testsuite.WorkflowTestSuite
 	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
 	env.RegisterActivity(ActivateBillActivity)
 	env.RegisterActivity(CloseBillActivity)
 	env.RegisterActivity(UpdateBillTotalActivity)
@@ -89,8 +101,8 @@ func TestBillingPeriodWorkflow_AddLineItemSignalUpdatesTotal(t *testing.T) {
 	billID := int32(303)
 
 	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
-	mockBiz.EXPECT().UpdateBillTotal(gomock.Any(), billID).Return(nil).Times(2)
-	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close").Return(nil).Times(1)
+	mockBiz.EXPECT().UpdateBillTotal(gomock.Any(), billID, model.ActorSystem).Return(nil).Times(2)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).Return(nil).Times(1)
 
 	env.RegisterDelayedCallback(func() {
 		env.SignalWorkflow(AddLineItemSignalName, AddLineItemSignal{LineItemID: 1})
@@ -105,6 +117,42 @@ func TestBillingPeriodWorkflow_AddLineItemSignalUpdatesTotal(t *testing.T) {
 	assert.NoError(t, env.GetWorkflowError())
 }
 
+func TestBillingPeriodWorkflow_BatchSignalUpdatesTotalOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(CloseBillActivity)
+	env.RegisterActivity(UpdateBillTotalActivity)
+
+	start := time.Now().Add(-150 * time.Millisecond)
+	end := time.Now().Add(1100 * time.Millisecond)
+	billID := int32(304)
+
+	lineItemIDs := make([]int32, bill.MaxLineItemBatchSize)
+	for i := range lineItemIDs {
+		lineItemIDs[i] = int32(i + 1)
+	}
+
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
+	mockBiz.EXPECT().UpdateBillTotal(gomock.Any(), billID, model.ActorSystem).Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).Return(nil).Times(1)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(AddLineItemSignalName, AddLineItemSignal{LineItemIDs: lineItemIDs})
+	}, 120*time.Millisecond)
+
+	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end}
+	env.ExecuteWorkflow(BillingPeriod, params)
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+}
+
 func TestBillingPeriodWorkflow_InvalidPeriodImmediateClose(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -113,6 +161,7 @@ func TestBillingPeriodWorkflow_InvalidPeriodImmediateClose(t *testing.T) {
 
 	var ts testsuite.WorkflowTestSuite
 	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
 	env.RegisterActivity(ActivateBillActivity)
 	env.RegisterActivity(CloseBillActivity)
 	env.RegisterActivity(UpdateBillTotalActivity)
@@ -122,7 +171,7 @@ func TestBillingPeriodWorkflow_InvalidPeriodImmediateClose(t *testing.T) {
 	billID := int32(404)
 
 	// Expect only close with invalid_period reason
-	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "invalid_period").Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "invalid_period", model.ActorSystem).Return(nil).Times(1)
 
 	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end}
 	env.ExecuteWorkflow(BillingPeriod, params)
@@ -130,6 +179,208 @@ func TestBillingPeriodWorkflow_InvalidPeriodImmediateClose(t *testing.T) {
 	assert.NoError(t, env.GetWorkflowError())
 }
 
+func TestBillingPeriodWorkflow_RetriesFailedFinalization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(CloseBillActivity)
+	env.RegisterActivity(RetryFinalizationActivity)
+	env.RegisterActivity(UpdateBillTotalActivity)
+
+	start := time.Now().Add(-1 * time.Second)
+	end := time.Now().Add(time.Second)
+	billID := int32(606)
+
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
+	// Every attempt of the close activity fails and leaves the bill in attention_required
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).Return(errors.New("boom")).Times(6)
+	mockBiz.EXPECT().GetBill(gomock.Any(), billID, model.GetBillOptions{OmitLineItems: true}).
+		Return(&model.Bill{ID: billID, Status: model.BillStatusAttentionRequired}, nil).Times(1)
+	mockBiz.EXPECT().RecoverBill(gomock.Any(), billID, model.BillRecovery{Action: model.BillRecoveryRetry, Justification: "auto_close"}, model.ActorSystem).
+		Return(nil).Times(1)
+
+	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end}
+	env.ExecuteWorkflow(BillingPeriod, params)
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+}
+
+func TestBillingPeriodWorkflow_ReactivationCancelsFinalizationRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(CloseBillActivity)
+	env.RegisterActivity(RetryFinalizationActivity)
+	env.RegisterActivity(UpdateBillTotalActivity)
+
+	start := time.Now().Add(-1 * time.Second)
+	end := time.Now().Add(time.Second)
+	billID := int32(707)
+
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).Return(errors.New("boom")).Times(6)
+	// No finalization retry runs once the operator reopened the bill; it is closed manually later
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "corrected", "admin").Return(nil).Times(1)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(BillReactivatedSignalName, BillReactivatedSignal{EndTime: env.Now().Add(2 * time.Hour)})
+	}, 5*time.Minute)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(CloseBillSignalName, CloseBillSignal{Reason: "corrected", ClosedBy: "admin"})
+	}, time.Hour)
+
+	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end}
+	env.ExecuteWorkflow(BillingPeriod, params)
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+}
+
+func TestBillingPeriodWorkflow_ReactivationSetsCloseTimerAgain(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	reactivatedAt := end.Add(5 * time.Minute)
+
+	testCases := []struct {
+		name            string
+		endTime         time.Time
+		expectedCloseAt time.Time
+	}{
+		{name: "closes_at_new_end_time", endTime: end.Add(48 * time.Hour), expectedCloseAt: end.Add(48 * time.Hour)},
+		{name: "closes_right_away_once_end_time_passed", endTime: end, expectedCloseAt: reactivatedAt},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockBiz := billmock.NewMockBusiness(ctrl)
+			setupMockDeps(ctrl, mockBiz)
+			billID := int32(909)
+
+			var ts testsuite.WorkflowTestSuite
+			env := ts.NewTestWorkflowEnvironment()
+			mockDunning(env)
+			env.SetStartTime(start)
+			env.RegisterActivity(ActivateBillActivity)
+			env.RegisterActivity(CloseBillActivity)
+			env.RegisterActivity(RetryFinalizationActivity)
+			env.RegisterActivity(UpdateBillTotalActivity)
+
+			var closedAt time.Time
+			mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
+			// The close at the original end time fails and the bill is left to an operator
+			failedClose := mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).Return(errors.New("boom")).Times(6)
+			mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).DoAndReturn(func(_ any, _ int32, _, _ string) error {
+				closedAt = env.Now()
+				return nil
+			}).Times(1).After(failedClose)
+
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(BillReactivatedSignalName, BillReactivatedSignal{EndTime: tc.endTime})
+			}, reactivatedAt.Sub(start))
+
+			params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end}
+			env.ExecuteWorkflow(BillingPeriod, params)
+			require.True(t, env.IsWorkflowCompleted())
+			assert.NoError(t, env.GetWorkflowError())
+			assert.Equal(t, tc.expectedCloseAt, closedAt.UTC())
+		})
+	}
+}
+
+func TestBillingPeriodWorkflow_UpdatePeriodReplacesTimer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	extendedEnd := end.Add(48 * time.Hour)
+	billID := int32(808)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
+	env.SetStartTime(start)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(CloseBillActivity)
+	env.RegisterActivity(UpdateBillTotalActivity)
+
+	var closedAt time.Time
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).DoAndReturn(func(_ any, _ int32, _, _ string) error {
+		closedAt = env.Now()
+		return nil
+	}).Times(1)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(UpdatePeriodSignalName, UpdatePeriodSignal{EndTime: extendedEnd})
+	}, time.Hour)
+
+	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end}
+	env.ExecuteWorkflow(BillingPeriod, params)
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, extendedEnd, closedAt.UTC())
+}
+
+func TestBillingPeriodWorkflow_ClosesAfterGracePeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	billID := int32(909)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
+	env.SetStartTime(start)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(StartClosingGraceActivity)
+	env.RegisterActivity(CloseBillActivity)
+	env.RegisterActivity(UpdateBillTotalActivity)
+
+	var graceAt, closedAt time.Time
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
+	mockBiz.EXPECT().StartClosingGrace(gomock.Any(), billID, model.ActorSystem).DoAndReturn(func(_ any, _ int32, _ string) error {
+		graceAt = env.Now()
+		return nil
+	}).Times(1)
+	// A late line item arriving during the grace window still updates the total
+	mockBiz.EXPECT().UpdateBillTotal(gomock.Any(), billID, model.ActorSystem).Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).DoAndReturn(func(_ any, _ int32, _, _ string) error {
+		closedAt = env.Now()
+		return nil
+	}).Times(1)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(AddLineItemSignalName, AddLineItemSignal{LineItemID: 1})
+	}, 24*time.Hour+5*time.Minute)
+
+	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end, GracePeriod: 15 * time.Minute}
+	env.ExecuteWorkflow(BillingPeriod, params)
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, end, graceAt.UTC())
+	assert.Equal(t, end.Add(15*time.Minute), closedAt.UTC())
+}
+
 func TestActivities_FailurePaths(t *testing.T) {
 	testErr := errors.New("boom")
 
@@ -138,13 +389,14 @@ func TestActivities_FailurePaths(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			mockBiz := billmock.NewMockBusiness(ctrl)
-			SetActivityDependencies(mockBiz)
-			t.Cleanup(func() { SetActivityDependencies(nil) })
+			SetActivityDependencies(mockBiz, nil)
+			t.Cleanup(func() { SetActivityDependencies(nil, nil) })
 
 			var ts testsuite.WorkflowTestSuite
 			env := ts.NewTestActivityEnvironment()
 			env.RegisterActivity(ActivateBillActivity)
 			env.RegisterActivity(CloseBillActivity)
+			env.RegisterActivity(RetryFinalizationActivity)
 			env.RegisterActivity(UpdateBillTotalActivity)
 
 			expect(mockBiz)
@@ -169,9 +421,23 @@ func TestActivities_FailurePaths(t *testing.T) {
 	})
 
 	run("CloseBillActivity failure", func(m *billmock.MockBusiness) {
-		m.EXPECT().CloseBill(gomock.Any(), int32(1), "reason").Return(testErr).Times(1)
+		m.EXPECT().CloseBill(gomock.Any(), int32(1), "reason", model.ActorSystem).Return(testErr).Times(1)
+	}, func(env *testsuite.TestActivityEnvironment) error {
+		fut, err := env.ExecuteActivity(CloseBillActivity, int32(1), "reason", "")
+		if err != nil {
+			return err
+		}
+		var out interface{}
+		return fut.Get(&out)
+	})
+
+	run("RetryFinalizationActivity failure", func(m *billmock.MockBusiness) {
+		m.EXPECT().GetBill(gomock.Any(), int32(1), model.GetBillOptions{OmitLineItems: true}).
+			Return(&model.Bill{ID: 1, Status: model.BillStatusAttentionRequired}, nil).Times(1)
+		m.EXPECT().RecoverBill(gomock.Any(), int32(1), model.BillRecovery{Action: model.BillRecoveryRetry, Justification: "reason"}, model.ActorSystem).
+			Return(testErr).Times(1)
 	}, func(env *testsuite.TestActivityEnvironment) error {
-		fut, err := env.ExecuteActivity(CloseBillActivity, int32(1), "reason")
+		fut, err := env.ExecuteActivity(RetryFinalizationActivity, int32(1), "reason", "")
 		if err != nil {
 			return err
 		}
@@ -180,7 +446,7 @@ func TestActivities_FailurePaths(t *testing.T) {
 	})
 
 	run("UpdateBillTotalActivity failure", func(m *billmock.MockBusiness) {
-		m.EXPECT().UpdateBillTotal(gomock.Any(), int32(1)).Return(testErr).Times(1)
+		m.EXPECT().UpdateBillTotal(gomock.Any(), int32(1), model.ActorSystem).Return(testErr).Times(1)
 	}, func(env *testsuite.TestActivityEnvironment) error {
 		fut, err := env.ExecuteActivity(UpdateBillTotalActivity, int32(1))
 		if err != nil {
@@ -190,3 +456,39 @@ func TestActivities_FailurePaths(t *testing.T) {
 		return fut.Get(&out)
 	})
 }
+
+func TestBillingPeriodWorkflow_AutoClosesAtLocalMidnightAcrossDST(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// Clocks spring forward on this day, so the local day is 23 hours long
+	start := time.Date(2025, 3, 9, 0, 0, 0, 0, loc)
+	end := time.Date(2025, 3, 10, 0, 0, 0, 0, loc)
+	billID := int32(505)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	mockDunning(env)
+	env.SetStartTime(start)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(CloseBillActivity)
+	env.RegisterActivity(UpdateBillTotalActivity)
+
+	var closedAt time.Time
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), billID).Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), billID, "auto_close", model.ActorSystem).DoAndReturn(func(_ any, _ int32, _, _ string) error {
+		closedAt = env.Now()
+		return nil
+	}).Times(1)
+
+	params := BillingPeriodWorkflowParams{BillID: billID, StartTime: start, EndTime: end}
+	env.ExecuteWorkflow(BillingPeriod, params)
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, 23*time.Hour, closedAt.Sub(start))
+	assert.False(t, closedAt.Before(end))
+}
@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"encore.app/billing/model"
+)
+
+const day = 24 * time.Hour
+
+// DefaultDunningSchedule reminds the customer twice, charges a 2% late fee after two weeks and
+// escalates after thirty days
+var DefaultDunningSchedule = []model.DunningStep{
+	{After: 1 * day, Action: model.DunningActionReminder},
+	{After: 7 * day, Action: model.DunningActionReminder},
+	{After: 14 * day, Action: model.DunningActionLateFee, LateFeeBasisPoints: 200},
+	{After: 30 * day, Action: model.DunningActionEscalate},
+}
+
+// DunningWorkflowParams contains parameters for dunning an unpaid bill. An empty Schedule runs
+// DefaultDunningSchedule.
+type DunningWorkflowParams struct {
+	BillID   int32               `json:"bill_id"`
+	Schedule []model.DunningStep `json:"schedule,omitempty"`
+}
+
+// BillDue is the payment state of a bill when dunning starts
+type BillDue struct {
+	Closed       bool      `json:"closed"`
+	DueAt        time.Time `json:"due_at"`
+	BalanceCents int64     `json:"balance_cents"`
+}
+
+// DunningWorkflowID is the ID of the dunning workflow of a bill, so payments can signal it
+func DunningWorkflowID(billID int32) string {
+	return fmt.Sprintf("dunning-%d", billID)
+}
+
+// Dunning runs the dunning schedule of a closed bill, applying each step once its time after the
+// due date has come. It ends early when a payment-received signal reports the balance settled
+// or a step finds the bill no longer needs dunning.
+func Dunning(ctx workflow.Context, params DunningWorkflowParams) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Starting dunning workflow", "billID", params.BillID)
+
+	due, err := getBillDue(ctx, params.BillID)
+	if err != nil {
+		logger.Error("Failed to get bill due date", "billID", params.BillID, "error", err)
+		return err
+	}
+	if !due.Closed || due.BalanceCents <= 0 {
+		logger.Info("Bill needs no dunning", "billID", params.BillID, "balance", due.BalanceCents)
+		return nil
+	}
+
+	schedule := params.Schedule
+	if len(schedule) == 0 {
+		schedule = DefaultDunningSchedule
+	}
+
+	paymentReceivedCh := workflow.GetSignalChannel(ctx, PaymentReceivedSignalName)
+	settled := false
+
+	for i, step := range schedule {
+		wait := max(due.DueAt.Add(step.After).Sub(workflow.Now(ctx)), 0)
+		timerCtx, cancelTimer := workflow.WithCancel(ctx)
+		timer := workflow.NewTimer(timerCtx, wait)
+
+		timerFired := false
+		for !timerFired && !settled {
+			selector := workflow.NewSelector(ctx)
+			selector.AddReceive(paymentReceivedCh, func(c workflow.ReceiveChannel, more bool) {
+				var signal PaymentReceivedSignal
+				c.Receive(ctx, &signal)
+				logger.Info("Payment received", "billID", params.BillID, "paymentID", signal.PaymentID, "balance", signal.BalanceCents)
+				if signal.BalanceCents <= 0 {
+					settled = true
+					cancelTimer()
+				}
+			})
+			selector.AddFuture(timer, func(f workflow.Future) {
+				timerFired = true
+			})
+			selector.Select(ctx)
+		}
+		if settled {
+			logger.Info("Bill settled, stopping dunning", "billID", params.BillID, "step", i)
+			return nil
+		}
+
+		logger.Info("Applying dunning step", "billID", params.BillID, "step", i, "action", step.Action)
+		done, err := applyDunningStep(ctx, params.BillID, i, step)
+		if err != nil {
+			// A failed reminder must not hold back the steps after it
+			logger.Error("Failed to apply dunning step", "billID", params.BillID, "step", i, "error", err)
+			continue
+		}
+		if done {
+			logger.Info("Bill no longer needs dunning", "billID", params.BillID, "step", i)
+			return nil
+		}
+	}
+
+	logger.Info("Dunning schedule completed", "billID", params.BillID)
+	return nil
+}
+
+// startDunning starts the dunning workflow of a closed bill. The workflow outlives the billing
+// period workflow that starts it.
+func startDunning(ctx workflow.Context, billID int32, schedule []model.DunningStep) error {
+	childOptions := workflow.ChildWorkflowOptions{
+		WorkflowID:        DunningWorkflowID(billID),
+		ParentClosePolicy: enumspb.PARENT_CLOSE_POLICY_ABANDON,
+	}
+	childCtx := workflow.WithChildOptions(ctx, childOptions)
+	dunning := workflow.ExecuteChildWorkflow(childCtx, Dunning, DunningWorkflowParams{
+		BillID:   billID,
+		Schedule: schedule,
+	})
+	return dunning.GetChildWorkflowExecution().Get(ctx, nil)
+}
+
+// getBillDue executes the GetBillDue activity
+func getBillDue(ctx workflow.Context, billID int32) (*BillDue, error) {
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    1 * time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    5,
+		},
+	}
+	activityCtx := workflow.WithActivityOptions(ctx, activityOptions)
+
+	var result BillDue
+	if err := workflow.ExecuteActivity(activityCtx, GetBillDueActivity, billID).Get(ctx, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// applyDunningStep executes the DunningStep activity; it reports whether dunning is over
+func applyDunningStep(ctx workflow.Context, billID int32, stepIndex int, step model.DunningStep) (bool, error) {
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    2 * time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	}
+	activityCtx := workflow.WithActivityOptions(ctx, activityOptions)
+
+	var done bool
+	if err := workflow.ExecuteActivity(activityCtx, DunningStepActivity, billID, stepIndex, step).Get(ctx, &done); err != nil {
+		return false, err
+	}
+	return done, nil
+}
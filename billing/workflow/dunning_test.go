@@ -0,0 +1,253 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/mock/gomock"
+
+	"encore.dev/beta/errs"
+
+	billmock "encore.app/billing/mocks/business/bill_business"
+	"encore.app/billing/model"
+)
+
+// dunningDueAt is the due date of the bills dunned in these tests
+var dunningDueAt = time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC)
+
+// newDunningTestEnv registers the dunning workflow with a mocked bill business and records the
+// notices it sends
+func newDunningTestEnv(t *testing.T) (*testsuite.TestWorkflowEnvironment, *billmock.MockBusiness, *[]model.DunningNotice) {
+	ctrl := gomock.NewController(t)
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	SetActivityDependencies(mockBiz, nil)
+	t.Cleanup(func() { SetActivityDependencies(nil, nil) })
+
+	var notices []model.DunningNotice
+	SetDunningNotifier(func(ctx context.Context, notice model.DunningNotice) error {
+		notices = append(notices, notice)
+		return nil
+	})
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	env.SetStartTime(dunningDueAt)
+	env.RegisterWorkflow(Dunning)
+	env.RegisterActivity(GetBillDueActivity)
+	env.RegisterActivity(DunningStepActivity)
+
+	return env, mockBiz, &notices
+}
+
+func unpaidBill(id int32, balance int64) *model.Bill {
+	dueAt := dunningDueAt
+	return &model.Bill{ID: id, Status: model.BillStatusClosed, BalanceCents: balance, DueAt: &dueAt}
+}
+
+// expectDunningSteps makes ApplyDunningStep return a notice for each step, recording when it ran
+func expectDunningSteps(env *testsuite.TestWorkflowEnvironment, mockBiz *billmock.MockBusiness, billID int32, appliedAt *[]time.Duration) {
+	mockBiz.EXPECT().ApplyDunningStep(gomock.Any(), billID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id int32, stepIndex int, step model.DunningStep) (*model.DunningNotice, error) {
+			*appliedAt = append(*appliedAt, env.Now().Sub(dunningDueAt))
+			return &model.DunningNotice{BillID: id, Step: stepIndex, Action: step.Action, LateFeeCents: step.LateFee(5000), BalanceCents: 5000}, nil
+		}).AnyTimes()
+}
+
+func TestDunningWorkflow_RunsDefaultSchedule(t *testing.T) {
+	env, mockBiz, notices := newDunningTestEnv(t)
+
+	mockBiz.EXPECT().GetBill(gomock.Any(), int32(10), model.GetBillOptions{OmitLineItems: true}).Return(unpaidBill(10, 5000), nil).Times(1)
+	var appliedAt []time.Duration
+	expectDunningSteps(env, mockBiz, 10, &appliedAt)
+
+	env.ExecuteWorkflow(Dunning, DunningWorkflowParams{BillID: 10})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	assert.Equal(t, []time.Duration{1 * day, 7 * day, 14 * day, 30 * day}, appliedAt)
+	require.Len(t, *notices, 4)
+	assert.Equal(t, model.DunningActionReminder, (*notices)[0].Action)
+	assert.Equal(t, model.DunningActionLateFee, (*notices)[2].Action)
+	assert.Equal(t, int64(100), (*notices)[2].LateFeeCents, "2% of the balance")
+	assert.Equal(t, model.DunningActionEscalate, (*notices)[3].Action)
+}
+
+func TestDunningWorkflow_RunsConfiguredSchedule(t *testing.T) {
+	env, mockBiz, notices := newDunningTestEnv(t)
+
+	mockBiz.EXPECT().GetBill(gomock.Any(), int32(11), gomock.Any()).Return(unpaidBill(11, 5000), nil).Times(1)
+	var appliedAt []time.Duration
+	expectDunningSteps(env, mockBiz, 11, &appliedAt)
+
+	schedule := []model.DunningStep{
+		{After: 3 * day, Action: model.DunningActionLateFee, LateFeeCents: 1500},
+		{After: 10 * day, Action: model.DunningActionEscalate},
+	}
+	env.ExecuteWorkflow(Dunning, DunningWorkflowParams{BillID: 11, Schedule: schedule})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	assert.Equal(t, []time.Duration{3 * day, 10 * day}, appliedAt)
+	require.Len(t, *notices, 2)
+	assert.Equal(t, int64(1500), (*notices)[0].LateFeeCents)
+}
+
+func TestDunningWorkflow_PaymentSettlingBalanceStopsDunning(t *testing.T) {
+	env, mockBiz, notices := newDunningTestEnv(t)
+
+	mockBiz.EXPECT().GetBill(gomock.Any(), int32(12), gomock.Any()).Return(unpaidBill(12, 5000), nil).Times(1)
+	var appliedAt []time.Duration
+	expectDunningSteps(env, mockBiz, 12, &appliedAt)
+
+	// A partial payment keeps dunning going; the payment that settles the balance stops it
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(PaymentReceivedSignalName, PaymentReceivedSignal{PaymentID: 1, BalanceCents: 2000})
+	}, 2*day)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(PaymentReceivedSignalName, PaymentReceivedSignal{PaymentID: 2, BalanceCents: 0})
+	}, 9*day)
+
+	env.ExecuteWorkflow(Dunning, DunningWorkflowParams{BillID: 12})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	assert.Equal(t, []time.Duration{1 * day, 7 * day}, appliedAt)
+	assert.Len(t, *notices, 2)
+	assert.Equal(t, 9*day, env.Now().Sub(dunningDueAt), "workflow ends when the balance is settled")
+}
+
+func TestDunningWorkflow_SettledBillIsNotDunned(t *testing.T) {
+	testCases := []struct {
+		name string
+		bill *model.Bill
+	}{
+		{name: "paid", bill: unpaidBill(13, 0)},
+		{name: "overpaid", bill: unpaidBill(13, -100)},
+		{name: "not_closed", bill: &model.Bill{ID: 13, Status: model.BillStatusAttentionRequired, BalanceCents: 5000}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env, mockBiz, notices := newDunningTestEnv(t)
+
+			mockBiz.EXPECT().GetBill(gomock.Any(), int32(13), gomock.Any()).Return(tc.bill, nil).Times(1)
+			mockBiz.EXPECT().ApplyDunningStep(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			env.ExecuteWorkflow(Dunning, DunningWorkflowParams{BillID: 13})
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			assert.Empty(t, *notices)
+		})
+	}
+}
+
+func TestDunningWorkflow_StopsWhenStepFindsBillPaid(t *testing.T) {
+	env, mockBiz, notices := newDunningTestEnv(t)
+
+	mockBiz.EXPECT().GetBill(gomock.Any(), int32(14), gomock.Any()).Return(unpaidBill(14, 5000), nil).Times(1)
+	gomock.InOrder(
+		mockBiz.EXPECT().ApplyDunningStep(gomock.Any(), int32(14), 0, DefaultDunningSchedule[0]).
+			Return(&model.DunningNotice{BillID: 14, Step: 0, Action: model.DunningActionReminder, BalanceCents: 5000}, nil),
+		// Paid without a signal reaching the workflow
+		mockBiz.EXPECT().ApplyDunningStep(gomock.Any(), int32(14), 1, DefaultDunningSchedule[1]).Return(nil, nil),
+	)
+
+	env.ExecuteWorkflow(Dunning, DunningWorkflowParams{BillID: 14})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Len(t, *notices, 1)
+}
+
+func TestDunningWorkflow_FailedStepDoesNotStopSchedule(t *testing.T) {
+	env, mockBiz, notices := newDunningTestEnv(t)
+
+	mockBiz.EXPECT().GetBill(gomock.Any(), int32(15), gomock.Any()).Return(unpaidBill(15, 5000), nil).Times(1)
+	schedule := []model.DunningStep{
+		{After: day, Action: model.DunningActionLateFee},
+		{After: 2 * day, Action: model.DunningActionEscalate},
+	}
+	gomock.InOrder(
+		// A late fee step without a fee is rejected and not retried
+		mockBiz.EXPECT().ApplyDunningStep(gomock.Any(), int32(15), 0, schedule[0]).
+			Return(nil, &errs.Error{Code: errs.InvalidArgument, Message: "late fee must be positive"}).Times(1),
+		mockBiz.EXPECT().ApplyDunningStep(gomock.Any(), int32(15), 1, schedule[1]).
+			Return(&model.DunningNotice{BillID: 15, Step: 1, Action: model.DunningActionEscalate, BalanceCents: 5000}, nil),
+	)
+
+	env.ExecuteWorkflow(Dunning, DunningWorkflowParams{BillID: 15, Schedule: schedule})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, *notices, 1)
+	assert.Equal(t, model.DunningActionEscalate, (*notices)[0].Action)
+}
+
+func TestDunningWorkflow_GetBillFailure(t *testing.T) {
+	env, mockBiz, _ := newDunningTestEnv(t)
+
+	mockBiz.EXPECT().GetBill(gomock.Any(), int32(16), gomock.Any()).Return(nil, errors.New("database error")).MinTimes(1)
+
+	env.ExecuteWorkflow(Dunning, DunningWorkflowParams{BillID: 16})
+	require.True(t, env.IsWorkflowCompleted())
+	assert.Error(t, env.GetWorkflowError())
+}
+
+func TestBillingPeriodWorkflow_StartsDunningAfterClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(Dunning)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(CloseBillActivity)
+
+	schedule := []model.DunningStep{{After: day, Action: model.DunningActionEscalate}}
+	var dunningParams DunningWorkflowParams
+	env.OnWorkflow(Dunning, mock.Anything, mock.Anything).Return(func(ctx workflow.Context, params DunningWorkflowParams) error {
+		assert.Equal(t, DunningWorkflowID(606), workflow.GetInfo(ctx).WorkflowExecution.ID)
+		dunningParams = params
+		return nil
+	}).Once()
+
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), int32(606)).Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), int32(606), "auto_close", model.ActorSystem).Return(nil).Times(1)
+
+	start := time.Now()
+	env.ExecuteWorkflow(BillingPeriod, BillingPeriodWorkflowParams{BillID: 606, StartTime: start, EndTime: start.Add(time.Hour), DunningSchedule: schedule})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	assert.Equal(t, DunningWorkflowParams{BillID: 606, Schedule: schedule}, dunningParams)
+}
+
+func TestBillingPeriodWorkflow_DunningFailureDoesNotFailPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBiz := billmock.NewMockBusiness(ctrl)
+	setupMockDeps(ctrl, mockBiz)
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(Dunning)
+	env.RegisterActivity(ActivateBillActivity)
+	env.RegisterActivity(CloseBillActivity)
+	env.OnWorkflow(Dunning, mock.Anything, mock.Anything).Return(errors.New("dunning failed"))
+
+	mockBiz.EXPECT().ActivateBill(gomock.Any(), int32(607)).Return(nil).Times(1)
+	mockBiz.EXPECT().CloseBill(gomock.Any(), int32(607), "auto_close", model.ActorSystem).Return(nil).Times(1)
+
+	start := time.Now()
+	env.ExecuteWorkflow(BillingPeriod, BillingPeriodWorkflowParams{BillID: 607, StartTime: start, EndTime: start.Add(time.Hour)})
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+}
@@ -1,15 +1,29 @@
 package workflow
 
+import "time"
+
 const (
 	// Signal names
 	AddLineItemSignalName = "add-line-item"
 	CloseBillSignalName   = "close-bill"
+	// BillReactivatedSignalName tells the workflow an operator returned the bill to active,
+	// so pending finalization retries must not close it and the period closes at its end time
+	BillReactivatedSignalName = "bill-reactivated"
+	UpdatePeriodSignalName    = "update-period"
+	// PaymentReceivedSignalName is sent to the dunning workflow of a bill for each payment
+	PaymentReceivedSignalName = "payment-received"
+
+	PauseSubscriptionSignalName  = "pause-subscription"
+	ResumeSubscriptionSignalName = "resume-subscription"
+	CancelSubscriptionSignalName = "cancel-subscription"
 )
 
 // AddLineItemSignal contains simplified data for adding a line item to a bill
 // The activity will query the database for full line item details and handle bill total updates
 type AddLineItemSignal struct {
 	LineItemID int32 `json:"line_item_id"`
+	// LineItemIDs is set instead of LineItemID when a batch of line items was added
+	LineItemIDs []int32 `json:"line_item_ids,omitempty"`
 }
 
 // CloseBillSignal contains data for manually closing a bill
@@ -17,3 +31,22 @@ type CloseBillSignal struct {
 	Reason   string `json:"reason"`
 	ClosedBy string `json:"closed_by"`
 }
+
+// UpdatePeriodSignal carries the new end time of a billing period; the workflow replaces its
+// close timer with one that fires at EndTime
+type UpdatePeriodSignal struct {
+	EndTime time.Time `json:"end_time"`
+}
+
+// BillReactivatedSignal carries the end time of a bill an operator returned to active; the
+// workflow closes the bill at EndTime, or right away once it has passed
+type BillReactivatedSignal struct {
+	EndTime time.Time `json:"end_time"`
+}
+
+// PaymentReceivedSignal reports a payment against a bill in dunning; BalanceCents is what is
+// left to pay after it
+type PaymentReceivedSignal struct {
+	PaymentID    int32 `json:"payment_id"`
+	BalanceCents int64 `json:"balance_cents"`
+}
@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"encore.app/billing/model"
+)
+
+// SubscriptionWorkflowParams contains parameters for running a subscription. Each run bills
+// one period and continues as new with the next period index, so history stays bounded.
+type SubscriptionWorkflowParams struct {
+	SubscriptionID int32 `json:"subscription_id"`
+	PeriodIndex    int32 `json:"period_index"`
+	Paused         bool  `json:"paused"`
+	// DunningSchedule is passed on to the billing period of each bill; empty runs
+	// DefaultDunningSchedule
+	DunningSchedule []model.DunningStep `json:"dunning_schedule,omitempty"`
+}
+
+// SubscriptionPeriodBill describes the bill created for a subscription period
+type SubscriptionPeriodBill struct {
+	BillID      int32     `json:"bill_id"`
+	PeriodIndex int32     `json:"period_index"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	WorkflowID  string    `json:"workflow_id"`
+}
+
+// subscriptionState tracks the control signals received by a subscription run
+type subscriptionState struct {
+	paused    bool
+	cancelled bool
+
+	pauseCh  workflow.ReceiveChannel
+	resumeCh workflow.ReceiveChannel
+	cancelCh workflow.ReceiveChannel
+}
+
+// Subscription creates a bill for each period of a subscription and runs its BillingPeriod
+// lifecycle as a child workflow. The next bill is created once the previous one closes.
+// Pausing stops new bills from being created until resumed; cancelling ends the subscription
+// while the current bill, if any, runs to completion.
+func Subscription(ctx workflow.Context, params SubscriptionWorkflowParams) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Starting subscription workflow", "subscriptionID", params.SubscriptionID, "periodIndex", params.PeriodIndex, "paused", params.Paused)
+
+	state := &subscriptionState{
+		paused:   params.Paused,
+		pauseCh:  workflow.GetSignalChannel(ctx, PauseSubscriptionSignalName),
+		resumeCh: workflow.GetSignalChannel(ctx, ResumeSubscriptionSignalName),
+		cancelCh: workflow.GetSignalChannel(ctx, CancelSubscriptionSignalName),
+	}
+
+	for state.paused && !state.cancelled {
+		logger.Info("Subscription paused, waiting for resume", "subscriptionID", params.SubscriptionID)
+		selector := workflow.NewSelector(ctx)
+		state.addSignalHandlers(ctx, selector)
+		selector.Select(ctx)
+	}
+	if state.cancelled {
+		logger.Info("Subscription cancelled", "subscriptionID", params.SubscriptionID)
+		return nil
+	}
+
+	periodBill, err := createSubscriptionBill(ctx, params.SubscriptionID, params.PeriodIndex)
+	if err != nil {
+		logger.Error("Failed to create subscription bill", "subscriptionID", params.SubscriptionID, "periodIndex", params.PeriodIndex, "error", err)
+		return err
+	}
+
+	// The bill outlives this run: it is abandoned rather than terminated on continue-as-new or cancellation
+	childOptions := workflow.ChildWorkflowOptions{
+		WorkflowID:        periodBill.WorkflowID,
+		ParentClosePolicy: enumspb.PARENT_CLOSE_POLICY_ABANDON,
+	}
+	childCtx := workflow.WithChildOptions(ctx, childOptions)
+	billingPeriod := workflow.ExecuteChildWorkflow(childCtx, BillingPeriod, BillingPeriodWorkflowParams{
+		BillID:    periodBill.BillID,
+		StartTime: periodBill.StartTime,
+		EndTime:   periodBill.EndTime,
+
+		DunningSchedule: params.DunningSchedule,
+	})
+
+	billClosed := false
+	for !billClosed && !state.cancelled {
+		selector := workflow.NewSelector(ctx)
+		state.addSignalHandlers(ctx, selector)
+		selector.AddFuture(billingPeriod, func(f workflow.Future) {
+			// A bill closed manually terminates its workflow; either way the period is over
+			if err := f.Get(ctx, nil); err != nil {
+				logger.Warn("Billing period ended with error", "subscriptionID", params.SubscriptionID, "billID", periodBill.BillID, "error", err)
+			}
+			billClosed = true
+		})
+		selector.Select(ctx)
+	}
+
+	// Signals that arrived alongside the last event would be lost by continue-as-new
+	state.drainSignals(ctx)
+	if state.cancelled {
+		logger.Info("Subscription cancelled", "subscriptionID", params.SubscriptionID, "billID", periodBill.BillID)
+		return nil
+	}
+
+	logger.Info("Subscription period completed", "subscriptionID", params.SubscriptionID, "billID", periodBill.BillID, "periodIndex", periodBill.PeriodIndex)
+	return workflow.NewContinueAsNewError(ctx, Subscription, SubscriptionWorkflowParams{
+		SubscriptionID:  params.SubscriptionID,
+		PeriodIndex:     periodBill.PeriodIndex + 1,
+		Paused:          state.paused,
+		DunningSchedule: params.DunningSchedule,
+	})
+}
+
+// addSignalHandlers registers the pause, resume and cancel signal handlers on the selector
+func (s *subscriptionState) addSignalHandlers(ctx workflow.Context, selector workflow.Selector) {
+	selector.AddReceive(s.pauseCh, func(c workflow.ReceiveChannel, more bool) {
+		c.Receive(ctx, nil)
+		s.paused = true
+	})
+	selector.AddReceive(s.resumeCh, func(c workflow.ReceiveChannel, more bool) {
+		c.Receive(ctx, nil)
+		s.paused = false
+	})
+	selector.AddReceive(s.cancelCh, func(c workflow.ReceiveChannel, more bool) {
+		c.Receive(ctx, nil)
+		s.cancelled = true
+	})
+}
+
+// drainSignals applies any buffered signals without blocking
+func (s *subscriptionState) drainSignals(ctx workflow.Context) {
+	for {
+		selector := workflow.NewSelector(ctx)
+		s.addSignalHandlers(ctx, selector)
+		if !selector.HasPending() {
+			return
+		}
+		selector.Select(ctx)
+	}
+}
+
+// createSubscriptionBill executes the CreateSubscriptionBill activity
+func createSubscriptionBill(ctx workflow.Context, subscriptionID int32, minPeriodIndex int32) (*SubscriptionPeriodBill, error) {
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    2 * time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	}
+	activityCtx := workflow.WithActivityOptions(ctx, activityOptions)
+
+	var result SubscriptionPeriodBill
+	if err := workflow.ExecuteActivity(activityCtx, CreateSubscriptionBillActivity, subscriptionID, minPeriodIndex).Get(ctx, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
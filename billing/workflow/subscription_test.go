@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/mock/gomock"
+
+	submock "encore.app/billing/mocks/business/subscription_business"
+	"encore.app/billing/model"
+)
+
+// newSubscriptionTestEnv registers the subscription workflow with a mocked bill lifecycle
+func newSubscriptionTestEnv(t *testing.T) (*testsuite.TestWorkflowEnvironment, *submock.MockBusiness) {
+	ctrl := gomock.NewController(t)
+	mockSub := submock.NewMockBusiness(ctrl)
+	SetActivityDependencies(nil, mockSub)
+	t.Cleanup(func() { SetActivityDependencies(nil, nil) })
+
+	var ts testsuite.WorkflowTestSuite
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(BillingPeriod)
+	env.RegisterActivity(CreateSubscriptionBillActivity)
+
+	return env, mockSub
+}
+
+func periodBill(id int32, start time.Time) *model.Bill {
+	workflowID := "bill-subscription-period"
+	return &model.Bill{ID: id, StartTime: start, EndTime: start.Add(time.Hour), WorkflowID: &workflowID}
+}
+
+// continuedParams extracts the parameters a workflow continued as new with
+func continuedParams(t *testing.T, err error) SubscriptionWorkflowParams {
+	t.Helper()
+
+	var continueAsNew *workflow.ContinueAsNewError
+	require.True(t, errors.As(err, &continueAsNew), "expected continue-as-new, got %v", err)
+
+	var params SubscriptionWorkflowParams
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayloads(continueAsNew.Input, &params))
+	return params
+}
+
+func TestSubscriptionWorkflow_BillsPeriodThenContinuesAsNew(t *testing.T) {
+	env, mockSub := newSubscriptionTestEnv(t)
+
+	start := time.Now()
+	mockSub.EXPECT().CreatePeriodBill(gomock.Any(), int32(7), int32(2)).Return(periodBill(70, start), int32(3), nil).Times(1)
+
+	var childParams BillingPeriodWorkflowParams
+	env.OnWorkflow(BillingPeriod, mock.Anything, mock.Anything).Return(func(ctx workflow.Context, params BillingPeriodWorkflowParams) error {
+		childParams = params
+		return nil
+	})
+
+	env.ExecuteWorkflow(Subscription, SubscriptionWorkflowParams{SubscriptionID: 7, PeriodIndex: 2})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.Equal(t, int32(70), childParams.BillID)
+	assert.True(t, childParams.EndTime.Equal(start.Add(time.Hour)))
+	// Periods skipped while paused are not revisited: the next run starts after the billed period
+	assert.Equal(t, SubscriptionWorkflowParams{SubscriptionID: 7, PeriodIndex: 4}, continuedParams(t, env.GetWorkflowError()))
+}
+
+func TestSubscriptionWorkflow_ClosedBillWithErrorStillAdvances(t *testing.T) {
+	env, mockSub := newSubscriptionTestEnv(t)
+
+	mockSub.EXPECT().CreatePeriodBill(gomock.Any(), int32(7), int32(0)).Return(periodBill(70, time.Now()), int32(0), nil).Times(1)
+	env.OnWorkflow(BillingPeriod, mock.Anything, mock.Anything).Return(errors.New("terminated"))
+
+	env.ExecuteWorkflow(Subscription, SubscriptionWorkflowParams{SubscriptionID: 7})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.Equal(t, int32(1), continuedParams(t, env.GetWorkflowError()).PeriodIndex)
+}
+
+func TestSubscriptionWorkflow_PauseCarriedAcrossContinueAsNew(t *testing.T) {
+	env, mockSub := newSubscriptionTestEnv(t)
+
+	mockSub.EXPECT().CreatePeriodBill(gomock.Any(), int32(7), int32(0)).Return(periodBill(70, time.Now()), int32(0), nil).Times(1)
+	env.OnWorkflow(BillingPeriod, mock.Anything, mock.Anything).After(time.Hour).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(PauseSubscriptionSignalName, nil)
+	}, time.Minute)
+
+	env.ExecuteWorkflow(Subscription, SubscriptionWorkflowParams{SubscriptionID: 7})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.Equal(t, SubscriptionWorkflowParams{SubscriptionID: 7, PeriodIndex: 1, Paused: true}, continuedParams(t, env.GetWorkflowError()))
+}
+
+func TestSubscriptionWorkflow_PausedWaitsForResume(t *testing.T) {
+	env, mockSub := newSubscriptionTestEnv(t)
+
+	var billedAt time.Time
+	mockSub.EXPECT().CreatePeriodBill(gomock.Any(), int32(7), int32(1)).
+		DoAndReturn(func(_ any, _ int32, _ int32) (*model.Bill, int32, error) {
+			billedAt = env.Now()
+			return periodBill(71, time.Now()), int32(1), nil
+		}).Times(1)
+	env.OnWorkflow(BillingPeriod, mock.Anything, mock.Anything).Return(nil)
+
+	startedAt := env.Now()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(ResumeSubscriptionSignalName, nil)
+	}, 48*time.Hour)
+
+	env.ExecuteWorkflow(Subscription, SubscriptionWorkflowParams{SubscriptionID: 7, PeriodIndex: 1, Paused: true})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.GreaterOrEqual(t, billedAt.Sub(startedAt), 48*time.Hour)
+	assert.Equal(t, SubscriptionWorkflowParams{SubscriptionID: 7, PeriodIndex: 2}, continuedParams(t, env.GetWorkflowError()))
+}
+
+func TestSubscriptionWorkflow_CancelledWhilePaused(t *testing.T) {
+	env, _ := newSubscriptionTestEnv(t)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(CancelSubscriptionSignalName, nil)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(Subscription, SubscriptionWorkflowParams{SubscriptionID: 7, Paused: true})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+}
+
+func TestSubscriptionWorkflow_CancelledDuringBillingPeriod(t *testing.T) {
+	env, mockSub := newSubscriptionTestEnv(t)
+
+	mockSub.EXPECT().CreatePeriodBill(gomock.Any(), int32(7), int32(0)).Return(periodBill(70, time.Now()), int32(0), nil).Times(1)
+	env.OnWorkflow(BillingPeriod, mock.Anything, mock.Anything).After(24 * time.Hour).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(CancelSubscriptionSignalName, nil)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(Subscription, SubscriptionWorkflowParams{SubscriptionID: 7})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+}
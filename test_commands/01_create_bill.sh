@@ -71,6 +71,7 @@ echo "  Currency: $CURRENCY"
 echo "  Start time: $START_TIME_DISPLAY"
 echo "  End time: $END_TIME"
 
+ensure_account
 IDEMP="create-$(date +%s)-$RANDOM"
 RESP=$(api POST /v1/bills '{
   "currency": "'$CURRENCY'",
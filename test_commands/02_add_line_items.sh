@@ -9,6 +9,7 @@ if [[ -z "$BILL_ID" ]]; then
   BILL_ID=$(load_bill_id)
 fi
 [[ -n "$BILL_ID" ]] || fail "Bill ID required (arg or .last_bill_id)"
+ensure_account
 
 info "Adding line items to bill $BILL_ID"
 TOTAL=0
@@ -9,6 +9,7 @@ if [[ -z "$BILL_ID" ]]; then
   BILL_ID=$(load_bill_id)
 fi
 [[ -n "$BILL_ID" ]] || fail "Bill ID required (arg or .last_bill_id)"
+ensure_account
 
 CONCURRENT="${RACE_CONCURRENT:-5}"
 AMOUNT="${RACE_AMOUNT:-1000}"
@@ -25,6 +26,7 @@ for i in $(seq 1 "$CONCURRENT"); do
 JSON
 )
     resp=$(curl -sS -w '\n%{http_code}' -X POST "$BASE_URL/v1/bills/$BILL_ID/line_items" \
+      -H "Authorization: Bearer $API_KEY" \
       -H 'Content-Type: application/json' \
       -H "X-Idempotency-Key: $key" \
       -d "$body") || echo "curl_error" > "$TMP_DIR/$i.status"
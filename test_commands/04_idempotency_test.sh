@@ -7,6 +7,7 @@ require jq
 DUPLICATE_KEY="dup-$(date +%s)"
 START_TIME=$(date -v+1d -u +"%Y-%m-%dT%H:%M:%SZ")
 END_TIME=$(date -v+2d -u +"%Y-%m-%dT%H:%M:%SZ")
+ensure_account
 BODY="{\"currency\":\"USD\",\"start_time\":\"$START_TIME\",\"end_time\":\"$END_TIME\"}"
 
 info "Idempotent create bill (key=$DUPLICATE_KEY) first call"
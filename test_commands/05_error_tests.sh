@@ -1,8 +1,20 @@
 #!/bin/bash
 # Test 5: Error handling tests
 
+# Create an account and API key to own the test bills (requires ADMIN_API_KEY)
+ACCOUNT_ID=$(curl -s -X POST 'http://localhost:4000/v1/accounts' \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer $ADMIN_API_KEY" \
+  -H "X-Idempotency-Key: account-$(date +%s)-$RANDOM" \
+  -d '{"name": "Test Account"}' | jq -r '.account.id')
+API_KEY=$(curl -s -X POST 'http://localhost:4000/v1/accounts/'$ACCOUNT_ID'/api_keys' \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer $ADMIN_API_KEY" \
+  -d '{"name": "test scripts"}' | jq -r '.api_key.key')
+
 echo "=== Error Test 1: Invalid Bill ID ==="
 curl -X POST 'http://localhost:4000/v1/bills/99999/line_items' \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json' \
   -H 'X-Idempotency-Key: error-test-'$(date +%s)-$RANDOM'' \
   -d '{
@@ -16,8 +28,10 @@ curl -X POST 'http://localhost:4000/v1/bills/99999/line_items' \
 echo ""
 echo "=== Error Test 2: Missing Idempotency Key ==="
 curl -X POST 'http://localhost:4000/v1/bills' \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json' \
   -d '{
+    "account_id": '$ACCOUNT_ID',
     "currency": "USD",
     "start_time": null,
     "end_time": "'$(date -v+1d -u +"%Y-%m-%dT%H:%M:%SZ")'"
@@ -27,9 +41,11 @@ curl -X POST 'http://localhost:4000/v1/bills' \
 echo ""
 echo "=== Error Test 3: Invalid Currency ==="
 curl -X POST 'http://localhost:4000/v1/bills' \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json' \
   -H 'X-Idempotency-Key: error-currency-'$(date +%s)-$RANDOM'' \
   -d '{
+    "account_id": '$ACCOUNT_ID',
     "currency": "INVALID",
     "start_time": null,
     "end_time": "'$(date -v+1d -u +"%Y-%m-%dT%H:%M:%SZ")'"
@@ -41,9 +57,11 @@ echo "=== Error Test 4: End Time Before Start Time ==="
 FUTURE_START=$(date -v+2d -u +"%Y-%m-%dT%H:%M:%SZ")
 PAST_END=$(date -v+1d -u +"%Y-%m-%dT%H:%M:%SZ")
 curl -X POST 'http://localhost:4000/v1/bills' \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json' \
   -H 'X-Idempotency-Key: error-time-'$(date +%s)-$RANDOM'' \
   -d '{
+    "account_id": '$ACCOUNT_ID',
     "currency": "USD",
     "start_time": "'$FUTURE_START'",
     "end_time": "'$PAST_END'"
@@ -55,9 +73,11 @@ echo "=== Error Test 5: Start Time in Past ==="
 PAST_START=$(date -v-1d -u +"%Y-%m-%dT%H:%M:%SZ")
 FUTURE_END=$(date -v+1d -u +"%Y-%m-%dT%H:%M:%SZ")
 curl -X POST 'http://localhost:4000/v1/bills' \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json' \
   -H 'X-Idempotency-Key: error-past-start-'$(date +%s)-$RANDOM'' \
   -d '{
+    "account_id": '$ACCOUNT_ID',
     "currency": "USD",
     "start_time": "'$PAST_START'",
     "end_time": "'$FUTURE_END'"
@@ -5,12 +5,25 @@ echo "=========================================="
 echo "      COMPLETE BILLING SERVICE TEST"
 echo "=========================================="
 
+# Create an account and API key to own the test bills (requires ADMIN_API_KEY)
+ACCOUNT_ID=$(curl -s -X POST 'http://localhost:4000/v1/accounts' \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer $ADMIN_API_KEY" \
+  -H "X-Idempotency-Key: account-$(date +%s)-$RANDOM" \
+  -d '{"name": "Test Account"}' | jq -r '.account.id')
+API_KEY=$(curl -s -X POST 'http://localhost:4000/v1/accounts/'$ACCOUNT_ID'/api_keys' \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer $ADMIN_API_KEY" \
+  -d '{"name": "test scripts"}' | jq -r '.api_key.key')
+
 # Step 1: Create bill
 echo "STEP 1: Creating bill..."
 RESPONSE=$(curl -s -X POST 'http://localhost:4000/v1/bills' \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json' \
   -H 'X-Idempotency-Key: complete-flow-'$(date +%s)-$RANDOM'' \
   -d '{
+    "account_id": '$ACCOUNT_ID',
     "currency": "USD",
     "start_time": null,
     "end_time": "'$(date -v+2d -u +"%Y-%m-%dT%H:%M:%SZ")'"
@@ -42,6 +55,7 @@ for i in ${!amounts[@]}; do
   
   echo "Adding item $item_num: $amount cents"
   curl -s -X POST 'http://localhost:4000/v1/bills/'$BILL_ID'/line_items' \
+    -H "Authorization: Bearer $API_KEY" \
     -H 'Content-Type: application/json' \
     -H 'X-Idempotency-Key: flow-item-$item_num-'$(date +%s)-$RANDOM'' \
     -d '{
@@ -69,6 +83,7 @@ sleep 5
 echo ""
 echo "STEP 3: Fetching bill status..."
 BILL_STATUS_RESPONSE=$(curl -s -X GET "http://localhost:4000/v1/bills/$BILL_ID" \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json')
 
 echo "$BILL_STATUS_RESPONSE" | jq '.'
@@ -96,6 +111,7 @@ fi
 echo ""
 echo "STEP 4: Closing bill..."
 CLOSE_RESPONSE=$(curl -s -X POST "http://localhost:4000/v1/bills/$BILL_ID/close" \
+  -H "Authorization: Bearer $API_KEY" \
   -H 'Content-Type: application/json' \
   -H 'X-Idempotency-Key: complete-flow-close-'$(date +%s)-$RANDOM'' \
   -d '{
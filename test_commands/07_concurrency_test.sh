@@ -4,15 +4,28 @@
 
 BASE_URL="http://localhost:4000"
 
+# Create an account and API key to own the test bills (requires ADMIN_API_KEY)
+ACCOUNT_ID=$(curl -s -X POST "$BASE_URL/v1/accounts" \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer $ADMIN_API_KEY" \
+  -H "X-Idempotency-Key: account-$(date +%s)-$RANDOM" \
+  -d '{"name": "Concurrency Test Account"}' | jq -r '.account.id')
+API_KEY=$(curl -s -X POST "$BASE_URL/v1/accounts/$ACCOUNT_ID/api_keys" \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer $ADMIN_API_KEY" \
+  -d '{"name": "test scripts"}' | jq -r '.api_key.key')
+
 # Helper function to create an active bill
 create_active_bill() {
   local test_name="$1"
   echo "📋 Creating new active bill for $test_name..." >&2
 
   local response=$(curl -s -X POST "$BASE_URL/v1/bills" \
+    -H "Authorization: Bearer $API_KEY" \
     -H 'Content-Type: application/json' \
     -H "X-Idempotency-Key: concurrency-$test_name-$(date +%s)-$RANDOM" \
     -d '{
+      "account_id": '$ACCOUNT_ID',
       "currency": "USD",
       "start_time": null,
       "end_time": "'$(date -v+1d -u +"%Y-%m-%dT%H:%M:%SZ")'"
@@ -35,7 +48,7 @@ create_active_bill() {
     sleep 3
 
     # Check status again
-    local status_response=$(curl -s -X GET "$BASE_URL/v1/bills/$bill_id" -H 'Content-Type: application/json')
+    local status_response=$(curl -s -X GET "$BASE_URL/v1/bills/$bill_id" -H 'Content-Type: application/json' -H "Authorization: Bearer $API_KEY")
     local new_status=$(echo "$status_response" | jq -r '.bill.status // "unknown"')
     echo "📊 Bill status after wait: $new_status" >&2
   fi
@@ -72,6 +85,7 @@ else
   (
     echo "🟢 Starting AddLineItem..."
     curl -X POST "$BASE_URL/v1/bills/$BILL_ID_1/line_items" \
+      -H "Authorization: Bearer $API_KEY" \
       -H 'Content-Type: application/json' \
       -H "X-Idempotency-Key: concurrent-add-$(date +%s)-$RANDOM" \
       -d '{
@@ -88,6 +102,7 @@ else
   (
     echo "🔴 Starting CloseBill..."
     curl -X POST "$BASE_URL/v1/bills/$BILL_ID_1/close" \
+      -H "Authorization: Bearer $API_KEY" \
       -H 'Content-Type: application/json' \
       -H "X-Idempotency-Key: concurrent-close-$(date +%s)-$RANDOM" \
       -d '{"reason": "concurrent_test"}' \
@@ -138,6 +153,7 @@ else
       (
         echo "🟢 Rapid AddLineItem $i"
         curl -s -X POST "$BASE_URL/v1/bills/$BILL_ID_2/line_items" \
+          -H "Authorization: Bearer $API_KEY" \
           -H 'Content-Type: application/json' \
           -H "X-Idempotency-Key: rapid-add-$i-$(date +%s)-$RANDOM" \
           -d "{
@@ -154,6 +170,7 @@ else
       (
         echo "🔴 Rapid CloseBill $i"
         curl -s -X POST "$BASE_URL/v1/bills/$BILL_ID_2/close" \
+          -H "Authorization: Bearer $API_KEY" \
           -H 'Content-Type: application/json' \
           -H "X-Idempotency-Key: rapid-close-$i-$(date +%s)-$RANDOM" \
           -d "{\"reason\": \"rapid_test_$i\"}" \
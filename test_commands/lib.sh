@@ -2,6 +2,8 @@
 set -euo pipefail
 
 BASE_URL="${BASE_URL:-http://localhost:4000}"
+# ADMIN_API_KEY must match the AdminAPIKey secret of the running service
+ADMIN_API_KEY="${ADMIN_API_KEY:-}"
 
 _red()  { printf "\033[31m%s\033[0m\n" "$*"; }
 _grn()  { printf "\033[32m%s\033[0m\n" "$*"; }
@@ -37,6 +39,7 @@ api() {
   local body="${1:-}"; shift || true
   local idem="${1:-}"; shift || true
   local hdrs=(-H 'Content-Type: application/json')
+  [[ -n "${API_KEY:-}" ]] && hdrs+=( -H "Authorization: Bearer $API_KEY" )
   [[ -n "$idem" ]] && hdrs+=( -H "X-Idempotency-Key: $idem" )
   curl -sS -X "$method" "${hdrs[@]}" "$BASE_URL$path" -d "$body"
 }
@@ -48,6 +51,7 @@ api_status() {
   local body="${1:-}"; shift || true
   local idem="${1:-}"; shift || true
   local hdrs=(-H 'Content-Type: application/json')
+  [[ -n "${API_KEY:-}" ]] && hdrs+=( -H "Authorization: Bearer $API_KEY" )
   [[ -n "$idem" ]] && hdrs+=( -H "X-Idempotency-Key: $idem" )
   local resp
   resp=$(curl -sS -w '\n%{http_code}' -X "$method" "${hdrs[@]}" "$BASE_URL$path" -d "$body") || fail "curl failed"
@@ -64,8 +68,31 @@ summary_inc() { SUMMARY_TOTAL=$((SUMMARY_TOTAL+1)); }
 summary_fail() { SUMMARY_FAIL=$((SUMMARY_FAIL+1)); }
 summary_report() { if [[ $SUMMARY_FAIL -eq 0 ]]; then pass "Summary: $SUMMARY_TOTAL checks passed"; else fail "Summary: $SUMMARY_FAIL failed / $SUMMARY_TOTAL total"; fi }
 
+# admin_api <method> <path> <json-body> [idempotency-key] calls the API with the admin key
+admin_api() { API_KEY="$ADMIN_API_KEY" api "$@"; }
+
+# ensure_account creates a test account with an API key unless API_KEY is already set or
+# saved by a previous script, and exports API_KEY. Bills created with the key belong to that account.
+ensure_account() {
+  [[ -n "${API_KEY:-}" ]] || API_KEY=$(load_api_key)
+  if [[ -z "$API_KEY" ]]; then
+    [[ -n "$ADMIN_API_KEY" ]] || fail "ADMIN_API_KEY must be set to create a test account"
+    local resp
+    resp=$(admin_api POST /v1/accounts '{"name":"Test Account"}' "account-$(date +%s)-$RANDOM")
+    ACCOUNT_ID=$(json_field "$resp" '.account.id')
+    assert_nonempty "$ACCOUNT_ID" account_id
+    resp=$(admin_api POST "/v1/accounts/$ACCOUNT_ID/api_keys" '{"name":"test scripts"}')
+    API_KEY=$(json_field "$resp" '.api_key.key')
+    assert_nonempty "$API_KEY" api_key
+    save_api_key "$API_KEY"
+  fi
+  export API_KEY
+}
+
 save_bill_id() { echo -n "$1" > "$(dirname "$0")/.last_bill_id"; }
 load_bill_id() { cat "$(dirname "$0")/.last_bill_id" 2>/dev/null || true; }
+save_api_key() { echo -n "$1" > "$(dirname "$0")/.last_api_key"; }
+load_api_key() { cat "$(dirname "$0")/.last_api_key" 2>/dev/null || true; }
 
 # trap to show failing line
 trap 's=$?; if [[ $s -ne 0 ]]; then _red "Script failed (exit $s) at line $BASH_LINENO"; fi' EXIT
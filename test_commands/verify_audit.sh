@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+# Verifies the hash-chained audit log of the given bills, or of every bill when no bill ID is
+# given (admin key required). Exits non-zero when any chain is broken.
+#
+# Usage: ADMIN_API_KEY=... bash test_commands/verify_audit.sh [bill_id ...]
+set -euo pipefail
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+source "$SCRIPT_DIR/lib.sh"
+require curl
+require jq
+
+API_KEY="${API_KEY:-$ADMIN_API_KEY}"
+AUTH=(-H "Authorization: Bearer $API_KEY")
+
+if [[ $# -eq 0 ]]; then
+  info "Verifying the audit log of every bill"
+  RESPONSE=$(curl -sS "${AUTH[@]}" "$BASE_URL/v1/audit/verify")
+  FAILURES=$(json_field "$RESPONSE" '.report.failures | length') || fail "Unexpected response: $RESPONSE"
+  if [[ "$FAILURES" != "0" ]]; then
+    echo "$RESPONSE" | jq '.report.failures'
+    fail "$FAILURES bill(s) failed audit verification"
+  fi
+  pass "$(json_field "$RESPONSE" '.report.bills_checked') bill(s) verified"
+  exit 0
+fi
+
+for BILL_ID in "$@"; do
+  RESPONSE=$(curl -sS "${AUTH[@]}" "$BASE_URL/v1/bills/$BILL_ID/audit/verify")
+  VALID=$(json_field "$RESPONSE" '.verification.valid') || fail "Unexpected response: $RESPONSE"
+  if [[ "$VALID" != "true" ]]; then
+    fail "bill $BILL_ID: $(json_field "$RESPONSE" '.verification.problem') (seq $(json_field "$RESPONSE" '.verification.broken_at_seq'))"
+  fi
+  pass "bill $BILL_ID: $(json_field "$RESPONSE" '.verification.entries') entries verified"
+done